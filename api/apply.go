@@ -0,0 +1,207 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/go-rancher/api"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+const (
+	applyActionCreate      = "create"
+	applyActionUpdate      = "update"
+	applyActionUnchanged   = "unchanged"
+	applyActionUnsupported = "unsupported"
+)
+
+// Apply reconciles the cluster toward the desired-state document in the
+// request: missing volumes and recurring jobs are created, drifted settings
+// and supported volume fields are updated. With dryRun set nothing is
+// changed and only the planned actions are returned, so infrastructure-as-code
+// tools can present a diff before applying it.
+func (s *Server) Apply(rw http.ResponseWriter, req *http.Request) error {
+	var input ApplyInput
+	apiContext := api.GetApiContext(req)
+
+	if err := apiContext.Read(&input); err != nil {
+		return err
+	}
+
+	output := &ApplyOutput{DryRun: input.DryRun}
+
+	// Iterate the settings in a stable order so the plan output is
+	// reproducible.
+	settingNames := make([]string, 0, len(input.Settings))
+	for name := range input.Settings {
+		settingNames = append(settingNames, name)
+	}
+	sort.Strings(settingNames)
+	for _, name := range settingNames {
+		change, err := s.applySetting(name, input.Settings[name], input.DryRun)
+		if err != nil {
+			return err
+		}
+		output.Changes = append(output.Changes, *change)
+	}
+
+	for i := range input.RecurringJobs {
+		change, err := s.applyRecurringJob(&input.RecurringJobs[i], input.DryRun)
+		if err != nil {
+			return err
+		}
+		output.Changes = append(output.Changes, *change)
+	}
+
+	for i := range input.Volumes {
+		change, err := s.applyVolume(&input.Volumes[i], input.DryRun)
+		if err != nil {
+			return err
+		}
+		output.Changes = append(output.Changes, *change)
+	}
+
+	apiContext.Write(output)
+	return nil
+}
+
+func (s *Server) applySetting(name, value string, dryRun bool) (*ApplyChange, error) {
+	change := &ApplyChange{Kind: "setting", Name: name, Action: applyActionUnchanged}
+
+	setting, err := s.m.GetSetting(types.SettingName(name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get setting %v", name)
+	}
+	if setting.Value == value {
+		return change, nil
+	}
+
+	change.Action = applyActionUpdate
+	change.Detail = fmt.Sprintf("value %v -> %v", setting.Value, value)
+	if dryRun {
+		return change, nil
+	}
+	setting.Value = value
+	if _, err := s.m.CreateOrUpdateSetting(setting); err != nil {
+		return nil, errors.Wrapf(err, "unable to update setting %v", name)
+	}
+	return change, nil
+}
+
+func (s *Server) applyRecurringJob(job *RecurringJob, dryRun bool) (*ApplyChange, error) {
+	change := &ApplyChange{Kind: "recurringJob", Name: job.Name, Action: applyActionUnchanged}
+
+	if job.Task != longhorn.RecurringJobTypeBackup && job.Task != longhorn.RecurringJobTypeSnapshot {
+		return nil, fmt.Errorf("recurring job type %v is not valid", job.Task)
+	}
+	spec := longhorn.RecurringJobSpec{
+		Name:        job.Name,
+		Groups:      job.Groups,
+		Task:        job.Task,
+		Cron:        job.Cron,
+		Retain:      job.Retain,
+		Concurrency: job.Concurrency,
+		Labels:      job.Labels,
+	}
+
+	existing, err := s.m.GetRecurringJob(job.Name)
+	if err != nil {
+		if !datastore.ErrorIsNotFound(err) {
+			return nil, errors.Wrapf(err, "unable to get recurring job %v", job.Name)
+		}
+		change.Action = applyActionCreate
+		change.Detail = fmt.Sprintf("task %v, cron %v", spec.Task, spec.Cron)
+		if dryRun {
+			return change, nil
+		}
+		if _, err := s.m.CreateRecurringJob(&spec); err != nil {
+			return nil, errors.Wrapf(err, "unable to create recurring job %v", job.Name)
+		}
+		return change, nil
+	}
+
+	if reflect.DeepEqual(existing.Spec, spec) {
+		return change, nil
+	}
+	change.Action = applyActionUpdate
+	change.Detail = fmt.Sprintf("spec %+v -> %+v", existing.Spec, spec)
+	if dryRun {
+		return change, nil
+	}
+	if _, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.UpdateRecurringJob(spec)
+	}); err != nil {
+		return nil, errors.Wrapf(err, "unable to update recurring job %v", job.Name)
+	}
+	return change, nil
+}
+
+func (s *Server) applyVolume(volume *Volume, dryRun bool) (*ApplyChange, error) {
+	change := &ApplyChange{Kind: "volume", Name: volume.Name, Action: applyActionUnchanged}
+
+	v, err := s.m.Get(volume.Name)
+	if err != nil {
+		if !datastore.ErrorIsNotFound(err) {
+			return nil, errors.Wrapf(err, "unable to get volume %v", volume.Name)
+		}
+		change.Action = applyActionCreate
+		change.Detail = fmt.Sprintf("size %v, %v replica(s)", volume.Size, volume.NumberOfReplicas)
+		if dryRun {
+			return change, nil
+		}
+		if _, err := s.createVolume(volume); err != nil {
+			return nil, err
+		}
+		return change, nil
+	}
+
+	// Only the fields the manager can reconcile on a live volume are
+	// applied; any other drift is reported so the caller can decide how to
+	// resolve it.
+	size, err := util.ConvertSize(volume.Size)
+	if err != nil {
+		return nil, types.NewCodedError(types.ErrorCodeInvalidArgument, fmt.Errorf("fail to parse size %v", err))
+	}
+	size = util.RoundUpSize(size)
+	if size < v.Spec.Size {
+		change.Action = applyActionUnsupported
+		change.Detail = fmt.Sprintf("cannot shrink volume from size %v to %v", v.Spec.Size, size)
+		return change, nil
+	}
+	if size > v.Spec.Size {
+		change.Action = applyActionUpdate
+		change.Detail = fmt.Sprintf("expand size %v -> %v", v.Spec.Size, size)
+		if !dryRun {
+			if _, err := util.RetryOnConflictCause(func() (interface{}, error) {
+				return s.m.Expand(volume.Name, size)
+			}); err != nil {
+				return nil, errors.Wrapf(err, "unable to expand volume %v", volume.Name)
+			}
+		}
+	}
+	if volume.NumberOfReplicas != 0 && volume.NumberOfReplicas != v.Spec.NumberOfReplicas {
+		detail := fmt.Sprintf("replica count %v -> %v", v.Spec.NumberOfReplicas, volume.NumberOfReplicas)
+		if change.Action == applyActionUpdate {
+			change.Detail = change.Detail + "; " + detail
+		} else {
+			change.Action = applyActionUpdate
+			change.Detail = detail
+		}
+		if !dryRun {
+			if _, err := util.RetryOnConflictCause(func() (interface{}, error) {
+				return s.m.UpdateReplicaCount(volume.Name, volume.NumberOfReplicas)
+			}); err != nil {
+				return nil, errors.Wrapf(err, "unable to update the replica count of volume %v", volume.Name)
+			}
+		}
+	}
+	return change, nil
+}
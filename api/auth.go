@@ -0,0 +1,121 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-manager/manager"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+const (
+	authSecretAdminTokensKey    = "admin-tokens"
+	authSecretReadOnlyTokensKey = "read-only-tokens"
+
+	roleAdmin           = "admin"
+	roleReadOnly        = "read-only"
+	roleUnauthenticated = "unauthenticated"
+)
+
+// authenticationHandler enforces the optional bearer token authentication of
+// the REST API and writes the audit trail of mutating requests. With the
+// api-authentication-secret setting unset every request passes through, which
+// matches the historical in-cluster behavior.
+type authenticationHandler struct {
+	m    *manager.VolumeManager
+	next http.Handler
+}
+
+func NewAuthenticationHandler(m *manager.VolumeManager, next http.Handler) http.Handler {
+	return &authenticationHandler{m: m, next: next}
+}
+
+func (h *authenticationHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	role, status, err := h.authenticate(req)
+	if err != nil {
+		if isMutatingRequest(req) {
+			logrus.Warnf("API audit: denied %v %v from %v: %v", req.Method, req.URL.Path, req.RemoteAddr, err)
+		}
+		http.Error(rw, err.Error(), status)
+		return
+	}
+	if isMutatingRequest(req) {
+		logrus.Infof("API audit: %v %v from %v role=%v", req.Method, req.URL.Path, req.RemoteAddr, role)
+	}
+	h.next.ServeHTTP(rw, req)
+}
+
+// authenticate resolves the role of the request, or the HTTP status and the
+// error to answer with. The token lists are read from the secret on every
+// request through the informer cache, so rotating the secret takes effect
+// without restarting the manager.
+func (h *authenticationHandler) authenticate(req *http.Request) (string, int, error) {
+	setting, err := h.m.GetSetting(types.SettingNameAPIAuthenticationSecret)
+	if err != nil {
+		// Fail closed: not knowing whether authentication is enabled
+		// must not turn it off.
+		logrus.Errorf("Failed to get the %v setting: %v", types.SettingNameAPIAuthenticationSecret, err)
+		return "", http.StatusServiceUnavailable, errors.New("cannot verify the API credentials")
+	}
+	if setting.Value == "" {
+		return roleUnauthenticated, 0, nil
+	}
+
+	namespace := os.Getenv(types.EnvPodNamespace)
+	secret, err := h.m.GetSecretRO(namespace, setting.Value)
+	if err != nil {
+		// Fail closed: an unreadable token secret must not turn the
+		// authentication off.
+		logrus.Errorf("Failed to get the API authentication secret %v: %v", setting.Value, err)
+		return "", http.StatusServiceUnavailable, errors.New("cannot verify the API credentials")
+	}
+
+	token := bearerToken(req)
+	if token == "" {
+		return "", http.StatusUnauthorized, errors.New("missing bearer token")
+	}
+	if tokenListContains(secret.Data[authSecretAdminTokensKey], token) {
+		return roleAdmin, 0, nil
+	}
+	if tokenListContains(secret.Data[authSecretReadOnlyTokensKey], token) {
+		if isMutatingRequest(req) {
+			return "", http.StatusForbidden, errors.New("the read-only role cannot perform mutating requests")
+		}
+		return roleReadOnly, 0, nil
+	}
+	return "", http.StatusUnauthorized, errors.New("invalid bearer token")
+}
+
+func bearerToken(req *http.Request) string {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+}
+
+func tokenListContains(list []byte, token string) bool {
+	// Compare every candidate in constant time so the response timing does
+	// not leak how much of a token matched, or which list it is in.
+	match := 0
+	for _, candidate := range strings.Split(string(list), "\n") {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			match |= subtle.ConstantTimeCompare([]byte(candidate), []byte(token))
+		}
+	}
+	return match == 1
+}
+
+func isMutatingRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
@@ -1,13 +1,20 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/rancher/go-rancher/api"
 	"github.com/rancher/go-rancher/client"
 	"github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/longhorn/longhorn-manager/util"
 )
 
 func (s *Server) BackupTargetList(w http.ResponseWriter, req *http.Request) error {
@@ -21,14 +28,125 @@ func (s *Server) BackupTargetList(w http.ResponseWriter, req *http.Request) erro
 	return nil
 }
 
+func (s *Server) BackupTargetGet(w http.ResponseWriter, req *http.Request) error {
+	apiContext := api.GetApiContext(req)
+
+	name := mux.Vars(req)["name"]
+
+	backupTarget, err := s.m.GetBackupTarget(name)
+	if err != nil {
+		return errors.Wrapf(err, "error getting backup target '%s'", name)
+	}
+	apiContext.Write(toBackupTargetResource(backupTarget))
+	return nil
+}
+
+func (s *Server) BackupTargetCreate(w http.ResponseWriter, req *http.Request) error {
+	var input BackupTarget
+	apiContext := api.GetApiContext(req)
+
+	if err := apiContext.Read(&input); err != nil {
+		return err
+	}
+	if input.Name == "" {
+		return fmt.Errorf("backup target name is required")
+	}
+
+	spec, err := toBackupTargetSpec(&input)
+	if err != nil {
+		return err
+	}
+	backupTarget, err := s.m.CreateBackupTarget(input.Name, spec)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create backup target %v", input.Name)
+	}
+	apiContext.Write(toBackupTargetResource(backupTarget))
+	return nil
+}
+
+func (s *Server) BackupTargetUpdate(w http.ResponseWriter, req *http.Request) error {
+	var input BackupTarget
+	apiContext := api.GetApiContext(req)
+
+	if err := apiContext.Read(&input); err != nil {
+		return err
+	}
+
+	name := mux.Vars(req)["name"]
+
+	spec, err := toBackupTargetSpec(&input)
+	if err != nil {
+		return err
+	}
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.UpdateBackupTarget(name, spec)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to update backup target %v", name)
+	}
+	backupTarget, ok := obj.(*longhorn.BackupTarget)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to backup target %v object", name)
+	}
+	apiContext.Write(toBackupTargetResource(backupTarget))
+	return nil
+}
+
+func (s *Server) BackupTargetDelete(w http.ResponseWriter, req *http.Request) error {
+	name := mux.Vars(req)["name"]
+	if err := s.m.DeleteBackupTarget(name); err != nil {
+		return errors.Wrapf(err, "unable to delete backup target %v", name)
+	}
+
+	return nil
+}
+
+func toBackupTargetSpec(bt *BackupTarget) (*longhorn.BackupTargetSpec, error) {
+	// Default to the same interval as the backupstore poll interval setting
+	pollInterval := 300 * time.Second
+	if bt.PollInterval != "" {
+		var err error
+		pollInterval, err = time.ParseDuration(bt.PollInterval)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse poll interval %v", bt.PollInterval)
+		}
+	}
+	return &longhorn.BackupTargetSpec{
+		BackupTargetURL:  bt.BackupTargetURL,
+		CredentialSecret: bt.CredentialSecret,
+		PollInterval:     metav1.Duration{Duration: pollInterval},
+	}, nil
+}
+
 func (s *Server) BackupVolumeList(w http.ResponseWriter, req *http.Request) error {
 	apiContext := api.GetApiContext(req)
 
-	bvs, err := s.backupVolumeList(apiContext)
+	query, err := parseListQuery(req)
 	if err != nil {
 		return err
 	}
-	apiContext.Write(bvs)
+
+	bvs, err := s.m.ListBackupVolumesSorted()
+	if err != nil {
+		return errors.Wrap(err, "error listing backup volume")
+	}
+
+	pager := newPager(query)
+	page := []*longhorn.BackupVolume{}
+	for _, bv := range bvs {
+		if !pager.include(bv.Name) {
+			if pager.truncated {
+				break
+			}
+			continue
+		}
+		page = append(page, bv)
+	}
+
+	resp := toBackupVolumeCollection(page, apiContext)
+	resp.Pagination = pager.pagination()
+	resp.Data = selectFields(resp.Data, query)
+	apiContext.Write(resp)
 	return nil
 }
 
@@ -53,6 +171,25 @@ func (s *Server) BackupVolumeGet(w http.ResponseWriter, req *http.Request) error
 	return nil
 }
 
+func (s *Server) BackupVolumeSync(w http.ResponseWriter, req *http.Request) error {
+	apiContext := api.GetApiContext(req)
+
+	volName := mux.Vars(req)["volName"]
+
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.SyncBackupVolume(volName)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error requesting backup volume '%s' sync", volName)
+	}
+	bv, ok := obj.(*longhorn.BackupVolume)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to backup volume %v object", volName)
+	}
+	apiContext.Write(toBackupVolumeResource(bv, apiContext))
+	return nil
+}
+
 func (s *Server) BackupVolumeDelete(w http.ResponseWriter, req *http.Request) error {
 	volName := mux.Vars(req)["volName"]
 	if err := s.m.DeleteBackupVolume(volName); err != nil {
@@ -66,11 +203,36 @@ func (s *Server) BackupList(w http.ResponseWriter, req *http.Request) error {
 
 	volName := mux.Vars(req)["volName"]
 
+	query, err := parseListQuery(req)
+	if err != nil {
+		return err
+	}
+	state := req.URL.Query().Get(queryState)
+
 	bs, err := s.m.ListBackupsForVolumeSorted(volName)
 	if err != nil {
 		return errors.Wrapf(err, "error listing backups for volume '%s'", volName)
 	}
-	apiContext.Write(toBackupCollection(bs))
+
+	pager := newPager(query)
+	page := []*longhorn.Backup{}
+	for _, b := range bs {
+		if state != "" && string(b.Status.State) != state {
+			continue
+		}
+		if !pager.include(b.Name) {
+			if pager.truncated {
+				break
+			}
+			continue
+		}
+		page = append(page, b)
+	}
+
+	resp := toBackupCollection(page)
+	resp.Pagination = pager.pagination()
+	resp.Data = selectFields(resp.Data, query)
+	apiContext.Write(resp)
 	return nil
 }
 
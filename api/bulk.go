@@ -0,0 +1,161 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/rancher/go-rancher/api"
+	"github.com/rancher/go-rancher/client"
+
+	bsutil "github.com/longhorn/backupstore/util"
+
+	"github.com/longhorn/longhorn-manager/util"
+)
+
+const (
+	bulkOperationAttach             = "attach"
+	bulkOperationDetach             = "detach"
+	bulkOperationDelete             = "delete"
+	bulkOperationUpdateReplicaCount = "updateReplicaCount"
+	bulkOperationBackup             = "backup"
+
+	defaultBulkConcurrency = 5
+	maxBulkConcurrency     = 20
+)
+
+// VolumeBulk runs one operation against a set of volumes, selected either by
+// name or by label, with a bounded number of workers. Failures are collected
+// per volume instead of aborting the batch, so the caller gets one result
+// entry for every requested volume.
+func (s *Server) VolumeBulk(rw http.ResponseWriter, req *http.Request) error {
+	apiContext := api.GetApiContext(req)
+
+	var input BulkVolumeInput
+	if err := apiContext.Read(&input); err != nil {
+		return err
+	}
+
+	operation, err := s.bulkOperation(&input)
+	if err != nil {
+		return err
+	}
+
+	names, err := s.resolveBulkVolumes(&input)
+	if err != nil {
+		return err
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+	if concurrency > maxBulkConcurrency {
+		concurrency = maxBulkConcurrency
+	}
+
+	results := make([]BulkVolumeResult, len(names))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				name := names[index]
+				results[index] = BulkVolumeResult{Volume: name}
+				if err := operation(name); err != nil {
+					results[index].Error = err.Error()
+				}
+			}
+		}()
+	}
+	for index := range names {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	apiContext.Write(&BulkVolumeOutput{
+		Resource: client.Resource{Type: "bulkVolumeOutput"},
+		Results:  results,
+	})
+	return nil
+}
+
+// bulkOperation maps the requested operation name onto the per-volume action,
+// reusing the same manager calls as the single volume endpoints.
+func (s *Server) bulkOperation(input *BulkVolumeInput) (func(name string) error, error) {
+	switch input.Operation {
+	case bulkOperationAttach:
+		if input.HostID == "" {
+			return nil, fmt.Errorf("hostId required for the %v operation", input.Operation)
+		}
+		return func(name string) error {
+			_, err := util.RetryOnConflictCause(func() (interface{}, error) {
+				return s.m.Attach(name, input.HostID, input.DisableFrontend, input.AttachedBy)
+			})
+			return err
+		}, nil
+	case bulkOperationDetach:
+		return func(name string) error {
+			_, err := util.RetryOnConflictCause(func() (interface{}, error) {
+				return s.m.Detach(name, input.HostID)
+			})
+			return err
+		}, nil
+	case bulkOperationDelete:
+		return func(name string) error {
+			return s.m.Delete(name)
+		}, nil
+	case bulkOperationUpdateReplicaCount:
+		if input.ReplicaCount < 1 {
+			return nil, fmt.Errorf("replicaCount required for the %v operation", input.Operation)
+		}
+		return func(name string) error {
+			_, err := util.RetryOnConflictCause(func() (interface{}, error) {
+				return s.m.UpdateReplicaCount(name, input.ReplicaCount)
+			})
+			return err
+		}, nil
+	case bulkOperationBackup:
+		if input.SnapshotName == "" {
+			return nil, fmt.Errorf("snapshotName required for the %v operation", input.Operation)
+		}
+		return func(name string) error {
+			return s.m.BackupSnapshot(bsutil.GenerateName("backup"), name, input.SnapshotName, input.Labels)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported bulk operation %v", input.Operation)
+	}
+}
+
+// resolveBulkVolumes turns the volume names or the label selector of the
+// request into the sorted list of volumes the operation runs against.
+func (s *Server) resolveBulkVolumes(input *BulkVolumeInput) ([]string, error) {
+	if len(input.Volumes) > 0 {
+		return input.Volumes, nil
+	}
+	if len(input.LabelSelector) == 0 {
+		return nil, fmt.Errorf("either volumes or labelSelector is required")
+	}
+
+	volumes, err := s.m.ListSorted()
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, v := range volumes {
+		match := true
+		for key, value := range input.LabelSelector {
+			if v.Labels[key] != value {
+				match = false
+				break
+			}
+		}
+		if match {
+			names = append(names, v.Name)
+		}
+	}
+	return names, nil
+}
@@ -51,6 +51,40 @@ func (s *Server) EngineImageGet(rw http.ResponseWriter, req *http.Request) error
 	return nil
 }
 
+// EngineImageVolumeList returns the volumes still referencing the engine
+// image, i.e. the ones blocking its deprecation.
+func (s *Server) EngineImageVolumeList(rw http.ResponseWriter, req *http.Request) error {
+	apiContext := api.GetApiContext(req)
+
+	id := mux.Vars(req)["name"]
+
+	volumes, err := s.m.ListVolumesForEngineImage(id)
+	if err != nil {
+		return errors.Wrapf(err, "error listing volumes for engine image '%s'", id)
+	}
+
+	resp := &client.GenericCollection{}
+	for _, v := range volumes {
+		controllers, err := s.m.GetEnginesSorted(v.Name)
+		if err != nil {
+			return err
+		}
+		replicas, err := s.m.GetReplicasSorted(v.Name)
+		if err != nil {
+			return err
+		}
+		backups, err := s.m.ListBackupsForVolumeSorted(v.Name)
+		if err != nil {
+			return err
+		}
+		resp.Data = append(resp.Data, toVolumeResource(v, controllers, replicas, backups, apiContext))
+	}
+	resp.ResourceType = "volume"
+
+	apiContext.Write(resp)
+	return nil
+}
+
 func (s *Server) EngineImageCreate(rw http.ResponseWriter, req *http.Request) error {
 	var img EngineImage
 	apiContext := api.GetApiContext(req)
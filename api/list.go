@@ -0,0 +1,180 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/go-rancher/client"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+const (
+	queryLimit    = "limit"
+	queryContinue = "continue"
+	queryFields   = "fields"
+	queryState    = "state"
+	queryNode     = "node"
+	queryLabel    = "label"
+)
+
+// resourceEnvelopeFields are always kept when a field selection is applied,
+// so the returned items remain valid API resources.
+var resourceEnvelopeFields = map[string]struct{}{
+	"id":      {},
+	"type":    {},
+	"links":   {},
+	"actions": {},
+	"name":    {},
+}
+
+// listQuery carries the pagination and projection parameters shared by the
+// collection endpoints. A nil query returns the complete collection, which
+// keeps the existing clients and the websocket streams working unchanged.
+type listQuery struct {
+	limit         int
+	continueAfter string
+	fields        []string
+}
+
+func parseListQuery(req *http.Request) (*listQuery, error) {
+	values := req.URL.Query()
+	query := &listQuery{
+		continueAfter: values.Get(queryContinue),
+	}
+	if limitStr := values.Get(queryLimit); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid %v %v, must be a non-negative integer", queryLimit, limitStr)
+		}
+		query.limit = limit
+	}
+	if fieldsStr := values.Get(queryFields); fieldsStr != "" {
+		for _, field := range strings.Split(fieldsStr, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				query.fields = append(query.fields, field)
+			}
+		}
+	}
+	return query, nil
+}
+
+// pager tracks the pagination state while walking a name-sorted collection.
+// The continue token is simply the last name of the previous page, so a
+// deleted item cannot invalidate the token.
+type pager struct {
+	query     *listQuery
+	count     int
+	last      string
+	truncated bool
+}
+
+func newPager(query *listQuery) *pager {
+	return &pager{query: query}
+}
+
+// include reports whether the item with the given name belongs to the
+// requested page. Once the page is full the remaining items only mark the
+// collection as truncated, so the caller can stop iterating.
+func (p *pager) include(name string) bool {
+	if p.query == nil {
+		return true
+	}
+	if p.query.continueAfter != "" && name <= p.query.continueAfter {
+		return false
+	}
+	if p.query.limit > 0 && p.count >= p.query.limit {
+		p.truncated = true
+		return false
+	}
+	p.count++
+	p.last = name
+	return true
+}
+
+// pagination returns the pagination block of the collection, or nil when the
+// whole collection fit in one page.
+func (p *pager) pagination() *client.Pagination {
+	if !p.truncated {
+		return nil
+	}
+	limit := int64(p.query.limit)
+	return &client.Pagination{
+		Marker:  p.last,
+		Limit:   &limit,
+		Partial: true,
+	}
+}
+
+// selectFields reduces every item of the collection to the requested fields
+// plus the resource envelope, round-tripping through JSON so the field names
+// match what the client sees on the wire.
+func selectFields(data []interface{}, query *listQuery) []interface{} {
+	if query == nil || len(query.fields) == 0 {
+		return data
+	}
+	keep := map[string]struct{}{}
+	for _, field := range query.fields {
+		keep[field] = struct{}{}
+	}
+	selected := make([]interface{}, 0, len(data))
+	for _, item := range data {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			selected = append(selected, item)
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			selected = append(selected, item)
+			continue
+		}
+		for name := range fields {
+			if _, ok := keep[name]; ok {
+				continue
+			}
+			if _, ok := resourceEnvelopeFields[name]; ok {
+				continue
+			}
+			delete(fields, name)
+		}
+		selected = append(selected, fields)
+	}
+	return selected
+}
+
+// parseVolumeFilter builds the server-side filter of the volume collection
+// from the state, node and label query parameters. It returns nil when no
+// filter is requested.
+func parseVolumeFilter(req *http.Request) func(v *longhorn.Volume) bool {
+	values := req.URL.Query()
+	state := values.Get(queryState)
+	node := values.Get(queryNode)
+	labels := map[string]string{}
+	for _, label := range values[queryLabel] {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) == 2 {
+			labels[kv[0]] = kv[1]
+		}
+	}
+	if state == "" && node == "" && len(labels) == 0 {
+		return nil
+	}
+	return func(v *longhorn.Volume) bool {
+		if state != "" && string(v.Status.State) != state {
+			return false
+		}
+		if node != "" && v.Spec.NodeID != node && v.Status.CurrentNodeID != node {
+			return false
+		}
+		for key, value := range labels {
+			if v.Labels[key] != value {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListQuery(t *testing.T) {
+	assert := require.New(t)
+
+	req := httptest.NewRequest("GET", "/v1/volumes?limit=2&continue=vol-1&fields=name,%20state,", nil)
+	query, err := parseListQuery(req)
+	assert.Nil(err)
+	assert.Equal(2, query.limit)
+	assert.Equal("vol-1", query.continueAfter)
+	assert.Equal([]string{"name", "state"}, query.fields)
+
+	req = httptest.NewRequest("GET", "/v1/volumes?limit=-1", nil)
+	_, err = parseListQuery(req)
+	assert.NotNil(err)
+
+	req = httptest.NewRequest("GET", "/v1/volumes?limit=two", nil)
+	_, err = parseListQuery(req)
+	assert.NotNil(err)
+}
+
+func TestPager(t *testing.T) {
+	assert := require.New(t)
+
+	names := []string{"vol-1", "vol-2", "vol-3", "vol-4"}
+
+	// Without a query everything is one page and no pagination block is
+	// returned.
+	p := newPager(nil)
+	for _, name := range names {
+		assert.True(p.include(name))
+	}
+	assert.Nil(p.pagination())
+
+	// A limit truncates the collection and the marker points at the last
+	// included item.
+	p = newPager(&listQuery{limit: 2})
+	included := []string{}
+	for _, name := range names {
+		if p.include(name) {
+			included = append(included, name)
+		}
+	}
+	assert.Equal([]string{"vol-1", "vol-2"}, included)
+	pagination := p.pagination()
+	assert.NotNil(pagination)
+	assert.Equal("vol-2", pagination.Marker)
+	assert.True(pagination.Partial)
+
+	// The continue token resumes after the marker, and a final page has no
+	// pagination block.
+	p = newPager(&listQuery{limit: 2, continueAfter: "vol-2"})
+	included = included[:0]
+	for _, name := range names {
+		if p.include(name) {
+			included = append(included, name)
+		}
+	}
+	assert.Equal([]string{"vol-3", "vol-4"}, included)
+	assert.Nil(p.pagination())
+}
+
+func TestSelectFields(t *testing.T) {
+	assert := require.New(t)
+
+	type item struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		State string `json:"state"`
+		Size  string `json:"size"`
+	}
+	data := []interface{}{
+		item{ID: "vol-1", Name: "vol-1", State: "attached", Size: "1Gi"},
+	}
+
+	// Without a field selection the items pass through untouched.
+	assert.Equal(data, selectFields(data, nil))
+	assert.Equal(data, selectFields(data, &listQuery{}))
+
+	selected := selectFields(data, &listQuery{fields: []string{"state"}})
+	assert.Equal(1, len(selected))
+	fields, ok := selected[0].(map[string]interface{})
+	assert.True(ok)
+	assert.Equal("attached", fields["state"])
+	// The resource envelope fields survive the projection, the rest do not.
+	assert.Equal("vol-1", fields["id"])
+	assert.Equal("vol-1", fields["name"])
+	_, ok = fields["size"]
+	assert.False(ok)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/longhorn/longhorn-manager/monitoring"
+)
+
+// metricsMiddleware records the latency and the status code of every matched
+// API request, labeled with the route template so the metric cardinality is
+// bounded by the number of routes.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: rw, code: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+
+		path := req.URL.Path
+		if route := mux.CurrentRoute(req); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				path = template
+			}
+		}
+		monitoring.ObserveAPIRequest(req.Method, path, recorder.code, time.Since(start))
+	})
+}
+
+// statusRecorder captures the status code while passing the optional
+// interfaces the websocket upgrade and the streaming handlers rely on
+// through to the underlying writer.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
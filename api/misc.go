@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,8 +11,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rancher/go-rancher/api"
 	"github.com/rancher/go-rancher/client"
+	"github.com/sirupsen/logrus"
 
 	"github.com/longhorn/longhorn-manager/manager"
+	"github.com/longhorn/longhorn-manager/upgrade"
 )
 
 func (s *Server) EventList(rw http.ResponseWriter, req *http.Request) error {
@@ -89,6 +92,19 @@ func (s *Server) DownloadSupportBundle(w http.ResponseWriter, req *http.Request)
 	return nil
 }
 
+// UpgradeReportGet serves the report of the upgrade run that happened when
+// this manager process started, so slow migrations on big clusters can be
+// identified. It responds with 204 when no upgrade has run in this process.
+func (s *Server) UpgradeReportGet(rw http.ResponseWriter, req *http.Request) error {
+	report := upgrade.GetReport()
+	if report == nil {
+		rw.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(rw).Encode(report)
+}
+
 func (s *Server) DiskTagList(rw http.ResponseWriter, req *http.Request) error {
 	apiContext := api.GetApiContext(req)
 
@@ -126,6 +142,54 @@ func (s *Server) InstanceManagerGet(rw http.ResponseWriter, req *http.Request) e
 	return nil
 }
 
+// InstanceManagerProcessLog follows the log of one engine or replica process
+// of an instance manager over plain chunked HTTP, so a single volume's data
+// path can be inspected from the UI or the CLI without kubectl access.
+func (s *Server) InstanceManagerProcessLog(rw http.ResponseWriter, req *http.Request) error {
+	imName := mux.Vars(req)["name"]
+	processName := req.URL.Query().Get("process")
+	if processName == "" {
+		return fmt.Errorf("process query parameter required")
+	}
+
+	stream, err := s.m.GetInstanceManagerProcessLog(imName, processName)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	// Closing the stream when the client goes away unblocks the pending Recv.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-req.Context().Done():
+			stream.Close()
+		case <-done:
+		}
+	}()
+
+	flusher, _ := rw.(http.Flusher)
+	rw.Header().Set("Content-Type", "text/plain")
+	for {
+		line, err := stream.Recv()
+		if err != nil {
+			// The stream errors out when either side closes it, which is
+			// also how a follow regularly ends.
+			if err != io.EOF {
+				logrus.Debugf("Log stream of process %v in instance manager %v ended: %v", processName, imName, err)
+			}
+			return nil
+		}
+		if _, err := fmt.Fprintln(rw, line); err != nil {
+			return nil
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
 func (s *Server) InstanceManagerList(rw http.ResponseWriter, req *http.Request) error {
 	apiContext := api.GetApiContext(req)
 
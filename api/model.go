@@ -22,33 +22,44 @@ import (
 type Volume struct {
 	client.Resource
 
-	Name                    string                    `json:"name"`
-	Size                    string                    `json:"size"`
-	Frontend                longhorn.VolumeFrontend   `json:"frontend"`
-	DisableFrontend         bool                      `json:"disableFrontend"`
-	FromBackup              string                    `json:"fromBackup"`
-	DataSource              longhorn.VolumeDataSource `json:"dataSource"`
-	DataLocality            longhorn.DataLocality     `json:"dataLocality"`
-	StaleReplicaTimeout     int                       `json:"staleReplicaTimeout"`
-	State                   longhorn.VolumeState      `json:"state"`
-	Robustness              longhorn.VolumeRobustness `json:"robustness"`
-	EngineImage             string                    `json:"engineImage"`
-	CurrentImage            string                    `json:"currentImage"`
-	BackingImage            string                    `json:"backingImage"`
-	Created                 string                    `json:"created"`
-	LastBackup              string                    `json:"lastBackup"`
-	LastBackupAt            string                    `json:"lastBackupAt"`
-	LastAttachedBy          string                    `json:"lastAttachedBy"`
-	Standby                 bool                      `json:"standby"`
-	RestoreRequired         bool                      `json:"restoreRequired"`
-	RevisionCounterDisabled bool                      `json:"revisionCounterDisabled"`
+	Name                    string                           `json:"name"`
+	Size                    string                           `json:"size"`
+	Frontend                longhorn.VolumeFrontend          `json:"frontend"`
+	DisableFrontend         bool                             `json:"disableFrontend"`
+	FromBackup              string                           `json:"fromBackup"`
+	DataSource              longhorn.VolumeDataSource        `json:"dataSource"`
+	DataLocality            longhorn.DataLocality            `json:"dataLocality"`
+	BackupCompressionMethod longhorn.BackupCompressionMethod `json:"backupCompressionMethod"`
+	StaleReplicaTimeout     int                              `json:"staleReplicaTimeout"`
+	State                   longhorn.VolumeState             `json:"state"`
+	Robustness              longhorn.VolumeRobustness        `json:"robustness"`
+	EngineImage             string                           `json:"engineImage"`
+	CurrentImage            string                           `json:"currentImage"`
+	BackingImage            string                           `json:"backingImage"`
+	Created                 string                           `json:"created"`
+	LastBackup              string                           `json:"lastBackup"`
+	LastBackupAt            string                           `json:"lastBackupAt"`
+	LastRestoredBackup      string                           `json:"lastRestoredBackup"`
+	LastAttachedBy          string                           `json:"lastAttachedBy"`
+	BackupTargetName        string                           `json:"backupTargetName"`
+	Standby                 bool                             `json:"standby"`
+	RestoreRequired         bool                             `json:"restoreRequired"`
+	RevisionCounterDisabled bool                             `json:"revisionCounterDisabled"`
+	StrictProvisioning      bool                             `json:"strictProvisioning"`
 
 	DiskSelector         []string                      `json:"diskSelector"`
+	DiskGroup            string                        `json:"diskGroup"`
 	NodeSelector         []string                      `json:"nodeSelector"`
 	RecurringJobSelector []longhorn.VolumeRecurringJob `json:"recurringJobSelector"`
 
-	NumberOfReplicas   int                         `json:"numberOfReplicas"`
-	ReplicaAutoBalance longhorn.ReplicaAutoBalance `json:"replicaAutoBalance"`
+	NumberOfReplicas        int                              `json:"numberOfReplicas"`
+	ReplicaAutoBalance      longhorn.ReplicaAutoBalance      `json:"replicaAutoBalance"`
+	ReplicaSoftAntiAffinity longhorn.ReplicaSoftAntiAffinity `json:"replicaSoftAntiAffinity"`
+	AutoSalvage             longhorn.AutoSalvage             `json:"autoSalvage"`
+
+	SnapshotMaxCount int    `json:"snapshotMaxCount"`
+	SnapshotMaxSize  string `json:"snapshotMaxSize"`
+	SnapshotsSize    string `json:"snapshotsSize"`
 
 	Conditions       map[string]longhorn.Condition `json:"conditions"`
 	KubernetesStatus longhorn.KubernetesStatus     `json:"kubernetesStatus"`
@@ -61,8 +72,13 @@ type Volume struct {
 
 	Migratable bool `json:"migratable"`
 
+	Maintenance          bool   `json:"maintenance"`
+	MaintenanceExpiresAt string `json:"maintenanceExpiresAt"`
+
 	Encrypted bool `json:"encrypted"`
 
+	LastKeyRotatedAt string `json:"lastKeyRotatedAt"`
+
 	Replicas      []Replica       `json:"replicas"`
 	Controllers   []Controller    `json:"controllers"`
 	BackupStatus  []BackupStatus  `json:"backupStatus"`
@@ -73,11 +89,12 @@ type Volume struct {
 
 type Snapshot struct {
 	client.Resource
-	longhorn.Snapshot
+	longhorn.SnapshotInfo
 }
 
 type BackupTarget struct {
 	client.Resource
+	Name string `json:"name"`
 	engineapi.BackupTarget
 }
 
@@ -140,6 +157,7 @@ type Controller struct {
 	Endpoint               string `json:"endpoint"`
 	LastRestoredBackup     string `json:"lastRestoredBackup"`
 	RequestedBackupRestore string `json:"requestedBackupRestore"`
+	IsIOFrozen             bool   `json:"isIOFrozen"`
 	IsExpanding            bool   `json:"isExpanding"`
 	LastExpansionError     string `json:"lastExpansionError"`
 	LastExpansionFailedAt  string `json:"lastExpansionFailedAt"`
@@ -198,6 +216,14 @@ type SnapshotInput struct {
 	Labels map[string]string `json:"labels"`
 }
 
+type CloneInput struct {
+	// Name of the new volume seeded from the source volume.
+	Name string `json:"name"`
+	// SnapshotName is the snapshot of the source volume to clone from. When
+	// empty, a fresh snapshot of the source volume is taken.
+	SnapshotName string `json:"snapshotName"`
+}
+
 type BackupInput struct {
 	Name string `json:"name"`
 }
@@ -218,6 +244,14 @@ type UpdateReplicaCountInput struct {
 	ReplicaCount int `json:"replicaCount"`
 }
 
+type UpdateSnapshotMaxCountInput struct {
+	SnapshotMaxCount int `json:"snapshotMaxCount"`
+}
+
+type UpdateSnapshotMaxSizeInput struct {
+	SnapshotMaxSize string `json:"snapshotMaxSize"`
+}
+
 type UpdateReplicaAutoBalanceInput struct {
 	ReplicaAutoBalance string `json:"replicaAutoBalance"`
 }
@@ -230,6 +264,10 @@ type UpdateAccessModeInput struct {
 	AccessMode string `json:"accessMode"`
 }
 
+type UpdateLastKeyRotatedAtInput struct {
+	LastKeyRotatedAt string `json:"lastKeyRotatedAt"`
+}
+
 type PVCreateInput struct {
 	PVName string `json:"pvName"`
 	FSType string `json:"fsType"`
@@ -251,6 +289,12 @@ type ExpandInput struct {
 	Size string `json:"size"`
 }
 
+type IOFreezeInput struct {
+	// Timeout in seconds after which the engine controller automatically
+	// unfreezes the volume IO again. 0 means the default timeout.
+	Timeout int64 `json:"timeout"`
+}
+
 type Node struct {
 	client.Resource
 	Name                     string                        `json:"name"`
@@ -267,12 +311,13 @@ type Node struct {
 }
 
 type DiskStatus struct {
-	Conditions       map[string]longhorn.Condition `json:"conditions"`
-	StorageAvailable int64                         `json:"storageAvailable"`
-	StorageScheduled int64                         `json:"storageScheduled"`
-	StorageMaximum   int64                         `json:"storageMaximum"`
-	ScheduledReplica map[string]int64              `json:"scheduledReplica"`
-	DiskUUID         string                        `json:"diskUUID"`
+	Conditions                    map[string]longhorn.Condition `json:"conditions"`
+	StorageAvailable              int64                         `json:"storageAvailable"`
+	StorageScheduled              int64                         `json:"storageScheduled"`
+	StorageMaximum                int64                         `json:"storageMaximum"`
+	ScheduledReplica              map[string]int64              `json:"scheduledReplica"`
+	EvictionRequestedReplicaCount int64                         `json:"evictionRequestedReplicaCount"`
+	DiskUUID                      string                        `json:"diskUUID"`
 }
 
 type DiskInfo struct {
@@ -365,9 +410,28 @@ type InstanceManager struct {
 	Instances    map[string]longhorn.InstanceProcess `json:"instances"`
 }
 
+type Operation struct {
+	client.Resource
+	OperationType string `json:"operationType"`
+	Volume        string `json:"volume"`
+	Snapshot      string `json:"snapshot,omitempty"`
+	Created       string `json:"created"`
+	Finished      string `json:"finished,omitempty"`
+	State         string `json:"state"`
+	Progress      int    `json:"progress"`
+	Error         string `json:"error,omitempty"`
+}
+
 type RecurringJob struct {
 	client.Resource
 	longhorn.RecurringJobSpec
+
+	NextRunAt       string `json:"nextRunAt"`
+	LastScheduledAt string `json:"lastScheduledAt"`
+	LastRunAt       string `json:"lastRunAt"`
+	LastRunState    string `json:"lastRunState"`
+	LastRunError    string `json:"lastRunError,omitempty"`
+	SkipReason      string `json:"skipReason,omitempty"`
 }
 
 type VolumeRecurringJob struct {
@@ -379,6 +443,92 @@ type VolumeRecurringJobInput struct {
 	longhorn.VolumeRecurringJob
 }
 
+type VolumeStatsSample struct {
+	client.Resource
+	manager.VolumeStatsSample
+}
+
+type VolumeDiagnostics struct {
+	client.Resource
+	manager.VolumeDiagnostics
+}
+
+type SettingValidation struct {
+	client.Resource
+
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+type SettingChangePreview struct {
+	client.Resource
+
+	Changed               bool     `json:"changed"`
+	RedeployedDeployments []string `json:"redeployedDeployments"`
+	RedeployedDaemonSets  []string `json:"redeployedDaemonSets"`
+	RestartedPods         []string `json:"restartedPods"`
+	AffectedVolumes       []string `json:"affectedVolumes"`
+}
+
+type NodeEvacuationInput struct {
+	Execute bool `json:"execute"`
+}
+
+type NodeEvacuationPlan struct {
+	client.Resource
+	manager.EvacuationPlan
+}
+
+type ApplyInput struct {
+	client.Resource
+
+	DryRun        bool              `json:"dryRun"`
+	Settings      map[string]string `json:"settings"`
+	Volumes       []Volume          `json:"volumes"`
+	RecurringJobs []RecurringJob    `json:"recurringJobs"`
+}
+
+type ApplyChange struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type ApplyOutput struct {
+	client.Resource
+
+	DryRun  bool          `json:"dryRun"`
+	Changes []ApplyChange `json:"changes"`
+}
+
+type BulkVolumeInput struct {
+	client.Resource
+
+	Operation     string            `json:"operation"`
+	Volumes       []string          `json:"volumes"`
+	LabelSelector map[string]string `json:"labelSelector"`
+	Concurrency   int               `json:"concurrency"`
+
+	HostID          string            `json:"hostId"`
+	AttachedBy      string            `json:"attachedBy"`
+	DisableFrontend bool              `json:"disableFrontend"`
+	ReplicaCount    int               `json:"replicaCount"`
+	SnapshotName    string            `json:"snapshotName"`
+	Labels          map[string]string `json:"labels"`
+}
+
+type BulkVolumeResult struct {
+	Volume string `json:"volume"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkVolumeOutput struct {
+	client.Resource
+
+	Results []BulkVolumeResult `json:"results"`
+}
+
 type BackupListOutput struct {
 	Data []Backup `json:"data"`
 	Type string   `json:"type"`
@@ -398,7 +548,8 @@ func NewSchema() *client.Schemas {
 	schemas.AddType("attachInput", AttachInput{})
 	schemas.AddType("detachInput", DetachInput{})
 	schemas.AddType("snapshotInput", SnapshotInput{})
-	schemas.AddType("backupTarget", BackupTarget{})
+	schemas.AddType("cloneInput", CloneInput{})
+	backupTargetSchema(schemas.AddType("backupTarget", BackupTarget{}))
 	schemas.AddType("backup", Backup{})
 	schemas.AddType("backupInput", BackupInput{})
 	schemas.AddType("backupStatus", BackupStatus{})
@@ -409,24 +560,38 @@ func NewSchema() *client.Schemas {
 	schemas.AddType("salvageInput", SalvageInput{})
 	schemas.AddType("activateInput", ActivateInput{})
 	schemas.AddType("expandInput", ExpandInput{})
+	schemas.AddType("ioFreezeInput", IOFreezeInput{})
 	schemas.AddType("engineUpgradeInput", EngineUpgradeInput{})
 	schemas.AddType("replica", Replica{})
 	schemas.AddType("controller", Controller{})
 	schemas.AddType("diskUpdate", longhorn.DiskSpec{})
 	schemas.AddType("UpdateReplicaCountInput", UpdateReplicaCountInput{})
+	schemas.AddType("UpdateSnapshotMaxCountInput", UpdateSnapshotMaxCountInput{})
+	schemas.AddType("UpdateSnapshotMaxSizeInput", UpdateSnapshotMaxSizeInput{})
 	schemas.AddType("UpdateReplicaAutoBalanceInput", UpdateReplicaAutoBalanceInput{})
 	schemas.AddType("UpdateDataLocalityInput", UpdateDataLocalityInput{})
 	schemas.AddType("UpdateAccessModeInput", UpdateAccessModeInput{})
+	schemas.AddType("UpdateLastKeyRotatedAtInput", UpdateLastKeyRotatedAtInput{})
 	schemas.AddType("workloadStatus", longhorn.WorkloadStatus{})
 	schemas.AddType("cloneStatus", longhorn.VolumeCloneStatus{})
 
 	schemas.AddType("volumeRecurringJob", VolumeRecurringJob{})
 	schemas.AddType("volumeRecurringJobInput", VolumeRecurringJobInput{})
+	schemas.AddType("volumeStatsSample", VolumeStatsSample{})
+	schemas.AddType("volumeDiagnostics", VolumeDiagnostics{})
+	schemas.AddType("applyInput", ApplyInput{})
+	schemas.AddType("applyChange", ApplyChange{})
+	schemas.AddType("applyOutput", ApplyOutput{})
+	schemas.AddType("bulkVolumeInput", BulkVolumeInput{})
+	schemas.AddType("bulkVolumeResult", BulkVolumeResult{})
+	schemas.AddType("bulkVolumeOutput", BulkVolumeOutput{})
 
 	schemas.AddType("PVCreateInput", PVCreateInput{})
 	schemas.AddType("PVCCreateInput", PVCCreateInput{})
 
 	schemas.AddType("settingDefinition", types.SettingDefinition{})
+	schemas.AddType("settingValidation", SettingValidation{})
+	schemas.AddType("settingChangePreview", SettingChangePreview{})
 	// to avoid duplicate name with built-in type condition
 	schemas.AddType("volumeCondition", longhorn.Condition{})
 	schemas.AddType("nodeCondition", longhorn.Condition{})
@@ -441,6 +606,8 @@ func NewSchema() *client.Schemas {
 	schemas.AddType("instanceManager", InstanceManager{})
 	schemas.AddType("instanceProcess", longhorn.InstanceProcess{})
 
+	schemas.AddType("operation", Operation{})
+
 	schemas.AddType("backingImageDiskFileStatus", longhorn.BackingImageDiskFileStatus{})
 	schemas.AddType("backingImageCleanupInput", BackingImageCleanupInput{})
 
@@ -453,6 +620,8 @@ func NewSchema() *client.Schemas {
 	backingImageSchema(schemas.AddType("backingImage", BackingImage{}))
 	nodeSchema(schemas.AddType("node", Node{}))
 	diskSchema(schemas.AddType("diskUpdateInput", DiskUpdateInput{}))
+	schemas.AddType("nodeEvacuationInput", NodeEvacuationInput{})
+	schemas.AddType("nodeEvacuationPlan", NodeEvacuationPlan{})
 	diskInfoSchema(schemas.AddType("diskInfo", DiskInfo{}))
 	kubernetesStatusSchema(schemas.AddType("kubernetesStatus", longhorn.KubernetesStatus{}))
 	backupListOutputSchema(schemas.AddType("backupListOutput", BackupListOutput{}))
@@ -470,6 +639,10 @@ func nodeSchema(node *client.Schema) {
 			Input:  "diskUpdateInput",
 			Output: "node",
 		},
+		"evacuationPlan": {
+			Input:  "nodeEvacuationInput",
+			Output: "nodeEvacuationPlan",
+		},
 	}
 
 	allowScheduling := node.ResourceFields["allowScheduling"]
@@ -570,6 +743,33 @@ func backingImageSchema(backingImage *client.Schema) {
 	backingImage.ResourceFields["diskFileStatusMap"] = diskFileStatusMap
 }
 
+func backupTargetSchema(backupTarget *client.Schema) {
+	backupTarget.CollectionMethods = []string{"GET", "POST"}
+	backupTarget.ResourceMethods = []string{"GET", "PUT", "DELETE"}
+
+	name := backupTarget.ResourceFields["name"]
+	name.Required = true
+	name.Unique = true
+	name.Create = true
+	backupTarget.ResourceFields["name"] = name
+
+	backupTargetURL := backupTarget.ResourceFields["backupTargetURL"]
+	backupTargetURL.Required = true
+	backupTargetURL.Create = true
+	backupTargetURL.Update = true
+	backupTarget.ResourceFields["backupTargetURL"] = backupTargetURL
+
+	credentialSecret := backupTarget.ResourceFields["credentialSecret"]
+	credentialSecret.Create = true
+	credentialSecret.Update = true
+	backupTarget.ResourceFields["credentialSecret"] = credentialSecret
+
+	pollInterval := backupTarget.ResourceFields["pollInterval"]
+	pollInterval.Create = true
+	pollInterval.Update = true
+	backupTarget.ResourceFields["pollInterval"] = pollInterval
+}
+
 func recurringJobSchema(job *client.Schema) {
 	job.CollectionMethods = []string{"GET", "POST"}
 	job.ResourceMethods = []string{"GET", "PUT", "DELETE"}
@@ -630,6 +830,9 @@ func backupVolumeSchema(backupVolume *client.Schema) {
 			Input:  "backupInput",
 			Output: "backupVolume",
 		},
+		"backupVolumeSync": {
+			Output: "backupVolume",
+		},
 	}
 }
 
@@ -669,6 +872,10 @@ func volumeSchema(volume *client.Schema) {
 			Input:  "salvageInput",
 			Output: "volume",
 		},
+		"clone": {
+			Input:  "cloneInput",
+			Output: "volume",
+		},
 		"activate": {
 			Input:  "activateInput",
 			Output: "volume",
@@ -680,6 +887,16 @@ func volumeSchema(volume *client.Schema) {
 		"cancelExpansion": {
 			Output: "volume",
 		},
+		"ioFreeze": {
+			Input:  "ioFreezeInput",
+			Output: "volume",
+		},
+		"ioUnfreeze": {
+			Output: "volume",
+		},
+		"trimFilesystem": {
+			Output: "volume",
+		},
 
 		"snapshotPurge": {
 			Output: "volume",
@@ -726,6 +943,14 @@ func volumeSchema(volume *client.Schema) {
 			Input: "UpdateReplicaCountInput",
 		},
 
+		"updateSnapshotMaxCount": {
+			Input: "UpdateSnapshotMaxCountInput",
+		},
+
+		"updateSnapshotMaxSize": {
+			Input: "UpdateSnapshotMaxSizeInput",
+		},
+
 		"updateReplicaAutoBalance": {
 			Input: "ReplicaAutoBalance",
 		},
@@ -738,6 +963,10 @@ func volumeSchema(volume *client.Schema) {
 			Input: "UpdateAccessModeInput",
 		},
 
+		"updateLastKeyRotatedAt": {
+			Input: "UpdateLastKeyRotatedAtInput",
+		},
+
 		"pvCreate": {
 			Input:  "PVCreateInput",
 			Output: "volume",
@@ -916,6 +1145,18 @@ func toSettingCollection(settings []*longhorn.Setting) *client.GenericCollection
 	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "setting"}}
 }
 
+func toSettingChangePreviewResource(preview *manager.SettingChangePreview) *SettingChangePreview {
+	return &SettingChangePreview{
+		Resource: client.Resource{Type: "settingChangePreview"},
+
+		Changed:               preview.Changed,
+		RedeployedDeployments: preview.RedeployedDeployments,
+		RedeployedDaemonSets:  preview.RedeployedDaemonSets,
+		RestartedPods:         preview.RestartedPods,
+		AffectedVolumes:       preview.AffectedVolumes,
+	}
+}
+
 func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhorn.Replica, backups []*longhorn.Backup, apiContext *api.ApiContext) *Volume {
 	var ve *longhorn.Engine
 	controllers := []Controller{}
@@ -949,6 +1190,7 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 			Endpoint:               e.Status.Endpoint,
 			LastRestoredBackup:     e.Status.LastRestoredBackup,
 			RequestedBackupRestore: e.Spec.RequestedBackupRestore,
+			IsIOFrozen:             e.Status.IsIOFrozen,
 			IsExpanding:            e.Status.IsExpanding,
 			LastExpansionError:     e.Status.LastExpansionError,
 			LastExpansionFailedAt:  e.Status.LastExpansionFailedAt,
@@ -1067,31 +1309,40 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 			Actions: map[string]string{},
 			Links:   map[string]string{},
 		},
-		Name:                v.Name,
-		Size:                strconv.FormatInt(v.Spec.Size, 10),
-		Frontend:            v.Spec.Frontend,
-		DisableFrontend:     v.Spec.DisableFrontend,
-		LastAttachedBy:      v.Spec.LastAttachedBy,
-		FromBackup:          v.Spec.FromBackup,
-		DataSource:          v.Spec.DataSource,
-		NumberOfReplicas:    v.Spec.NumberOfReplicas,
-		ReplicaAutoBalance:  v.Spec.ReplicaAutoBalance,
-		DataLocality:        v.Spec.DataLocality,
-		StaleReplicaTimeout: v.Spec.StaleReplicaTimeout,
-		Created:             v.CreationTimestamp.String(),
-		EngineImage:         v.Spec.EngineImage,
-		BackingImage:        v.Spec.BackingImage,
-		Standby:             v.Spec.Standby,
-		DiskSelector:        v.Spec.DiskSelector,
-		NodeSelector:        v.Spec.NodeSelector,
+		Name:                    v.Name,
+		Size:                    strconv.FormatInt(v.Spec.Size, 10),
+		Frontend:                v.Spec.Frontend,
+		DisableFrontend:         v.Spec.DisableFrontend,
+		LastAttachedBy:          v.Spec.LastAttachedBy,
+		FromBackup:              v.Spec.FromBackup,
+		BackupTargetName:        v.Spec.BackupTargetName,
+		DataSource:              v.Spec.DataSource,
+		NumberOfReplicas:        v.Spec.NumberOfReplicas,
+		ReplicaAutoBalance:      v.Spec.ReplicaAutoBalance,
+		ReplicaSoftAntiAffinity: v.Spec.ReplicaSoftAntiAffinity,
+		AutoSalvage:             v.Spec.AutoSalvage,
+		SnapshotMaxCount:        v.Spec.SnapshotMaxCount,
+		SnapshotMaxSize:         strconv.FormatInt(v.Spec.SnapshotMaxSize, 10),
+		DataLocality:            v.Spec.DataLocality,
+		BackupCompressionMethod: v.Spec.BackupCompressionMethod,
+		StaleReplicaTimeout:     v.Spec.StaleReplicaTimeout,
+		Created:                 v.CreationTimestamp.String(),
+		EngineImage:             v.Spec.EngineImage,
+		BackingImage:            v.Spec.BackingImage,
+		Standby:                 v.Spec.Standby,
+		DiskSelector:            v.Spec.DiskSelector,
+		DiskGroup:               v.Spec.DiskGroup,
+		NodeSelector:            v.Spec.NodeSelector,
 
 		State:                   v.Status.State,
 		Robustness:              v.Status.Robustness,
 		CurrentImage:            v.Status.CurrentImage,
 		LastBackup:              v.Status.LastBackup,
 		LastBackupAt:            v.Status.LastBackupAt,
+		LastRestoredBackup:      v.Status.LastRestoredBackup,
 		RestoreRequired:         v.Status.RestoreRequired,
 		RevisionCounterDisabled: v.Spec.RevisionCounterDisabled,
+		SnapshotsSize:           strconv.FormatInt(v.Status.SnapshotsSize, 10),
 		Ready:                   ready,
 
 		AccessMode:    v.Spec.AccessMode,
@@ -1100,8 +1351,13 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 
 		Migratable: v.Spec.Migratable,
 
+		Maintenance:          v.Spec.Maintenance,
+		MaintenanceExpiresAt: v.Spec.MaintenanceExpiresAt,
+
 		Encrypted: v.Spec.Encrypted,
 
+		LastKeyRotatedAt: v.Spec.LastKeyRotatedAt,
+
 		Conditions:       sliceToMap(v.Status.Conditions),
 		KubernetesStatus: v.Status.KubernetesStatus,
 		CloneStatus:      v.Status.CloneStatus,
@@ -1136,6 +1392,8 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 			actions["updateDataLocality"] = struct{}{}
 			actions["updateAccessMode"] = struct{}{}
 			actions["updateReplicaAutoBalance"] = struct{}{}
+			actions["updateSnapshotMaxCount"] = struct{}{}
+			actions["updateSnapshotMaxSize"] = struct{}{}
 			actions["recurringJobAdd"] = struct{}{}
 			actions["recurringJobDelete"] = struct{}{}
 			actions["recurringJobList"] = struct{}{}
@@ -1146,6 +1404,8 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 			actions["recurringJobList"] = struct{}{}
 		case longhorn.VolumeStateAttached:
 			actions["activate"] = struct{}{}
+			actions["trimFilesystem"] = struct{}{}
+			actions["updateLastKeyRotatedAt"] = struct{}{}
 			actions["snapshotPurge"] = struct{}{}
 			actions["snapshotCreate"] = struct{}{}
 			actions["snapshotList"] = struct{}{}
@@ -1158,6 +1418,8 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 			actions["updateReplicaCount"] = struct{}{}
 			actions["updateDataLocality"] = struct{}{}
 			actions["updateReplicaAutoBalance"] = struct{}{}
+			actions["updateSnapshotMaxCount"] = struct{}{}
+			actions["updateSnapshotMaxSize"] = struct{}{}
 			actions["pvCreate"] = struct{}{}
 			actions["pvcCreate"] = struct{}{}
 			actions["cancelExpansion"] = struct{}{}
@@ -1174,7 +1436,7 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 	return r
 }
 
-func toSnapshotResource(s *longhorn.Snapshot) *Snapshot {
+func toSnapshotResource(s *longhorn.SnapshotInfo) *Snapshot {
 	if s == nil {
 		logrus.Warn("weird: nil snapshot")
 		return nil
@@ -1184,11 +1446,11 @@ func toSnapshotResource(s *longhorn.Snapshot) *Snapshot {
 			Id:   s.Name,
 			Type: "snapshot",
 		},
-		Snapshot: *s,
+		SnapshotInfo: *s,
 	}
 }
 
-func toSnapshotCollection(ss map[string]*longhorn.Snapshot) *client.GenericCollection {
+func toSnapshotCollection(ss map[string]*longhorn.SnapshotInfo) *client.GenericCollection {
 	data := []interface{}{}
 	for _, v := range ss {
 		data = append(data, toSnapshotResource(v))
@@ -1196,6 +1458,34 @@ func toSnapshotCollection(ss map[string]*longhorn.Snapshot) *client.GenericColle
 	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "snapshot"}}
 }
 
+func toVolumeStatsSampleResource(obj manager.VolumeStatsSample) *VolumeStatsSample {
+	return &VolumeStatsSample{
+		Resource: client.Resource{
+			Id:   obj.Timestamp,
+			Type: "volumeStatsSample",
+		},
+		VolumeStatsSample: obj,
+	}
+}
+
+func toVolumeStatsSampleCollection(samples []manager.VolumeStatsSample) *client.GenericCollection {
+	data := []interface{}{}
+	for _, sample := range samples {
+		data = append(data, toVolumeStatsSampleResource(sample))
+	}
+	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "volumeStatsSample"}}
+}
+
+func toVolumeDiagnosticsResource(obj *manager.VolumeDiagnostics) *VolumeDiagnostics {
+	return &VolumeDiagnostics{
+		Resource: client.Resource{
+			Id:   obj.VolumeName,
+			Type: "volumeDiagnostics",
+		},
+		VolumeDiagnostics: *obj,
+	}
+}
+
 func toVolumeRecurringJobResource(obj *longhorn.VolumeRecurringJob) *VolumeRecurringJob {
 	if obj == nil {
 		logrus.Warn("weird: nil volumeRecurringJob")
@@ -1230,6 +1520,7 @@ func toBackupTargetResource(bt *longhorn.BackupTarget) *BackupTarget {
 			Type:  "backupTarget",
 			Links: map[string]string{},
 		},
+		Name: bt.Name,
 		BackupTarget: engineapi.BackupTarget{
 			BackupTargetURL:  bt.Spec.BackupTargetURL,
 			CredentialSecret: bt.Spec.CredentialSecret,
@@ -1460,12 +1751,13 @@ func toNodeResource(node *longhorn.Node, address string, apiContext *api.ApiCont
 		}
 		if node.Status.DiskStatus != nil && node.Status.DiskStatus[name] != nil {
 			di.DiskStatus = DiskStatus{
-				Conditions:       sliceToMap(node.Status.DiskStatus[name].Conditions),
-				StorageAvailable: node.Status.DiskStatus[name].StorageAvailable,
-				StorageScheduled: node.Status.DiskStatus[name].StorageScheduled,
-				StorageMaximum:   node.Status.DiskStatus[name].StorageMaximum,
-				ScheduledReplica: node.Status.DiskStatus[name].ScheduledReplica,
-				DiskUUID:         node.Status.DiskStatus[name].DiskUUID,
+				Conditions:                    sliceToMap(node.Status.DiskStatus[name].Conditions),
+				StorageAvailable:              node.Status.DiskStatus[name].StorageAvailable,
+				StorageScheduled:              node.Status.DiskStatus[name].StorageScheduled,
+				StorageMaximum:                node.Status.DiskStatus[name].StorageMaximum,
+				ScheduledReplica:              node.Status.DiskStatus[name].ScheduledReplica,
+				EvictionRequestedReplicaCount: node.Status.DiskStatus[name].EvictionRequestedReplicaCount,
+				DiskUUID:                      node.Status.DiskStatus[name].DiskUUID,
 			}
 		}
 		disks[name] = di
@@ -1508,7 +1800,7 @@ func toEventCollection(eventList *v1.EventList) *client.GenericCollection {
 	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "event"}}
 }
 
-//Support Bundle Resource
+// Support Bundle Resource
 func toSupportBundleResource(nodeID string, sb *manager.SupportBundle) *SupportBundle {
 	return &SupportBundle{
 		Resource: client.Resource{
@@ -1568,8 +1860,33 @@ func toInstanceManagerCollection(instanceManagers map[string]*longhorn.InstanceM
 	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "instanceManager"}}
 }
 
-func toRecurringJobResource(recurringJob *longhorn.RecurringJob, apiContext *api.ApiContext) *RecurringJob {
-	return &RecurringJob{
+func toOperationResource(op *manager.Operation) *Operation {
+	return &Operation{
+		Resource: client.Resource{
+			Id:   op.ID,
+			Type: "operation",
+		},
+		OperationType: op.Type,
+		Volume:        op.Volume,
+		Snapshot:      op.Snapshot,
+		Created:       op.Created,
+		Finished:      op.Finished,
+		State:         string(op.State),
+		Progress:      op.Progress,
+		Error:         op.Error,
+	}
+}
+
+func toOperationCollection(ops []*manager.Operation) *client.GenericCollection {
+	data := []interface{}{}
+	for _, op := range ops {
+		data = append(data, toOperationResource(op))
+	}
+	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "operation"}}
+}
+
+func toRecurringJobResource(recurringJob *longhorn.RecurringJob, scheduleInfo *manager.RecurringJobScheduleInfo, apiContext *api.ApiContext) *RecurringJob {
+	job := &RecurringJob{
 		Resource: client.Resource{
 			Id:   recurringJob.Name,
 			Type: "recurringJob",
@@ -1584,12 +1901,21 @@ func toRecurringJobResource(recurringJob *longhorn.RecurringJob, apiContext *api
 			Labels:      recurringJob.Spec.Labels,
 		},
 	}
+	if scheduleInfo != nil {
+		job.NextRunAt = scheduleInfo.NextRunAt
+		job.LastScheduledAt = scheduleInfo.LastScheduledAt
+		job.LastRunAt = scheduleInfo.LastRunAt
+		job.LastRunState = scheduleInfo.LastRunState
+		job.LastRunError = scheduleInfo.LastRunError
+		job.SkipReason = scheduleInfo.SkipReason
+	}
+	return job
 }
 
-func toRecurringJobCollection(jobs []*longhorn.RecurringJob, apiContext *api.ApiContext) *client.GenericCollection {
+func toRecurringJobCollection(jobs []*longhorn.RecurringJob, scheduleInfos map[string]*manager.RecurringJobScheduleInfo, apiContext *api.ApiContext) *client.GenericCollection {
 	data := []interface{}{}
 	for _, job := range jobs {
-		data = append(data, toRecurringJobResource(job, apiContext))
+		data = append(data, toRecurringJobResource(job, scheduleInfos[job.Name], apiContext))
 	}
 	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "recurringJob"}}
 }
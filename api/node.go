@@ -143,3 +143,25 @@ func (s *Server) NodeDelete(rw http.ResponseWriter, req *http.Request) error {
 
 	return nil
 }
+
+func (s *Server) NodeEvacuationPlan(rw http.ResponseWriter, req *http.Request) error {
+	var input NodeEvacuationInput
+
+	apiContext := api.GetApiContext(req)
+	if err := apiContext.Read(&input); err != nil {
+		return err
+	}
+
+	id := mux.Vars(req)["name"]
+
+	plan, err := s.m.GetNodeEvacuationPlan(id, input.Execute)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the evacuation plan for node '%s'", id)
+	}
+
+	apiContext.Write(&NodeEvacuationPlan{
+		Resource:       client.Resource{Type: "nodeEvacuationPlan"},
+		EvacuationPlan: *plan,
+	})
+	return nil
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/rancher/go-rancher/client"
+)
+
+//go:generate go run ./openapigen -o ../docs/openapi.json
+
+// routedResources maps the schema name of a resource onto its path segment
+// under /v1 for the resources that actually have list and get routes in the
+// router. Input and status types stay out of the paths section but are still
+// published under components so clients can be generated for them.
+var routedResources = map[string]string{
+	"volume":          "volumes",
+	"node":            "nodes",
+	"setting":         "settings",
+	"engineImage":     "engineimages",
+	"backingImage":    "backingimages",
+	"backupTarget":    "backuptargets",
+	"backupVolume":    "backupvolumes",
+	"recurringJob":    "recurringjobs",
+	"instanceManager": "instancemanagers",
+	"event":           "events",
+	"operation":       "operations",
+}
+
+// BuildOpenAPISpec converts the schema registry the /v1 routes are built from
+// into an OpenAPI v3 document, so the document cannot drift from the Go types.
+func BuildOpenAPISpec(schemas *client.Schemas) map[string]interface{} {
+	knownTypes := map[string]bool{}
+	for _, schema := range schemas.Data {
+		knownTypes[schema.Id] = true
+	}
+
+	components := map[string]interface{}{}
+	paths := map[string]interface{}{}
+	for _, schema := range schemas.Data {
+		if schema.Id == "schema" || schema.Id == "apiVersion" {
+			continue
+		}
+		components[schema.Id] = schemaToOpenAPI(&schema, knownTypes)
+
+		segment, routed := routedResources[schema.Id]
+		if !routed {
+			continue
+		}
+		collection, resource := schemaToPathItems(&schema)
+		paths["/v1/"+segment] = collection
+		paths["/v1/"+segment+"/{name}"] = resource
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Longhorn API",
+			"description": "RESTful API of the Longhorn manager, generated from the same schema registry that serves /v1/schemas.",
+			"version":     "v1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": components,
+		},
+	}
+}
+
+func schemaToOpenAPI(schema *client.Schema, knownTypes map[string]bool) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for name, field := range schema.ResourceFields {
+		properties[name] = fieldToOpenAPI(field, knownTypes)
+		if field.Required {
+			required = append(required, name)
+		}
+	}
+	result := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		result["required"] = required
+	}
+	return result
+}
+
+func fieldToOpenAPI(field client.Field, knownTypes map[string]bool) map[string]interface{} {
+	return fieldTypeToOpenAPI(field.Type, field.Options, knownTypes)
+}
+
+func fieldTypeToOpenAPI(fieldType string, options []string, knownTypes map[string]bool) map[string]interface{} {
+	switch {
+	case fieldType == "string" || fieldType == "password" || fieldType == "date" ||
+		fieldType == "blob" || strings.HasPrefix(fieldType, "reference["):
+		return map[string]interface{}{"type": "string"}
+	case fieldType == "enum":
+		result := map[string]interface{}{"type": "string"}
+		if len(options) > 0 {
+			result["enum"] = options
+		}
+		return result
+	case fieldType == "int":
+		return map[string]interface{}{"type": "integer"}
+	case fieldType == "float":
+		return map[string]interface{}{"type": "number"}
+	case fieldType == "boolean":
+		return map[string]interface{}{"type": "boolean"}
+	case strings.HasPrefix(fieldType, "array["):
+		inner := strings.TrimSuffix(strings.TrimPrefix(fieldType, "array["), "]")
+		return map[string]interface{}{
+			"type":  "array",
+			"items": fieldTypeToOpenAPI(inner, nil, knownTypes),
+		}
+	case strings.HasPrefix(fieldType, "map["):
+		inner := strings.TrimSuffix(strings.TrimPrefix(fieldType, "map["), "]")
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": fieldTypeToOpenAPI(inner, nil, knownTypes),
+		}
+	case knownTypes[fieldType]:
+		return map[string]interface{}{"$ref": "#/components/schemas/" + fieldType}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// schemaToPathItems derives the collection and resource path items of a
+// routed resource from the methods declared on its schema.
+func schemaToPathItems(schema *client.Schema) (map[string]interface{}, map[string]interface{}) {
+	ref := map[string]interface{}{"$ref": "#/components/schemas/" + schema.Id}
+
+	collection := map[string]interface{}{}
+	for _, method := range schema.CollectionMethods {
+		switch method {
+		case "GET":
+			collection["get"] = map[string]interface{}{
+				"summary": "List " + schema.PluralName,
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The "+schema.Id+" collection", map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"data": map[string]interface{}{"type": "array", "items": ref},
+						},
+					}),
+				},
+			}
+		case "POST":
+			collection["post"] = map[string]interface{}{
+				"summary":     "Create a " + schema.Id,
+				"requestBody": jsonBody(ref),
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The created "+schema.Id, ref),
+				},
+			}
+		}
+	}
+
+	nameParameter := []interface{}{
+		map[string]interface{}{
+			"name":     "name",
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		},
+	}
+	resource := map[string]interface{}{"parameters": nameParameter}
+	for _, method := range schema.ResourceMethods {
+		switch method {
+		case "GET":
+			resource["get"] = map[string]interface{}{
+				"summary": "Get a " + schema.Id,
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The requested "+schema.Id, ref),
+				},
+			}
+		case "PUT":
+			resource["put"] = map[string]interface{}{
+				"summary":     "Update a " + schema.Id,
+				"requestBody": jsonBody(ref),
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The updated "+schema.Id, ref),
+				},
+			}
+		case "DELETE":
+			resource["delete"] = map[string]interface{}{
+				"summary": "Delete a " + schema.Id,
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "The " + schema.Id + " was deleted"},
+				},
+			}
+		}
+	}
+	return collection, resource
+}
+
+func jsonResponse(description string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+func jsonBody(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// OpenAPIHandler serves the OpenAPI document built from the given schemas.
+// The document only depends on the compiled-in types, so it is marshaled once
+// at router construction time.
+func OpenAPIHandler(schemas *client.Schemas) func(rw http.ResponseWriter, req *http.Request) error {
+	doc, err := json.Marshal(BuildOpenAPISpec(schemas))
+	return func(rw http.ResponseWriter, req *http.Request) error {
+		if err != nil {
+			return err
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_, err := rw.Write(doc)
+		return err
+	}
+}
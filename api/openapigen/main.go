@@ -0,0 +1,35 @@
+// Command openapigen writes the OpenAPI document of the Longhorn REST API to
+// a file, so a copy can be committed for offline client generation. It is run
+// through go:generate from the api package; the served document at
+// /v1/openapi.json is always built from the compiled-in schemas directly.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/longhorn/longhorn-manager/api"
+)
+
+func main() {
+	output := flag.String("o", "", "file to write the OpenAPI document to, defaults to stdout")
+	flag.Parse()
+
+	doc, err := json.MarshalIndent(api.BuildOpenAPISpec(api.NewSchema()), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot marshal the OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+	doc = append(doc, '\n')
+
+	if *output == "" {
+		fmt.Print(string(doc))
+		return
+	}
+	if err := os.WriteFile(*output, doc, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot write %v: %v\n", *output, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/go-rancher/api"
+	"github.com/rancher/go-rancher/client"
+)
+
+func (s *Server) OperationList(rw http.ResponseWriter, req *http.Request) error {
+	apiContext := api.GetApiContext(req)
+
+	list, err := s.operationList(apiContext)
+	if err != nil {
+		return err
+	}
+	apiContext.Write(list)
+	return nil
+}
+
+func (s *Server) operationList(apiContext *api.ApiContext) (*client.GenericCollection, error) {
+	return toOperationCollection(s.m.ListOperations()), nil
+}
+
+func (s *Server) OperationGet(rw http.ResponseWriter, req *http.Request) error {
+	id := mux.Vars(req)["name"]
+	apiContext := api.GetApiContext(req)
+
+	op, err := s.m.GetOperation(id)
+	if err != nil {
+		return err
+	}
+	apiContext.Write(toOperationResource(op))
+	return nil
+}
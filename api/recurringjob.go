@@ -8,8 +8,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rancher/go-rancher/api"
 	"github.com/rancher/go-rancher/client"
+	"github.com/sirupsen/logrus"
 
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/longhorn/longhorn-manager/manager"
 	"github.com/longhorn/longhorn-manager/util"
 )
 
@@ -29,7 +31,23 @@ func (s *Server) recurringJobList(apiContext *api.ApiContext) (*client.GenericCo
 	if err != nil {
 		return nil, errors.Wrap(err, "error listing recurring job")
 	}
-	return toRecurringJobCollection(list, apiContext), nil
+	scheduleInfos := map[string]*manager.RecurringJobScheduleInfo{}
+	for _, job := range list {
+		scheduleInfos[job.Name] = s.recurringJobScheduleInfo(job)
+	}
+	return toRecurringJobCollection(list, scheduleInfos, apiContext), nil
+}
+
+// recurringJobScheduleInfo resolves the computed scheduling state of the job.
+// The info only annotates the resource, so a failure to compute it must not
+// fail the request; it is logged and the fields are left empty instead.
+func (s *Server) recurringJobScheduleInfo(job *longhorn.RecurringJob) *manager.RecurringJobScheduleInfo {
+	scheduleInfo, err := s.m.GetRecurringJobScheduleInfo(job)
+	if err != nil {
+		logrus.WithError(err).Warnf("Failed to get the schedule info of recurring job %v", job.Name)
+		return nil
+	}
+	return scheduleInfo
 }
 
 func (s *Server) RecurringJobGet(rw http.ResponseWriter, req *http.Request) error {
@@ -41,7 +59,7 @@ func (s *Server) RecurringJobGet(rw http.ResponseWriter, req *http.Request) erro
 	if err != nil {
 		return errors.Wrapf(err, "error get recurring job policy '%s'", id)
 	}
-	apiContext.Write(toRecurringJobResource(job, apiContext))
+	apiContext.Write(toRecurringJobResource(job, s.recurringJobScheduleInfo(job), apiContext))
 	return nil
 }
 
@@ -69,7 +87,7 @@ func (s *Server) RecurringJobCreate(rw http.ResponseWriter, req *http.Request) e
 	if err != nil {
 		return errors.Wrapf(err, "unable to create recurring job %v", input.Name)
 	}
-	apiContext.Write(toRecurringJobResource(obj, apiContext))
+	apiContext.Write(toRecurringJobResource(obj, s.recurringJobScheduleInfo(obj), apiContext))
 	return nil
 }
 
@@ -106,7 +124,7 @@ func (s *Server) RecurringJobUpdate(rw http.ResponseWriter, req *http.Request) e
 		return fmt.Errorf("BUG: cannot convert %v to recurring job object", name)
 	}
 
-	apiContext.Write(toRecurringJobResource(job, apiContext))
+	apiContext.Write(toRecurringJobResource(job, s.recurringJobScheduleInfo(job), apiContext))
 	return nil
 }
 
@@ -13,6 +13,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/longhorn/longhorn-manager/monitoring"
+	"github.com/longhorn/longhorn-manager/types"
 )
 
 var (
@@ -36,14 +37,47 @@ func HandleError(s *client.Schemas, t HandleFuncWithError) http.Handler {
 		if err != nil {
 			logrus.Warnf("HTTP handling error %v", err)
 			apiContext := api.GetApiContext(req)
+			if code := errorCodeFromError(err); code != "" {
+				rw.WriteHeader(http.StatusInternalServerError)
+				if writeErr := apiContext.WriteResource(&client.ServerApiError{
+					Resource: client.Resource{
+						Type: "error",
+					},
+					Status:  http.StatusInternalServerError,
+					Code:    string(code),
+					Message: err.Error(),
+				}); writeErr != nil {
+					logrus.Warnf("Failed to write error %v", writeErr)
+				}
+				return
+			}
 			apiContext.WriteErr(err)
 		}
 	}))
 }
 
+// errorCodeFromError returns the machine-readable code attached to the error,
+// falling back to generic codes for well-known Kubernetes API errors.
+func errorCodeFromError(err error) types.ErrorCode {
+	if code := types.GetErrorCode(err); code != "" {
+		return code
+	}
+	cause := errors.Cause(err)
+	switch {
+	case apierrors.IsNotFound(cause):
+		return types.ErrorCodeNotFound
+	case apierrors.IsConflict(cause):
+		return types.ErrorCodeConflict
+	case apierrors.IsInvalid(cause) || apierrors.IsBadRequest(cause):
+		return types.ErrorCodeInvalidArgument
+	}
+	return ""
+}
+
 func NewRouter(s *Server) *mux.Router {
 	schemas := NewSchema()
 	r := mux.NewRouter().StrictSlash(true)
+	r.Use(metricsMiddleware)
 	f := HandleError
 
 	versionsHandler := api.VersionsHandler(schemas, "v1")
@@ -55,27 +89,43 @@ func NewRouter(s *Server) *mux.Router {
 	r.Methods("GET").Path("/v1/apiversions/v1").Handler(versionHandler)
 	r.Methods("GET").Path("/v1/schemas").Handler(api.SchemasHandler(schemas))
 	r.Methods("GET").Path("/v1/schemas/{id}").Handler(api.SchemaHandler(schemas))
+	r.Methods("GET").Path("/v1/openapi.json").Handler(f(schemas, OpenAPIHandler(schemas)))
 
 	r.Methods("GET").Path("/v1/settings").Handler(f(schemas, s.SettingList))
 	r.Methods("GET").Path("/v1/settings/{name}").Handler(f(schemas, s.SettingGet))
 	r.Methods("PUT").Path("/v1/settings/{name}").Handler(f(schemas, s.SettingSet))
+	r.Methods("POST").Path("/v1/settings/{name}").Queries("action", "validate").Handler(f(schemas, s.SettingValidate))
+	r.Methods("POST").Path("/v1/settings/{name}").Queries("action", "preview").Handler(f(schemas, s.SettingPreview))
 
 	r.Methods("GET").Path("/v1/volumes").Handler(f(schemas, s.VolumeList))
 	r.Methods("GET").Path("/v1/volumes/{name}").Handler(f(schemas, s.VolumeGet))
+	r.Methods("GET").Path("/v1/volumes/{name}/activitylog").Handler(f(schemas,
+		s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(OwnerIDFromVolume(s.m)), s.VolumeActivityLogDownload)))
+	r.Methods("GET").Path("/v1/volumes/{name}/statshistory").Handler(f(schemas,
+		s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(OwnerIDFromVolume(s.m)), s.VolumeStatsHistory)))
+	r.Methods("GET").Path("/v1/volumes/{name}/diagnostics").Handler(f(schemas, s.VolumeDiagnostics))
 	r.Methods("DELETE").Path("/v1/volumes/{name}").Handler(f(schemas, s.VolumeDelete))
 	r.Methods("POST").Path("/v1/volumes").Handler(f(schemas, s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.VolumeCreate)))
 	volumeActions := map[string]func(http.ResponseWriter, *http.Request) error{
-		"attach":             s.VolumeAttach,
-		"detach":             s.VolumeDetach,
-		"salvage":            s.VolumeSalvage,
-		"updateDataLocality": s.VolumeUpdateDataLocality,
-		"updateAccessMode":   s.VolumeUpdateAccessMode,
-		"activate":           s.VolumeActivate,
-		"expand":             s.VolumeExpand,
-		"cancelExpansion":    s.VolumeCancelExpansion,
+		"attach":                 s.VolumeAttach,
+		"detach":                 s.VolumeDetach,
+		"salvage":                s.VolumeSalvage,
+		"clone":                  s.VolumeClone,
+		"updateDataLocality":     s.VolumeUpdateDataLocality,
+		"updateAccessMode":       s.VolumeUpdateAccessMode,
+		"updateLastKeyRotatedAt": s.VolumeUpdateLastKeyRotatedAt,
+		"activate":               s.VolumeActivate,
+		"expand":                 s.VolumeExpand,
+		"cancelExpansion":        s.VolumeCancelExpansion,
+		"ioFreeze":               s.VolumeIOFreeze,
+		"ioUnfreeze":             s.VolumeIOUnfreeze,
+
+		"trimFilesystem": s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(OwnerIDFromVolume(s.m)), s.VolumeTrimFilesystem),
 
 		"updateReplicaCount":       s.VolumeUpdateReplicaCount,
 		"updateReplicaAutoBalance": s.VolumeUpdateReplicaAutoBalance,
+		"updateSnapshotMaxCount":   s.VolumeUpdateSnapshotMaxCount,
+		"updateSnapshotMaxSize":    s.VolumeUpdateSnapshotMaxSize,
 		"replicaRemove":            s.ReplicaRemove,
 
 		"engineUpgrade": s.EngineUpgrade,
@@ -100,13 +150,18 @@ func NewRouter(s *Server) *mux.Router {
 	}
 
 	r.Methods("GET").Path("/v1/backuptargets").Handler(f(schemas, s.BackupTargetList))
+	r.Methods("GET").Path("/v1/backuptargets/{name}").Handler(f(schemas, s.BackupTargetGet))
+	r.Methods("POST").Path("/v1/backuptargets").Handler(f(schemas, s.BackupTargetCreate))
+	r.Methods("PUT").Path("/v1/backuptargets/{name}").Handler(f(schemas, s.BackupTargetUpdate))
+	r.Methods("DELETE").Path("/v1/backuptargets/{name}").Handler(f(schemas, s.BackupTargetDelete))
 	r.Methods("GET").Path("/v1/backupvolumes").Handler(f(schemas, s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupVolumeList)))
 	r.Methods("GET").Path("/v1/backupvolumes/{volName}").Handler(f(schemas, s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupVolumeGet)))
 	r.Methods("DELETE").Path("/v1/backupvolumes/{volName}").Handler(f(schemas, s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupVolumeDelete)))
 	backupActions := map[string]func(http.ResponseWriter, *http.Request) error{
-		"backupList":   s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupList),
-		"backupGet":    s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupGet),
-		"backupDelete": s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupDelete),
+		"backupList":       s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupList),
+		"backupGet":        s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupGet),
+		"backupDelete":     s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupDelete),
+		"backupVolumeSync": s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(NodeHasDefaultEngineImage(s.m)), s.BackupVolumeSync),
 	}
 	for name, action := range backupActions {
 		r.Methods("POST").Path("/v1/backupvolumes/{volName}").Queries("action", name).Handler(f(schemas, action))
@@ -117,7 +172,8 @@ func NewRouter(s *Server) *mux.Router {
 	r.Methods("PUT").Path("/v1/nodes/{name}").Handler(f(schemas, s.NodeUpdate))
 	r.Methods("DELETE").Path("/v1/nodes/{name}").Handler(f(schemas, s.NodeDelete))
 	nodeActions := map[string]func(http.ResponseWriter, *http.Request) error{
-		"diskUpdate": s.DiskUpdate,
+		"diskUpdate":     s.DiskUpdate,
+		"evacuationPlan": s.NodeEvacuationPlan,
 	}
 	for name, action := range nodeActions {
 		r.Methods("POST").Path("/v1/nodes/{name}").Queries("action", name).Handler(f(schemas, action))
@@ -125,6 +181,7 @@ func NewRouter(s *Server) *mux.Router {
 
 	r.Methods("GET").Path("/v1/engineimages").Handler(f(schemas, s.EngineImageList))
 	r.Methods("GET").Path("/v1/engineimages/{name}").Handler(f(schemas, s.EngineImageGet))
+	r.Methods("GET").Path("/v1/engineimages/{name}/volumes").Handler(f(schemas, s.EngineImageVolumeList))
 	r.Methods("DELETE").Path("/v1/engineimages/{name}").Handler(f(schemas, s.EngineImageDelete))
 	r.Methods("POST").Path("/v1/engineimages").Handler(f(schemas, s.EngineImageCreate))
 
@@ -133,8 +190,11 @@ func NewRouter(s *Server) *mux.Router {
 	r.Methods("GET").Path("/v1/disktags").Handler(f(schemas, s.DiskTagList))
 	r.Methods("GET").Path("/v1/nodetags").Handler(f(schemas, s.NodeTagList))
 
+	r.Methods("GET").Path("/v1/upgradereport").Handler(f(schemas, s.UpgradeReportGet))
+
 	r.Methods("GET").Path("/v1/instancemanagers").Handler(f(schemas, s.InstanceManagerList))
 	r.Methods("GET").Path("/v1/instancemanagers/{name}").Handler(f(schemas, s.InstanceManagerGet))
+	r.Methods("GET").Path("/v1/instancemanagers/{name}/logs").Handler(f(schemas, s.InstanceManagerProcessLog))
 
 	r.Methods("GET").Path("/v1/backingimages").Handler(f(schemas, s.BackingImageList))
 	r.Methods("GET").Path("/v1/backingimages/{name}").Handler(f(schemas, s.BackingImageGet))
@@ -155,6 +215,12 @@ func NewRouter(s *Server) *mux.Router {
 	r.Methods("POST").Path("/v1/recurringjobs").Handler(f(schemas, s.RecurringJobCreate))
 	r.Methods("PUT").Path("/v1/recurringjobs/{name}").Handler(f(schemas, s.RecurringJobUpdate))
 
+	r.Methods("GET").Path("/v1/operations").Handler(f(schemas, s.OperationList))
+	r.Methods("GET").Path("/v1/operations/{name}").Handler(f(schemas, s.OperationGet))
+
+	r.Methods("POST").Path("/v1/apply").Handler(f(schemas, s.Apply))
+	r.Methods("POST").Path("/v1/volumes/bulk").Handler(f(schemas, s.VolumeBulk))
+
 	r.Methods("POST").Path("/v1/supportbundles").Handler(f(schemas, s.InitiateSupportBundle))
 	r.Methods("GET").Path("/v1/supportbundles/{name}/{bundleName}").Handler(f(schemas,
 		s.fwd.Handler(s.fwd.HandleProxyRequestByNodeID, s.fwd.GetHTTPAddressByNodeID(OwnerIDFromNode(s.m)), s.QuerySupportBundle)))
@@ -205,5 +271,9 @@ func NewRouter(s *Server) *mux.Router {
 	r.Path("/v1/ws/events").Handler(f(schemas, eventListStream))
 	r.Path("/v1/ws/{period}/events").Handler(f(schemas, eventListStream))
 
+	operationListStream := NewStreamHandlerFunc("operations", s.wsc.NewWatcher("operation"), s.operationList)
+	r.Path("/v1/ws/operations").Handler(f(schemas, operationListStream))
+	r.Path("/v1/ws/{period}/operations").Handler(f(schemas, operationListStream))
+
 	return r
 }
@@ -67,3 +67,51 @@ func (s *Server) SettingSet(w http.ResponseWriter, req *http.Request) error {
 	apiContext.Write(toSettingResource(si))
 	return nil
 }
+
+// SettingValidate checks a setting value against the typed setting schema
+// without persisting anything, so the UI can validate user input before
+// applying it.
+func (s *Server) SettingValidate(w http.ResponseWriter, req *http.Request) error {
+	var setting Setting
+
+	apiContext := api.GetApiContext(req)
+	if err := apiContext.Read(&setting); err != nil {
+		return err
+	}
+
+	name := mux.Vars(req)["name"]
+
+	validation := &SettingValidation{
+		Resource: client.Resource{Type: "settingValidation"},
+		Valid:    true,
+	}
+	if err := types.ValidateInitSetting(name, strings.TrimSpace(setting.Value)); err != nil {
+		validation.Valid = false
+		validation.Error = err.Error()
+	}
+
+	apiContext.Write(validation)
+	return nil
+}
+
+// SettingPreview dry-runs a proposed setting change: it reports which system
+// managed components would be redeployed or restarted and which volumes those
+// components serve, without committing the new value.
+func (s *Server) SettingPreview(w http.ResponseWriter, req *http.Request) error {
+	var setting Setting
+
+	apiContext := api.GetApiContext(req)
+	if err := apiContext.Read(&setting); err != nil {
+		return err
+	}
+
+	name := mux.Vars(req)["name"]
+
+	preview, err := s.m.PreviewSettingChange(types.SettingName(name), strings.TrimSpace(setting.Value))
+	if err != nil {
+		return errors.Wrapf(err, "fail to preview change of setting %v", name)
+	}
+
+	apiContext.Write(toSettingChangePreviewResource(preview))
+	return nil
+}
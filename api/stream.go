@@ -1,10 +1,14 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/rand"
 	"net/http"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -32,6 +36,11 @@ func init() {
 
 func NewStreamHandlerFunc(streamType string, watcher *controller.Watcher, listFunc func(ctx *api.ApiContext) (*client.GenericCollection, error)) func(w http.ResponseWriter, r *http.Request) error {
 	return func(w http.ResponseWriter, r *http.Request) error {
+		sw, err := newStreamWriter(r)
+		if err != nil {
+			return err
+		}
+
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			return err
@@ -56,8 +65,7 @@ func NewStreamHandlerFunc(streamType string, watcher *controller.Watcher, listFu
 
 		apiContext := api.GetApiContext(r)
 
-		resp, err := writeList(conn, nil, listFunc, apiContext)
-		if err != nil {
+		if err := sw.write(conn, listFunc, apiContext); err != nil {
 			return err
 		}
 
@@ -75,13 +83,14 @@ func NewStreamHandlerFunc(streamType string, watcher *controller.Watcher, listFu
 			case <-done:
 				return nil
 			case <-watcher.Events():
-				resp, err = writeList(conn, resp, listFunc, apiContext)
+				err = sw.write(conn, listFunc, apiContext)
 			case <-keepAliveTicker.C:
 				err = conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(writeWait))
-				// WebsocketController doesn't include eventInformer so it only
-				// gets triggered here.
-				if streamType == "events" {
-					resp, err = writeList(conn, resp, listFunc, apiContext)
+				// WebsocketController doesn't include eventInformer, and
+				// operations only live in manager memory, so those streams
+				// only get refreshed here.
+				if streamType == "events" || streamType == "operations" {
+					err = sw.write(conn, listFunc, apiContext)
 				}
 			}
 			if err != nil {
@@ -91,30 +100,153 @@ func NewStreamHandlerFunc(streamType string, watcher *controller.Watcher, listFu
 	}
 }
 
-func writeList(conn *websocket.Conn, oldResp *client.GenericCollection, listFunc func(ctx *api.ApiContext) (*client.GenericCollection, error), apiContext *api.ApiContext) (*client.GenericCollection, error) {
+// streamDiff is the incremental update sent on a stream opened with
+// ?diff=true: only the resources that changed since the previous message,
+// plus the ids of the removed ones. The first message always carries the
+// complete collection so the client starts from a full view.
+type streamDiff struct {
+	ResourceType string        `json:"resourceType"`
+	Modified     []interface{} `json:"modified"`
+	Removed      []string      `json:"removed,omitempty"`
+}
+
+// streamWriter tracks what has already been sent on one websocket stream, so
+// the follow-up messages can honor the field selection and the filters of the
+// request and, in diff mode, be reduced to incremental updates.
+type streamWriter struct {
+	query    *listQuery
+	filters  map[string]string
+	diff     bool
+	lastResp *client.GenericCollection
+	lastSeen map[string]string
+}
+
+func newStreamWriter(r *http.Request) (*streamWriter, error) {
+	query, err := parseListQuery(r)
+	if err != nil {
+		return nil, err
+	}
+	filters := map[string]string{}
+	for _, filter := range r.URL.Query()["filter"] {
+		kv := strings.SplitN(filter, "=", 2)
+		if len(kv) == 2 {
+			filters[kv[0]] = kv[1]
+		}
+	}
+	return &streamWriter{
+		query:    query,
+		filters:  filters,
+		diff:     r.URL.Query().Get("diff") == "true",
+		lastSeen: map[string]string{},
+	}, nil
+}
+
+func (sw *streamWriter) write(conn *websocket.Conn, listFunc func(ctx *api.ApiContext) (*client.GenericCollection, error), apiContext *api.ApiContext) error {
 	newResp, err := listFunc(apiContext)
 	if err != nil {
-		return oldResp, err
+		return err
 	}
+	newResp.Data = filterStreamData(newResp.Data, sw.filters)
+	newResp.Data = selectFields(newResp.Data, sw.query)
 
-	if oldResp != nil && reflect.DeepEqual(oldResp, newResp) {
-		return oldResp, nil
+	if !sw.diff {
+		if sw.lastResp != nil && reflect.DeepEqual(sw.lastResp, newResp) {
+			return nil
+		}
+		data, err := apiContext.PopulateCollection(newResp)
+		if err != nil {
+			return err
+		}
+		if err := writeConn(conn, data); err != nil {
+			return err
+		}
+		sw.lastResp = newResp
+		return nil
 	}
-	data, err := apiContext.PopulateCollection(newResp)
-	if err != nil {
-		return oldResp, err
+
+	seen := map[string]string{}
+	modified := []interface{}{}
+	for _, item := range newResp.Data {
+		id, raw := resourceKey(item)
+		if id == "" {
+			modified = append(modified, item)
+			continue
+		}
+		seen[id] = raw
+		if previous, ok := sw.lastSeen[id]; !ok || previous != raw {
+			modified = append(modified, item)
+		}
+	}
+	removed := []string{}
+	for id := range sw.lastSeen {
+		if _, ok := seen[id]; !ok {
+			removed = append(removed, id)
+		}
 	}
+	sort.Strings(removed)
+	sw.lastSeen = seen
 
-	err = conn.SetWriteDeadline(time.Now().Add(writeWait))
-	if err != nil {
-		return oldResp, err
+	if len(modified) == 0 && len(removed) == 0 {
+		return nil
 	}
-	err = conn.WriteJSON(data)
-	if err != nil {
-		return oldResp, err
+	return writeConn(conn, &streamDiff{
+		ResourceType: newResp.ResourceType,
+		Modified:     modified,
+		Removed:      removed,
+	})
+}
+
+func writeConn(conn *websocket.Conn, data interface{}) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+		return err
+	}
+	return conn.WriteJSON(data)
+}
+
+// filterStreamData keeps the items whose serialized top level fields match
+// every filter=key=value parameter of the stream request.
+func filterStreamData(data []interface{}, filters map[string]string) []interface{} {
+	if len(filters) == 0 {
+		return data
 	}
+	filtered := make([]interface{}, 0, len(data))
+	for _, item := range data {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			continue
+		}
+		match := true
+		for key, value := range filters {
+			if fmt.Sprintf("%v", fields[key]) != value {
+				match = false
+				break
+			}
+		}
+		if match {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
 
-	return newResp, nil
+// resourceKey returns the id of the resource and its serialized form, the
+// latter doubling as the change detection fingerprint of the diff mode.
+func resourceKey(item interface{}) (string, string) {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return "", ""
+	}
+	envelope := struct {
+		ID string `json:"id"`
+	}{}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return "", string(raw)
+	}
+	return envelope.ID, string(raw)
 }
 
 func maybeNewTicker(d time.Duration) *time.Ticker {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"sort"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/rancher/go-rancher/client"
 
 	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
 	"github.com/longhorn/longhorn-manager/util"
 
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
@@ -23,7 +25,12 @@ func (s *Server) VolumeList(rw http.ResponseWriter, req *http.Request) (err erro
 
 	apiContext := api.GetApiContext(req)
 
-	resp, err := s.volumeList(apiContext)
+	query, err := parseListQuery(req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.volumeListPage(apiContext, query, parseVolumeFilter(req))
 	if err != nil {
 		return err
 	}
@@ -34,6 +41,10 @@ func (s *Server) VolumeList(rw http.ResponseWriter, req *http.Request) (err erro
 }
 
 func (s *Server) volumeList(apiContext *api.ApiContext) (*client.GenericCollection, error) {
+	return s.volumeListPage(apiContext, nil, nil)
+}
+
+func (s *Server) volumeListPage(apiContext *api.ApiContext, query *listQuery, filter func(v *longhorn.Volume) bool) (*client.GenericCollection, error) {
 	resp := &client.GenericCollection{}
 
 	volumes, err := s.m.ListSorted()
@@ -41,7 +52,17 @@ func (s *Server) volumeList(apiContext *api.ApiContext) (*client.GenericCollecti
 		return nil, err
 	}
 
+	pager := newPager(query)
 	for _, v := range volumes {
+		if filter != nil && !filter(v) {
+			continue
+		}
+		if !pager.include(v.Name) {
+			if pager.truncated {
+				break
+			}
+			continue
+		}
 		controllers, err := s.m.GetEnginesSorted(v.Name)
 		if err != nil {
 			return nil, err
@@ -57,6 +78,8 @@ func (s *Server) volumeList(apiContext *api.ApiContext) (*client.GenericCollecti
 
 		resp.Data = append(resp.Data, toVolumeResource(v, controllers, replicas, backups, apiContext))
 	}
+	resp.Pagination = pager.pagination()
+	resp.Data = selectFields(resp.Data, query)
 	resp.ResourceType = "volume"
 	resp.CreateTypes = map[string]string{
 		"volume": apiContext.UrlBuilder.Collection("volume"),
@@ -114,12 +137,23 @@ func (s *Server) VolumeCreate(rw http.ResponseWriter, req *http.Request) error {
 		return err
 	}
 
+	v, err := s.createVolume(&volume)
+	if err != nil {
+		return err
+	}
+	return s.responseWithVolume(rw, req, "", v)
+}
+
+// createVolume validates the volume input and creates the volume. It is
+// shared between the volume creation endpoint and the declarative apply
+// endpoint.
+func (s *Server) createVolume(volume *Volume) (*longhorn.Volume, error) {
 	if volume.Standby {
 		if volume.Frontend != "" {
-			return fmt.Errorf("cannot set frontend for standby volume: %v", volume.Name)
+			return nil, fmt.Errorf("cannot set frontend for standby volume: %v", volume.Name)
 		}
 		if volume.FromBackup == "" {
-			return fmt.Errorf("cannot create standby volume %v without field FromBackup", volume.Name)
+			return nil, fmt.Errorf("cannot create standby volume %v without field FromBackup", volume.Name)
 		}
 	} else {
 		if volume.Frontend == "" {
@@ -129,54 +163,124 @@ func (s *Server) VolumeCreate(rw http.ResponseWriter, req *http.Request) error {
 
 	size, err := util.ConvertSize(volume.Size)
 	if err != nil {
-		return fmt.Errorf("fail to parse size %v", err)
+		return nil, types.NewCodedError(types.ErrorCodeInvalidArgument, fmt.Errorf("fail to parse size %v", err))
+	}
+
+	snapshotMaxSize, err := util.ConvertSize(volume.SnapshotMaxSize)
+	if err != nil {
+		return nil, types.NewCodedError(types.ErrorCodeInvalidArgument, fmt.Errorf("fail to parse snapshot max size %v", err))
 	}
 
 	// Check DiskSelector.
 	diskTags, err := s.m.GetDiskTags()
 	if err != nil {
-		return errors.Wrapf(err, "failed to get all disk tags")
+		return nil, errors.Wrapf(err, "failed to get all disk tags")
 	}
 	sort.Strings(diskTags)
 	for _, selector := range volume.DiskSelector {
 		if index := sort.SearchStrings(diskTags, selector); index >= len(diskTags) || diskTags[index] != selector {
-			return fmt.Errorf("specified disk tag %v does not exist", selector)
+			return nil, fmt.Errorf("specified disk tag %v does not exist", selector)
+		}
+	}
+
+	// Check DiskGroup.
+	if volume.DiskGroup != "" {
+		diskGroups, err := s.m.GetDiskGroups()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get all disk groups")
+		}
+		sort.Strings(diskGroups)
+		if index := sort.SearchStrings(diskGroups, volume.DiskGroup); index >= len(diskGroups) || diskGroups[index] != volume.DiskGroup {
+			return nil, fmt.Errorf("specified disk group %v does not exist", volume.DiskGroup)
+		}
+	}
+
+	// Check BackupTargetName.
+	if volume.BackupTargetName != "" {
+		if _, err := s.m.GetBackupTarget(volume.BackupTargetName); err != nil {
+			return nil, errors.Wrapf(err, "failed to get backup target %v", volume.BackupTargetName)
 		}
 	}
 
 	// Check NodeSelector.
 	nodeTags, err := s.m.GetNodeTags()
 	if err != nil {
-		return errors.Wrapf(err, "failed to get all node tags")
+		return nil, errors.Wrapf(err, "failed to get all node tags")
 	}
 	sort.Strings(nodeTags)
 	for _, selector := range volume.NodeSelector {
 		if index := sort.SearchStrings(nodeTags, selector); index >= len(nodeTags) || nodeTags[index] != selector {
-			return fmt.Errorf("specified node tag %v does not exist", selector)
+			return nil, fmt.Errorf("specified node tag %v does not exist", selector)
 		}
 	}
 
-	v, err := s.m.Create(volume.Name, &longhorn.VolumeSpec{
+	spec := &longhorn.VolumeSpec{
 		Size:                    size,
 		AccessMode:              volume.AccessMode,
 		Migratable:              volume.Migratable,
 		Encrypted:               volume.Encrypted,
 		Frontend:                volume.Frontend,
 		FromBackup:              volume.FromBackup,
+		BackupTargetName:        volume.BackupTargetName,
 		DataSource:              volume.DataSource,
 		NumberOfReplicas:        volume.NumberOfReplicas,
 		ReplicaAutoBalance:      volume.ReplicaAutoBalance,
+		ReplicaSoftAntiAffinity: volume.ReplicaSoftAntiAffinity,
+		AutoSalvage:             volume.AutoSalvage,
+		SnapshotMaxCount:        volume.SnapshotMaxCount,
+		SnapshotMaxSize:         snapshotMaxSize,
 		DataLocality:            volume.DataLocality,
+		BackupCompressionMethod: volume.BackupCompressionMethod,
 		StaleReplicaTimeout:     volume.StaleReplicaTimeout,
 		BackingImage:            volume.BackingImage,
 		Standby:                 volume.Standby,
 		RevisionCounterDisabled: volume.RevisionCounterDisabled,
 		DiskSelector:            volume.DiskSelector,
+		DiskGroup:               volume.DiskGroup,
 		NodeSelector:            volume.NodeSelector,
-	}, volume.RecurringJobSelector)
+	}
+
+	// Under strict provisioning an unschedulable volume is refused up front
+	// rather than created and left detached.
+	if volume.StrictProvisioning {
+		if err := s.m.CheckVolumeSchedulable(volume.Name, spec); err != nil {
+			return nil, errors.Wrap(err, "volume is not schedulable under strict provisioning")
+		}
+	}
+
+	v, err := s.m.Create(volume.Name, spec, volume.RecurringJobSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create volume")
+	}
+	return v, nil
+}
+
+// VolumeClone creates a new volume seeded from a snapshot of the addressed
+// volume; the cloning progress is reported in the clone status of the new
+// volume.
+func (s *Server) VolumeClone(rw http.ResponseWriter, req *http.Request) error {
+	var input CloneInput
+
+	apiContext := api.GetApiContext(req)
+	if err := apiContext.Read(&input); err != nil {
+		return err
+	}
+	if input.Name == "" {
+		return fmt.Errorf("new volume name required")
+	}
+	id := mux.Vars(req)["name"]
+
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.Clone(input.Name, id, input.SnapshotName)
+	})
 	if err != nil {
-		return errors.Wrap(err, "unable to create volume")
+		return err
 	}
+	v, ok := obj.(*longhorn.Volume)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to volume %v object", input.Name)
+	}
+
 	return s.responseWithVolume(rw, req, "", v)
 }
 
@@ -346,6 +450,57 @@ func (s *Server) VolumeUpdateReplicaCount(rw http.ResponseWriter, req *http.Requ
 	return s.responseWithVolume(rw, req, "", v)
 }
 
+func (s *Server) VolumeUpdateSnapshotMaxCount(rw http.ResponseWriter, req *http.Request) error {
+	var input UpdateSnapshotMaxCountInput
+	id := mux.Vars(req)["name"]
+
+	apiContext := api.GetApiContext(req)
+	if err := apiContext.Read(&input); err != nil {
+		return errors.Wrapf(err, "error reading snapshotMaxCount")
+	}
+
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.UpdateSnapshotMaxCount(id, input.SnapshotMaxCount)
+	})
+	if err != nil {
+		return err
+	}
+	v, ok := obj.(*longhorn.Volume)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to volume %v object", id)
+	}
+
+	return s.responseWithVolume(rw, req, "", v)
+}
+
+func (s *Server) VolumeUpdateSnapshotMaxSize(rw http.ResponseWriter, req *http.Request) error {
+	var input UpdateSnapshotMaxSizeInput
+	id := mux.Vars(req)["name"]
+
+	apiContext := api.GetApiContext(req)
+	if err := apiContext.Read(&input); err != nil {
+		return errors.Wrapf(err, "error reading snapshotMaxSize")
+	}
+
+	size, err := util.ConvertSize(input.SnapshotMaxSize)
+	if err != nil {
+		return fmt.Errorf("fail to parse snapshot max size %v", err)
+	}
+
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.UpdateSnapshotMaxSize(id, size)
+	})
+	if err != nil {
+		return err
+	}
+	v, ok := obj.(*longhorn.Volume)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to volume %v object", id)
+	}
+
+	return s.responseWithVolume(rw, req, "", v)
+}
+
 func (s *Server) VolumeUpdateReplicaAutoBalance(rw http.ResponseWriter, req *http.Request) error {
 	var input UpdateReplicaAutoBalanceInput
 	id := mux.Vars(req)["name"]
@@ -413,6 +568,28 @@ func (s *Server) VolumeUpdateAccessMode(rw http.ResponseWriter, req *http.Reques
 	return s.responseWithVolume(rw, req, "", v)
 }
 
+func (s *Server) VolumeUpdateLastKeyRotatedAt(rw http.ResponseWriter, req *http.Request) error {
+	var input UpdateLastKeyRotatedAtInput
+	id := mux.Vars(req)["name"]
+
+	apiContext := api.GetApiContext(req)
+	if err := apiContext.Read(&input); err != nil {
+		return errors.Wrapf(err, "error reading last key rotation time")
+	}
+
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.UpdateLastKeyRotatedAt(id, input.LastKeyRotatedAt)
+	})
+	if err != nil {
+		return err
+	}
+	v, ok := obj.(*longhorn.Volume)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to volume %v object", id)
+	}
+	return s.responseWithVolume(rw, req, "", v)
+}
+
 func (s *Server) VolumeActivate(rw http.ResponseWriter, req *http.Request) error {
 	var input ActivateInput
 
@@ -491,6 +668,64 @@ func (s *Server) VolumeCancelExpansion(rw http.ResponseWriter, req *http.Request
 	return s.responseWithVolume(rw, req, "", v)
 }
 
+func (s *Server) VolumeIOFreeze(rw http.ResponseWriter, req *http.Request) error {
+	var input IOFreezeInput
+
+	apiContext := api.GetApiContext(req)
+	if err := apiContext.Read(&input); err != nil {
+		return err
+	}
+
+	id := mux.Vars(req)["name"]
+
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.FreezeVolumeIO(id, input.Timeout)
+	})
+	if err != nil {
+		return err
+	}
+	v, ok := obj.(*longhorn.Volume)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to volume %v object", id)
+	}
+
+	return s.responseWithVolume(rw, req, "", v)
+}
+
+func (s *Server) VolumeIOUnfreeze(rw http.ResponseWriter, req *http.Request) error {
+	id := mux.Vars(req)["name"]
+
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.UnfreezeVolumeIO(id)
+	})
+	if err != nil {
+		return err
+	}
+	v, ok := obj.(*longhorn.Volume)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to volume %v object", id)
+	}
+
+	return s.responseWithVolume(rw, req, "", v)
+}
+
+func (s *Server) VolumeTrimFilesystem(rw http.ResponseWriter, req *http.Request) error {
+	id := mux.Vars(req)["name"]
+
+	obj, err := util.RetryOnConflictCause(func() (interface{}, error) {
+		return s.m.TrimFilesystem(id)
+	})
+	if err != nil {
+		return err
+	}
+	v, ok := obj.(*longhorn.Volume)
+	if !ok {
+		return fmt.Errorf("BUG: cannot convert to volume %v object", id)
+	}
+
+	return s.responseWithVolume(rw, req, "", v)
+}
+
 func (s *Server) PVCreate(rw http.ResponseWriter, req *http.Request) error {
 	var input PVCreateInput
 	id := mux.Vars(req)["name"]
@@ -585,3 +820,53 @@ func (s *Server) EngineUpgrade(rw http.ResponseWriter, req *http.Request) error
 
 	return s.responseWithVolume(rw, req, id, v)
 }
+
+func (s *Server) VolumeStatsHistory(w http.ResponseWriter, req *http.Request) (err error) {
+	defer func() {
+		err = errors.Wrap(err, "fail to get volume stats history")
+	}()
+
+	volName := mux.Vars(req)["name"]
+
+	samples, err := s.m.GetVolumeStatsHistory(volName)
+	if err != nil {
+		return err
+	}
+	api.GetApiContext(req).Write(toVolumeStatsSampleCollection(samples))
+	return nil
+}
+
+// VolumeDiagnostics surfaces the runtime details of the volume, so support
+// can inspect engine versions, snapshot chains, rebuild progress and
+// instance-manager process states without exec'ing into the pods.
+func (s *Server) VolumeDiagnostics(w http.ResponseWriter, req *http.Request) (err error) {
+	defer func() {
+		err = errors.Wrap(err, "fail to get volume diagnostics")
+	}()
+
+	volName := mux.Vars(req)["name"]
+
+	diagnostics, err := s.m.GetVolumeDiagnostics(volName)
+	if err != nil {
+		return err
+	}
+	api.GetApiContext(req).Write(toVolumeDiagnosticsResource(diagnostics))
+	return nil
+}
+
+func (s *Server) VolumeActivityLogDownload(w http.ResponseWriter, req *http.Request) error {
+	volName := mux.Vars(req)["name"]
+
+	activityLog, err := s.m.GetVolumeActivityLog(volName)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get activity log for volume %v", volName)
+	}
+	defer activityLog.Close()
+
+	w.Header().Set("Content-Disposition", "attachment; filename="+volName+"-activity.log")
+	w.Header().Set("Content-Type", "text/plain")
+	if _, err := io.Copy(w, activityLog); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/webhook"
+	"github.com/longhorn/longhorn-manager/webhook/admission"
+
+	lhclientset "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned"
+)
+
+const (
+	FlagAdmissionWebhookAddress  = "address"
+	FlagAdmissionWebhookCertFile = "cert-file"
+	FlagAdmissionWebhookKeyFile  = "key-file"
+	FlagAdmissionWebhookCAFile   = "ca-file"
+	FlagAdmissionWebhookService  = "service"
+
+	DefaultAdmissionWebhookAddress = ":9444"
+	DefaultAdmissionWebhookService = "longhorn-admission-webhook"
+	DefaultAdmissionWebhookPort    = 9444
+)
+
+func AdmissionWebhookCmd() cli.Command {
+	return cli.Command{
+		Name: "admission-webhook",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  FlagAdmissionWebhookAddress,
+				Value: DefaultAdmissionWebhookAddress,
+				Usage: "Address the admission webhook server listens on",
+			},
+			cli.StringFlag{
+				Name:  FlagAdmissionWebhookCertFile,
+				Usage: "Path to the TLS certificate used to serve the admission webhook",
+			},
+			cli.StringFlag{
+				Name:  FlagAdmissionWebhookKeyFile,
+				Usage: "Path to the TLS key used to serve the admission webhook",
+			},
+			cli.StringFlag{
+				Name:  FlagAdmissionWebhookCAFile,
+				Usage: "Path to the CA bundle registered in the webhook configuration so the API server can verify the webhook",
+			},
+			cli.StringFlag{
+				Name:  FlagAdmissionWebhookService,
+				Value: DefaultAdmissionWebhookService,
+				Usage: "Name of the Kubernetes service exposing the admission webhook",
+			},
+			cli.StringFlag{
+				Name:  FlagKubeConfig,
+				Usage: "Specify path to kube config (optional)",
+			},
+		},
+		Action: func(c *cli.Context) {
+			if err := startAdmissionWebhook(c); err != nil {
+				logrus.Fatalf("Error starting admission webhook server: %v", err)
+			}
+		},
+	}
+}
+
+func startAdmissionWebhook(c *cli.Context) error {
+	certFile := c.String(FlagAdmissionWebhookCertFile)
+	keyFile := c.String(FlagAdmissionWebhookKeyFile)
+
+	namespace := os.Getenv(types.EnvPodNamespace)
+	if namespace == "" {
+		logrus.Warnf("Cannot detect pod namespace, environment variable %v is missing, "+
+			"using default namespace", types.EnvPodNamespace)
+		namespace = corev1.NamespaceDefault
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", c.String(FlagKubeConfig))
+	if err != nil {
+		return fmt.Errorf("unable to get client config: %v", err)
+	}
+	kubeClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to get k8s client: %v", err)
+	}
+	lhClient, err := lhclientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to get clientset: %v", err)
+	}
+
+	address := c.String(FlagAdmissionWebhookAddress)
+	serviceName := c.String(FlagAdmissionWebhookService)
+	port := getAdmissionWebhookPort(address)
+
+	registerWebhooks := func(caBundle []byte) error {
+		if err := admission.RegisterValidatingWebhook(kubeClient, namespace, serviceName, port, caBundle); err != nil {
+			return err
+		}
+		return admission.RegisterMutatingWebhook(kubeClient, namespace, serviceName, port, caBundle)
+	}
+
+	validator := admission.NewValidator(namespace, lhClient)
+	mutator := admission.NewMutator(namespace, lhClient)
+	server := admission.NewServer(address, certFile, keyFile, validator, mutator)
+
+	// An operator-provided certificate secret takes precedence over the
+	// certificate files shipped with the deployment.
+	secretName, err := getTLSCertificateSecretSetting(lhClient, namespace)
+	if err != nil {
+		return err
+	}
+	if secretName != "" {
+		hostnames := []string{
+			fmt.Sprintf("%v.%v.svc", serviceName, namespace),
+			fmt.Sprintf("%v.%v.svc.cluster.local", serviceName, namespace),
+		}
+		certificate, err := webhook.NewSecretCertificate(kubeClient, namespace, secretName, hostnames)
+		if err != nil {
+			return errors.Wrapf(err, "unable to use the TLS certificate from secret %v", secretName)
+		}
+		server.UseCertificateSource(certificate)
+		// The API server verifies the served certificate against the CA
+		// registered in the webhook configurations, so the registration
+		// must use the CA of the secret and follow its rotations.
+		if err := registerWebhooks(certificate.CABundle()); err != nil {
+			return err
+		}
+		certificate.SetRotationHandler(func(caBundle []byte) {
+			if err := registerWebhooks(caBundle); err != nil {
+				logrus.WithError(err).Error("Failed to re-register the admission webhooks after the certificate rotation")
+			}
+		})
+	} else {
+		if certFile == "" || keyFile == "" {
+			return fmt.Errorf("require %v and %v when the %v setting is not set",
+				FlagAdmissionWebhookCertFile, FlagAdmissionWebhookKeyFile, types.SettingNameTLSCertificateSecret)
+		}
+		caBundle := []byte{}
+		if caFile := c.String(FlagAdmissionWebhookCAFile); caFile != "" {
+			caBundle, err = ioutil.ReadFile(caFile)
+			if err != nil {
+				return fmt.Errorf("unable to read CA bundle %v: %v", caFile, err)
+			}
+		}
+		if err := registerWebhooks(caBundle); err != nil {
+			return err
+		}
+	}
+
+	server.Serve()
+	return nil
+}
+
+// getTLSCertificateSecretSetting reads the tls-certificate-secret setting, or
+// returns an empty name when the setting does not exist yet.
+func getTLSCertificateSecretSetting(lhClient lhclientset.Interface, namespace string) (string, error) {
+	setting, err := lhClient.LonghornV1beta2().Settings(namespace).Get(
+		context.TODO(), string(types.SettingNameTLSCertificateSecret), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "unable to get setting %v", types.SettingNameTLSCertificateSecret)
+	}
+	return setting.Value, nil
+}
+
+// getAdmissionWebhookPort extracts the port from the listen address so that
+// the webhook registration points at the port the server actually serves on,
+// falling back to the default port when the address doesn't specify one
+func getAdmissionWebhookPort(address string) int32 {
+	_, portStr, err := net.SplitHostPort(address)
+	if err != nil || portStr == "" {
+		return DefaultAdmissionWebhookPort
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return DefaultAdmissionWebhookPort
+	}
+	return int32(port)
+}
@@ -0,0 +1,391 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	iscsi_util "github.com/longhorn/go-iscsi-helper/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhclientset "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned"
+	lhinformers "github.com/longhorn/longhorn-manager/k8s/pkg/client/informers/externalversions"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+)
+
+const (
+	FlagDiskPath = "disk-path"
+	FlagNodeID   = "node-id"
+	FlagDryRun   = "dry-run"
+
+	// replicaVolumeMetaFile is the metadata file the replica process
+	// maintains in every replica data directory.
+	replicaVolumeMetaFile = "volume.meta"
+)
+
+// replicaVolumeMeta is the subset of the volume.meta content the adoption
+// needs. The file is written by the replica process, so the field names
+// follow its convention rather than ours.
+type replicaVolumeMeta struct {
+	Size            int64  `json:"Size"`
+	Head            string `json:"Head"`
+	Rebuilding      bool   `json:"Rebuilding"`
+	Error           string `json:"Error"`
+	BackingFilePath string `json:"BackingFilePath"`
+}
+
+// adoptableReplica is one replica data directory found on the disk together
+// with its parsed metadata.
+type adoptableReplica struct {
+	directoryName string
+	meta          *replicaVolumeMeta
+}
+
+// AdoptDiskCmd scans a disk holding replica data of a previous Longhorn
+// installation and recreates the Volume/Engine/Replica CRs for it, so the
+// data can be recovered after a complete control-plane loss without backups.
+func AdoptDiskCmd() cli.Command {
+	return cli.Command{
+		Name: "adopt-disk",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  FlagDiskPath,
+				Usage: "The path of the disk holding the replica data to adopt",
+			},
+			cli.StringFlag{
+				Name:   FlagNodeID,
+				EnvVar: types.EnvNodeName,
+				Usage:  "The Longhorn node the disk is attached to",
+			},
+			cli.StringFlag{
+				Name:   FlagNamespace,
+				EnvVar: EnvLonghornNamespace,
+			},
+			cli.StringFlag{
+				Name:  FlagKubeConfig,
+				Usage: "Specify path to kube config (optional)",
+			},
+			cli.BoolFlag{
+				Name:  FlagDryRun,
+				Usage: "Only report what would be adopted without creating anything",
+			},
+		},
+		Action: func(c *cli.Context) {
+			if err := adoptDisk(c); err != nil {
+				logrus.Fatalf("Error adopting the disk: %v", err)
+			}
+		},
+	}
+}
+
+func adoptDisk(c *cli.Context) error {
+	diskPath := c.String(FlagDiskPath)
+	if diskPath == "" {
+		return errors.New("disk path is required")
+	}
+	nodeID := c.String(FlagNodeID)
+	if nodeID == "" {
+		return errors.New("node id is required")
+	}
+	namespace := c.String(FlagNamespace)
+	if namespace == "" {
+		return errors.New("namespace is required")
+	}
+	dryRun := c.Bool(FlagDryRun)
+
+	config, err := clientcmd.BuildConfigFromFlags("", c.String(FlagKubeConfig))
+	if err != nil {
+		return errors.Wrap(err, "unable to get client config")
+	}
+	kubeClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "unable to get k8s client")
+	}
+	lhClient, err := lhclientset.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "unable to get lh client")
+	}
+
+	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, time.Second*30)
+	lhInformerFactory := lhinformers.NewSharedInformerFactory(lhClient, time.Second*30)
+	ds := datastore.NewDataStore(lhInformerFactory, lhClient, kubeInformerFactory, kubeClient, namespace)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	lhInformerFactory.Start(stopCh)
+	kubeInformerFactory.Start(stopCh)
+	lhInformerFactory.WaitForCacheSync(stopCh)
+	kubeInformerFactory.WaitForCacheSync(stopCh)
+
+	diskCfg, err := util.GetDiskConfig(diskPath)
+	if err != nil {
+		return errors.Wrapf(err, "cannot read the disk config of %v", diskPath)
+	}
+
+	// The adopted replicas reference the disk by UUID, so the disk must
+	// already be registered on the node for the node controller and the
+	// scheduler to manage them.
+	node, err := ds.GetNode(nodeID)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get node %v", nodeID)
+	}
+	diskName := ""
+	for name, diskStatus := range node.Status.DiskStatus {
+		if diskStatus.DiskUUID == diskCfg.DiskUUID {
+			diskName = name
+			break
+		}
+	}
+	if diskName == "" {
+		return fmt.Errorf("disk %v (UUID %v) is not registered on node %v, add the disk to the node before adopting it", diskPath, diskCfg.DiskUUID, nodeID)
+	}
+
+	volumes, err := scanReplicaDirectories(diskPath)
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		logrus.Infof("Found no adoptable replica directory on disk %v", diskPath)
+		return nil
+	}
+
+	engineImage, err := ds.GetSettingValueExisted(types.SettingNameDefaultEngineImage)
+	if err != nil {
+		return errors.Wrap(err, "cannot get the default engine image for the adopted volumes")
+	}
+
+	volumeNames := []string{}
+	for volumeName := range volumes {
+		volumeNames = append(volumeNames, volumeName)
+	}
+	sort.Strings(volumeNames)
+
+	failed := 0
+	for _, volumeName := range volumeNames {
+		if err := adoptVolume(ds, volumeName, volumes[volumeName], nodeID, diskCfg.DiskUUID, diskPath, engineImage, dryRun); err != nil {
+			logrus.Errorf("Failed to adopt volume %v: %v", volumeName, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to adopt %v of %v volumes found on disk %v", failed, len(volumeNames), diskPath)
+	}
+	logrus.Infof("Adopted %v volumes from disk %v", len(volumeNames), diskPath)
+	return nil
+}
+
+// scanReplicaDirectories reads the replica data directories of the disk on
+// the host and groups the adoptable ones by volume name. Directories without
+// valid metadata or with incomplete data are reported and skipped rather
+// than failing the whole scan.
+func scanReplicaDirectories(diskPath string) (map[string][]adoptableReplica, error) {
+	nsPath := iscsi_util.GetHostNamespacePath(util.HostProcPath)
+	nsExec, err := iscsi_util.NewNamespaceExecutor(nsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	dirPath := filepath.Join(diskPath, util.ReplicaDirectory)
+	output, err := nsExec.Execute("ls", []string{"-1", dirPath})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the replica directory %v on host: %v", dirPath, err)
+	}
+
+	volumes := map[string][]adoptableReplica{}
+	for _, dir := range strings.Fields(output) {
+		// The data directory name is <volume name>-<8 char random id>.
+		sep := strings.LastIndex(dir, "-")
+		if sep <= 0 || sep == len(dir)-1 {
+			logrus.Warnf("Directory %v does not look like a replica data directory, skipped", dir)
+			continue
+		}
+		volumeName := dir[:sep]
+
+		metaOutput, err := nsExec.Execute("cat", []string{filepath.Join(dirPath, dir, replicaVolumeMetaFile)})
+		if err != nil {
+			logrus.Warnf("Directory %v has no readable %v, skipped: %v", dir, replicaVolumeMetaFile, err)
+			continue
+		}
+		meta := &replicaVolumeMeta{}
+		if err := json.Unmarshal([]byte(metaOutput), meta); err != nil {
+			logrus.Warnf("Cannot parse %v of directory %v, skipped: %v", replicaVolumeMetaFile, dir, err)
+			continue
+		}
+		if meta.Rebuilding {
+			logrus.Warnf("Replica in directory %v was rebuilding when the installation was lost, the data is incomplete, skipped", dir)
+			continue
+		}
+		if meta.Size <= 0 || meta.Head == "" {
+			logrus.Warnf("Replica in directory %v has invalid metadata, skipped", dir)
+			continue
+		}
+		volumes[volumeName] = append(volumes[volumeName], adoptableReplica{
+			directoryName: dir,
+			meta:          meta,
+		})
+	}
+	return volumes, nil
+}
+
+// adoptVolume reconstructs the Volume, Engine and Replica CRs for the replica
+// data directories of one volume. The volume comes up detached; the replicas
+// are marked healthy so an attach afterwards starts from the existing data.
+func adoptVolume(ds *datastore.DataStore, volumeName string, replicas []adoptableReplica, nodeID, diskUUID, diskPath, engineImage string, dryRun bool) error {
+	size := replicas[0].meta.Size
+	for _, replica := range replicas {
+		if replica.meta.Size != size {
+			return fmt.Errorf("the replica directories disagree on the volume size (%v vs %v), cannot adopt them together", size, replica.meta.Size)
+		}
+		if replica.meta.BackingFilePath != "" {
+			// The backing image cannot be reconstructed from the replica
+			// directory, so adopting the replica would yield a volume that
+			// cannot start.
+			return fmt.Errorf("replica directory %v uses backing file %v, recreate the backing image and its volume manually", replica.directoryName, replica.meta.BackingFilePath)
+		}
+	}
+
+	v, err := ds.GetVolume(volumeName)
+	if err != nil {
+		if !datastore.ErrorIsNotFound(err) {
+			return err
+		}
+		v = nil
+	}
+	if v != nil {
+		if v.Spec.Size != size {
+			return fmt.Errorf("volume %v already exists with size %v, cannot adopt replicas of size %v", volumeName, v.Spec.Size, size)
+		}
+	} else {
+		v = &longhorn.Volume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: volumeName,
+			},
+			Spec: longhorn.VolumeSpec{
+				Size:                    size,
+				Frontend:                longhorn.VolumeFrontendBlockDev,
+				EngineImage:             engineImage,
+				NumberOfReplicas:        len(replicas),
+				AccessMode:              longhorn.AccessModeReadWriteOnce,
+				DataLocality:            longhorn.DataLocalityDisabled,
+				ReplicaAutoBalance:      longhorn.ReplicaAutoBalanceIgnored,
+				ReplicaSoftAntiAffinity: longhorn.ReplicaSoftAntiAffinityIgnored,
+				AutoSalvage:             longhorn.AutoSalvageIgnored,
+			},
+		}
+		if dryRun {
+			logrus.Infof("Would create volume %v with size %v and %v replicas", volumeName, size, len(replicas))
+		} else {
+			if v, err = ds.CreateVolume(v); err != nil {
+				return errors.Wrapf(err, "cannot create volume %v", volumeName)
+			}
+			logrus.Infof("Created volume %v with size %v", volumeName, size)
+		}
+	}
+
+	// The replicas must reference an engine, otherwise the volume controller
+	// would never include them when the volume gets attached.
+	var e *longhorn.Engine
+	es, err := ds.ListVolumeEngines(volumeName)
+	if err != nil {
+		return err
+	}
+	for _, existing := range es {
+		if existing.Spec.Active {
+			e = existing
+			break
+		}
+	}
+	if e == nil {
+		e = &longhorn.Engine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            types.GenerateEngineNameForVolume(volumeName),
+				OwnerReferences: datastore.GetOwnerReferencesForVolume(v),
+			},
+			Spec: longhorn.EngineSpec{
+				InstanceSpec: longhorn.InstanceSpec{
+					VolumeName:  volumeName,
+					VolumeSize:  size,
+					EngineImage: engineImage,
+					DesireState: longhorn.InstanceStateStopped,
+				},
+				Frontend:                  v.Spec.Frontend,
+				ReplicaAddressMap:         map[string]string{},
+				UpgradedReplicaAddressMap: map[string]string{},
+				Active:                    true,
+			},
+		}
+		if dryRun {
+			logrus.Infof("Would create engine %v for volume %v", e.Name, volumeName)
+		} else {
+			if e, err = ds.CreateEngine(e); err != nil {
+				return errors.Wrapf(err, "cannot create engine for volume %v", volumeName)
+			}
+		}
+	}
+
+	// A rerun of the adoption must not duplicate replicas that already own
+	// one of the data directories.
+	rs, err := ds.ListVolumeReplicas(volumeName)
+	if err != nil {
+		return err
+	}
+	adoptedDirectories := map[string]string{}
+	for _, existing := range rs {
+		if existing.Spec.DiskID == diskUUID && existing.Spec.DataDirectoryName != "" {
+			adoptedDirectories[existing.Spec.DataDirectoryName] = existing.Name
+		}
+	}
+
+	for _, replica := range replicas {
+		if owner, exists := adoptedDirectories[replica.directoryName]; exists {
+			logrus.Infof("Replica directory %v is already owned by replica %v, skipped", replica.directoryName, owner)
+			continue
+		}
+		r := &longhorn.Replica{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            types.GenerateReplicaNameForVolume(volumeName),
+				OwnerReferences: datastore.GetOwnerReferencesForVolume(v),
+			},
+			Spec: longhorn.ReplicaSpec{
+				InstanceSpec: longhorn.InstanceSpec{
+					VolumeName:  volumeName,
+					VolumeSize:  size,
+					NodeID:      nodeID,
+					EngineImage: engineImage,
+					DesireState: longhorn.InstanceStateStopped,
+				},
+				EngineName:        e.Name,
+				Active:            true,
+				HealthyAt:         util.Now(),
+				DiskID:            diskUUID,
+				DiskPath:          diskPath,
+				DataDirectoryName: replica.directoryName,
+			},
+		}
+		if dryRun {
+			logrus.Infof("Would create replica %v for volume %v from directory %v", r.Name, volumeName, replica.directoryName)
+			continue
+		}
+		if r, err = ds.CreateReplica(r); err != nil {
+			return errors.Wrapf(err, "cannot create replica for directory %v", replica.directoryName)
+		}
+		logrus.Infof("Created replica %v for volume %v from directory %v", r.Name, volumeName, replica.directoryName)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextension "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/webhook"
+	"github.com/longhorn/longhorn-manager/webhook/conversion"
+
+	lhclientset "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned"
+)
+
+const (
+	FlagConversionWebhookAddress  = "address"
+	FlagConversionWebhookCertFile = "cert-file"
+	FlagConversionWebhookKeyFile  = "key-file"
+	FlagConversionWebhookCAFile   = "ca-file"
+	FlagConversionWebhookService  = "service"
+
+	DefaultConversionWebhookAddress = ":9443"
+	DefaultConversionWebhookService = "longhorn-conversion-webhook"
+	DefaultConversionWebhookPort    = 9443
+)
+
+func ConversionWebhookCmd() cli.Command {
+	return cli.Command{
+		Name: "conversion-webhook",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  FlagConversionWebhookAddress,
+				Value: DefaultConversionWebhookAddress,
+				Usage: "Address the conversion webhook server listens on",
+			},
+			cli.StringFlag{
+				Name:  FlagConversionWebhookCertFile,
+				Usage: "Path to the TLS certificate used to serve the conversion webhook",
+			},
+			cli.StringFlag{
+				Name:  FlagConversionWebhookKeyFile,
+				Usage: "Path to the TLS key used to serve the conversion webhook",
+			},
+			cli.StringFlag{
+				Name:  FlagConversionWebhookCAFile,
+				Usage: "Path to the CA bundle registered in the CRDs so the API server can verify the webhook",
+			},
+			cli.StringFlag{
+				Name:  FlagConversionWebhookService,
+				Value: DefaultConversionWebhookService,
+				Usage: "Name of the Kubernetes service exposing the conversion webhook",
+			},
+			cli.StringFlag{
+				Name:  FlagKubeConfig,
+				Usage: "Specify path to kube config (optional)",
+			},
+		},
+		Action: func(c *cli.Context) {
+			if err := startConversionWebhook(c); err != nil {
+				logrus.Fatalf("Error starting conversion webhook server: %v", err)
+			}
+		},
+	}
+}
+
+func startConversionWebhook(c *cli.Context) error {
+	certFile := c.String(FlagConversionWebhookCertFile)
+	keyFile := c.String(FlagConversionWebhookKeyFile)
+
+	namespace := os.Getenv(types.EnvPodNamespace)
+	if namespace == "" {
+		logrus.Warnf("Cannot detect pod namespace, environment variable %v is missing, "+
+			"using default namespace", types.EnvPodNamespace)
+		namespace = corev1.NamespaceDefault
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", c.String(FlagKubeConfig))
+	if err != nil {
+		return fmt.Errorf("unable to get client config: %v", err)
+	}
+	kubeClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to get k8s client: %v", err)
+	}
+	extensionsClient, err := apiextension.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to get k8s extensions client: %v", err)
+	}
+	lhClient, err := lhclientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to get clientset: %v", err)
+	}
+
+	address := c.String(FlagConversionWebhookAddress)
+	serviceName := c.String(FlagConversionWebhookService)
+	port := getConversionWebhookPort(address)
+
+	registerWebhook := func(caBundle []byte) error {
+		return conversion.RegisterConversionWebhook(extensionsClient, namespace, serviceName, port, caBundle)
+	}
+
+	server := conversion.NewServer(address, certFile, keyFile)
+
+	// An operator-provided certificate secret takes precedence over the
+	// certificate files shipped with the deployment.
+	secretName, err := getTLSCertificateSecretSetting(lhClient, namespace)
+	if err != nil {
+		return err
+	}
+	if secretName != "" {
+		hostnames := []string{
+			fmt.Sprintf("%v.%v.svc", serviceName, namespace),
+			fmt.Sprintf("%v.%v.svc.cluster.local", serviceName, namespace),
+		}
+		certificate, err := webhook.NewSecretCertificate(kubeClient, namespace, secretName, hostnames)
+		if err != nil {
+			return errors.Wrapf(err, "unable to use the TLS certificate from secret %v", secretName)
+		}
+		server.UseCertificateSource(certificate)
+		// The API server verifies the served certificate against the CA
+		// registered in the CRDs, so the registration must use the CA of
+		// the secret and follow its rotations.
+		if err := registerWebhook(certificate.CABundle()); err != nil {
+			return err
+		}
+		certificate.SetRotationHandler(func(caBundle []byte) {
+			if err := registerWebhook(caBundle); err != nil {
+				logrus.WithError(err).Error("Failed to re-register the conversion webhook after the certificate rotation")
+			}
+		})
+	} else {
+		if certFile == "" || keyFile == "" {
+			return fmt.Errorf("require %v and %v when the %v setting is not set",
+				FlagConversionWebhookCertFile, FlagConversionWebhookKeyFile, types.SettingNameTLSCertificateSecret)
+		}
+		caBundle := []byte{}
+		if caFile := c.String(FlagConversionWebhookCAFile); caFile != "" {
+			caBundle, err = ioutil.ReadFile(caFile)
+			if err != nil {
+				return fmt.Errorf("unable to read CA bundle %v: %v", caFile, err)
+			}
+		}
+		if err := registerWebhook(caBundle); err != nil {
+			return err
+		}
+	}
+
+	server.Serve()
+	return nil
+}
+
+// getConversionWebhookPort extracts the port from the listen address so that
+// the CRD registration points at the port the server actually serves on,
+// falling back to the default port when the address doesn't specify one
+func getConversionWebhookPort(address string) int32 {
+	_, portStr, err := net.SplitHostPort(address)
+	if err != nil || portStr == "" {
+		return DefaultConversionWebhookPort
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return DefaultConversionWebhookPort
+	}
+	return int32(port)
+}
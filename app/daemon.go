@@ -11,14 +11,12 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
-	"github.com/longhorn/go-iscsi-helper/iscsi"
-	iscsi_util "github.com/longhorn/go-iscsi-helper/util"
-
 	"github.com/longhorn/longhorn-manager/api"
 	"github.com/longhorn/longhorn-manager/controller"
 	"github.com/longhorn/longhorn-manager/datastore"
 	"github.com/longhorn/longhorn-manager/manager"
 	"github.com/longhorn/longhorn-manager/monitoring"
+	"github.com/longhorn/longhorn-manager/rpc"
 	"github.com/longhorn/longhorn-manager/types"
 	"github.com/longhorn/longhorn-manager/upgrade"
 	"github.com/longhorn/longhorn-manager/util"
@@ -34,6 +32,12 @@ const (
 	FlagManagerImage             = "manager-image"
 	FlagServiceAccount           = "service-account"
 	FlagKubeConfig               = "kube-config"
+	FlagRecoveryMode             = "recovery-mode"
+
+	FlagUpgradeLeaseDuration = "upgrade-lease-duration"
+	FlagUpgradeRenewDeadline = "upgrade-renew-deadline"
+	FlagUpgradeRetryPeriod   = "upgrade-retry-period"
+	FlagUpgradeTimeout       = "upgrade-timeout"
 )
 
 func DaemonCmd() cli.Command {
@@ -68,6 +72,34 @@ func DaemonCmd() cli.Command {
 				Name:  FlagKubeConfig,
 				Usage: "Specify path to kube config (optional)",
 			},
+			cli.BoolFlag{
+				Name:   FlagRecoveryMode,
+				Usage:  "Start only the essential controllers with automatic healing disabled, so broken state can be untangled manually (optional)",
+				EnvVar: "LONGHORN_RECOVERY_MODE",
+			},
+			cli.DurationFlag{
+				Name:   FlagUpgradeLeaseDuration,
+				Value:  upgrade.DefaultLeaseDuration,
+				Usage:  "Specify the lease duration of the upgrade leader election",
+				EnvVar: "LONGHORN_UPGRADE_LEASE_DURATION",
+			},
+			cli.DurationFlag{
+				Name:   FlagUpgradeRenewDeadline,
+				Value:  upgrade.DefaultRenewDeadline,
+				Usage:  "Specify the renew deadline of the upgrade leader election",
+				EnvVar: "LONGHORN_UPGRADE_RENEW_DEADLINE",
+			},
+			cli.DurationFlag{
+				Name:   FlagUpgradeRetryPeriod,
+				Value:  upgrade.DefaultRetryPeriod,
+				Usage:  "Specify the retry period of the upgrade leader election",
+				EnvVar: "LONGHORN_UPGRADE_RETRY_PERIOD",
+			},
+			cli.DurationFlag{
+				Name:   FlagUpgradeTimeout,
+				Usage:  "Abort the upgrade when it does not finish within this duration, 0 means no timeout (optional)",
+				EnvVar: "LONGHORN_UPGRADE_TIMEOUT",
+			},
 		},
 		Action: func(c *cli.Context) {
 			if err := startManager(c); err != nil {
@@ -109,12 +141,6 @@ func startManager(c *cli.Context) error {
 	}
 	kubeconfigPath := c.String(FlagKubeConfig)
 
-	if err := environmentCheck(); err != nil {
-		logrus.Errorf("Failed environment check, please make sure you " +
-			"have iscsiadm/open-iscsi installed on the host")
-		return fmt.Errorf("environment check failed: %v", err)
-	}
-
 	currentNodeID, err := util.GetRequiredEnv(types.EnvNodeName)
 	if err != nil {
 		return fmt.Errorf("BUG: fail to detect the node name")
@@ -129,10 +155,26 @@ func startManager(c *cli.Context) error {
 
 	logger := logrus.StandardLogger().WithField("node", currentNodeID)
 
-	if err := upgrade.Upgrade(kubeconfigPath, currentNodeID); err != nil {
-		return err
+	recoveryMode := c.Bool(FlagRecoveryMode)
+	if recoveryMode {
+		logger.Warnf("Starting the manager in recovery mode, the resource upgrade and the non-essential controllers are skipped")
+		controller.RecoveryMode = true
+	} else {
+		upgradeOpts := upgrade.Options{
+			LeaseDuration: c.Duration(FlagUpgradeLeaseDuration),
+			RenewDeadline: c.Duration(FlagUpgradeRenewDeadline),
+			RetryPeriod:   c.Duration(FlagUpgradeRetryPeriod),
+			Timeout:       c.Duration(FlagUpgradeTimeout),
+		}
+		if err := upgrade.Upgrade(kubeconfigPath, currentNodeID, VERSION, upgradeOpts); err != nil {
+			return err
+		}
 	}
 
+	// The workqueue metrics provider has to be in place before the
+	// controllers create their queues.
+	monitoring.InitWorkqueueMetrics()
+
 	ds, wsc, err := controller.StartControllers(logger, done, currentNodeID, serviceAccount, managerImage, kubeconfigPath, VERSION)
 	if err != nil {
 		return err
@@ -140,7 +182,9 @@ func startManager(c *cli.Context) error {
 
 	m := manager.NewVolumeManager(currentNodeID, ds)
 
-	monitoring.InitMonitoringSystem(logger, currentNodeID, ds, kubeconfigPath)
+	monitoring.InitMonitoringSystem(logger, currentNodeID, ds, kubeconfigPath, m)
+
+	go m.RunVolumeStatsSampling(done)
 
 	if err := ds.InitSettings(); err != nil {
 		return err
@@ -170,12 +214,24 @@ func startManager(c *cli.Context) error {
 		return err
 	}
 
-	if err := m.DeployEngineImage(engineImage); err != nil {
+	// Sanity check failures are reported through the node condition instead
+	// of aborting the daemon, so a broken environment is diagnosed from the
+	// UI rather than from a crash looping pod.
+	if err := runSanityChecks(logger, ds, currentNodeID); err != nil {
 		return err
 	}
 
+	// In recovery mode the engine image controller is not running, so a
+	// newly deployed engine image could never get ready anyway.
+	if !recoveryMode {
+		if err := m.DeployEngineImage(engineImage); err != nil {
+			return err
+		}
+	}
+
 	server := api.NewServer(m, wsc)
 	router := http.Handler(api.NewRouter(server))
+	router = api.NewAuthenticationHandler(m, router)
 	router = util.FilteredLoggingHandler(map[string]struct{}{
 		"/v1/apiversions":  {},
 		"/v1/schemas":      {},
@@ -187,11 +243,23 @@ func startManager(c *cli.Context) error {
 	}, os.Stdout, router)
 	router = handlers.ProxyHeaders(router)
 
-	listen := types.GetAPIServerAddressFromIP(currentIP)
+	apiPort, err := ds.GetSettingAsInt(types.SettingNameAPIPort)
+	if err != nil {
+		return err
+	}
+	listen := types.GetAPIServerAddress(currentIP, int(apiPort))
 	logger.Infof("Listening on %s", listen)
 
 	go http.ListenAndServe(listen, router)
 
+	grpcListen := types.GetAPIServerAddress(currentIP, rpc.DefaultPort)
+	logger.Infof("gRPC API listening on %s", grpcListen)
+	go func() {
+		if err := rpc.NewServer(m).ListenAndServe(grpcListen); err != nil {
+			logger.WithError(err).Errorf("Failed to serve the gRPC API on %s", grpcListen)
+		}
+	}()
+
 	go func() {
 		debugAddress := "127.0.0.1:6060"
 		debugHandler := http.DefaultServeMux
@@ -206,18 +274,6 @@ func startManager(c *cli.Context) error {
 	return nil
 }
 
-func environmentCheck() error {
-	initiatorNSPath := iscsi_util.GetHostNamespacePath(util.HostProcPath)
-	namespace, err := iscsi_util.NewNamespaceExecutor(initiatorNSPath)
-	if err != nil {
-		return err
-	}
-	if err := iscsi.CheckForInitiatorExistence(namespace); err != nil {
-		return err
-	}
-	return nil
-}
-
 func updateDefaultImageSetting(m *manager.VolumeManager, settingName types.SettingName, image string) error {
 	settingDefaultImage, err := m.GetSetting(settingName)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -176,6 +177,43 @@ func deployDriver(c *cli.Context) error {
 	return deployCSIDriver(kubeClient, lhClient, c, managerImage, managerURL)
 }
 
+// getLeaderElectionTimings reads the lease tuning for the leader elected CSI
+// sidecar components from the Longhorn settings
+func getLeaderElectionTimings(lhClient *lhclientset.Clientset, namespace string) (csi.LeaderElectionTimings, error) {
+	timings := csi.LeaderElectionTimings{}
+
+	getSecondsSetting := func(name types.SettingName) (int, error) {
+		setting, err := lhClient.LonghornV1beta2().Settings(namespace).Get(context.TODO(), string(name), metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		seconds, err := strconv.Atoi(setting.Value)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid value %v of setting %v", setting.Value, name)
+		}
+		return seconds, nil
+	}
+
+	var err error
+	if timings.LeaseDurationSec, err = getSecondsSetting(types.SettingNameLeaderElectionLeaseDuration); err != nil {
+		return timings, err
+	}
+	if timings.RenewDeadlineSec, err = getSecondsSetting(types.SettingNameLeaderElectionRenewDeadline); err != nil {
+		return timings, err
+	}
+	if timings.RetryPeriodSec, err = getSecondsSetting(types.SettingNameLeaderElectionRetryPeriod); err != nil {
+		return timings, err
+	}
+
+	if timings.LeaseDurationSec <= timings.RenewDeadlineSec {
+		return timings, fmt.Errorf("leader election lease duration %vs must be greater than the renew deadline %vs", timings.LeaseDurationSec, timings.RenewDeadlineSec)
+	}
+	if timings.RenewDeadlineSec <= timings.RetryPeriodSec {
+		return timings, fmt.Errorf("leader election renew deadline %vs must be greater than the retry period %vs", timings.RenewDeadlineSec, timings.RetryPeriodSec)
+	}
+	return timings, nil
+}
+
 func checkKubernetesVersion(kubeClient *clientset.Clientset) error {
 	serverVersion, err := kubeClient.Discovery().ServerVersion()
 	if err != nil {
@@ -257,6 +295,20 @@ func deployCSIDriver(kubeClient *clientset.Clientset, lhClient *lhclientset.Clie
 		return fmt.Errorf("invalid image pull policy %v", imagePullPolicySetting.Value)
 	}
 
+	csiPluginHostNetworkSetting, err := lhClient.LonghornV1beta2().Settings(namespace).Get(context.TODO(), string(types.SettingNameCSIPluginHostNetwork), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	csiPluginHostNetwork, err := strconv.ParseBool(csiPluginHostNetworkSetting.Value)
+	if err != nil {
+		return fmt.Errorf("invalid value %v of setting %v", csiPluginHostNetworkSetting.Value, types.SettingNameCSIPluginHostNetwork)
+	}
+
+	leaderElectionTimings, err := getLeaderElectionTimings(lhClient, namespace)
+	if err != nil {
+		return err
+	}
+
 	if rootDir == "" {
 		var err error
 		rootDir, err = getProcArg(kubeClient, managerImage, serviceAccountName, ArgKubeletRootDir, tolerations, priorityClass, registrySecret, nodeSelector)
@@ -278,27 +330,27 @@ func deployCSIDriver(kubeClient *clientset.Clientset, lhClient *lhclientset.Clie
 		return err
 	}
 
-	attacherDeployment := csi.NewAttacherDeployment(namespace, serviceAccountName, csiAttacherImage, rootDir, csiAttacherReplicaCount, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector)
+	attacherDeployment := csi.NewAttacherDeployment(namespace, serviceAccountName, csiAttacherImage, rootDir, csiAttacherReplicaCount, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector, leaderElectionTimings)
 	if err := attacherDeployment.Deploy(kubeClient); err != nil {
 		return err
 	}
 
-	provisionerDeployment := csi.NewProvisionerDeployment(namespace, serviceAccountName, csiProvisionerImage, rootDir, csiProvisionerReplicaCount, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector)
+	provisionerDeployment := csi.NewProvisionerDeployment(namespace, serviceAccountName, csiProvisionerImage, rootDir, csiProvisionerReplicaCount, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector, leaderElectionTimings)
 	if err := provisionerDeployment.Deploy(kubeClient); err != nil {
 		return err
 	}
 
-	resizerDeployment := csi.NewResizerDeployment(namespace, serviceAccountName, csiResizerImage, rootDir, csiResizerReplicaCount, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector)
+	resizerDeployment := csi.NewResizerDeployment(namespace, serviceAccountName, csiResizerImage, rootDir, csiResizerReplicaCount, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector, leaderElectionTimings)
 	if err := resizerDeployment.Deploy(kubeClient); err != nil {
 		return err
 	}
 
-	snapshotterDeployment := csi.NewSnapshotterDeployment(namespace, serviceAccountName, csiSnapshotterImage, rootDir, csiSnapshotterReplicaCount, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector)
+	snapshotterDeployment := csi.NewSnapshotterDeployment(namespace, serviceAccountName, csiSnapshotterImage, rootDir, csiSnapshotterReplicaCount, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector, leaderElectionTimings)
 	if err := snapshotterDeployment.Deploy(kubeClient); err != nil {
 		return err
 	}
 
-	pluginDeployment := csi.NewPluginDeployment(namespace, serviceAccountName, csiNodeDriverRegistrarImage, managerImage, managerURL, rootDir, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector)
+	pluginDeployment := csi.NewPluginDeployment(namespace, serviceAccountName, csiNodeDriverRegistrarImage, managerImage, managerURL, rootDir, tolerations, string(tolerationsByte), priorityClass, registrySecret, imagePullPolicy, nodeSelector, csiPluginHostNetwork)
 	if err := pluginDeployment.Deploy(kubeClient); err != nil {
 		return err
 	}
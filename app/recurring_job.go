@@ -139,14 +139,22 @@ func recurringJob(c *cli.Context) error {
 	}
 	filteredVolumes := []string{}
 	filterVolumesForJob(allowDetached, volumes, &filteredVolumes)
+	// The members of a group are processed as one unit so that their snapshots
+	// form a consistent multi-volume restore point; a volume that is both
+	// labeled for the job directly and a group member only takes part in the
+	// group run.
+	groupMembers := map[string][]string{}
 	for _, jobGroup := range jobGroups {
 		volumes, err := getVolumesBySelector(types.LonghornLabelRecurringJobGroup, jobGroup, namespace, lhClient)
 		if err != nil {
 			return err
 		}
-		filterVolumesForJob(allowDetached, volumes, &filteredVolumes)
+		members := []string{}
+		filterVolumesForJob(allowDetached, volumes, &members)
+		groupMembers[jobGroup] = members
+		filteredVolumes = excludeVolumes(filteredVolumes, members)
 	}
-	logger.Infof("Found %v volumes with recurring job %v", len(filteredVolumes), jobName)
+	logger.Infof("Found %v volumes and %v groups with recurring job %v", len(filteredVolumes), len(groupMembers), jobName)
 
 	var wg sync.WaitGroup
 	concurrentLimiter := make(chan struct{}, jobConcurrent)
@@ -194,6 +202,39 @@ func recurringJob(c *cli.Context) error {
 		}(volumeName)
 	}
 
+	for jobGroup, members := range groupMembers {
+		if len(members) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(jobGroup string, members []string) {
+			defer wg.Done()
+
+			log := logger.WithFields(logrus.Fields{
+				"job":        jobName,
+				"group":      jobGroup,
+				"volumes":    strings.Join(members, ","),
+				"task":       jobTask,
+				"retain":     jobRetain,
+				"concurrent": jobConcurrent,
+				"labels":     string(labelJSON),
+			})
+			log.Info("Creating group job")
+
+			job, err := newGroupJob(logger, managerURL, jobName, jobGroup, members, jobLabelMap, jobRetain, doBackup)
+			if err != nil {
+				log.WithError(err).Error("failed to create new job for group")
+				return
+			}
+			if err := job.run(concurrentLimiter); err != nil {
+				log.WithError(err).Error("failed to run job for group")
+				return
+			}
+
+			log.Info("Created group job")
+		}(jobGroup, members)
+	}
+
 	return nil
 }
 
@@ -312,6 +353,31 @@ func (job *Job) run() (err error) {
 
 	defer job.handleVolumeDetachment()
 
+	if err := job.ensureVolumeAttached(); err != nil {
+		return err
+	}
+
+	if job.jobType == jobTypeBackup {
+		job.logger.Infof("Running recurring backup for volume %v", volumeName)
+		return job.doRecurringBackup()
+	}
+	job.logger.Infof("Running recurring snapshot for volume %v", volumeName)
+	return job.doRecurringSnapshot()
+}
+
+// ensureVolumeAttached makes sure the volume is in a state the recurring job
+// can work with and attaches it with the frontend disabled when it is
+// currently detached.
+func (job *Job) ensureVolumeAttached() error {
+	volumeAPI := job.api.Volume
+	volumeName := job.volumeName
+	jobName, _ := job.labels[types.RecurringJobLabel]
+
+	volume, err := volumeAPI.ById(volumeName)
+	if err != nil {
+		return errors.Wrapf(err, "could not get volume %v", volumeName)
+	}
+
 	if volume.State != string(longhorn.VolumeStateAttached) && volume.State != string(longhorn.VolumeStateDetached) {
 		return fmt.Errorf("volume %v is in an invalid state for recurring job: %v. Volume must be in state Attached or Detached", volumeName, volume.State)
 	}
@@ -343,12 +409,7 @@ func (job *Job) run() (err error) {
 		job.logger.Infof("Volume %v is in state %v", volumeName, volume.State)
 	}
 
-	if job.jobType == jobTypeBackup {
-		job.logger.Infof("Running recurring backup for volume %v", volumeName)
-		return job.doRecurringBackup()
-	}
-	job.logger.Infof("Running recurring snapshot for volume %v", volumeName)
-	return job.doRecurringSnapshot()
+	return nil
 }
 
 func (job *Job) doRecurringSnapshot() (err error) {
@@ -399,7 +460,15 @@ func (job *Job) doSnapshotCleanup(backupDone bool) (err error) {
 		return err
 	}
 
-	cleanupSnapshotNames := job.listSnapshotNamesForCleanup(collection.Data, backupDone)
+	return job.deleteSnapshots(volume, job.listSnapshotNamesForCleanup(collection.Data, backupDone))
+}
+
+// deleteSnapshots removes the given snapshots from the volume and waits for
+// the follow-up purge to finish.
+func (job *Job) deleteSnapshots(volume *longhornclient.Volume, cleanupSnapshotNames []string) error {
+	volumeAPI := job.api.Volume
+	volumeName := job.volumeName
+
 	for _, snapshot := range cleanupSnapshotNames {
 		if _, err := volumeAPI.ActionSnapshotDelete(volume, &longhornclient.SnapshotInput{
 			Name: snapshot,
@@ -499,7 +568,18 @@ func (job *Job) listSnapshotNamesForCleanup(snapshots []longhornclient.Snapshot,
 	snapshots = filterSnapshotsWithLabel(snapshots, types.RecurringJobLabel, jobLabel)
 
 	if job.jobType == jobTypeSnapshot {
-		return filterExpiredItems(snapshotsToNameWithTimestamps(snapshots), job.retain)
+		// The snapshot timestamps come from the clock of whichever node the engine
+		// was running on when the snapshot was taken. If the node clocks are skewed,
+		// the snapshot just created by this run may sort below older ones, so never
+		// let it expire here.
+		expiredNames := []string{}
+		for _, name := range filterExpiredItems(snapshotsToNameWithTimestamps(snapshots), job.retain) {
+			if name == job.snapshotName {
+				continue
+			}
+			expiredNames = append(expiredNames, name)
+		}
+		return expiredNames
 	}
 
 	// For the recurring backup job, only keep the snapshot of the last backup and the current snapshot
@@ -519,7 +599,25 @@ func (job *Job) doRecurringBackup() (err error) {
 			job.logger.Info("Finish running recurring backup")
 		}
 	}()
-	backupAPI := job.api.BackupVolume
+
+	if err := job.doSnapshot(); err != nil {
+		return err
+	}
+
+	if err := job.doSnapshotCleanup(false); err != nil {
+		return err
+	}
+
+	if err := job.doBackup(); err != nil {
+		return err
+	}
+
+	return job.doBackupCleanup()
+}
+
+// doBackup backs up the snapshot of this job run and waits for the backup to
+// complete.
+func (job *Job) doBackup() (err error) {
 	volumeAPI := job.api.Volume
 	snapshot := job.snapshotName
 	volumeName := job.volumeName
@@ -533,14 +631,6 @@ func (job *Job) doRecurringBackup() (err error) {
 		return errors.Wrapf(err, "could not get volume %v", volumeName)
 	}
 
-	if err := job.doSnapshot(); err != nil {
-		return err
-	}
-
-	if err := job.doSnapshotCleanup(false); err != nil {
-		return err
-	}
-
 	if _, err := volumeAPI.ActionSnapshotBackup(volume, &longhornclient.SnapshotInput{
 		Labels: job.labels,
 		Name:   snapshot,
@@ -602,6 +692,16 @@ func (job *Job) doRecurringBackup() (err error) {
 		time.Sleep(WaitInterval)
 	}
 
+	return nil
+}
+
+// doBackupCleanup applies the backup retention for this job on the volume and
+// then removes the snapshots that are no longer needed. Since the backup
+// itself already succeeded, cleanup errors are logged instead of reported.
+func (job *Job) doBackupCleanup() (err error) {
+	backupAPI := job.api.BackupVolume
+	volumeName := job.volumeName
+
 	defer func() {
 		if err != nil {
 			job.logger.Warnf("created backup successfully but errored on cleanup for %v: %v", volumeName, err)
@@ -633,6 +733,295 @@ func (job *Job) doRecurringBackup() (err error) {
 	return nil
 }
 
+// groupJob coordinates one run of a recurring job over the member volumes of
+// a volume group. All members share a single snapshot name, so their
+// snapshots and the backups taken from them form one multi-volume restore
+// point that is created and expired as a unit.
+type groupJob struct {
+	logger       logrus.FieldLogger
+	group        string
+	snapshotName string
+	retain       int
+	doBackup     bool
+	jobs         []*Job
+}
+
+func newGroupJob(logger logrus.FieldLogger, managerURL, jobName, jobGroup string, members []string, jobLabels map[string]string, retain int, backup bool) (*groupJob, error) {
+	snapshotName := types.GetCronJobNameForRecurringJob(jobName) + "-" + util.RandomID()
+
+	labels := map[string]string{}
+	for k, v := range jobLabels {
+		labels[k] = v
+	}
+	labels[types.RecurringJobGroupLabel] = jobGroup
+
+	jobs := make([]*Job, 0, len(members))
+	for _, volumeName := range members {
+		job, err := NewJob(logger, managerURL, volumeName, snapshotName, labels, retain, backup)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create job for group member volume %v", volumeName)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return &groupJob{
+		logger:       logger.WithField("group", jobGroup),
+		group:        jobGroup,
+		snapshotName: snapshotName,
+		retain:       retain,
+		doBackup:     backup,
+		jobs:         jobs,
+	}, nil
+}
+
+func (g *groupJob) run(concurrentLimiter chan struct{}) (err error) {
+	g.logger.Info("group job starts running")
+
+	defer g.detachMembers()
+
+	if !g.snapshotMembers() {
+		return fmt.Errorf("failed to snapshot all members of group %v, aborted the group run", g.group)
+	}
+
+	if g.doBackup {
+		if !g.backupMembers(concurrentLimiter) {
+			return fmt.Errorf("failed to back up all members of group %v, skipping the retention for this run", g.group)
+		}
+		g.cleanupBackups()
+		return nil
+	}
+
+	g.cleanupSnapshots()
+	return nil
+}
+
+// snapshotMembers attaches every member and takes the shared snapshot on all
+// of them at once, before any backup starts, so the restore point is cut at
+// (nearly) the same instant across the whole group. The concurrency limit is
+// deliberately not applied here; serializing the snapshots would spread the
+// cut over time. A partial set of snapshots is not a usable restore point, so
+// on any failure the snapshots that were taken are rolled back.
+func (g *groupJob) snapshotMembers() bool {
+	var wg sync.WaitGroup
+	snapshotted := make([]bool, len(g.jobs))
+
+	for i, job := range g.jobs {
+		wg.Add(1)
+		go func(i int, job *Job) {
+			defer wg.Done()
+			if err := job.ensureVolumeAttached(); err != nil {
+				job.logger.WithError(err).Error("failed to attach group member volume")
+				return
+			}
+			if err := job.doSnapshot(); err != nil {
+				job.logger.WithError(err).Error("failed to snapshot group member volume")
+				return
+			}
+			snapshotted[i] = true
+		}(i, job)
+	}
+	wg.Wait()
+
+	failed := false
+	for _, ok := range snapshotted {
+		if !ok {
+			failed = true
+			break
+		}
+	}
+
+	if failed {
+		for i, job := range g.jobs {
+			if !snapshotted[i] {
+				continue
+			}
+			volume, err := job.api.Volume.ById(job.volumeName)
+			if err != nil {
+				job.logger.WithError(err).Warnf("failed to get volume %v to roll back the group snapshot", job.volumeName)
+				continue
+			}
+			if err := job.deleteSnapshots(volume, []string{g.snapshotName}); err != nil {
+				job.logger.WithError(err).Warnf("failed to roll back the group snapshot %v", g.snapshotName)
+			}
+		}
+	}
+	return !failed
+}
+
+// backupMembers backs up the shared snapshot of every member, honoring the
+// job concurrency limit since the restore point is already cut.
+func (g *groupJob) backupMembers(concurrentLimiter chan struct{}) bool {
+	var wg sync.WaitGroup
+	backedUp := make([]bool, len(g.jobs))
+
+	for i, job := range g.jobs {
+		wg.Add(1)
+		go func(i int, job *Job) {
+			concurrentLimiter <- struct{}{}
+			defer func() {
+				<-concurrentLimiter
+				wg.Done()
+			}()
+			if err := job.doBackup(); err != nil {
+				job.logger.WithError(err).Error("failed to back up group member volume")
+				return
+			}
+			backedUp[i] = true
+		}(i, job)
+	}
+	wg.Wait()
+
+	for _, ok := range backedUp {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanupBackups expires the group restore points as a unit: the runs, which
+// are identified by the snapshot name shared across the members, are collected
+// from all member backups, and only the backups of runs beyond the retain
+// count are deleted. A run is therefore expired either on every member or on
+// none, and the surviving backups always form complete restore points.
+func (g *groupJob) cleanupBackups() {
+	jobLabel := ""
+	if len(g.jobs) > 0 {
+		jobLabel = g.jobs[0].labels[types.RecurringJobLabel]
+	}
+
+	runs := map[string]time.Time{}
+	memberBackups := make([][]longhornclient.Backup, len(g.jobs))
+	backupVolumes := make([]*longhornclient.BackupVolume, len(g.jobs))
+	for i, job := range g.jobs {
+		backupVolume, err := job.api.BackupVolume.ById(job.volumeName)
+		if err != nil {
+			g.logger.WithError(err).Warnf("failed to get the backup volume %v, skipping the group retention for this run", job.volumeName)
+			return
+		}
+		backups, err := job.api.BackupVolume.ActionBackupList(backupVolume)
+		if err != nil {
+			g.logger.WithError(err).Warnf("failed to list backups of volume %v, skipping the group retention for this run", job.volumeName)
+			return
+		}
+		backupVolumes[i] = backupVolume
+		for _, backup := range backups.Data {
+			if backup.Labels[types.RecurringJobLabel] != jobLabel || backup.Labels[types.RecurringJobGroupLabel] != g.group {
+				continue
+			}
+			memberBackups[i] = append(memberBackups[i], backup)
+			t, err := time.Parse(time.RFC3339, backup.Created)
+			if err != nil {
+				g.logger.Errorf("Fail to parse datetime %v for backup %v", backup.Created, backup.Name)
+				continue
+			}
+			if created, ok := runs[backup.SnapshotName]; !ok || t.Before(created) {
+				runs[backup.SnapshotName] = t
+			}
+		}
+	}
+
+	expired := expiredRuns(runs, g.retain, g.snapshotName)
+
+	for i, job := range g.jobs {
+		for _, backup := range memberBackups[i] {
+			if _, ok := expired[backup.SnapshotName]; !ok {
+				continue
+			}
+			if _, err := job.api.BackupVolume.ActionBackupDelete(backupVolumes[i], &longhornclient.BackupInput{
+				Name: backup.Name,
+			}); err != nil {
+				job.logger.WithError(err).Warnf("failed to clean up backup %v for %v", backup.Name, job.volumeName)
+				continue
+			}
+			job.logger.Debugf("Cleaned up backup %v for %v", backup.Name, job.volumeName)
+		}
+		if err := job.doSnapshotCleanup(true); err != nil {
+			job.logger.WithError(err).Warnf("failed to clean up snapshots for %v", job.volumeName)
+		}
+	}
+}
+
+// cleanupSnapshots is the snapshot task counterpart of cleanupBackups: the
+// group snapshot restore points are expired as a unit across all members.
+func (g *groupJob) cleanupSnapshots() {
+	jobLabel := ""
+	if len(g.jobs) > 0 {
+		jobLabel = g.jobs[0].labels[types.RecurringJobLabel]
+	}
+
+	runs := map[string]time.Time{}
+	memberSnapshots := make([][]longhornclient.Snapshot, len(g.jobs))
+	volumes := make([]*longhornclient.Volume, len(g.jobs))
+	for i, job := range g.jobs {
+		volume, err := job.api.Volume.ById(job.volumeName)
+		if err != nil {
+			g.logger.WithError(err).Warnf("failed to get the volume %v, skipping the group retention for this run", job.volumeName)
+			return
+		}
+		collection, err := job.api.Volume.ActionSnapshotList(volume)
+		if err != nil {
+			g.logger.WithError(err).Warnf("failed to list snapshots of volume %v, skipping the group retention for this run", job.volumeName)
+			return
+		}
+		volumes[i] = volume
+		snapshots := filterSnapshotsWithLabel(collection.Data, types.RecurringJobLabel, jobLabel)
+		snapshots = filterSnapshotsWithLabel(snapshots, types.RecurringJobGroupLabel, g.group)
+		memberSnapshots[i] = snapshots
+		for _, nt := range snapshotsToNameWithTimestamps(snapshots) {
+			if created, ok := runs[nt.Name]; !ok || nt.Timestamp.Before(created) {
+				runs[nt.Name] = nt.Timestamp
+			}
+		}
+	}
+
+	expired := expiredRuns(runs, g.retain, g.snapshotName)
+
+	for i, job := range g.jobs {
+		cleanupSnapshotNames := []string{}
+		for _, snapshot := range memberSnapshots[i] {
+			if _, ok := expired[snapshot.Name]; ok {
+				cleanupSnapshotNames = append(cleanupSnapshotNames, snapshot.Name)
+			}
+		}
+		if err := job.deleteSnapshots(volumes[i], cleanupSnapshotNames); err != nil {
+			job.logger.WithError(err).Warnf("failed to clean up snapshots for %v", job.volumeName)
+		}
+	}
+}
+
+// detachMembers detaches the member volumes this run attached, mirroring the
+// deferred detachment of an individual job.
+func (g *groupJob) detachMembers() {
+	var wg sync.WaitGroup
+	for _, job := range g.jobs {
+		wg.Add(1)
+		go func(job *Job) {
+			defer wg.Done()
+			job.handleVolumeDetachment()
+		}(job)
+	}
+	wg.Wait()
+}
+
+// expiredRuns returns the run identities beyond the retain count as a set.
+// The run of the current job execution never expires here, for the same clock
+// skew reason as in listSnapshotNamesForCleanup.
+func expiredRuns(runs map[string]time.Time, retain int, currentRun string) map[string]struct{} {
+	nts := []NameWithTimestamp{}
+	for name, created := range runs {
+		nts = append(nts, NameWithTimestamp{Name: name, Timestamp: created})
+	}
+	expired := map[string]struct{}{}
+	for _, name := range filterExpiredItems(nts, retain) {
+		if name == currentRun {
+			continue
+		}
+		expired[name] = struct{}{}
+	}
+	return expired
+}
+
 // waitForBackupProcessStart timeout in second
 // Return nil if the backup progress has started; error if error or timeout
 func (job *Job) waitForBackupProcessStart(timeout int) error {
@@ -813,7 +1202,12 @@ func filterSnapshotsNotInTargets(snapshots []longhornclient.Snapshot, targets ma
 
 // filterExpiredItems returns a list of names from the input sts excluding the latest retainCount names
 func filterExpiredItems(nts []NameWithTimestamp, retainCount int) []string {
+	// The timestamps only have second granularity and may come from different
+	// node clocks, so break ties by name to keep the expiration order deterministic.
 	sort.Slice(nts, func(i, j int) bool {
+		if nts[i].Timestamp.Equal(nts[j].Timestamp) {
+			return nts[i].Name < nts[j].Name
+		}
 		return nts[i].Timestamp.Before(nts[j].Timestamp)
 	})
 
@@ -865,6 +1259,18 @@ func filterVolumesForJob(allowDetached bool, volumes []longhorn.Volume, filterNa
 	}
 }
 
+// excludeVolumes returns names without the excluded volume names
+func excludeVolumes(names, excluded []string) []string {
+	remaining := []string{}
+	for _, name := range names {
+		if util.Contains(excluded, name) {
+			continue
+		}
+		remaining = append(remaining, name)
+	}
+	return remaining
+}
+
 func getVolumesBySelector(recurringJobType, recurringJobName, namespace string, client *lhclientset.Clientset) ([]longhorn.Volume, error) {
 	logger := logrus.StandardLogger()
 
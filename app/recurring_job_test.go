@@ -0,0 +1,35 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterExpiredItems(t *testing.T) {
+	assert := require.New(t)
+
+	base := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	nts := []NameWithTimestamp{
+		{Name: "newest", Timestamp: base.Add(3 * time.Hour)},
+		{Name: "oldest", Timestamp: base},
+		{Name: "middle", Timestamp: base.Add(2 * time.Hour)},
+	}
+
+	// The oldest items beyond the retain count expire, oldest first.
+	assert.Equal([]string{"oldest"}, filterExpiredItems(nts, 2))
+	assert.Equal([]string{"oldest", "middle"}, filterExpiredItems(nts, 1))
+
+	// Nothing expires when everything fits in the retain count.
+	assert.Equal([]string{}, filterExpiredItems(nts, 3))
+	assert.Equal([]string{}, filterExpiredItems(nts, 10))
+
+	// Equal timestamps are ordered by name so the result is deterministic.
+	tied := []NameWithTimestamp{
+		{Name: "b", Timestamp: base},
+		{Name: "a", Timestamp: base},
+		{Name: "c", Timestamp: base},
+	}
+	assert.Equal([]string{"a", "b"}, filterExpiredItems(tied, 1))
+}
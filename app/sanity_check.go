@@ -0,0 +1,171 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/go-iscsi-helper/iscsi"
+	iscsi_util "github.com/longhorn/go-iscsi-helper/util"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+const (
+	sanityCheckWebhookDialTimeout = 5 * time.Second
+)
+
+// sanityCheck is a single startup validation. The message returned on failure
+// is surfaced to the user verbatim through the node condition, so it should
+// spell out how to fix the problem rather than just what went wrong.
+type sanityCheck struct {
+	name string
+	run  func() error
+}
+
+// runSanityChecks validates the prerequisites of the installation and
+// publishes the aggregate result as a condition on this node, so a broken
+// environment shows up as one actionable report in the UI instead of the
+// manager crash looping on whichever prerequisite happens to fail first.
+func runSanityChecks(logger logrus.FieldLogger, ds *datastore.DataStore, nodeID string) error {
+	checks := []sanityCheck{
+		{
+			name: "kernel-prerequisites",
+			run:  checkKernelPrerequisites,
+		},
+		{
+			name: "crd-api-version",
+			run:  func() error { return checkCRDAPIVersion(ds) },
+		},
+		{
+			name: "disk-paths",
+			run:  func() error { return checkDiskPaths(ds, nodeID) },
+		},
+		{
+			name: "admission-webhook",
+			run:  checkAdmissionWebhookReachable,
+		},
+		{
+			name: "settings",
+			run:  func() error { return checkSettingsConsistency(ds) },
+		},
+	}
+
+	failures := []string{}
+	for _, check := range checks {
+		if err := check.run(); err != nil {
+			logger.Errorf("Sanity check %v failed: %v", check.name, err)
+			failures = append(failures, fmt.Sprintf("%v: %v", check.name, err))
+		}
+	}
+
+	node, err := ds.GetNode(nodeID)
+	if err != nil {
+		return err
+	}
+	if len(failures) == 0 {
+		node.Status.Conditions = types.SetCondition(node.Status.Conditions,
+			longhorn.NodeConditionTypeSanityCheck, longhorn.ConditionStatusTrue,
+			"", fmt.Sprintf("All %v sanity checks passed", len(checks)))
+	} else {
+		node.Status.Conditions = types.SetCondition(node.Status.Conditions,
+			longhorn.NodeConditionTypeSanityCheck, longhorn.ConditionStatusFalse,
+			longhorn.NodeConditionReasonSanityCheckFailed, strings.Join(failures, "; "))
+	}
+	if _, err := ds.UpdateNodeStatus(node); err != nil {
+		return err
+	}
+	return nil
+}
+
+func checkKernelPrerequisites() error {
+	initiatorNSPath := iscsi_util.GetHostNamespacePath(util.HostProcPath)
+	namespace, err := iscsi_util.NewNamespaceExecutor(initiatorNSPath)
+	if err != nil {
+		return err
+	}
+	if err := iscsi.CheckForInitiatorExistence(namespace); err != nil {
+		return fmt.Errorf("the iscsi initiator is not available, please make sure iscsiadm/open-iscsi is installed on the host: %v", err)
+	}
+	return nil
+}
+
+func checkCRDAPIVersion(ds *datastore.DataStore) error {
+	crdAPIVersion, err := ds.GetSettingValueExisted(types.SettingNameCRDAPIVersion)
+	if err != nil {
+		return err
+	}
+	if crdAPIVersion != "" && crdAPIVersion != types.CurrentCRDAPIVersion {
+		return fmt.Errorf("the installed CRDs are at %v but this manager requires %v, please complete the resource upgrade before restarting the manager", crdAPIVersion, types.CurrentCRDAPIVersion)
+	}
+	return nil
+}
+
+func checkDiskPaths(ds *datastore.DataStore, nodeID string) error {
+	node, err := ds.GetNode(nodeID)
+	if err != nil {
+		return err
+	}
+	paths := []string{}
+	for _, disk := range node.Spec.Disks {
+		paths = append(paths, disk.Path)
+	}
+	sort.Strings(paths)
+	unusable := []string{}
+	for _, path := range paths {
+		if _, err := util.GetDiskInfo(path); err != nil {
+			unusable = append(unusable, fmt.Sprintf("%v (%v)", path, err))
+		}
+	}
+	if len(unusable) > 0 {
+		return fmt.Errorf("cannot use disk path(s) %v, please make sure the paths exist on the host and are writable", strings.Join(unusable, ", "))
+	}
+	return nil
+}
+
+func checkAdmissionWebhookReachable() error {
+	namespace := os.Getenv(types.EnvPodNamespace)
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+	endpoint := fmt.Sprintf("%v.%v.svc:%v", DefaultAdmissionWebhookService, namespace, DefaultAdmissionWebhookPort)
+	conn, err := net.DialTimeout("tcp", endpoint, sanityCheckWebhookDialTimeout)
+	if err != nil {
+		return fmt.Errorf("cannot reach the admission webhook at %v, please make sure the %v service and its pods are running: %v", endpoint, DefaultAdmissionWebhookService, err)
+	}
+	return conn.Close()
+}
+
+func checkSettingsConsistency(ds *datastore.DataStore) error {
+	settings, err := ds.ListSettings()
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for name := range settings {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	invalid := []string{}
+	for _, name := range names {
+		setting := settings[types.SettingName(name)]
+		if err := types.ValidateInitSetting(name, setting.Value); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%v (%v)", name, err))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("setting(s) %v have invalid values, please correct them before using the affected features", strings.Join(invalid, ", "))
+	}
+	return nil
+}
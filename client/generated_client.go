@@ -27,6 +27,7 @@ type RancherClient struct {
 	UpdateReplicaAutoBalanceInput UpdateReplicaAutoBalanceInputOperations
 	UpdateDataLocalityInput       UpdateDataLocalityInputOperations
 	UpdateAccessModeInput         UpdateAccessModeInputOperations
+	UpdateLastKeyRotatedAtInput   UpdateLastKeyRotatedAtInputOperations
 	WorkloadStatus                WorkloadStatusOperations
 	CloneStatus                   CloneStatusOperations
 	VolumeRecurringJob            VolumeRecurringJobOperations
@@ -87,6 +88,7 @@ func constructClient(rancherBaseClient *RancherBaseClientImpl) *RancherClient {
 	client.UpdateReplicaAutoBalanceInput = newUpdateReplicaAutoBalanceInputClient(client)
 	client.UpdateDataLocalityInput = newUpdateDataLocalityInputClient(client)
 	client.UpdateAccessModeInput = newUpdateAccessModeInputClient(client)
+	client.UpdateLastKeyRotatedAtInput = newUpdateLastKeyRotatedAtInputClient(client)
 	client.WorkloadStatus = newWorkloadStatusClient(client)
 	client.CloneStatus = newCloneStatusClient(client)
 	client.VolumeRecurringJob = newVolumeRecurringJobClient(client)
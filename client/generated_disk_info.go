@@ -15,6 +15,8 @@ type DiskInfo struct {
 
 	EvictionRequested bool `json:"evictionRequested,omitempty" yaml:"eviction_requested,omitempty"`
 
+	EvictionRequestedReplicaCount int64 `json:"evictionRequestedReplicaCount,omitempty" yaml:"eviction_requested_replica_count,omitempty"`
+
 	Path string `json:"path,omitempty" yaml:"path,omitempty"`
 
 	ScheduledReplica map[string]string `json:"scheduledReplica,omitempty" yaml:"scheduled_replica,omitempty"`
@@ -0,0 +1,79 @@
+package client
+
+const (
+	UPDATE_LAST_KEY_ROTATED_AT_INPUT_TYPE = "UpdateLastKeyRotatedAtInput"
+)
+
+type UpdateLastKeyRotatedAtInput struct {
+	Resource `yaml:"-"`
+
+	LastKeyRotatedAt string `json:"lastKeyRotatedAt,omitempty" yaml:"last_key_rotated_at,omitempty"`
+}
+
+type UpdateLastKeyRotatedAtInputCollection struct {
+	Collection
+	Data   []UpdateLastKeyRotatedAtInput `json:"data,omitempty"`
+	client *UpdateLastKeyRotatedAtInputClient
+}
+
+type UpdateLastKeyRotatedAtInputClient struct {
+	rancherClient *RancherClient
+}
+
+type UpdateLastKeyRotatedAtInputOperations interface {
+	List(opts *ListOpts) (*UpdateLastKeyRotatedAtInputCollection, error)
+	Create(opts *UpdateLastKeyRotatedAtInput) (*UpdateLastKeyRotatedAtInput, error)
+	Update(existing *UpdateLastKeyRotatedAtInput, updates interface{}) (*UpdateLastKeyRotatedAtInput, error)
+	ById(id string) (*UpdateLastKeyRotatedAtInput, error)
+	Delete(container *UpdateLastKeyRotatedAtInput) error
+}
+
+func newUpdateLastKeyRotatedAtInputClient(rancherClient *RancherClient) *UpdateLastKeyRotatedAtInputClient {
+	return &UpdateLastKeyRotatedAtInputClient{
+		rancherClient: rancherClient,
+	}
+}
+
+func (c *UpdateLastKeyRotatedAtInputClient) Create(container *UpdateLastKeyRotatedAtInput) (*UpdateLastKeyRotatedAtInput, error) {
+	resp := &UpdateLastKeyRotatedAtInput{}
+	err := c.rancherClient.doCreate(UPDATE_LAST_KEY_ROTATED_AT_INPUT_TYPE, container, resp)
+	return resp, err
+}
+
+func (c *UpdateLastKeyRotatedAtInputClient) Update(existing *UpdateLastKeyRotatedAtInput, updates interface{}) (*UpdateLastKeyRotatedAtInput, error) {
+	resp := &UpdateLastKeyRotatedAtInput{}
+	err := c.rancherClient.doUpdate(UPDATE_LAST_KEY_ROTATED_AT_INPUT_TYPE, &existing.Resource, updates, resp)
+	return resp, err
+}
+
+func (c *UpdateLastKeyRotatedAtInputClient) List(opts *ListOpts) (*UpdateLastKeyRotatedAtInputCollection, error) {
+	resp := &UpdateLastKeyRotatedAtInputCollection{}
+	err := c.rancherClient.doList(UPDATE_LAST_KEY_ROTATED_AT_INPUT_TYPE, opts, resp)
+	resp.client = c
+	return resp, err
+}
+
+func (cc *UpdateLastKeyRotatedAtInputCollection) Next() (*UpdateLastKeyRotatedAtInputCollection, error) {
+	if cc != nil && cc.Pagination != nil && cc.Pagination.Next != "" {
+		resp := &UpdateLastKeyRotatedAtInputCollection{}
+		err := cc.client.rancherClient.doNext(cc.Pagination.Next, resp)
+		resp.client = cc.client
+		return resp, err
+	}
+	return nil, nil
+}
+
+func (c *UpdateLastKeyRotatedAtInputClient) ById(id string) (*UpdateLastKeyRotatedAtInput, error) {
+	resp := &UpdateLastKeyRotatedAtInput{}
+	err := c.rancherClient.doById(UPDATE_LAST_KEY_ROTATED_AT_INPUT_TYPE, id, resp)
+	if apiError, ok := err.(*ApiError); ok {
+		if apiError.StatusCode == 404 {
+			return nil, nil
+		}
+	}
+	return resp, err
+}
+
+func (c *UpdateLastKeyRotatedAtInputClient) Delete(container *UpdateLastKeyRotatedAtInput) error {
+	return c.rancherClient.doResourceDelete(UPDATE_LAST_KEY_ROTATED_AT_INPUT_TYPE, &container.Resource)
+}
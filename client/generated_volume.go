@@ -11,8 +11,12 @@ type Volume struct {
 
 	BackingImage string `json:"backingImage,omitempty" yaml:"backing_image,omitempty"`
 
+	BackupCompressionMethod string `json:"backupCompressionMethod,omitempty" yaml:"backup_compression_method,omitempty"`
+
 	BackupStatus []BackupStatus `json:"backupStatus,omitempty" yaml:"backup_status,omitempty"`
 
+	BackupTargetName string `json:"backupTargetName,omitempty" yaml:"backup_target_name,omitempty"`
+
 	CloneStatus CloneStatus `json:"cloneStatus,omitempty" yaml:"clone_status,omitempty"`
 
 	Conditions map[string]interface{} `json:"conditions,omitempty" yaml:"conditions,omitempty"`
@@ -29,6 +33,8 @@ type Volume struct {
 
 	DisableFrontend bool `json:"disableFrontend,omitempty" yaml:"disable_frontend,omitempty"`
 
+	DiskGroup string `json:"diskGroup,omitempty" yaml:"disk_group,omitempty"`
+
 	DiskSelector []string `json:"diskSelector,omitempty" yaml:"disk_selector,omitempty"`
 
 	Encrypted bool `json:"encrypted,omitempty" yaml:"encrypted,omitempty"`
@@ -47,6 +53,14 @@ type Volume struct {
 
 	LastBackupAt string `json:"lastBackupAt,omitempty" yaml:"last_backup_at,omitempty"`
 
+	LastKeyRotatedAt string `json:"lastKeyRotatedAt,omitempty" yaml:"last_key_rotated_at,omitempty"`
+
+	LastRestoredBackup string `json:"lastRestoredBackup,omitempty" yaml:"last_restored_backup,omitempty"`
+
+	Maintenance bool `json:"maintenance,omitempty" yaml:"maintenance,omitempty"`
+
+	MaintenanceExpiresAt string `json:"maintenanceExpiresAt,omitempty" yaml:"maintenance_expires_at,omitempty"`
+
 	Migratable bool `json:"migratable,omitempty" yaml:"migratable,omitempty"`
 
 	Name string `json:"name,omitempty" yaml:"name,omitempty"`
@@ -87,6 +101,8 @@ type Volume struct {
 
 	Standby bool `json:"standby,omitempty" yaml:"standby,omitempty"`
 
+	StrictProvisioning bool `json:"strictProvisioning,omitempty" yaml:"strict_provisioning,omitempty"`
+
 	State string `json:"state,omitempty" yaml:"state,omitempty"`
 }
 
@@ -146,6 +162,8 @@ type VolumeOperations interface {
 	ActionSnapshotRevert(*Volume, *SnapshotInput) (*Snapshot, error)
 
 	ActionUpdateAccessMode(*Volume, *UpdateAccessModeInput) (*Volume, error)
+
+	ActionUpdateLastKeyRotatedAt(*Volume, *UpdateLastKeyRotatedAtInput) (*Volume, error)
 }
 
 func newVolumeClient(rancherClient *RancherClient) *VolumeClient {
@@ -377,3 +395,12 @@ func (c *VolumeClient) ActionUpdateAccessMode(resource *Volume, input *UpdateAcc
 
 	return resp, err
 }
+
+func (c *VolumeClient) ActionUpdateLastKeyRotatedAt(resource *Volume, input *UpdateLastKeyRotatedAtInput) (*Volume, error) {
+
+	resp := &Volume{}
+
+	err := c.rancherClient.doAction(VOLUME_TYPE, "updateLastKeyRotatedAt", &resource.Resource, input, resp)
+
+	return resp, err
+}
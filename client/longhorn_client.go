@@ -0,0 +1,166 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// LonghornClient is a hand-written facade over the generated RancherClient.
+// It exposes typed helpers for the resources external tools commonly touch,
+// so they can depend on this package instead of copying the schema code. The
+// embedded RancherClient remains available for the less common operations.
+type LonghornClient struct {
+	*RancherClient
+}
+
+// NewLonghornClient creates a client for the Longhorn API at the given
+// manager URL, e.g. "http://longhorn-backend:9500/v1".
+func NewLonghornClient(managerURL string) (*LonghornClient, error) {
+	rancherClient, err := NewRancherClient(&ClientOpts{Url: managerURL})
+	if err != nil {
+		return nil, err
+	}
+	return &LonghornClient{RancherClient: rancherClient}, nil
+}
+
+// GetVolume returns the volume with the given name, or an error if it does
+// not exist.
+func (c *LonghornClient) GetVolume(name string) (*Volume, error) {
+	return c.Volume.ById(name)
+}
+
+// ListVolumes returns all the volumes.
+func (c *LonghornClient) ListVolumes() ([]Volume, error) {
+	collection, err := c.Volume.List(&ListOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return collection.Data, nil
+}
+
+// CreateVolume creates the given volume and returns it with the fields the
+// manager filled in.
+func (c *LonghornClient) CreateVolume(volume *Volume) (*Volume, error) {
+	return c.Volume.Create(volume)
+}
+
+// DeleteVolume deletes the volume with the given name.
+func (c *LonghornClient) DeleteVolume(name string) error {
+	volume, err := c.GetVolume(name)
+	if err != nil {
+		return err
+	}
+	return c.Volume.Delete(volume)
+}
+
+// AttachVolume attaches the volume with the given name to the given host.
+func (c *LonghornClient) AttachVolume(name, hostID, attachedBy string, disableFrontend bool) (*Volume, error) {
+	volume, err := c.GetVolume(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.Volume.ActionAttach(volume, &AttachInput{
+		HostId:          hostID,
+		AttachedBy:      attachedBy,
+		DisableFrontend: disableFrontend,
+	})
+}
+
+// DetachVolume detaches the volume with the given name from the given host.
+func (c *LonghornClient) DetachVolume(name, hostID string) (*Volume, error) {
+	volume, err := c.GetVolume(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.Volume.ActionDetach(volume, &DetachInput{HostId: hostID})
+}
+
+// GetNode returns the node with the given name.
+func (c *LonghornClient) GetNode(name string) (*Node, error) {
+	return c.Node.ById(name)
+}
+
+// ListNodes returns all the Longhorn nodes.
+func (c *LonghornClient) ListNodes() ([]Node, error) {
+	collection, err := c.Node.List(&ListOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return collection.Data, nil
+}
+
+// ListBackupVolumes returns all the volumes on the backup target.
+func (c *LonghornClient) ListBackupVolumes() ([]BackupVolume, error) {
+	collection, err := c.BackupVolume.List(&ListOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return collection.Data, nil
+}
+
+// ListBackupsForVolume returns all the backups of the given backup volume.
+func (c *LonghornClient) ListBackupsForVolume(volumeName string) ([]Backup, error) {
+	backupVolume, err := c.BackupVolume.ById(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	output, err := c.BackupVolume.ActionBackupList(backupVolume)
+	if err != nil {
+		return nil, err
+	}
+	return output.Data, nil
+}
+
+// GetSetting returns the setting with the given name.
+func (c *LonghornClient) GetSetting(name string) (*Setting, error) {
+	return c.Setting.ById(name)
+}
+
+// ListSettings returns all the settings with their definitions.
+func (c *LonghornClient) ListSettings() ([]Setting, error) {
+	collection, err := c.Setting.List(&ListOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return collection.Data, nil
+}
+
+// UpdateSetting sets the setting with the given name to the given value.
+func (c *LonghornClient) UpdateSetting(name, value string) (*Setting, error) {
+	setting, err := c.GetSetting(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.Setting.Update(setting, &Setting{Value: value})
+}
+
+// WatchVolumes opens the volume event stream. The manager pushes the full
+// volume list on every change; the caller owns the returned connection.
+func (c *LonghornClient) WatchVolumes() (*websocket.Conn, error) {
+	return c.watch("volumes")
+}
+
+// WatchNodes opens the node event stream.
+func (c *LonghornClient) WatchNodes() (*websocket.Conn, error) {
+	return c.watch("nodes")
+}
+
+// WatchSettings opens the setting event stream.
+func (c *LonghornClient) WatchSettings() (*websocket.Conn, error) {
+	return c.watch("settings")
+}
+
+func (c *LonghornClient) watch(resourceType string) (*websocket.Conn, error) {
+	opts := c.GetOpts()
+	if opts == nil {
+		return nil, fmt.Errorf("cannot watch %v: the client options are not set", resourceType)
+	}
+	wsURL := strings.Replace(opts.Url, "http", "ws", 1) + "/ws/" + resourceType
+	conn, _, err := c.Websocket(wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot watch %v at %v: %v", resourceType, wsURL, err)
+	}
+	return conn, nil
+}
@@ -271,6 +271,8 @@ func (bic *BackingImageController) syncBackingImage(key string) (err error) {
 		return err
 	}
 
+	bic.verifyExpectedChecksum(backingImage)
+
 	if err := bic.updateDiskLastReferenceMap(backingImage); err != nil {
 		return err
 	}
@@ -737,6 +739,26 @@ func (bic *BackingImageController) updateStatusWithFileInfo(bi *longhorn.Backing
 	return nil
 }
 
+// verifyExpectedChecksum fails the disk file records once the actual file
+// checksum is recorded and doesn't match the user specified expected checksum
+func (bic *BackingImageController) verifyExpectedChecksum(bi *longhorn.BackingImage) {
+	if bi.Spec.Checksum == "" || bi.Status.Checksum == "" || bi.Spec.Checksum == bi.Status.Checksum {
+		return
+	}
+
+	log := getLoggerForBackingImage(bic.logger, bi)
+	msg := fmt.Sprintf("Backing image expected checksum %v doesn't match the actual file checksum %v", bi.Spec.Checksum, bi.Status.Checksum)
+	for diskUUID, fileStatus := range bi.Status.DiskFileStatusMap {
+		if fileStatus.State == longhorn.BackingImageStateFailed {
+			continue
+		}
+		log.WithField("diskUUID", diskUUID).Warn(msg)
+		fileStatus.State = longhorn.BackingImageStateFailed
+		fileStatus.Message = msg
+		fileStatus.LastStateTransitionTime = util.Now()
+	}
+}
+
 func (bic *BackingImageController) updateDiskLastReferenceMap(bi *longhorn.BackingImage) error {
 	replicas, err := bic.ds.ListReplicasByBackingImage(bi.Name)
 	if err != nil {
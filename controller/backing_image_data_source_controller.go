@@ -548,13 +548,22 @@ func (c *BackingImageDataSourceController) generateBackingImageDataSourcePodMani
 		cmd = append(cmd, "--checksum", bids.Spec.Checksum)
 	}
 
+	annotations := map[string]string{types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix): string(tolerationsByte)}
+	storageNetwork, err := c.ds.GetSetting(types.SettingNameStorageNetwork)
+	if err != nil {
+		return nil, err
+	}
+	if storageNetwork.Value != "" {
+		annotations[types.CNIAnnotationNetworks] = storageNetwork.Value
+	}
+
 	podSpec := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            types.GetBackingImageDataSourcePodName(bids.Name),
 			Namespace:       c.namespace,
 			OwnerReferences: datastore.GetOwnerReferencesForBackingImageDataSource(bids),
 			Labels:          types.GetBackingImageDataSourceLabels(bids.Name, bids.Spec.NodeID, bids.Spec.DiskUUID),
-			Annotations:     map[string]string{types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix): string(tolerationsByte)},
+			Annotations:     annotations,
 		},
 		Spec: v1.PodSpec{
 			ServiceAccountName: c.serviceAccount,
@@ -775,13 +775,22 @@ func (c *BackingImageManagerController) generateBackingImageManagerPodManifest(b
 		return nil, err
 	}
 
+	annotations := map[string]string{types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix): string(tolerationsByte)}
+	storageNetwork, err := c.ds.GetSetting(types.SettingNameStorageNetwork)
+	if err != nil {
+		return nil, err
+	}
+	if storageNetwork.Value != "" {
+		annotations[types.CNIAnnotationNetworks] = storageNetwork.Value
+	}
+
 	podSpec := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            bim.Name,
 			Namespace:       c.namespace,
 			OwnerReferences: datastore.GetOwnerReferencesForBackingImageManager(bim),
 			Labels:          types.GetBackingImageManagerLabels(bim.Spec.NodeID, bim.Spec.DiskUUID),
-			Annotations:     map[string]string{types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix): string(tolerationsByte)},
+			Annotations:     annotations,
 		},
 		Spec: v1.PodSpec{
 			ServiceAccountName: c.serviceAccount,
@@ -3,6 +3,7 @@ package controller
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +22,7 @@ import (
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/kubernetes/pkg/controller"
 
 	"github.com/longhorn/backupstore"
@@ -46,11 +48,22 @@ type BackupController struct {
 	monitors    map[string]*engineapi.BackupMonitor
 	monitorLock sync.RWMutex
 
+	// purgeQueue holds the backup store cleanup requests of already deleted
+	// Backup CRs so the workers are not blocked by slow remote deletions
+	purgeQueue workqueue.RateLimitingInterface
+
 	ds *datastore.DataStore
 
 	cacheSyncs []cache.InformerSynced
 }
 
+// backupPurgeItem identifies the backup data to be removed from the backup
+// store after the corresponding Backup CR is gone from the cluster
+type backupPurgeItem struct {
+	backupTargetName string
+	backupURL        string
+}
+
 func NewBackupController(
 	logger logrus.FieldLogger,
 	ds *datastore.DataStore,
@@ -74,6 +87,8 @@ func NewBackupController(
 		monitors:    map[string]*engineapi.BackupMonitor{},
 		monitorLock: sync.RWMutex{},
 
+		purgeQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "longhorn-backup-purge"),
+
 		ds: ds,
 
 		kubeClient:    kubeClient,
@@ -107,6 +122,7 @@ func (bc *BackupController) enqueueBackupForMonitor(key string) {
 func (bc *BackupController) Run(workers int, stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	defer bc.queue.ShutDown()
+	defer bc.purgeQueue.ShutDown()
 
 	bc.logger.Infof("Start Longhorn Backup controller")
 	defer bc.logger.Infof("Shutting down Longhorn Backup controller")
@@ -117,6 +133,8 @@ func (bc *BackupController) Run(workers int, stopCh <-chan struct{}) {
 	for i := 0; i < workers; i++ {
 		go wait.Until(bc.worker, time.Second, stopCh)
 	}
+	// A single purge worker is enough to keep the backup store load bounded
+	go wait.Until(bc.purgeWorker, time.Second, stopCh)
 	<-stopCh
 }
 
@@ -153,6 +171,61 @@ func (bc *BackupController) handleErr(err error, key interface{}) {
 	bc.queue.Forget(key)
 }
 
+func (bc *BackupController) purgeWorker() {
+	for bc.processNextPurgeItem() {
+	}
+}
+
+func (bc *BackupController) processNextPurgeItem() bool {
+	item, quit := bc.purgeQueue.Get()
+	if quit {
+		return false
+	}
+	defer bc.purgeQueue.Done(item)
+	err := bc.purgeBackup(item.(backupPurgeItem))
+	bc.handlePurgeErr(err, item)
+	return true
+}
+
+func (bc *BackupController) handlePurgeErr(err error, item interface{}) {
+	if err == nil {
+		bc.purgeQueue.Forget(item)
+		return
+	}
+
+	if bc.purgeQueue.NumRequeues(item) < maxRetries {
+		bc.logger.WithError(err).Warnf("Error purging backup %+v from the backup store", item)
+		bc.purgeQueue.AddRateLimited(item)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	bc.logger.WithError(err).Warnf("Dropping backup purge item %+v out of the queue", item)
+	bc.purgeQueue.Forget(item)
+}
+
+// purgeBackup removes the backup data blocks from the backup store after the
+// corresponding Backup CR is already deleted in the cluster
+func (bc *BackupController) purgeBackup(item backupPurgeItem) error {
+	backupTarget, err := bc.ds.GetBackupTargetRO(item.backupTargetName)
+	if err != nil {
+		// The backup store is gone together with the backup target
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if backupTarget.Spec.BackupTargetURL == "" {
+		return nil
+	}
+
+	backupTargetClient, err := getBackupTargetClient(bc.ds, backupTarget)
+	if err != nil {
+		return err
+	}
+	return backupTargetClient.DeleteBackup(item.backupURL)
+}
+
 func (bc *BackupController) syncHandler(key string) (err error) {
 	defer func() {
 		err = errors.Wrapf(err, "%v: fail to sync backup %v", bc.name, key)
@@ -165,6 +238,12 @@ func (bc *BackupController) syncHandler(key string) (err error) {
 	if namespace != bc.namespace {
 		return nil
 	}
+
+	if disabled, err := bc.ds.IsControllerDisabled(bc.name); err != nil {
+		return err
+	} else if disabled {
+		return nil
+	}
 	return bc.reconcile(name)
 }
 
@@ -212,16 +291,6 @@ func (bc *BackupController) reconcile(backupName string) (err error) {
 
 	log := getLoggerForBackup(bc.logger, backup)
 
-	// Get default backup target
-	backupTarget, err := bc.ds.GetBackupTargetRO(types.DefaultBackupTargetName)
-	if err != nil {
-		if !apierrors.IsNotFound(err) {
-			return err
-		}
-		log.Warnf("Cannot found the %s backup target", types.DefaultBackupTargetName)
-		return nil
-	}
-
 	// Find the backup volume name from label
 	backupVolumeName, err := bc.getBackupVolumeName(backup)
 	if err != nil {
@@ -232,6 +301,17 @@ func (bc *BackupController) reconcile(backupName string) (err error) {
 		return err
 	}
 
+	// Get the backup target the backup belongs to
+	backupTargetName := bc.getBackupTargetName(backupVolumeName)
+	backupTarget, err := bc.ds.GetBackupTargetRO(backupTargetName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		log.Warnf("Cannot found the %s backup target", backupTargetName)
+		return nil
+	}
+
 	// Examine DeletionTimestamp to determine if object is under deletion
 	if !backup.DeletionTimestamp.IsZero() {
 		backupVolume, err := bc.ds.GetBackupVolume(backupVolumeName)
@@ -241,18 +321,14 @@ func (bc *BackupController) reconcile(backupName string) (err error) {
 
 		if backupTarget.Spec.BackupTargetURL != "" &&
 			backupVolume != nil && backupVolume.DeletionTimestamp == nil {
-			// Initialize a backup target client
-			backupTargetClient, err := getBackupTargetClient(bc.ds, backupTarget)
-			if err != nil {
-				log.WithError(err).Error("Error init backup target client")
-				return nil // Ignore error to prevent enqueue
-			}
-
-			backupURL := backupstore.EncodeBackupURL(backup.Name, backupVolumeName, backupTargetClient.URL)
-			if err := backupTargetClient.DeleteBackup(backupURL); err != nil {
-				log.WithError(err).Error("Error deleting remote backup")
-				return err
-			}
+			// Hand the backup store cleanup over to the background purger
+			// so the deletion of the Backup CR doesn't wait for the remote deletion
+			backupURL := backupstore.EncodeBackupURL(backup.Name, backupVolumeName, backupTarget.Spec.BackupTargetURL)
+			bc.purgeQueue.AddRateLimited(backupPurgeItem{
+				backupTargetName: backupTarget.Name,
+				backupURL:        backupURL,
+			})
+			log.Debugf("Enqueued backup %v for backup store purge", backupURL)
 		}
 
 		// Request backup_volume_controller to reconcile BackupVolume immediately if it's the last backup
@@ -406,6 +482,20 @@ func (bc *BackupController) getBackupVolumeName(backup *longhorn.Backup) (string
 	return backupVolumeName, nil
 }
 
+// getBackupTargetName returns the name of the backup target the backups of
+// the volume are stored on. It prefers the volume setting, falls back to the
+// backup volume CR and finally the default backup target for backups whose
+// volume no longer exists.
+func (bc *BackupController) getBackupTargetName(volumeName string) string {
+	if volume, err := bc.ds.GetVolume(volumeName); err == nil && volume.Spec.BackupTargetName != "" {
+		return volume.Spec.BackupTargetName
+	}
+	if backupVolume, err := bc.ds.GetBackupVolumeRO(volumeName); err == nil && backupVolume.Spec.BackupTargetName != "" {
+		return backupVolume.Spec.BackupTargetName
+	}
+	return types.DefaultBackupTargetName
+}
+
 func (bc *BackupController) getEngineClient(volumeName string) (engineapi.EngineClient, error) {
 	engine, err := bc.ds.GetVolumeCurrentEngine(volumeName)
 	if err != nil {
@@ -463,6 +553,20 @@ func (bc *BackupController) checkMonitor(backup *longhorn.Backup, volume *longho
 		return nil, err
 	}
 
+	// Back off the upload parallelism while this node is under resource
+	// pressure; the upload runs in the engine process on this node.
+	if concurrencyStr, exists := backupTargetClient.Credential[types.BackupUploadConcurrency]; exists {
+		pressured, err := bc.ds.IsNodeUnderResourcePressure(bc.controllerID)
+		if err != nil {
+			return nil, err
+		}
+		if pressured {
+			if concurrency, err := strconv.ParseInt(concurrencyStr, 10, 64); err == nil && concurrency > 1 {
+				backupTargetClient.Credential[types.BackupUploadConcurrency] = strconv.FormatInt((concurrency+1)/2, 10)
+			}
+		}
+	}
+
 	// Find the corresponding engine client
 	engineClient, err := bc.getEngineClient(volume.Name)
 	if err != nil {
@@ -526,7 +630,7 @@ func (bc *BackupController) syncBackupVolume(volumeName string) error {
 		}
 	} else if err != nil && apierrors.IsNotFound(err) {
 		// Request backup_target_controller to reconcile BackupTarget immediately.
-		backupTarget, err := bc.ds.GetBackupTarget(types.DefaultBackupTargetName)
+		backupTarget, err := bc.ds.GetBackupTarget(bc.getBackupTargetName(volumeName))
 		if err != nil {
 			bc.logger.WithError(err).Warn("Failed to get backup target")
 			return err
@@ -551,9 +655,14 @@ func (bc *BackupController) enableBackupMonitor(backup *longhorn.Backup, volume
 		return monitor, nil
 	}
 
+	compressionMethod, compressionLevel, err := bc.getBackupCompression(volume)
+	if err != nil {
+		return nil, err
+	}
+
 	bc.monitorLock.Lock()
 	defer bc.monitorLock.Unlock()
-	monitor, err := engineapi.NewBackupMonitor(bc.logger, backup, volume, backupTargetClient, biChecksum, engineClient, bc.enqueueBackupForMonitor)
+	monitor, err = engineapi.NewBackupMonitor(bc.logger, backup, volume, backupTargetClient, biChecksum, compressionMethod, compressionLevel, engineClient, bc.enqueueBackupForMonitor)
 	if err != nil {
 		return nil, err
 	}
@@ -561,6 +670,25 @@ func (bc *BackupController) enableBackupMonitor(backup *longhorn.Backup, volume
 	return monitor, nil
 }
 
+// getBackupCompression resolves the compression method of the volume backups,
+// preferring the per-volume override over the global setting, along with the
+// globally configured compression level.
+func (bc *BackupController) getBackupCompression(volume *longhorn.Volume) (string, int, error) {
+	compressionMethod := string(volume.Spec.BackupCompressionMethod)
+	if compressionMethod == "" {
+		var err error
+		compressionMethod, err = bc.ds.GetSettingValueExisted(types.SettingNameBackupCompressionMethod)
+		if err != nil {
+			return "", 0, err
+		}
+	}
+	compressionLevel, err := bc.ds.GetSettingAsInt(types.SettingNameBackupCompressionLevel)
+	if err != nil {
+		return "", 0, err
+	}
+	return compressionMethod, int(compressionLevel), nil
+}
+
 func (bc *BackupController) disableBackupMonitor(backupName string) {
 	monitor := bc.hasMonitor(backupName)
 	if monitor == nil {
@@ -3,6 +3,7 @@ package controller
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -106,6 +107,16 @@ func (btc *BackupTargetController) enqueueBackupTarget(obj interface{}) {
 	btc.queue.AddRateLimited(key)
 }
 
+func (btc *BackupTargetController) enqueueBackupTargetAfter(obj interface{}, duration time.Duration) {
+	key, err := controller.KeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", obj, err))
+		return
+	}
+
+	btc.queue.AddAfter(key, duration)
+}
+
 func (btc *BackupTargetController) enqueueEngineImage(obj interface{}) {
 	ei, ok := obj.(*longhorn.EngineImage)
 	if !ok {
@@ -117,10 +128,14 @@ func (btc *BackupTargetController) enqueueEngineImage(obj interface{}) {
 	if err != nil || ei.Spec.Image != defaultEngineImage || ei.Status.State != longhorn.EngineImageStateDeployed {
 		return
 	}
-	// For now, we only support a default backup target
-	// We've to enhance it once we support multiple backup targets
-	// https://github.com/longhorn/longhorn/issues/2317
-	btc.queue.AddRateLimited(ei.Namespace + "/" + types.DefaultBackupTargetName)
+	backupTargets, err := btc.ds.ListBackupTargets()
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list backup targets: %v", err))
+		return
+	}
+	for _, bt := range backupTargets {
+		btc.enqueueBackupTarget(bt)
+	}
 }
 
 func (btc *BackupTargetController) Run(workers int, stopCh <-chan struct{}) {
@@ -168,10 +183,10 @@ func (btc *BackupTargetController) syncHandler(key string) (err error) {
 		// Not ours, skip it
 		return nil
 	}
-	if name != types.DefaultBackupTargetName {
-		// For now, we only support a default backup target
-		// We've to enhance it once we support multiple backup targets
-		// https://github.com/longhorn/longhorn/issues/2317
+
+	if disabled, err := btc.ds.IsControllerDisabled(btc.name); err != nil {
+		return err
+	} else if disabled {
 		return nil
 	}
 	return btc.reconcile(name)
@@ -222,17 +237,43 @@ func getBackupTargetClient(ds *datastore.DataStore, backupTarget *longhorn.Backu
 		if err != nil {
 			return nil, err
 		}
+		if err := applyBackupUploadTuning(ds, credential); err != nil {
+			return nil, err
+		}
 	}
 	return engineapi.NewBackupTargetClient(defaultEngineImage, backupTarget.Spec.BackupTargetURL, credential), nil
 }
 
+// applyBackupUploadTuning injects the backup upload tuning settings into the
+// credential map, so they reach the backup driver of the engine as
+// environment variables along with the S3 credential.
+func applyBackupUploadTuning(ds *datastore.DataStore, credential map[string]string) error {
+	tunings := map[string]types.SettingName{
+		types.BackupUploadConcurrency: types.SettingNameBackupUploadConcurrency,
+		types.BackupUploadPartSize:    types.SettingNameBackupUploadPartSize,
+		types.BackupUploadMaxRetries:  types.SettingNameBackupUploadMaxRetries,
+	}
+	for env, settingName := range tunings {
+		value, err := ds.GetSettingAsInt(settingName)
+		if err != nil {
+			return err
+		}
+		if value > 0 {
+			credential[env] = strconv.FormatInt(value, 10)
+		}
+	}
+	return nil
+}
+
 func (btc *BackupTargetController) reconcile(name string) (err error) {
 	backupTarget, err := btc.ds.GetBackupTarget(name)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
 			return err
 		}
-		return nil
+		// The backup target CR is gone; clean up the backup volume CRs
+		// that were synced from it
+		return btc.cleanupBackupVolumes(name)
 	}
 
 	// Check the responsible node
@@ -262,8 +303,7 @@ func (btc *BackupTargetController) reconcile(name string) (err error) {
 	log := getLoggerForBackupTarget(btc.logger, backupTarget)
 
 	// Check the controller should run synchronization
-	if !backupTarget.Status.LastSyncedAt.IsZero() &&
-		!backupTarget.Spec.SyncRequestedAt.After(backupTarget.Status.LastSyncedAt.Time) {
+	if !btc.isSyncDue(backupTarget) {
 		return nil
 	}
 
@@ -280,6 +320,16 @@ func (btc *BackupTargetController) reconcile(name string) (err error) {
 			// In this case, the controller should not update `Status.LastSyncedAt`.
 			backupTarget.Status.LastSyncedAt = syncTime
 		}
+		if backupTarget.Status.Available != existingBackupTarget.Status.Available {
+			if backupTarget.Status.Available {
+				btc.eventRecorder.Eventf(backupTarget, v1.EventTypeNormal, EventReasonAvailable,
+					"Backup target %v became reachable", backupTarget.Name)
+			} else {
+				condition := types.GetCondition(backupTarget.Status.Conditions, longhorn.BackupTargetConditionTypeUnavailable)
+				btc.eventRecorder.Eventf(backupTarget, v1.EventTypeWarning, EventReasonUnavailable,
+					"Backup target %v became unreachable: %v", backupTarget.Name, condition.Message)
+			}
+		}
 		if reflect.DeepEqual(existingBackupTarget.Status, backupTarget.Status) {
 			return
 		}
@@ -294,8 +344,8 @@ func (btc *BackupTargetController) reconcile(name string) (err error) {
 		backupTarget.Status.Conditions = types.SetCondition(backupTarget.Status.Conditions,
 			longhorn.BackupTargetConditionTypeUnavailable, longhorn.ConditionStatusTrue,
 			longhorn.BackupTargetConditionReasonUnavailable, "backup target URL is empty")
-		// Clean up all BackupVolume CRs
-		if err := btc.cleanupBackupVolumes(); err != nil {
+		// Clean up all BackupVolume CRs of this backup target
+		if err := btc.cleanupBackupVolumes(name); err != nil {
 			log.WithError(err).Error("Error deleting backup volumes")
 			return err
 		}
@@ -326,7 +376,7 @@ func (btc *BackupTargetController) reconcile(name string) (err error) {
 	backupStoreBackupVolumes := sets.NewString(res...)
 
 	// Get a list of all the backup volumes that exist as custom resources in the cluster
-	clusterBackupVolumes, err := btc.ds.ListBackupVolumes()
+	clusterBackupVolumes, err := btc.ds.ListBackupVolumesForBackupTarget(name)
 	if err != nil {
 		log.WithError(err).Error("Error listing backup volumes in the cluster")
 		return err
@@ -349,7 +399,13 @@ func (btc *BackupTargetController) reconcile(name string) (err error) {
 			ObjectMeta: metav1.ObjectMeta{
 				Name: backupVolumeName,
 			},
+			Spec: longhorn.BackupVolumeSpec{
+				BackupTargetName: name,
+			},
 		}
+		// BackupVolume CR names are shared across backup targets. When another
+		// target already owns a backup volume with the same name, the creation
+		// fails with AlreadyExists and the first target keeps the ownership.
 		if _, err = btc.ds.CreateBackupVolume(backupVolume); err != nil && !apierrors.IsAlreadyExists(err) {
 			log.WithError(err).Errorf("Error creating backup volume %s into cluster", backupVolumeName)
 			return err
@@ -385,9 +441,35 @@ func (btc *BackupTargetController) reconcile(name string) (err error) {
 	backupTarget.Status.Conditions = types.SetCondition(backupTarget.Status.Conditions,
 		longhorn.BackupTargetConditionTypeUnavailable, longhorn.ConditionStatusFalse,
 		"", "")
+
+	// Schedule the next poll-based synchronization
+	if pollInterval := backupTarget.Spec.PollInterval.Duration; pollInterval > 0 {
+		btc.enqueueBackupTargetAfter(backupTarget, pollInterval)
+	}
 	return nil
 }
 
+// isSyncDue checks whether the backup target should run the synchronization
+// now, either because a sync was requested explicitly or because the poll
+// interval has elapsed since the last synchronization. When the poll interval
+// has not elapsed yet, the backup target is re-enqueued for the remaining time.
+func (btc *BackupTargetController) isSyncDue(backupTarget *longhorn.BackupTarget) bool {
+	if backupTarget.Status.LastSyncedAt.IsZero() ||
+		backupTarget.Spec.SyncRequestedAt.After(backupTarget.Status.LastSyncedAt.Time) {
+		return true
+	}
+	pollInterval := backupTarget.Spec.PollInterval.Duration
+	if pollInterval == 0 {
+		// Polling is disabled for this backup target
+		return false
+	}
+	if elapsed := time.Since(backupTarget.Status.LastSyncedAt.Time); elapsed < pollInterval {
+		btc.enqueueBackupTargetAfter(backupTarget, pollInterval-elapsed)
+		return false
+	}
+	return true
+}
+
 func (btc *BackupTargetController) isResponsibleFor(bt *longhorn.BackupTarget, defaultEngineImage string) (bool, error) {
 	var err error
 	defer func() {
@@ -421,9 +503,9 @@ func (btc *BackupTargetController) isResponsibleFor(bt *longhorn.BackupTarget, d
 	return isPreferredOwner || continueToBeOwner || requiresNewOwner, nil
 }
 
-// cleanupBackupVolumes deletes all BackupVolume CRs
-func (btc *BackupTargetController) cleanupBackupVolumes() error {
-	clusterBackupVolumes, err := btc.ds.ListBackupVolumes()
+// cleanupBackupVolumes deletes all BackupVolume CRs of the backup target
+func (btc *BackupTargetController) cleanupBackupVolumes(backupTargetName string) error {
+	clusterBackupVolumes, err := btc.ds.ListBackupVolumesForBackupTarget(backupTargetName)
 	if err != nil {
 		return err
 	}
@@ -136,6 +136,12 @@ func (bvc *BackupVolumeController) syncHandler(key string) (err error) {
 		// Not ours, skip it
 		return nil
 	}
+
+	if disabled, err := bvc.ds.IsControllerDisabled(bvc.name); err != nil {
+		return err
+	} else if disabled {
+		return nil
+	}
 	return bvc.reconcile(name)
 }
 
@@ -200,13 +206,17 @@ func (bvc *BackupVolumeController) reconcile(backupVolumeName string) (err error
 
 	log := getLoggerForBackupVolume(bvc.logger, backupVolume)
 
-	// Get default backup target
-	backupTarget, err := bvc.ds.GetBackupTargetRO(types.DefaultBackupTargetName)
+	// Get the backup target the backup volume belongs to
+	backupTargetName := backupVolume.Spec.BackupTargetName
+	if backupTargetName == "" {
+		backupTargetName = types.DefaultBackupTargetName
+	}
+	backupTarget, err := bvc.ds.GetBackupTargetRO(backupTargetName)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
 			return err
 		}
-		log.Warnf("Cannot found the %s backup target", types.DefaultBackupTargetName)
+		log.Warnf("Cannot found the %s backup target", backupTargetName)
 		return nil
 	}
 
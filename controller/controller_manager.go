@@ -31,6 +31,12 @@ import (
 var (
 	Workers              = 5
 	longhornFinalizerKey = longhorn.SchemeGroupVersion.Group
+
+	// RecoveryMode makes StartControllers run only the controllers needed
+	// for a working API and attached volumes, and suspends the automatic
+	// healing in the volume controller. It has to be set before
+	// StartControllers is called.
+	RecoveryMode = false
 )
 
 func StartControllers(logger logrus.FieldLogger, stopCh chan struct{}, controllerID, serviceAccount, managerImage, kubeconfigPath, version string) (*datastore.DataStore, *WebsocketController, error) {
@@ -87,10 +93,15 @@ func StartControllers(logger logrus.FieldLogger, stopCh chan struct{}, controlle
 	bimc := NewBackingImageManagerController(logger, ds, scheme, kubeClient, namespace, controllerID, serviceAccount)
 	bidsc := NewBackingImageDataSourceController(logger, ds, scheme, kubeClient, namespace, controllerID, serviceAccount)
 	rjc := NewRecurringJobController(logger, ds, scheme, kubeClient, namespace, controllerID, serviceAccount, managerImage)
+	snapc := NewSnapshotController(logger, ds, scheme, kubeClient, &engineapi.EngineCollection{}, namespace, controllerID)
+	sbc := NewSystemBackupController(logger, ds, scheme, kubeClient, namespace, controllerID, managerImage, version)
+	src := NewSystemRestoreController(logger, ds, scheme, kubeClient, namespace, controllerID)
 	kpvc := NewKubernetesPVController(logger, ds, scheme, kubeClient, controllerID)
 	knc := NewKubernetesNodeController(logger, ds, scheme, kubeClient, controllerID)
 	kpc := NewKubernetesPodController(logger, ds, scheme, kubeClient, controllerID)
+	kvac := NewKubernetesVAController(logger, ds, scheme, kubeClient, controllerID)
 	kcfmc := NewKubernetesConfigMapController(logger, ds, scheme, kubeClient, controllerID, namespace)
+	idc := NewInstanceDriftChecker(logger, ds, scheme, kubeClient, namespace, controllerID)
 	ksc := NewKubernetesSecretController(logger, ds, scheme, kubeClient, controllerID, namespace)
 
 	go kubeInformerFactory.Start(stopCh)
@@ -101,11 +112,16 @@ func StartControllers(logger logrus.FieldLogger, stopCh chan struct{}, controlle
 	go rc.Run(Workers, stopCh)
 	go ec.Run(Workers, stopCh)
 	go vc.Run(Workers, stopCh)
+	go ws.Run(stopCh)
+	go imc.Run(Workers, stopCh)
+	if RecoveryMode {
+		logrus.Warn("Recovery mode: only the replica, engine, volume, and instance manager controllers are running; " +
+			"the remaining controllers and the automatic healing are suspended")
+		return ds, ws, nil
+	}
 	go ic.Run(Workers, stopCh)
 	go nc.Run(Workers, stopCh)
-	go ws.Run(stopCh)
 	go sc.Run(stopCh)
-	go imc.Run(Workers, stopCh)
 	go smc.Run(Workers, stopCh)
 	go bic.Run(Workers, stopCh)
 	go bimc.Run(Workers, stopCh)
@@ -114,10 +130,15 @@ func StartControllers(logger logrus.FieldLogger, stopCh chan struct{}, controlle
 	go bvc.Run(Workers, stopCh)
 	go bc.Run(Workers, stopCh)
 	go rjc.Run(Workers, stopCh)
+	go snapc.Run(Workers, stopCh)
+	go sbc.Run(Workers, stopCh)
+	go src.Run(Workers, stopCh)
+	go idc.Run(stopCh)
 
 	go kpvc.Run(Workers, stopCh)
 	go knc.Run(Workers, stopCh)
 	go kpc.Run(Workers, stopCh)
+	go kvac.Run(Workers, stopCh)
 	go kcfmc.Run(Workers, stopCh)
 	go ksc.Run(Workers, stopCh)
 
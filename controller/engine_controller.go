@@ -375,6 +375,37 @@ func (ec *EngineController) enqueueInstanceManagerChange(obj interface{}) {
 
 }
 
+// isEngineFrontendFenced checks whether the engine must keep its frontend shut
+// down for now. During the live migration of a migratable RWO volume two
+// engines exist simultaneously, but only one of them is allowed to expose a
+// writable frontend. The engine requesting a frontend stays fenced as long as
+// a sibling engine of the same volume is still running with an active endpoint.
+func isEngineFrontendFenced(ds *datastore.DataStore, e *longhorn.Engine) (bool, error) {
+	if e.Spec.DisableFrontend || e.Spec.Frontend == longhorn.VolumeFrontendEmpty {
+		return false, nil
+	}
+	v, err := ds.GetVolume(e.Spec.VolumeName)
+	if err != nil {
+		return false, err
+	}
+	if v.Spec.AccessMode != longhorn.AccessModeReadWriteOnce || !v.Spec.Migratable {
+		return false, nil
+	}
+	es, err := ds.ListVolumeEngines(v.Name)
+	if err != nil {
+		return false, err
+	}
+	for _, sibling := range es {
+		if sibling.Name == e.Name {
+			continue
+		}
+		if sibling.Status.CurrentState == longhorn.InstanceStateRunning && sibling.Status.Endpoint != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (ec *EngineController) CreateInstance(obj interface{}) (*longhorn.InstanceProcess, error) {
 	e, ok := obj.(*longhorn.Engine)
 	if !ok {
@@ -387,6 +418,15 @@ func (ec *EngineController) CreateInstance(obj interface{}) (*longhorn.InstanceP
 	if e.Spec.DisableFrontend {
 		frontend = longhorn.VolumeFrontendEmpty
 	}
+	// make sure the second engine of a migrating RWO volume starts fenced,
+	// it will pick up the frontend once the old engine is gone
+	fenced, err := isEngineFrontendFenced(ec.ds, e)
+	if err != nil {
+		return nil, err
+	}
+	if fenced {
+		frontend = longhorn.VolumeFrontendEmpty
+	}
 
 	im, err := ec.ds.GetInstanceManagerByInstance(obj)
 	if err != nil {
@@ -755,9 +795,15 @@ func (m *EngineMonitor) refresh(engine *longhorn.Engine) error {
 	}
 	engine.Status.ReplicaModeMap = currentReplicaModeMap
 
+	if ei, err := m.ds.GetEngineImage(types.GetEngineImageChecksumName(engine.Status.CurrentImage)); err == nil {
+		engine.Status.CurrentImageVersionDetails = ei.Status.EngineVersionDetails
+	} else {
+		m.logger.WithError(err).Debugf("Cannot resolve the version details of the current engine image %v", engine.Status.CurrentImage)
+	}
+
 	snapshots, err := client.SnapshotList()
 	if err != nil {
-		engine.Status.Snapshots = map[string]*longhorn.Snapshot{}
+		engine.Status.Snapshots = map[string]*longhorn.SnapshotInfo{}
 		engine.Status.SnapshotsError = err.Error()
 	} else {
 		engine.Status.Snapshots = snapshots
@@ -794,9 +840,17 @@ func (m *EngineMonitor) refresh(engine *longhorn.Engine) error {
 		engine.Status.LastExpansionFailedAt = volumeInfo.LastExpansionFailedAt
 
 		if engine.Status.Endpoint == "" && !engine.Spec.DisableFrontend && engine.Spec.Frontend != longhorn.VolumeFrontendEmpty {
-			m.logger.Infof("Preparing to start frontend %v", engine.Spec.Frontend)
-			if err := client.FrontendStart(engine.Spec.Frontend); err != nil {
-				return errors.Wrapf(err, "failed to start frontend %v", engine.Spec.Frontend)
+			fenced, err := isEngineFrontendFenced(m.ds, engine)
+			if err != nil {
+				return err
+			}
+			if fenced {
+				m.logger.Debugf("Delaying frontend start since another engine of volume %v still owns the active frontend", engine.Spec.VolumeName)
+			} else {
+				m.logger.Infof("Preparing to start frontend %v", engine.Spec.Frontend)
+				if err := client.FrontendStart(engine.Spec.Frontend); err != nil {
+					return errors.Wrapf(err, "failed to start frontend %v", engine.Spec.Frontend)
+				}
 			}
 		}
 
@@ -1083,13 +1137,17 @@ func checkSizeBeforeRestoration(log logrus.FieldLogger, engine *longhorn.Engine,
 }
 
 func restoreBackup(log logrus.FieldLogger, engine *longhorn.Engine, rsMap map[string]*longhorn.RestoreStatus, client engineapi.EngineClient, cliAPIVersion int, ds *datastore.DataStore) error {
-	// Get default backup target
-	backupTarget, err := ds.GetBackupTargetRO(types.DefaultBackupTargetName)
+	// Get the backup target used by the volume
+	volume, err := ds.GetVolume(engine.Spec.VolumeName)
+	if err != nil {
+		return err
+	}
+	backupTarget, err := ds.GetVolumeBackupTargetRO(volume)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
 			return err
 		}
-		return fmt.Errorf("cannot found the %s backup target", types.DefaultBackupTargetName)
+		return fmt.Errorf("cannot found the backup target for volume %v", volume.Name)
 	}
 
 	// Initialize a backup target client
@@ -1133,6 +1191,10 @@ func handleRestoreError(log logrus.FieldLogger, engine *longhorn.Engine, rsMap m
 				log.WithError(re).Debugf("Ignore restore error from replica %v", re.Address)
 				continue
 			}
+			if isTransientBackupstoreError(re.Error()) {
+				log.WithError(re).Warnf("Replica %v hit a transient backupstore error during the restore, will retry in the next monitor round", re.Address)
+				continue
+			}
 			status.Error = re.Error()
 		}
 	}
@@ -1140,6 +1202,29 @@ func handleRestoreError(log logrus.FieldLogger, engine *longhorn.Engine, rsMap m
 	return nil
 }
 
+// isTransientBackupstoreError returns true for backupstore errors that are
+// expected to go away on their own, typically network blips between the
+// replica and the backupstore. Recording them in the restore status would
+// make the volume controller fail the affected replicas even though simply
+// retrying the restore would succeed.
+func isTransientBackupstoreError(errMsg string) bool {
+	transientErrorSubstrings := []string{
+		"connection refused",
+		"connection reset",
+		"connection timed out",
+		"i/o timeout",
+		"no such host",
+		"TLS handshake timeout",
+		"too many requests",
+	}
+	for _, substring := range transientErrorSubstrings {
+		if strings.Contains(strings.ToLower(errMsg), strings.ToLower(substring)) {
+			return true
+		}
+	}
+	return false
+}
+
 func handleRestoreErrorForCompatibleEngine(log logrus.FieldLogger, engine *longhorn.Engine, rsMap map[string]*longhorn.RestoreStatus, err error) error {
 	taskErr, ok := err.(engineapi.TaskError)
 	if !ok {
@@ -1149,6 +1234,10 @@ func handleRestoreErrorForCompatibleEngine(log logrus.FieldLogger, engine *longh
 
 	for _, re := range taskErr.ReplicaErrors {
 		if status, exists := rsMap[re.Address]; exists {
+			if isTransientBackupstoreError(re.Error()) {
+				log.WithError(re).Warnf("Replica %v hit a transient backupstore error during the restore, will retry in the next monitor round", re.Address)
+				continue
+			}
 			status.Error = re.Error()
 		}
 	}
@@ -1202,12 +1291,72 @@ func (ec *EngineController) ReconcileEngineState(e *longhorn.Engine) error {
 	if err := ec.removeUnknownReplica(e); err != nil {
 		return err
 	}
+	if err := ec.syncIOFreeze(e); err != nil {
+		return err
+	}
 	if err := ec.rebuildNewReplica(e); err != nil {
 		return err
 	}
 	return nil
 }
 
+// syncIOFreeze freezes or unfreezes the engine IO to match the freeze request
+// in the engine spec. A freeze request expires after e.Spec.IOFreezeTimeout
+// seconds so that a crashed caller cannot leave the volume quiesced forever.
+func (ec *EngineController) syncIOFreeze(e *longhorn.Engine) error {
+	shouldFreeze := false
+	remaining := time.Duration(0)
+	if e.Spec.RequestedIOFreezeAt != "" {
+		requestedAt, err := util.ParseTime(e.Spec.RequestedIOFreezeAt)
+		if err != nil {
+			return err
+		}
+		remaining = time.Duration(e.Spec.IOFreezeTimeout)*time.Second - time.Since(requestedAt)
+		shouldFreeze = remaining > 0
+	}
+
+	if shouldFreeze == e.Status.IsIOFrozen {
+		if shouldFreeze {
+			// revisit the engine once the freeze request expires
+			ec.enqueueEngineAfter(e, remaining)
+		}
+		return nil
+	}
+
+	client, err := GetClientForEngine(e, ec.engines, e.Status.CurrentImage)
+	if err != nil {
+		return err
+	}
+	if shouldFreeze {
+		if err := client.FreezeIO(); err != nil {
+			return err
+		}
+		e.Status.IsIOFrozen = true
+		ec.eventRecorder.Eventf(e, v1.EventTypeNormal, EventReasonIOFrozen,
+			"Froze IO of volume %v for up to %v seconds", e.Spec.VolumeName, e.Spec.IOFreezeTimeout)
+		ec.enqueueEngineAfter(e, remaining)
+		return nil
+	}
+
+	if err := client.UnfreezeIO(); err != nil {
+		return err
+	}
+	e.Status.IsIOFrozen = false
+	ec.eventRecorder.Eventf(e, v1.EventTypeNormal, EventReasonIOUnfrozen,
+		"Unfroze IO of volume %v", e.Spec.VolumeName)
+	return nil
+}
+
+func (ec *EngineController) enqueueEngineAfter(e *longhorn.Engine, d time.Duration) {
+	key, err := controller.KeyFunc(e)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", e, err))
+		return
+	}
+
+	ec.queue.AddAfter(key, d)
+}
+
 func GetClientForEngine(e *longhorn.Engine, engines engineapi.EngineClientCollection, image string) (client engineapi.EngineClient, err error) {
 	defer func() {
 		err = errors.Wrapf(err, "cannot get client for engine %v", e.Name)
@@ -1322,6 +1471,11 @@ func (ec *EngineController) startRebuilding(e *longhorn.Engine, replica, addr st
 		return nil
 	}
 
+	fastSync, err := ec.ds.GetSettingAsBool(types.SettingNameFastReplicaRebuildEnabled)
+	if err != nil {
+		return err
+	}
+
 	replicaURL := engineapi.GetBackendReplicaURL(addr)
 	go func() {
 		log := ec.logger.WithField("volume", e.Spec.VolumeName)
@@ -1329,11 +1483,11 @@ func (ec *EngineController) startRebuilding(e *longhorn.Engine, replica, addr st
 		if e.Spec.RequestedBackupRestore != "" {
 			if e.Spec.NodeID != "" {
 				ec.eventRecorder.Eventf(e, v1.EventTypeNormal, EventReasonRebuilding, "Start rebuilding replica %v with Address %v for restore engine %v", replica, addr, e.Spec.VolumeName)
-				err = client.ReplicaAdd(replicaURL, true)
+				err = client.ReplicaAdd(replicaURL, true, fastSync)
 			}
 		} else {
 			ec.eventRecorder.Eventf(e, v1.EventTypeNormal, EventReasonRebuilding, "Start rebuilding replica %v with Address %v for normal engine %v", replica, addr, e.Spec.VolumeName)
-			err = client.ReplicaAdd(replicaURL, false)
+			err = client.ReplicaAdd(replicaURL, false, fastSync)
 		}
 		if err != nil {
 			log.WithError(err).Errorf("Failed rebuilding of replica %v", addr)
@@ -1382,11 +1536,24 @@ func (ec *EngineController) startRebuilding(e *longhorn.Engine, replica, addr st
 
 		autoCleanupSystemGeneratedSnapshot, err := ec.ds.GetSettingAsBool(types.SettingNameAutoCleanupSystemGeneratedSnapshot)
 		if err != nil {
-			log.WithError(err).Errorf("Failed to get %v setting", types.SettingDefinitionAutoCleanupSystemGeneratedSnapshot)
+			log.WithError(err).Errorf("Failed to get %v setting", types.SettingNameAutoCleanupSystemGeneratedSnapshot)
 			return
 		}
 		// If enabled, call SnapshotPurge to clean up system generated snapshot.
 		if autoCleanupSystemGeneratedSnapshot {
+			cleanupDelay, err := ec.ds.GetSettingAsInt(types.SettingNameAutoCleanupSystemGeneratedSnapshotDelay)
+			if err != nil {
+				log.WithError(err).Errorf("Failed to get %v setting", types.SettingNameAutoCleanupSystemGeneratedSnapshotDelay)
+				return
+			}
+			if cleanupDelay > 0 {
+				// Best effort only: the deferred purge is lost if the manager
+				// restarts before the delay elapses. The leftover snapshots
+				// will be collected by the purge after the next rebuild or by
+				// a manual purge.
+				log.Debugf("Delaying the system generated snapshot purge of volume %v for %v minute(s)", e.Spec.VolumeName, cleanupDelay)
+				time.Sleep(time.Duration(cleanupDelay) * time.Minute)
+			}
 			if err := client.SnapshotPurge(); err != nil {
 				log.WithError(err).Errorf("Failed to start snapshot purge for volume %v", e.Spec.VolumeName)
 				ec.eventRecorder.Eventf(e, v1.EventTypeWarning, EventReasonFailedStartingSnapshotPurge, "Failed to start snapshot purge for volume %v: %v", e.Spec.VolumeName, err)
@@ -35,8 +35,6 @@ import (
 
 var (
 	ownerKindEngineImage = longhorn.SchemeGroupVersion.WithKind("EngineImage").String()
-
-	ExpiredEngineImageTimeout = 60 * time.Minute
 )
 
 type EngineImageController struct {
@@ -425,6 +423,10 @@ func (ic *EngineImageController) handleAutoUpgradeEngineImageToDefaultEngineImag
 
 	candidates, inProgress := ic.getVolumesForEngineImageUpgrading(volumes, defaultEngineImageResource)
 
+	if err := ic.cancelStuckAutomaticEngineUpgrades(inProgress, defaultEngineImage); err != nil {
+		return err
+	}
+
 	limitedCandidates := limitAutomaticEngineUpgradePerNode(candidates, inProgress, int(concurrentAutomaticEngineUpgradePerNodeLimit))
 
 	for _, vs := range limitedCandidates {
@@ -441,6 +443,29 @@ func (ic *EngineImageController) handleAutoUpgradeEngineImageToDefaultEngineImag
 	return nil
 }
 
+// cancelStuckAutomaticEngineUpgrades rolls back the in-progress automatic engine upgrades that can no longer finish.
+// A live upgrade only proceeds while the volume is healthy but the replica rebuilding is paused during the upgrade,
+// so a volume that becomes degraded after the upgrade has started is deadlocked until the upgrade is cancelled.
+// The cancelled upgrade will be retried in a later sync once the volume becomes healthy again.
+func (ic *EngineImageController) cancelStuckAutomaticEngineUpgrades(inProgress map[string][]*longhorn.Volume, defaultEngineImage string) error {
+	for _, vs := range inProgress {
+		for _, v := range vs {
+			if v.Spec.EngineImage != defaultEngineImage || v.Status.CurrentImage == "" {
+				continue
+			}
+			if v.Status.State != longhorn.VolumeStateAttached || v.Status.Robustness != longhorn.VolumeRobustnessDegraded {
+				continue
+			}
+			ic.logger.WithFields(logrus.Fields{"volume": v.Name, "engineImage": v.Spec.EngineImage}).Warnf("Cancelling the stuck automatic engine upgrade and rolling back to the engine image %v since the volume is degraded", v.Status.CurrentImage)
+			v.Spec.EngineImage = v.Status.CurrentImage
+			if _, err := ic.ds.UpdateVolume(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func limitAutomaticEngineUpgradePerNode(candidates, inProgress map[string][]*longhorn.Volume, maxLimit int) (limitedCandidates map[string][]*longhorn.Volume) {
 	limitedCandidates = make(map[string][]*longhorn.Volume)
 	for node := range candidates {
@@ -577,7 +602,15 @@ func (ic *EngineImageController) cleanupExpiredEngineImage(ei *longhorn.EngineIm
 	if ei.Status.NoRefSince == "" {
 		return nil
 	}
-	if util.TimestampAfterTimeout(ei.Status.NoRefSince, ExpiredEngineImageTimeout) {
+	cleanupWaitInterval, err := ic.ds.GetSettingAsInt(types.SettingNameEngineImageCleanupWaitInterval)
+	if err != nil {
+		return err
+	}
+	if cleanupWaitInterval <= 0 {
+		// Automatic cleanup of unreferenced engine images is disabled
+		return nil
+	}
+	if util.TimestampAfterTimeout(ei.Status.NoRefSince, time.Duration(cleanupWaitInterval)*time.Minute) {
 		defaultEngineImage, err := ic.ds.GetSetting(types.SettingNameDefaultEngineImage)
 		if err != nil {
 			return err
@@ -22,12 +22,18 @@ const (
 	EventReasonFailedStartingSnapshotPurge = "FailedStartingSnapshotPurge"
 	EventReasonFailedSnapshotPurge         = "FailedSnapshotPurge"
 
+	EventReasonRestored      = "Restored"
 	EventReasonFailedRestore = "FailedRestore"
 
+	EventReasonIOFrozen   = "IOFrozen"
+	EventReasonIOUnfrozen = "IOUnfrozen"
+
 	EventReasonFailedExpansion    = "FailedExpansion"
 	EventReasonSucceededExpansion = "SucceededExpansion"
 	EventReasonCanceledExpansion  = "CanceledExpansion"
 
+	EventReasonCrashLoopBackOff = "CrashLoopBackOff"
+
 	EventReasonAttached       = "Attached"
 	EventReasonDetached       = "Detached"
 	EventReasonHealthy        = "Healthy"
@@ -43,4 +49,12 @@ const (
 
 	EventReasonFetching = "Fetching"
 	EventReasonSyncing  = "Syncing"
+
+	EventReasonAvailable   = "Available"
+	EventReasonUnavailable = "Unavailable"
+
+	EventReasonDriftDetected  = "DriftDetected"
+	EventReasonDriftCorrected = "DriftCorrected"
+
+	EventReasonClockSkewDetected = "ClockSkewDetected"
 )
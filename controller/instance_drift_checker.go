@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+const (
+	instanceDriftCheckInterval = 5 * time.Minute
+)
+
+// InstanceDriftChecker periodically compares the engine/replica CR statuses
+// against the processes reported by the instance managers and reports the
+// discrepancies, e.g. the CR says running but the process is missing, or the
+// recorded port doesn't match the process port. When the setting
+// instance-drift-auto-correction is enabled, the drifted CR statuses are
+// corrected so the instance controllers can recover the instances.
+type InstanceDriftChecker struct {
+	*baseController
+
+	// which namespace controller is running with
+	namespace string
+	// use as the OwnerID of the checker
+	controllerID string
+
+	kubeClient    clientset.Interface
+	eventRecorder record.EventRecorder
+
+	ds *datastore.DataStore
+
+	cacheSyncs []cache.InformerSynced
+}
+
+// instanceDrift describes one discrepancy between an instance CR status and
+// the instance manager the CR claims the process is running in
+type instanceDrift struct {
+	kind     string
+	name     string
+	imName   string
+	reason   string
+	obj      runtime.Object
+	portFix  bool
+	portReal int
+}
+
+func NewInstanceDriftChecker(
+	logger logrus.FieldLogger,
+	ds *datastore.DataStore,
+	scheme *runtime.Scheme,
+	kubeClient clientset.Interface,
+	namespace, controllerID string) *InstanceDriftChecker {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logrus.Infof)
+	// TODO: remove the wrapper when every clients have moved to use the clientset.
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{
+		Interface: v1core.New(kubeClient.CoreV1().RESTClient()).Events(""),
+	})
+
+	idc := &InstanceDriftChecker{
+		baseController: newBaseController("longhorn-instance-drift-checker", logger),
+
+		namespace:    namespace,
+		controllerID: controllerID,
+
+		ds: ds,
+
+		kubeClient:    kubeClient,
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, v1.EventSource{Component: "longhorn-instance-drift-checker"}),
+	}
+
+	idc.cacheSyncs = append(idc.cacheSyncs,
+		ds.EngineInformer.HasSynced,
+		ds.ReplicaInformer.HasSynced,
+		ds.InstanceManagerInformer.HasSynced,
+		ds.SettingInformer.HasSynced)
+
+	return idc
+}
+
+func (idc *InstanceDriftChecker) Run(stopCh <-chan struct{}) {
+	idc.logger.Info("Starting Longhorn instance drift checker")
+	defer idc.logger.Info("Shut down Longhorn instance drift checker")
+
+	if !cache.WaitForNamedCacheSync("longhorn instance drift checker", stopCh, idc.cacheSyncs...) {
+		return
+	}
+
+	wait.Until(func() {
+		if err := idc.checkInstanceDrift(); err != nil {
+			idc.logger.WithError(err).Warn("Failed to check the instance drift")
+		}
+	}, instanceDriftCheckInterval, stopCh)
+}
+
+func (idc *InstanceDriftChecker) checkInstanceDrift() error {
+	autoCorrection, err := idc.ds.GetSettingAsBool(types.SettingNameInstanceDriftAutoCorrection)
+	if err != nil {
+		return err
+	}
+
+	ims, err := idc.ds.ListInstanceManagers()
+	if err != nil {
+		return err
+	}
+
+	drifts := []*instanceDrift{}
+
+	engines, err := idc.ds.ListEngines()
+	if err != nil {
+		return err
+	}
+	for _, e := range engines {
+		drift := idc.detectInstanceDrift("engine", e.Name, &e.Status.InstanceStatus, ims)
+		if drift == nil {
+			continue
+		}
+		drift.obj = e
+		drifts = append(drifts, drift)
+		if autoCorrection {
+			idc.correctInstanceDrift(drift, &e.Status.InstanceStatus)
+			if _, err := idc.ds.UpdateEngineStatus(e); err != nil {
+				idc.logger.WithError(err).Warnf("Failed to correct the drifted status of engine %v", e.Name)
+			}
+		}
+	}
+
+	replicas, err := idc.ds.ListReplicas()
+	if err != nil {
+		return err
+	}
+	for _, r := range replicas {
+		drift := idc.detectInstanceDrift("replica", r.Name, &r.Status.InstanceStatus, ims)
+		if drift == nil {
+			continue
+		}
+		drift.obj = r
+		drifts = append(drifts, drift)
+		if autoCorrection {
+			idc.correctInstanceDrift(drift, &r.Status.InstanceStatus)
+			if _, err := idc.ds.UpdateReplicaStatus(r); err != nil {
+				idc.logger.WithError(err).Warnf("Failed to correct the drifted status of replica %v", r.Name)
+			}
+		}
+	}
+
+	idc.reportInstanceDrift(drifts, autoCorrection)
+
+	return nil
+}
+
+// detectInstanceDrift returns the discrepancy between the instance CR status
+// and the corresponding instance manager, or nil if there is none. Only the
+// running instances owned by the current node are checked; the instances in
+// transition and the not running instance managers are left to the instance
+// controllers.
+func (idc *InstanceDriftChecker) detectInstanceDrift(kind, name string, status *longhorn.InstanceStatus, ims map[string]*longhorn.InstanceManager) *instanceDrift {
+	if status.OwnerID != idc.controllerID {
+		return nil
+	}
+	if status.CurrentState != longhorn.InstanceStateRunning || status.InstanceManagerName == "" {
+		return nil
+	}
+
+	im, exists := ims[status.InstanceManagerName]
+	if !exists {
+		return &instanceDrift{
+			kind:   kind,
+			name:   name,
+			imName: status.InstanceManagerName,
+			reason: "the instance manager no longer exists",
+		}
+	}
+	if im.Status.CurrentState != longhorn.InstanceManagerStateRunning {
+		return nil
+	}
+
+	process, exists := im.Status.Instances[name]
+	if !exists {
+		return &instanceDrift{
+			kind:   kind,
+			name:   name,
+			imName: im.Name,
+			reason: "the process is missing in the instance manager",
+		}
+	}
+	if process.Status.State != longhorn.InstanceStateRunning {
+		return &instanceDrift{
+			kind:   kind,
+			name:   name,
+			imName: im.Name,
+			reason: fmt.Sprintf("the process state is %v in the instance manager", process.Status.State),
+		}
+	}
+	if status.Port != int(process.Status.PortStart) {
+		return &instanceDrift{
+			kind:     kind,
+			name:     name,
+			imName:   im.Name,
+			reason:   fmt.Sprintf("the recorded port %v doesn't match the process port %v", status.Port, process.Status.PortStart),
+			portFix:  true,
+			portReal: int(process.Status.PortStart),
+		}
+	}
+
+	return nil
+}
+
+// correctInstanceDrift fixes the drifted instance CR status in place. A port
+// mismatch only needs the recorded port updated; for the other discrepancies
+// the instance is marked as state error so the instance controllers can
+// recover it the same way as a crashed process.
+func (idc *InstanceDriftChecker) correctInstanceDrift(drift *instanceDrift, status *longhorn.InstanceStatus) {
+	if drift.portFix {
+		status.Port = drift.portReal
+		return
+	}
+	status.CurrentState = longhorn.InstanceStateError
+}
+
+func (idc *InstanceDriftChecker) reportInstanceDrift(drifts []*instanceDrift, autoCorrection bool) {
+	if len(drifts) == 0 {
+		idc.logger.Debug("Instance drift check found no discrepancy")
+		return
+	}
+
+	idc.logger.Warnf("Instance drift check found %v discrepancies between the instance CR statuses and the instance managers", len(drifts))
+	for _, drift := range drifts {
+		idc.logger.WithFields(logrus.Fields{
+			"kind":            drift.kind,
+			"instance":        drift.name,
+			"instanceManager": drift.imName,
+		}).Warnf("Instance drift detected: %v", drift.reason)
+		idc.eventRecorder.Eventf(drift.obj, v1.EventTypeWarning, EventReasonDriftDetected,
+			"The CR status of %v %v has drifted from the instance manager %v: %v", drift.kind, drift.name, drift.imName, drift.reason)
+		if autoCorrection {
+			idc.eventRecorder.Eventf(drift.obj, v1.EventTypeNormal, EventReasonDriftCorrected,
+				"Corrected the drifted CR status of %v %v", drift.kind, drift.name)
+		}
+	}
+}
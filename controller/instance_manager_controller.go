@@ -39,6 +39,17 @@ var (
 	hostToContainer = v1.MountPropagationHostToContainer
 )
 
+const (
+	// imCrashLoopRestartThreshold is how many container restarts are needed
+	// before an instance manager pod is considered to be crash looping rather
+	// than recovering from a transient failure.
+	imCrashLoopRestartThreshold = 3
+	// imCrashLoopRecreateBackoff is how long pod recreation is held off for
+	// an instance manager that was recently observed crash looping, so the
+	// kubelet backoff is not reset by a blind recreation.
+	imCrashLoopRecreateBackoff = 5 * time.Minute
+)
+
 type InstanceManagerController struct {
 	*baseController
 
@@ -56,6 +67,11 @@ type InstanceManagerController struct {
 	instanceManagerMonitorMutex *sync.RWMutex
 	instanceManagerMonitorMap   map[string]chan struct{}
 
+	crashLoopLock sync.Mutex
+	// crashLoopIMs maps the name of a crash looping instance manager to the
+	// time the crash loop was last observed.
+	crashLoopIMs map[string]time.Time
+
 	// for unit test
 	versionUpdater func(*longhorn.InstanceManager) error
 }
@@ -125,6 +141,8 @@ func NewInstanceManagerController(
 		instanceManagerMonitorMutex: &sync.RWMutex{},
 		instanceManagerMonitorMap:   map[string]chan struct{}{},
 
+		crashLoopIMs: map[string]time.Time{},
+
 		versionUpdater: updateInstanceManagerVersion,
 	}
 
@@ -332,6 +350,8 @@ func (imc *InstanceManagerController) syncInstanceManager(key string) (err error
 			}
 		}
 
+		imc.checkCrashLoopBackOff(im, pod)
+
 		switch pod.Status.Phase {
 		case v1.PodPending:
 			if im.Status.CurrentState != longhorn.InstanceManagerStateStarting {
@@ -406,6 +426,14 @@ func (imc *InstanceManagerController) syncInstanceManager(key string) (err error
 		// both Longhorn manager and IM pod are evicted. In this case we shouldn't repeatedly create the IM pod on that node.
 		// In order to do so, we make sure that only create the IM pod if this controller is the same as the im.Spec.NodeID
 		if imc.controllerID == im.Spec.NodeID {
+			// Recreating the pod of a crash looping instance manager right
+			// away would just reset the kubelet backoff and hide the failure,
+			// so hold the recreation off instead.
+			if delay := imc.crashLoopRecreateDelay(im.Name); delay > 0 {
+				log.Warnf("Delaying the pod recreation of the crash looping instance manager %v for %v", im.Name, delay)
+				imc.enqueueInstanceManagerAfter(im, delay)
+				return nil
+			}
 			if err := imc.createInstanceManagerPod(im); err != nil {
 				if !types.ErrorAlreadyExists(errors.Cause(err)) {
 					return err
@@ -545,11 +573,17 @@ func (imc *InstanceManagerController) canDeleteInstanceManagerPDB(im *longhorn.I
 		return false, nil
 	}
 
+	nodeDrainPolicy, err := imc.ds.GetSettingValueExisted(types.SettingNameNodeDrainPolicy)
+	if err != nil {
+		return false, err
+	}
+	// The deprecated setting allow-node-drain-with-last-healthy-replica is
+	// kept as an alias of the policy always-allow
 	allowDrainingNodeWithLastReplica, err := imc.ds.GetSettingAsBool(types.SettingNameAllowNodeDrainWithLastHealthyReplica)
 	if err != nil {
 		return false, err
 	}
-	if allowDrainingNodeWithLastReplica {
+	if nodeDrainPolicy == string(types.NodeDrainPolicyAlwaysAllow) || allowDrainingNodeWithLastReplica {
 		return true, nil
 	}
 
@@ -561,9 +595,23 @@ func (imc *InstanceManagerController) canDeleteInstanceManagerPDB(im *longhorn.I
 		return false, err
 	}
 
+	targetReplicas := replicasOnCurrentNode
+	if nodeDrainPolicy == string(types.NodeDrainPolicyAllowIfReplicaIsStopped) {
+		// The stopped replicas don't need the instance manager anymore, so
+		// they don't block the drain even if one of them is the last healthy
+		// replica of a volume. The data is preserved on the disk and the
+		// replicas can be restarted after the node is back.
+		targetReplicas = []*longhorn.Replica{}
+		for _, replica := range replicasOnCurrentNode {
+			if replica.Spec.NodeID != "" && replica.Status.CurrentState != longhorn.InstanceStateStopped {
+				targetReplicas = append(targetReplicas, replica)
+			}
+		}
+	}
+
 	// For each replica process in the current node,
 	// find out whether there is a healthy replica of the same volume on another node
-	for _, replica := range replicasOnCurrentNode {
+	for _, replica := range targetReplicas {
 		vol, err := imc.ds.GetVolume(replica.Spec.VolumeName)
 		if err != nil {
 			return false, err
@@ -656,6 +704,102 @@ func getIMNameFromPDBName(pdbName string) string {
 	return pdbName
 }
 
+// checkCrashLoopBackOff detects an instance manager pod that the kubelet has
+// put into CrashLoopBackOff. The failure is surfaced once through a warning
+// event and a node condition carrying the last termination details, since the
+// usual causes (a missing kernel feature, a cgroup misconfiguration) need an
+// operator to look at the node rather than yet another pod restart.
+func (imc *InstanceManagerController) checkCrashLoopBackOff(im *longhorn.InstanceManager, pod *v1.Pod) {
+	explanation := ""
+	for _, st := range pod.Status.ContainerStatuses {
+		if st.State.Waiting == nil || st.State.Waiting.Reason != "CrashLoopBackOff" ||
+			st.RestartCount < imCrashLoopRestartThreshold {
+			continue
+		}
+		explanation = fmt.Sprintf("container %v restarted %v times", st.Name, st.RestartCount)
+		if terminated := st.LastTerminationState.Terminated; terminated != nil {
+			explanation = fmt.Sprintf("%v, last exit code %v", explanation, terminated.ExitCode)
+			if terminated.Message != "" {
+				explanation = fmt.Sprintf("%v: %v", explanation, terminated.Message)
+			}
+		}
+		break
+	}
+	if explanation == "" {
+		imc.clearCrashLoopBackOff(im)
+		return
+	}
+
+	imc.crashLoopLock.Lock()
+	_, recorded := imc.crashLoopIMs[im.Name]
+	imc.crashLoopIMs[im.Name] = time.Now()
+	imc.crashLoopLock.Unlock()
+	if recorded {
+		return
+	}
+
+	message := fmt.Sprintf("Instance manager pod %v is in CrashLoopBackOff (%v); "+
+		"check the node for a missing kernel feature or a cgroup misconfiguration", im.Name, explanation)
+	imc.eventRecorder.Event(im, v1.EventTypeWarning, EventReasonCrashLoopBackOff, message)
+	if err := imc.updateNodeInstanceManagerCondition(im, longhorn.ConditionStatusFalse,
+		longhorn.NodeConditionReasonInstanceManagerCrashLoopBackOff, message); err != nil {
+		imc.logger.WithError(err).Warnf("Failed to update the instance manager condition of node %v", im.Spec.NodeID)
+	}
+}
+
+// clearCrashLoopBackOff resets the crash loop record and the node condition
+// once the instance manager pod stopped crash looping.
+func (imc *InstanceManagerController) clearCrashLoopBackOff(im *longhorn.InstanceManager) {
+	imc.crashLoopLock.Lock()
+	_, recorded := imc.crashLoopIMs[im.Name]
+	delete(imc.crashLoopIMs, im.Name)
+	imc.crashLoopLock.Unlock()
+	if !recorded {
+		return
+	}
+	if err := imc.updateNodeInstanceManagerCondition(im, longhorn.ConditionStatusTrue, "",
+		fmt.Sprintf("Instance manager pod %v recovered from CrashLoopBackOff", im.Name)); err != nil {
+		imc.logger.WithError(err).Warnf("Failed to update the instance manager condition of node %v", im.Spec.NodeID)
+	}
+}
+
+// crashLoopRecreateDelay returns how much longer the pod recreation of the
+// given instance manager should be held off, or 0 when it can proceed.
+func (imc *InstanceManagerController) crashLoopRecreateDelay(imName string) time.Duration {
+	imc.crashLoopLock.Lock()
+	defer imc.crashLoopLock.Unlock()
+	observedAt, exists := imc.crashLoopIMs[imName]
+	if !exists {
+		return 0
+	}
+	return time.Until(observedAt.Add(imCrashLoopRecreateBackoff))
+}
+
+func (imc *InstanceManagerController) updateNodeInstanceManagerCondition(im *longhorn.InstanceManager, status longhorn.ConditionStatus, reason, message string) error {
+	node, err := imc.ds.GetNode(im.Spec.NodeID)
+	if err != nil {
+		return err
+	}
+	existing := types.GetCondition(node.Status.Conditions, longhorn.NodeConditionTypeInstanceManager)
+	if existing.Status == status && existing.Reason == reason {
+		return nil
+	}
+	node.Status.Conditions = types.SetCondition(node.Status.Conditions,
+		longhorn.NodeConditionTypeInstanceManager, status, reason, message)
+	_, err = imc.ds.UpdateNodeStatus(node)
+	return err
+}
+
+func (imc *InstanceManagerController) enqueueInstanceManagerAfter(instanceManager interface{}, duration time.Duration) {
+	key, err := controller.KeyFunc(instanceManager)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", instanceManager, err))
+		return
+	}
+
+	imc.queue.AddAfter(key, duration)
+}
+
 func (imc *InstanceManagerController) enqueueInstanceManager(instanceManager interface{}) {
 	key, err := controller.KeyFunc(instanceManager)
 	if err != nil {
@@ -815,13 +959,22 @@ func (imc *InstanceManagerController) createGenericManagerPodSpec(im *longhorn.I
 		return nil, err
 	}
 
+	annotations := map[string]string{types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix): string(tolerationsByte)}
+	storageNetwork, err := imc.ds.GetSetting(types.SettingNameStorageNetwork)
+	if err != nil {
+		return nil, err
+	}
+	if storageNetwork.Value != "" {
+		annotations[types.CNIAnnotationNetworks] = storageNetwork.Value
+	}
+
 	privileged := true
 	podSpec := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            im.Name,
 			Namespace:       imc.namespace,
 			OwnerReferences: datastore.GetOwnerReferencesForInstanceManager(im),
-			Annotations:     map[string]string{types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix): string(tolerationsByte)},
+			Annotations:     annotations,
 		},
 		Spec: v1.PodSpec{
 			ServiceAccountName: imc.serviceAccount,
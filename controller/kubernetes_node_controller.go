@@ -305,10 +305,14 @@ func (knc *KubernetesNodeController) syncDefaultDisks(node *longhorn.Node) (err
 		disks, err = types.CreateDisksFromAnnotation(annotation)
 		if err != nil {
 			logrus.Warnf("Kubernetes node: invalid annotation %v: %v: %v", types.KubeNodeDefaultDiskConfigAnnotationKey, val, err)
+			knc.eventRecorder.Eventf(node, v1.EventTypeWarning, EventReasonFailedCreating,
+				"Failed to create default disks from annotation %v: %v", types.KubeNodeDefaultDiskConfigAnnotationKey, err)
 			return nil
 		}
 	default:
 		logrus.Warnf("Kubernetes node: invalid label value: %v: %v", types.NodeCreateDefaultDiskLabelKey, val)
+		knc.eventRecorder.Eventf(node, v1.EventTypeWarning, EventReasonFailedCreating,
+			"Failed to create default disks: invalid label value %v for %v", val, types.NodeCreateDefaultDiskLabelKey)
 		return nil
 	}
 
@@ -317,6 +321,8 @@ func (knc *KubernetesNodeController) syncDefaultDisks(node *longhorn.Node) (err
 	}
 
 	node.Spec.Disks = disks
+	knc.eventRecorder.Eventf(node, v1.EventTypeNormal, EventReasonCreate,
+		"Created %v default disk(s) on node %v based on label %v", len(disks), node.Name, types.NodeCreateDefaultDiskLabelKey)
 
 	return nil
 }
@@ -335,9 +341,13 @@ func (knc *KubernetesNodeController) syncDefaultNodeTags(node *longhorn.Node) er
 		tags, err := types.GetNodeTagsFromAnnotation(val)
 		if err != nil {
 			logrus.Errorf("failed to set default node tags for node %v: %v", node.Name, err)
+			knc.eventRecorder.Eventf(node, v1.EventTypeWarning, EventReasonUpdate,
+				"Failed to set default node tags from annotation %v: %v", types.KubeNodeDefaultNodeTagConfigAnnotationKey, err)
 			return nil
 		}
 		node.Spec.Tags = tags
+		knc.eventRecorder.Eventf(node, v1.EventTypeNormal, EventReasonUpdate,
+			"Set %v default node tag(s) on node %v based on annotation %v", len(tags), node.Name, types.KubeNodeDefaultNodeTagConfigAnnotationKey)
 	}
 	return nil
 }
@@ -146,7 +146,7 @@ func (kc *KubernetesPodController) syncHandler(key string) (err error) {
 	nodeID := pod.Spec.NodeName
 	if nodeID == "" {
 		kc.logger.WithField("pod", pod.Name).Trace("skipping pod check since pod is not scheduled yet")
-		return nil
+		return kc.handleReplicaLocalityHintIfPodPending(pod)
 	}
 	if err := kc.handlePodDeletionIfNodeDown(pod, nodeID, namespace); err != nil {
 		return err
@@ -207,10 +207,97 @@ func (kc *KubernetesPodController) handlePodDeletionIfNodeDown(pod *v1.Pod, node
 		return errors.Wrapf(err, "failed to forcefully delete Pod %v on the downed Node %v in handlePodDeletionIfNodeDown", pod.Name, nodeID)
 	}
 	kc.logger.Infof("%v: Forcefully deleted pod %v on downed node %v", controllerAgentName, pod.Name, nodeID)
+	kc.eventRecorder.Eventf(pod, v1.EventTypeNormal, EventReasonDelete,
+		"Forcefully deleted the pod stuck terminating on downed node %v so that its workload controller can reschedule it", nodeID)
 
 	return nil
 }
 
+// handleReplicaLocalityHintIfPodPending pre-schedules a replica onto the node a pending
+// workload pod is targeting before the volume is attached, so that the data of a
+// data-locality volume is already local once the pod starts. The candidate node is taken
+// from the pod's node selector or the required node affinity when the Kubernetes scheduler
+// hasn't placed the pod yet.
+func (kc *KubernetesPodController) handleReplicaLocalityHintIfPodPending(pod *v1.Pod) error {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodPending {
+		return nil
+	}
+
+	candidateNodeID := getCandidateNodeForPendingPod(pod)
+	if candidateNodeID == "" {
+		return nil
+	}
+
+	volumeList, err := kc.getAssociatedVolumes(pod)
+	if err != nil {
+		return err
+	}
+
+	for _, vol := range volumeList {
+		if vol.Spec.DataLocality != longhorn.DataLocalityBestEffort {
+			continue
+		}
+		if vol.Status.State != longhorn.VolumeStateDetached {
+			continue
+		}
+
+		replicas, err := kc.ds.ListVolumeReplicas(vol.Name)
+		if err != nil {
+			return err
+		}
+
+		var candidate *longhorn.Replica
+		hinted := false
+		for _, r := range replicas {
+			if r.Spec.NodeID == candidateNodeID || r.Spec.HardNodeAffinity == candidateNodeID {
+				hinted = true
+				break
+			}
+			if r.Spec.NodeID == "" && r.Spec.HardNodeAffinity == "" {
+				candidate = r
+			}
+		}
+		if hinted || candidate == nil {
+			continue
+		}
+
+		candidate.Spec.HardNodeAffinity = candidateNodeID
+		if _, err := kc.ds.UpdateReplica(candidate); err != nil {
+			return err
+		}
+		kc.logger.Infof("%v: Set node %v as the hard node affinity of replica %v to warm up data locality for pending pod %v",
+			controllerAgentName, candidateNodeID, candidate.Name, pod.Name)
+	}
+
+	return nil
+}
+
+// getCandidateNodeForPendingPod guesses the node an unscheduled pod will land on.
+// It only returns a node when the pod spec pins the pod to exactly one node,
+// either via the node selector or via the required node affinity.
+func getCandidateNodeForPendingPod(pod *v1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+	if node, ok := pod.Spec.NodeSelector[v1.LabelHostname]; ok {
+		return node
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+	terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 {
+		return ""
+	}
+	for _, exp := range terms[0].MatchExpressions {
+		if exp.Key == v1.LabelHostname && exp.Operator == v1.NodeSelectorOpIn && len(exp.Values) == 1 {
+			return exp.Values[0]
+		}
+	}
+	return ""
+}
+
 // handlePodDeletionIfVolumeRequestRemount will delete the pod which is using a volume that has requested remount.
 // By deleting the consuming pod, Kubernetes will recreated them, reattaches, and remounts the volume.
 func (kc *KubernetesPodController) handlePodDeletionIfVolumeRequestRemount(pod *v1.Pod) error {
@@ -227,11 +314,6 @@ func (kc *KubernetesPodController) handlePodDeletionIfVolumeRequestRemount(pod *
 		return nil
 	}
 
-	// Only delete pod which has controller to make sure that the pod will be recreated by its controller
-	if metav1.GetControllerOf(pod) == nil {
-		return nil
-	}
-
 	volumeList, err := kc.getAssociatedVolumes(pod)
 	if err != nil {
 		return err
@@ -257,6 +339,12 @@ func (kc *KubernetesPodController) handlePodDeletionIfVolumeRequestRemount(pod *
 		delayDuration := time.Duration(int64(5)) * time.Second
 
 		if podStartTime.Before(remountRequestedAt) && timeNow.After(remountRequestedAt.Add(delayDuration)) {
+			// Only delete pod which has controller to make sure that the pod will be recreated by its controller
+			if metav1.GetControllerOf(pod) == nil {
+				kc.eventRecorder.Eventf(pod, v1.EventTypeWarning, EventReasonRemount,
+					"Volume %v was detached unexpectedly but this pod is not managed by a controller, so it won't be deleted automatically. Delete the pod manually to remount the volume", vol.GetName())
+				return nil
+			}
 			gracePeriod := int64(30)
 			err := kc.kubeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.GetName(), metav1.DeleteOptions{
 				GracePeriodSeconds: &gracePeriod,
@@ -265,6 +353,8 @@ func (kc *KubernetesPodController) handlePodDeletionIfVolumeRequestRemount(pod *
 				return err
 			}
 			kc.logger.Infof("Deleted pod %v so that Kubernetes will handle remounting volume %v", pod.GetName(), vol.GetName())
+			kc.eventRecorder.Eventf(pod, v1.EventTypeNormal, EventReasonRemount,
+				"Deleted the pod so that Kubernetes will recreate it and remount volume %v which was detached unexpectedly", vol.GetName())
 			return nil
 		}
 
@@ -12,6 +12,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -464,8 +465,43 @@ func (kc *KubernetesPVController) setWorkloads(ks *longhorn.KubernetesStatus, po
 
 }
 
+// detectWorkload returns the name and kind of the workload the pod belongs to.
+// The intermediate owners are resolved so that e.g. a Deployment is reported
+// instead of its ReplicaSet, and a CronJob instead of its Job.
 func (kc *KubernetesPVController) detectWorkload(p *v1.Pod) (string, string) {
 	refs := p.GetObjectMeta().GetOwnerReferences()
+	for _, ref := range refs {
+		if ref.Name == "" || ref.Kind == "" {
+			continue
+		}
+		if name, kind := kc.resolveWorkloadOwner(p.Namespace, ref.Name, ref.Kind); name != "" {
+			return name, kind
+		}
+		return ref.Name, ref.Kind
+	}
+	return "", ""
+}
+
+// resolveWorkloadOwner looks up the owner of the pod's immediate controller
+// for the workload kinds that manage pods through an intermediate resource
+func (kc *KubernetesPVController) resolveWorkloadOwner(namespace, name, kind string) (string, string) {
+	var refs []metav1.OwnerReference
+	switch kind {
+	case "ReplicaSet":
+		rs, err := kc.ds.GetReplicaSetRO(namespace, name)
+		if err != nil {
+			return "", ""
+		}
+		refs = rs.GetOwnerReferences()
+	case "Job":
+		job, err := kc.ds.GetJobRO(namespace, name)
+		if err != nil {
+			return "", ""
+		}
+		refs = job.GetOwnerReferences()
+	default:
+		return "", ""
+	}
 	for _, ref := range refs {
 		if ref.Name != "" && ref.Kind != "" {
 			return ref.Name, ref.Kind
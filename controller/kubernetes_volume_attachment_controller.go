@@ -0,0 +1,303 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+const (
+	vaControllerAgentName = "longhorn-kubernetes-volume-attachment-controller"
+)
+
+// KubernetesVAController reconciles the Kubernetes VolumeAttachment objects of
+// Longhorn volumes. After a forced node removal the external attacher can no
+// longer finish the detachment, so the VolumeAttachment keeps referencing the
+// removed node and Kubernetes refuses to attach the volume anywhere else,
+// leaving the workload pods stuck in Multi-Attach errors. This controller
+// cleans up such stale VolumeAttachments when the cleanup setting is enabled.
+type KubernetesVAController struct {
+	*baseController
+
+	// use as the OwnerID of the controller
+	controllerID string
+
+	kubeClient    clientset.Interface
+	eventRecorder record.EventRecorder
+
+	ds *datastore.DataStore
+
+	cacheSyncs []cache.InformerSynced
+}
+
+func NewKubernetesVAController(
+	logger logrus.FieldLogger,
+	ds *datastore.DataStore,
+	scheme *runtime.Scheme,
+	kubeClient clientset.Interface,
+	controllerID string) *KubernetesVAController {
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logrus.Infof)
+	// TODO: remove the wrapper when every clients have moved to use the clientset.
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{
+		Interface: v1core.New(kubeClient.CoreV1().RESTClient()).Events(""),
+	})
+
+	kc := &KubernetesVAController{
+		baseController: newBaseController("longhorn-kubernetes-volume-attachment", logger),
+
+		controllerID: controllerID,
+
+		ds: ds,
+
+		kubeClient:    kubeClient,
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, v1.EventSource{Component: vaControllerAgentName}),
+	}
+
+	ds.VolumeAttachmentInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    kc.enqueueVolumeAttachmentChange,
+		UpdateFunc: func(old, cur interface{}) { kc.enqueueVolumeAttachmentChange(cur) },
+		DeleteFunc: kc.enqueueVolumeAttachmentChange,
+	})
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.VolumeAttachmentInformer.HasSynced)
+
+	ds.KubeNodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: kc.enqueueNodeDeletion,
+	})
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.KubeNodeInformer.HasSynced)
+
+	return kc
+}
+
+func (kc *KubernetesVAController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer kc.queue.ShutDown()
+
+	kc.logger.Infof("Start %v", vaControllerAgentName)
+	defer kc.logger.Infof("Shutting down %v", vaControllerAgentName)
+
+	if !cache.WaitForNamedCacheSync(vaControllerAgentName, stopCh, kc.cacheSyncs...) {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.Until(kc.worker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+func (kc *KubernetesVAController) worker() {
+	for kc.processNextWorkItem() {
+	}
+}
+
+func (kc *KubernetesVAController) processNextWorkItem() bool {
+	key, quit := kc.queue.Get()
+	if quit {
+		return false
+	}
+	defer kc.queue.Done(key)
+	err := kc.syncHandler(key.(string))
+	kc.handleErr(err, key)
+	return true
+}
+
+func (kc *KubernetesVAController) handleErr(err error, key interface{}) {
+	if err == nil {
+		kc.queue.Forget(key)
+		return
+	}
+
+	if kc.queue.NumRequeues(key) < maxRetries {
+		kc.logger.WithError(err).Warnf("%v: Error syncing VolumeAttachment %v", vaControllerAgentName, key)
+		kc.queue.AddRateLimited(key)
+		return
+	}
+
+	kc.logger.WithError(err).Warnf("%v: Dropping VolumeAttachment %v out of the queue", vaControllerAgentName, key)
+	kc.queue.Forget(key)
+	utilruntime.HandleError(err)
+}
+
+func (kc *KubernetesVAController) syncHandler(key string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "%v: fail to sync %v", vaControllerAgentName, key)
+	}()
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	va, err := kc.ds.GetVolumeAttachmentRO(name)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error getting VolumeAttachment: %s", name)
+	}
+	if va.Spec.Attacher != types.LonghornDriverName {
+		return nil
+	}
+
+	autoCleanup, err := kc.ds.GetSettingAsBool(types.SettingNameAutoCleanupStaleVolumeAttachment)
+	if err != nil {
+		return err
+	}
+	if !autoCleanup {
+		return nil
+	}
+
+	volume, err := kc.getAssociatedVolume(va)
+	if err != nil {
+		return err
+	}
+	if !kc.isResponsibleFor(volume) {
+		return nil
+	}
+
+	stale, err := kc.isVolumeAttachmentStale(va)
+	if err != nil {
+		return err
+	}
+	if !stale {
+		return nil
+	}
+
+	return kc.cleanupVolumeAttachment(va, volume)
+}
+
+// getAssociatedVolume resolves the Longhorn volume behind the VolumeAttachment.
+// A nil volume is returned when the volume no longer exists in the cluster.
+func (kc *KubernetesVAController) getAssociatedVolume(va *storagev1.VolumeAttachment) (*longhorn.Volume, error) {
+	if va.Spec.Source.PersistentVolumeName == nil {
+		return nil, nil
+	}
+	volume, err := kc.ds.GetVolume(*va.Spec.Source.PersistentVolumeName)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return volume, nil
+}
+
+// isResponsibleFor makes sure only a single manager cleans up the
+// VolumeAttachment: the volume owner when the volume still exists, otherwise
+// the node responsible for the cluster-scoped work.
+func (kc *KubernetesVAController) isResponsibleFor(volume *longhorn.Volume) bool {
+	if volume != nil {
+		return volume.Status.OwnerID == kc.controllerID
+	}
+	responsibleNodeID, err := getResponsibleNodeID(kc.ds)
+	if err != nil {
+		kc.logger.WithError(err).Warn("Failed to select node for stale VolumeAttachment cleanup")
+		return false
+	}
+	return responsibleNodeID == kc.controllerID
+}
+
+// isVolumeAttachmentStale checks whether the VolumeAttachment references a
+// node that has been removed from Kubernetes. A node that is merely down is
+// not considered stale since it may come back with the volume still attached.
+func (kc *KubernetesVAController) isVolumeAttachmentStale(va *storagev1.VolumeAttachment) (bool, error) {
+	if va.Spec.NodeName == "" {
+		return false, nil
+	}
+	if _, err := kc.ds.GetKubernetesNode(va.Spec.NodeName); err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+// cleanupVolumeAttachment deletes the stale VolumeAttachment. Since the
+// external attacher cannot detach the volume from the removed node, the
+// finalizers are stripped once the VolumeAttachment is being deleted so that
+// the object doesn't stay in terminating state forever.
+func (kc *KubernetesVAController) cleanupVolumeAttachment(va *storagev1.VolumeAttachment, volume *longhorn.Volume) error {
+	if va.DeletionTimestamp == nil {
+		if err := kc.ds.DeleteVolumeAttachment(va.Name); err != nil && !datastore.ErrorIsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete stale VolumeAttachment %v referencing removed node %v", va.Name, va.Spec.NodeName)
+		}
+		kc.logger.Infof("%v: Deleted stale VolumeAttachment %v referencing removed node %v", vaControllerAgentName, va.Name, va.Spec.NodeName)
+		if volume != nil {
+			kc.eventRecorder.Eventf(volume, v1.EventTypeNormal, EventReasonDelete,
+				"Deleted stale VolumeAttachment %v referencing removed node %v", va.Name, va.Spec.NodeName)
+		}
+		return nil
+	}
+
+	if len(va.Finalizers) == 0 {
+		return nil
+	}
+	vaCopy := va.DeepCopy()
+	vaCopy.Finalizers = []string{}
+	if _, err := kc.ds.UpdateVolumeAttachment(vaCopy); err != nil && !datastore.ErrorIsNotFound(err) {
+		return errors.Wrapf(err, "failed to remove finalizers from stale VolumeAttachment %v", va.Name)
+	}
+	kc.logger.Infof("%v: Removed finalizers from stale VolumeAttachment %v since node %v is gone", vaControllerAgentName, va.Name, va.Spec.NodeName)
+	return nil
+}
+
+func (kc *KubernetesVAController) enqueueVolumeAttachmentChange(obj interface{}) {
+	va, ok := obj.(*storagev1.VolumeAttachment)
+	if !ok {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+			return
+		}
+
+		va, ok = deletedState.Obj.(*storagev1.VolumeAttachment)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("DeletedFinalStateUnknown contained invalid object: %#v", deletedState.Obj))
+			return
+		}
+	}
+
+	if va.Spec.Attacher != types.LonghornDriverName {
+		return
+	}
+
+	key, err := controller.KeyFunc(va)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", va, err))
+		return
+	}
+	kc.queue.AddRateLimited(key)
+}
+
+// enqueueNodeDeletion re-evaluates all Longhorn VolumeAttachments when a
+// Kubernetes node is removed, since the VolumeAttachments themselves don't
+// get an event for it
+func (kc *KubernetesVAController) enqueueNodeDeletion(obj interface{}) {
+	vas, err := kc.ds.ListVolumeAttachmentsRO()
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list VolumeAttachments: %v", err))
+		return
+	}
+	for _, va := range vas {
+		kc.enqueueVolumeAttachmentChange(va)
+	}
+}
@@ -27,6 +27,17 @@ import (
 	"github.com/longhorn/longhorn-manager/util"
 )
 
+const (
+	// nodeHeartbeatRefreshInterval limits how often a node refreshes
+	// Status.LastHeartbeatTime, so that the periodic stamping doesn't keep
+	// the node object permanently dirty.
+	nodeHeartbeatRefreshInterval = time.Minute
+
+	// nodeClockSkewThreshold is the tolerated difference between the clocks
+	// of two nodes before a warning is emitted.
+	nodeClockSkewThreshold = 2 * time.Minute
+)
+
 type NodeController struct {
 	*baseController
 
@@ -422,6 +433,8 @@ func (nc *NodeController) syncNode(key string) (err error) {
 		return nil
 	}
 
+	nc.syncNodeHeartbeat(node)
+
 	// sync disks status on current node
 	if err := nc.syncDiskStatus(node); err != nil {
 		return err
@@ -439,6 +452,10 @@ func (nc *NodeController) syncNode(key string) (err error) {
 		return err
 	}
 
+	if err := nc.syncInstanceManagerCPURequests(node); err != nil {
+		return err
+	}
+
 	if err := nc.cleanUpBackingImagesInDisks(node); err != nil {
 		return err
 	}
@@ -446,6 +463,45 @@ func (nc *NodeController) syncNode(key string) (err error) {
 	return nil
 }
 
+// syncNodeHeartbeat periodically records the node's own wall clock time in the
+// node status and warns when the heartbeat of another node is ahead of the
+// local clock by more than nodeClockSkewThreshold. Since the heartbeats of the
+// other nodes are refreshed at unknown moments, only a heartbeat from the
+// future proves a skewed clock; a heartbeat from the past may just be stale.
+func (nc *NodeController) syncNodeHeartbeat(node *longhorn.Node) {
+	now := time.Now()
+	needRefresh := true
+	if node.Status.LastHeartbeatTime != "" {
+		if last, err := util.ParseTime(node.Status.LastHeartbeatTime); err == nil &&
+			now.Before(last.Add(nodeHeartbeatRefreshInterval)) {
+			needRefresh = false
+		}
+	}
+	if needRefresh {
+		node.Status.LastHeartbeatTime = util.Now()
+	}
+
+	nodes, err := nc.ds.ListNodes()
+	if err != nil {
+		logrus.Warnf("Failed to list nodes for the clock skew check: %v", err)
+		return
+	}
+	for _, other := range nodes {
+		if other.Name == node.Name || other.Status.LastHeartbeatTime == "" {
+			continue
+		}
+		heartbeat, err := util.ParseTime(other.Status.LastHeartbeatTime)
+		if err != nil {
+			continue
+		}
+		if skew := heartbeat.Sub(now); skew > nodeClockSkewThreshold {
+			nc.eventRecorder.Eventf(node, v1.EventTypeWarning, EventReasonClockSkewDetected,
+				"The clock of node %v is at least %v ahead of the clock of node %v; synchronize the node clocks to avoid confusing snapshot and backup timestamps",
+				other.Name, skew.Truncate(time.Second), node.Name)
+		}
+	}
+}
+
 func (nc *NodeController) enqueueNode(obj interface{}) {
 	key, err := controller.KeyFunc(obj)
 	if err != nil {
@@ -692,6 +748,8 @@ func (nc *NodeController) syncDiskStatus(node *longhorn.Node) error {
 				usableStorage := (diskInfoMap[id].entry.StorageAvailable / truncateTo) * truncateTo
 				diskStatus.StorageAvailable = usableStorage
 				diskStatus.StorageMaximum = diskInfoMap[id].entry.StorageMaximum
+				diskStatus.FSType = diskInfoMap[id].entry.Type
+				diskStatus.BlockSize = diskInfoMap[id].entry.BlockSize
 				diskStatusMap[id].Conditions = types.SetConditionAndRecord(diskStatusMap[id].Conditions,
 					longhorn.DiskConditionTypeReady, longhorn.ConditionStatusTrue,
 					"", fmt.Sprintf("Disk %v(%v) on node %v is ready", id, disk.Path, node.Name),
@@ -714,6 +772,7 @@ func (nc *NodeController) syncDiskStatus(node *longhorn.Node) error {
 		if types.GetCondition(diskStatus.Conditions, longhorn.DiskConditionTypeReady).Status != longhorn.ConditionStatusTrue {
 			diskStatus.StorageScheduled = 0
 			diskStatus.ScheduledReplica = map[string]int64{}
+			diskStatus.EvictionRequestedReplicaCount = 0
 			diskStatus.Conditions = types.SetConditionAndRecord(diskStatus.Conditions,
 				longhorn.DiskConditionTypeSchedulable, longhorn.ConditionStatusFalse,
 				string(longhorn.DiskConditionReasonDiskNotReady),
@@ -745,6 +804,7 @@ func (nc *NodeController) syncDiskStatus(node *longhorn.Node) error {
 			}
 			scheduledReplica := map[string]int64{}
 			storageScheduled := int64(0)
+			evictionRequestedReplicaCount := int64(0)
 			for _, replica := range replicas {
 				if replica.Spec.NodeID != node.Name || replica.Spec.DiskPath != disk.Path {
 					replica.Spec.NodeID = node.Name
@@ -757,15 +817,28 @@ func (nc *NodeController) syncDiskStatus(node *longhorn.Node) error {
 				}
 				storageScheduled += replica.Spec.VolumeSize
 				scheduledReplica[replica.Name] = replica.Spec.VolumeSize
+				if replica.Status.EvictionRequested {
+					evictionRequestedReplicaCount++
+				}
 			}
 			diskStatus.StorageScheduled = storageScheduled
 			diskStatus.ScheduledReplica = scheduledReplica
+			// the remaining count reports the eviction progress, it drops to 0
+			// once all the evicted replicas have been rebuilt elsewhere
+			diskStatus.EvictionRequestedReplicaCount = evictionRequestedReplicaCount
 			// check disk pressure
 			info, err := nc.scheduler.GetDiskSchedulingInfo(disk, diskStatus)
 			if err != nil {
 				return err
 			}
-			if !nc.scheduler.IsSchedulableToDisk(0, 0, info) {
+			if types.IsUnsupportedDiskFilesystem(diskStatus.FSType) {
+				diskStatus.Conditions = types.SetConditionAndRecord(diskStatus.Conditions,
+					longhorn.DiskConditionTypeSchedulable, longhorn.ConditionStatusFalse,
+					string(longhorn.DiskConditionReasonUnsupportedFilesystem),
+					fmt.Sprintf("the disk %v(%v) on the node %v uses the filesystem %v, which cannot hold replica data safely",
+						id, disk.Path, node.Name, diskStatus.FSType),
+					nc.eventRecorder, node, v1.EventTypeWarning)
+			} else if !nc.scheduler.IsSchedulableToDisk(0, 0, info) {
 				diskStatus.Conditions = types.SetConditionAndRecord(diskStatus.Conditions,
 					longhorn.DiskConditionTypeSchedulable, longhorn.ConditionStatusFalse,
 					string(longhorn.DiskConditionReasonDiskPressure),
@@ -883,6 +956,44 @@ func (nc *NodeController) syncInstanceManagers(node *longhorn.Node) error {
 	return nil
 }
 
+// syncInstanceManagerCPURequests deletes the instance manager pods on this node
+// whose CPU request no longer matches the node spec override or the global
+// settings, so that the instance manager controller recreates them with the
+// updated reservation. The setting controller covers global setting changes;
+// this covers changes to the per-node fields.
+func (nc *NodeController) syncInstanceManagerCPURequests(node *longhorn.Node) error {
+	imPodList, err := nc.ds.ListInstanceManagerPods()
+	if err != nil {
+		return errors.Wrapf(err, "failed to list instance manager pods for the CPU request update")
+	}
+	imMap, err := nc.ds.ListInstanceManagers()
+	if err != nil {
+		return err
+	}
+	for _, imPod := range imPodList {
+		if imPod.Spec.NodeName != node.Name {
+			continue
+		}
+		if _, exists := imMap[imPod.Name]; !exists {
+			continue
+		}
+		resourceReq, err := GetInstanceManagerCPURequirement(nc.ds, imPod.Name)
+		if err != nil {
+			return err
+		}
+		podResourceReq := imPod.Spec.Containers[0].Resources
+		if IsSameGuaranteedCPURequirement(resourceReq, &podResourceReq) {
+			continue
+		}
+		logrus.Infof("Delete instance manager pod %v to refresh CPU request option", imPod.Name)
+		if err := nc.ds.DeletePod(imPod.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (nc *NodeController) createInstanceManager(node *longhorn.Node, imName, image string, imType longhorn.InstanceManagerType) (*longhorn.InstanceManager, error) {
 	instanceManager := &longhorn.InstanceManager{
 		ObjectMeta: metav1.ObjectMeta{
@@ -270,6 +270,7 @@ func (s *TestSuite) TestSyncNode(c *C) {
 						replica1.Name: replica1.Spec.VolumeSize,
 					},
 					DiskUUID: TestDiskID1,
+					FSType:   "ext4",
 				},
 			},
 		},
@@ -339,6 +340,7 @@ func (s *TestSuite) TestSyncNode(c *C) {
 					},
 					ScheduledReplica: map[string]int64{},
 					DiskUUID:         TestDiskID1,
+					FSType:           "ext4",
 				},
 			},
 		},
@@ -459,6 +461,7 @@ func (s *TestSuite) TestSyncNode(c *C) {
 					},
 					ScheduledReplica: map[string]int64{},
 					DiskUUID:         TestDiskID1,
+					FSType:           "ext4",
 				},
 			},
 		},
@@ -504,6 +507,7 @@ func (s *TestSuite) TestSyncNode(c *C) {
 					},
 					ScheduledReplica: map[string]int64{},
 					DiskUUID:         TestDiskID1,
+					FSType:           "ext4",
 				},
 			},
 		},
@@ -167,6 +167,12 @@ func (control *RecurringJobController) syncRecurringJob(key string) (err error)
 		return nil
 	}
 
+	if disabled, err := control.ds.IsControllerDisabled(control.name); err != nil {
+		return err
+	} else if disabled {
+		return nil
+	}
+
 	recurringJob, err := control.ds.GetRecurringJob(name)
 	if err != nil {
 		log := control.logger.WithField("recurringJob", name)
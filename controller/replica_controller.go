@@ -463,6 +463,20 @@ func (rc *ReplicaController) CanStartRebuildingReplica(r *longhorn.Replica) (boo
 		return true, nil
 	}
 
+	// Back off to half of the configured concurrency while the node is under
+	// resource pressure, so that the rebuild traffic doesn't push the node
+	// into an eviction storm.
+	if concurrentRebuildingLimit > 1 {
+		pressured, err := rc.ds.IsNodeUnderResourcePressure(r.Spec.NodeID)
+		if err != nil {
+			return false, err
+		}
+		if pressured {
+			concurrentRebuildingLimit = (concurrentRebuildingLimit + 1) / 2
+			log.Debugf("Node %v is under resource pressure, lowering the concurrent rebuild limit to %v", r.Spec.NodeID, concurrentRebuildingLimit)
+		}
+	}
+
 	// This is the only place in which the controller will operate
 	// the in progress rebuilding replica map. Then the main reconcile loop
 	// and the normal replicas will not be affected by the locking.
@@ -14,7 +14,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
-	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,6 +44,15 @@ var (
 	checkUpgradeURL               = "https://longhorn-upgrade-responder.rancher.io/v1/checkupgrade"
 )
 
+const (
+	// systemManagedComponentsUpdateKey is the pseudo work item the
+	// deployment-affecting settings are funneled into, so a burst of setting
+	// changes rolls the system managed components only once.
+	systemManagedComponentsUpdateKey = "update-system-managed-components"
+
+	systemManagedComponentsCoalesceDelay = 10 * time.Second
+)
+
 type SettingController struct {
 	*baseController
 
@@ -209,12 +217,15 @@ func (sc *SettingController) syncSetting(key string) (err error) {
 		if err := sc.syncBackupTarget(); err != nil {
 			return err
 		}
-	case string(types.SettingNameTaintToleration):
-		if err := sc.updateTaintToleration(); err != nil {
-			return err
-		}
-	case string(types.SettingNameSystemManagedComponentsNodeSelector):
-		if err := sc.updateNodeSelector(); err != nil {
+	case string(types.SettingNameTaintToleration),
+		string(types.SettingNameSystemManagedComponentsNodeSelector),
+		string(types.SettingNamePriorityClass):
+		// These settings are often changed back to back; debounce them into
+		// one combined rolling update of the system managed components
+		// instead of restarting everything once per setting.
+		sc.queue.AddAfter(sc.namespace+"/"+systemManagedComponentsUpdateKey, systemManagedComponentsCoalesceDelay)
+	case systemManagedComponentsUpdateKey:
+		if err := sc.updateSystemManagedComponents(); err != nil {
 			return err
 		}
 	case string(types.SettingNameGuaranteedEngineManagerCPU):
@@ -222,10 +233,6 @@ func (sc *SettingController) syncSetting(key string) (err error) {
 		if err := sc.updateInstanceManagerCPURequest(); err != nil {
 			return err
 		}
-	case string(types.SettingNamePriorityClass):
-		if err := sc.updatePriorityClass(); err != nil {
-			return err
-		}
 	default:
 	}
 
@@ -242,17 +249,43 @@ func getResponsibleNodeID(ds *datastore.DataStore) (string, error) {
 		return "", fmt.Errorf("no ready nodes available")
 	}
 
+	// Prefer the nodes that still have a reachable disk, so that a node whose
+	// storage failed abdicates the cluster-scoped work to a healthy node
+	var responsibleNodes []string
+	for name, node := range readyNodes {
+		if nodeHasReadyDisk(node) {
+			responsibleNodes = append(responsibleNodes, name)
+		}
+	}
+	if len(responsibleNodes) == 0 {
+		for name := range readyNodes {
+			responsibleNodes = append(responsibleNodes, name)
+		}
+	}
+
 	// We use the first node as the responsible node
 	// If we pick a random node, there is probability
 	// more than one node be responsible node at the same time
-	var responsibleNodes []string
-	for node := range readyNodes {
-		responsibleNodes = append(responsibleNodes, node)
-	}
 	sort.Strings(responsibleNodes)
 	return responsibleNodes[0], nil
 }
 
+// nodeHasReadyDisk checks whether at least one disk of the node is still
+// reachable. Nodes without any disk configured are considered healthy since
+// they cannot lose storage they don't have.
+func nodeHasReadyDisk(node *longhorn.Node) bool {
+	if len(node.Status.DiskStatus) == 0 {
+		return true
+	}
+	for _, diskStatus := range node.Status.DiskStatus {
+		diskReadyCondition := types.GetCondition(diskStatus.Conditions, longhorn.DiskConditionTypeReady)
+		if diskReadyCondition.Status == longhorn.ConditionStatusTrue {
+			return true
+		}
+	}
+	return false
+}
+
 func (sc *SettingController) syncBackupTarget() (err error) {
 	defer func() {
 		err = errors.Wrapf(err, "failed to sync backup target")
@@ -357,65 +390,80 @@ func (sc *SettingController) syncBackupTarget() (err error) {
 	return nil
 }
 
-func (sc *SettingController) updateTaintToleration() error {
-	setting, err := sc.ds.GetSetting(types.SettingNameTaintToleration)
+// updateSystemManagedComponents applies the toleration, node selector and
+// priority class settings to the system managed components in a single pass:
+// each deployment and daemonset gets at most one update and each instance
+// manager, share manager or backing image manager pod is deleted at most
+// once, no matter how many of the settings changed.
+func (sc *SettingController) updateSystemManagedComponents() error {
+	newTolerationsList, err := sc.ds.GetSettingTaintToleration()
 	if err != nil {
 		return err
 	}
-	newTolerations := setting.Value
-	newTolerationsList, err := types.UnmarshalTolerations(newTolerations)
+	newTolerationsMap := util.TolerationListToMap(newTolerationsList)
+	newTolerationsByte, err := json.Marshal(newTolerationsList)
 	if err != nil {
 		return err
 	}
-	newTolerationsMap := util.TolerationListToMap(newTolerationsList)
+
+	newNodeSelector, err := sc.ds.GetSettingSystemManagedComponentsNodeSelector()
+	if err != nil {
+		return err
+	}
+
+	priorityClassSetting, err := sc.ds.GetSetting(types.SettingNamePriorityClass)
+	if err != nil {
+		return err
+	}
+	newPriorityClass := priorityClassSetting.Value
 
 	daemonsetList, err := sc.ds.ListDaemonSetWithLabels(types.GetBaseLabelsForSystemManagedComponent())
 	if err != nil {
-		return errors.Wrapf(err, "failed to list Longhorn daemonsets for toleration update")
+		return errors.Wrapf(err, "failed to list Longhorn daemonsets for system managed component update")
 	}
 
 	deploymentList, err := sc.ds.ListDeploymentWithLabels(types.GetBaseLabelsForSystemManagedComponent())
 	if err != nil {
-		return errors.Wrapf(err, "failed to list Longhorn deployments for toleration update")
+		return errors.Wrapf(err, "failed to list Longhorn deployments for system managed component update")
 	}
 
 	imPodList, err := sc.ds.ListInstanceManagerPods()
 	if err != nil {
-		return errors.Wrapf(err, "failed to list instance manager pods for toleration update")
+		return errors.Wrapf(err, "failed to list instance manager pods for system managed component update")
 	}
 
 	smPodList, err := sc.ds.ListShareManagerPods()
 	if err != nil {
-		return errors.Wrapf(err, "failed to list share manager pods for toleration update")
+		return errors.Wrapf(err, "failed to list share manager pods for system managed component update")
 	}
 
 	bimPodList, err := sc.ds.ListBackingImageManagerPods()
 	if err != nil {
-		return errors.Wrapf(err, "failed to list backing image manager pods for toleration update")
+		return errors.Wrapf(err, "failed to list backing image manager pods for system managed component update")
 	}
 
 	for _, dp := range deploymentList {
-		lastAppliedTolerationsList, err := getLastAppliedTolerationsList(dp)
+		updated, err := applySystemManagedPodSpecSettings(&dp.Spec.Template.Spec, dp, newTolerationsMap, newTolerationsByte, newNodeSelector, newPriorityClass)
 		if err != nil {
 			return err
 		}
-		if reflect.DeepEqual(util.TolerationListToMap(lastAppliedTolerationsList), newTolerationsMap) {
+		if !updated {
 			continue
 		}
-		if err := sc.updateTolerationForDeployment(dp, lastAppliedTolerationsList, newTolerationsList); err != nil {
+		if _, err := sc.ds.UpdateDeployment(dp); err != nil {
 			return err
 		}
 	}
 
 	for _, ds := range daemonsetList {
-		lastAppliedTolerationsList, err := getLastAppliedTolerationsList(ds)
+		updated, err := applySystemManagedPodSpecSettings(&ds.Spec.Template.Spec, ds, newTolerationsMap, newTolerationsByte, newNodeSelector, newPriorityClass)
 		if err != nil {
 			return err
 		}
-		if reflect.DeepEqual(util.TolerationListToMap(lastAppliedTolerationsList), newTolerationsMap) {
+		if !updated {
 			continue
 		}
-		if err := sc.updateTolerationForDaemonset(ds, lastAppliedTolerationsList, newTolerationsList); err != nil {
+		if _, err := sc.ds.UpdateDaemonSet(ds); err != nil {
 			return err
 		}
 	}
@@ -423,14 +471,16 @@ func (sc *SettingController) updateTaintToleration() error {
 	pods := append(imPodList, smPodList...)
 	pods = append(pods, bimPodList...)
 	for _, pod := range pods {
-		lastAppliedTolerations, err := getLastAppliedTolerationsList(pod)
+		lastAppliedTolerationsList, err := GetLastAppliedTolerationsList(pod)
 		if err != nil {
 			return err
 		}
-		if reflect.DeepEqual(util.TolerationListToMap(lastAppliedTolerations), newTolerationsMap) {
+		outdated := !reflect.DeepEqual(util.TolerationListToMap(lastAppliedTolerationsList), newTolerationsMap) ||
+			!isNodeSelectorUpToDate(pod.Spec.NodeSelector, newNodeSelector) ||
+			pod.Spec.PriorityClassName != newPriorityClass
+		if !outdated || pod.DeletionTimestamp != nil {
 			continue
 		}
-
 		if err := sc.ds.DeletePod(pod.Name); err != nil {
 			return err
 		}
@@ -439,43 +489,47 @@ func (sc *SettingController) updateTaintToleration() error {
 	return nil
 }
 
-func (sc *SettingController) updateTolerationForDeployment(dp *appsv1.Deployment, lastAppliedTolerations, newTolerations []v1.Toleration) error {
-	existingTolerationsMap := util.TolerationListToMap(dp.Spec.Template.Spec.Tolerations)
-	lastAppliedTolerationsMap := util.TolerationListToMap(lastAppliedTolerations)
-	newTolerationsMap := util.TolerationListToMap(newTolerations)
-	dp.Spec.Template.Spec.Tolerations = getFinalTolerations(existingTolerationsMap, lastAppliedTolerationsMap, newTolerationsMap)
-	newTolerationsByte, err := json.Marshal(newTolerations)
+// applySystemManagedPodSpecSettings mutates the pod spec of a workload so it
+// reflects all deployment-affecting settings and reports whether anything had
+// to change. The caller commits the workload once when it did.
+func applySystemManagedPodSpecSettings(podSpec *v1.PodSpec, obj runtime.Object, newTolerationsMap map[string]v1.Toleration, newTolerationsByte []byte, newNodeSelector map[string]string, newPriorityClass string) (bool, error) {
+	updated := false
+
+	lastAppliedTolerationsList, err := GetLastAppliedTolerationsList(obj)
 	if err != nil {
-		return err
-	}
-	if err := util.SetAnnotation(dp, types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix), string(newTolerationsByte)); err != nil {
-		return err
+		return false, err
 	}
-	if _, err := sc.ds.UpdateDeployment(dp); err != nil {
-		return err
+	if !reflect.DeepEqual(util.TolerationListToMap(lastAppliedTolerationsList), newTolerationsMap) {
+		podSpec.Tolerations = getFinalTolerations(util.TolerationListToMap(podSpec.Tolerations), util.TolerationListToMap(lastAppliedTolerationsList), newTolerationsMap)
+		if err := util.SetAnnotation(obj, types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix), string(newTolerationsByte)); err != nil {
+			return false, err
+		}
+		updated = true
 	}
-	return nil
-}
 
-func (sc *SettingController) updateTolerationForDaemonset(ds *appsv1.DaemonSet, lastAppliedTolerations, newTolerations []v1.Toleration) error {
-	existingTolerationsMap := util.TolerationListToMap(ds.Spec.Template.Spec.Tolerations)
-	lastAppliedTolerationsMap := util.TolerationListToMap(lastAppliedTolerations)
-	newTolerationsMap := util.TolerationListToMap(newTolerations)
-	ds.Spec.Template.Spec.Tolerations = getFinalTolerations(existingTolerationsMap, lastAppliedTolerationsMap, newTolerationsMap)
-	newTolerationsByte, err := json.Marshal(newTolerations)
-	if err != nil {
-		return err
+	if !isNodeSelectorUpToDate(podSpec.NodeSelector, newNodeSelector) {
+		podSpec.NodeSelector = newNodeSelector
+		updated = true
 	}
-	if err := util.SetAnnotation(ds, types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix), string(newTolerationsByte)); err != nil {
-		return err
+
+	if podSpec.PriorityClassName != newPriorityClass {
+		podSpec.PriorityClassName = newPriorityClass
+		updated = true
 	}
-	if _, err := sc.ds.UpdateDaemonSet(ds); err != nil {
-		return err
+
+	return updated, nil
+}
+
+func isNodeSelectorUpToDate(existing, desired map[string]string) bool {
+	if existing == nil && len(desired) == 0 {
+		return true
 	}
-	return nil
+	return reflect.DeepEqual(existing, desired)
 }
 
-func getLastAppliedTolerationsList(obj runtime.Object) ([]v1.Toleration, error) {
+// GetLastAppliedTolerationsList returns the tolerations the setting
+// controller applied to the object on the previous toleration update.
+func GetLastAppliedTolerationsList(obj runtime.Object) ([]v1.Toleration, error) {
 	lastAppliedTolerations, err := util.GetAnnotation(obj, types.GetLonghornLabelKey(types.LastAppliedTolerationAnnotationKeySuffix))
 	if err != nil {
 		return nil, err
@@ -493,71 +547,6 @@ func getLastAppliedTolerationsList(obj runtime.Object) ([]v1.Toleration, error)
 	return lastAppliedTolerationsList, nil
 }
 
-func (sc *SettingController) updatePriorityClass() error {
-	setting, err := sc.ds.GetSetting(types.SettingNamePriorityClass)
-	if err != nil {
-		return err
-	}
-	newPriorityClass := setting.Value
-
-	daemonsetList, err := sc.ds.ListDaemonSetWithLabels(types.GetBaseLabelsForSystemManagedComponent())
-	if err != nil {
-		return errors.Wrapf(err, "failed to list Longhorn daemonsets for priority class update")
-	}
-
-	deploymentList, err := sc.ds.ListDeploymentWithLabels(types.GetBaseLabelsForSystemManagedComponent())
-	if err != nil {
-		return errors.Wrapf(err, "failed to list Longhorn deployments for priority class update")
-	}
-
-	imPodList, err := sc.ds.ListInstanceManagerPods()
-	if err != nil {
-		return errors.Wrapf(err, "failed to list instance manager pods for priority class update")
-	}
-
-	smPodList, err := sc.ds.ListShareManagerPods()
-	if err != nil {
-		return errors.Wrapf(err, "failed to list share manager pods for priority class update")
-	}
-
-	bimPodList, err := sc.ds.ListBackingImageManagerPods()
-	if err != nil {
-		return errors.Wrapf(err, "failed to list backing image manager pods for priority class update")
-	}
-
-	for _, dp := range deploymentList {
-		if dp.Spec.Template.Spec.PriorityClassName == newPriorityClass {
-			continue
-		}
-		dp.Spec.Template.Spec.PriorityClassName = newPriorityClass
-		if _, err := sc.ds.UpdateDeployment(dp); err != nil {
-			return err
-		}
-	}
-	for _, ds := range daemonsetList {
-		if ds.Spec.Template.Spec.PriorityClassName == newPriorityClass {
-			continue
-		}
-		ds.Spec.Template.Spec.PriorityClassName = newPriorityClass
-		if _, err := sc.ds.UpdateDaemonSet(ds); err != nil {
-			return err
-		}
-	}
-
-	pods := append(imPodList, smPodList...)
-	pods = append(pods, bimPodList...)
-	for _, pod := range pods {
-		if pod.Spec.PriorityClassName == newPriorityClass {
-			continue
-		}
-		if err := sc.ds.DeletePod(pod.Name); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func getFinalTolerations(existingTolerations, lastAppliedTolerations, newTolerations map[string]v1.Toleration) []v1.Toleration {
 	resultMap := make(map[string]v1.Toleration)
 
@@ -581,83 +570,6 @@ func getFinalTolerations(existingTolerations, lastAppliedTolerations, newTolerat
 	return resultSlice
 }
 
-func (sc *SettingController) updateNodeSelector() error {
-	setting, err := sc.ds.GetSetting(types.SettingNameSystemManagedComponentsNodeSelector)
-	if err != nil {
-		return err
-	}
-	newNodeSelector, err := types.UnmarshalNodeSelector(setting.Value)
-	if err != nil {
-		return err
-	}
-	deploymentList, err := sc.ds.ListDeploymentWithLabels(types.GetBaseLabelsForSystemManagedComponent())
-	if err != nil {
-		return errors.Wrapf(err, "failed to list Longhorn deployments for node selector update")
-	}
-	daemonsetList, err := sc.ds.ListDaemonSetWithLabels(types.GetBaseLabelsForSystemManagedComponent())
-	if err != nil {
-		return errors.Wrapf(err, "failed to list Longhorn daemonsets for node selector update")
-	}
-	imPodList, err := sc.ds.ListInstanceManagerPods()
-	if err != nil {
-		return errors.Wrapf(err, "failed to list instance manager pods for node selector update")
-	}
-	smPodList, err := sc.ds.ListShareManagerPods()
-	if err != nil {
-		return errors.Wrapf(err, "failed to list share manager pods for node selector update")
-	}
-	bimPodList, err := sc.ds.ListBackingImageManagerPods()
-	if err != nil {
-		return errors.Wrapf(err, "failed to list backing image manager pods for node selector update")
-	}
-	for _, dp := range deploymentList {
-		if dp.Spec.Template.Spec.NodeSelector == nil {
-			if len(newNodeSelector) == 0 {
-				continue
-			}
-		}
-		if reflect.DeepEqual(dp.Spec.Template.Spec.NodeSelector, newNodeSelector) {
-			continue
-		}
-		dp.Spec.Template.Spec.NodeSelector = newNodeSelector
-		if _, err := sc.ds.UpdateDeployment(dp); err != nil {
-			return err
-		}
-	}
-	for _, ds := range daemonsetList {
-		if ds.Spec.Template.Spec.NodeSelector == nil {
-			if len(newNodeSelector) == 0 {
-				continue
-			}
-		}
-		if reflect.DeepEqual(ds.Spec.Template.Spec.NodeSelector, newNodeSelector) {
-			continue
-		}
-		ds.Spec.Template.Spec.NodeSelector = newNodeSelector
-		if _, err := sc.ds.UpdateDaemonSet(ds); err != nil {
-			return err
-		}
-	}
-	pods := append(imPodList, smPodList...)
-	pods = append(pods, bimPodList...)
-	for _, pod := range pods {
-		if pod.Spec.NodeSelector == nil {
-			if len(newNodeSelector) == 0 {
-				continue
-			}
-		}
-		if reflect.DeepEqual(pod.Spec.NodeSelector, newNodeSelector) {
-			continue
-		}
-		if pod.DeletionTimestamp == nil {
-			if err := sc.ds.DeletePod(pod.Name); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
-
 func (bst *BackupStoreTimer) Start() {
 	if bst == nil {
 		return
@@ -668,6 +580,20 @@ func (bst *BackupStoreTimer) Start() {
 	log.Debug("Start backup store timer")
 
 	wait.PollUntil(bst.pollInterval, func() (done bool, err error) {
+		// Re-check the leadership on every tick: a leader whose own node
+		// became unhealthy (e.g. all disks unreachable) must stop driving the
+		// backup store sync so the new responsible node takes over without
+		// two nodes working on it at the same time
+		responsibleNodeID, err := getResponsibleNodeID(bst.ds)
+		if err != nil {
+			log.WithError(err).Warn("Failed to determine the responsible node, skipping the backup store sync")
+			return false, nil
+		}
+		if responsibleNodeID != bst.controllerID {
+			log.Infof("Current node %v is no longer responsible for the backup store sync, skipping", bst.controllerID)
+			return false, nil
+		}
+
 		backupTarget, err := bst.ds.GetBackupTarget(types.DefaultBackupTargetName)
 		if err != nil {
 			log.WithError(err).Errorf("Cannot get %s backup target", types.DefaultBackupTargetName)
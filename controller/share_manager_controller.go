@@ -744,7 +744,7 @@ func (c *ShareManagerController) createServiceManifest(sm *longhorn.ShareManager
 			Name:            sm.Name,
 			Namespace:       c.namespace,
 			OwnerReferences: datastore.GetOwnerReferencesForShareManager(sm, false),
-			Labels:          types.GetShareManagerInstanceLabel(sm.Name),
+			Labels:          util.MergeStringMaps(types.GetShareManagerInstanceLabel(sm.Name), types.GetVolumeSystemManagedComponentLabels(sm.Name)),
 		},
 		Spec: v1.ServiceSpec{
 			ClusterIP: "", // we let the cluster assign a random ip
@@ -783,7 +783,7 @@ func (c *ShareManagerController) createPodManifest(sm *longhorn.ShareManager, an
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            types.GetShareManagerPodNameFromShareManagerName(sm.Name),
 			Namespace:       sm.Namespace,
-			Labels:          types.GetShareManagerLabels(sm.Name, sm.Spec.Image),
+			Labels:          util.MergeStringMaps(types.GetShareManagerLabels(sm.Name, sm.Spec.Image), types.GetVolumeSystemManagedComponentLabels(sm.Name)),
 			Annotations:     annotations,
 			OwnerReferences: datastore.GetOwnerReferencesForShareManager(sm, true),
 		},
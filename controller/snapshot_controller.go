@@ -0,0 +1,411 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/engineapi"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+type SnapshotController struct {
+	*baseController
+
+	// which namespace controller is running with
+	namespace string
+	// use as the OwnerID of the controller
+	controllerID string
+
+	kubeClient    clientset.Interface
+	eventRecorder record.EventRecorder
+
+	ds *datastore.DataStore
+
+	cacheSyncs []cache.InformerSynced
+
+	engines engineapi.EngineClientCollection
+}
+
+func NewSnapshotController(
+	logger logrus.FieldLogger,
+	ds *datastore.DataStore,
+	scheme *runtime.Scheme,
+	kubeClient clientset.Interface,
+	engines engineapi.EngineClientCollection,
+	namespace string, controllerID string) *SnapshotController {
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logrus.Infof)
+	// TODO: remove the wrapper when every clients have moved to use the clientset.
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: v1core.New(kubeClient.CoreV1().RESTClient()).Events("")})
+
+	sc := &SnapshotController{
+		baseController: newBaseController("longhorn-snapshot", logger),
+
+		namespace:    namespace,
+		controllerID: controllerID,
+
+		kubeClient:    kubeClient,
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, v1.EventSource{Component: "longhorn-snapshot-controller"}),
+
+		ds: ds,
+
+		engines: engines,
+	}
+
+	ds.SnapshotInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    sc.enqueueSnapshot,
+		UpdateFunc: func(old, cur interface{}) { sc.enqueueSnapshot(cur) },
+		DeleteFunc: sc.enqueueSnapshot,
+	})
+	sc.cacheSyncs = append(sc.cacheSyncs, ds.SnapshotInformer.HasSynced)
+
+	ds.EngineInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    sc.enqueueSnapshotsForEngine,
+		UpdateFunc: func(old, cur interface{}) { sc.enqueueSnapshotsForEngine(cur) },
+		DeleteFunc: sc.enqueueSnapshotsForEngine,
+	})
+	sc.cacheSyncs = append(sc.cacheSyncs, ds.EngineInformer.HasSynced)
+
+	return sc
+}
+
+func (sc *SnapshotController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer sc.queue.ShutDown()
+
+	sc.logger.Info("Start Longhorn snapshot controller")
+	defer sc.logger.Info("Shutting down Longhorn snapshot controller")
+
+	if !cache.WaitForNamedCacheSync("longhorn snapshots", stopCh, sc.cacheSyncs...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(sc.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (sc *SnapshotController) worker() {
+	for sc.processNextWorkItem() {
+	}
+}
+
+func (sc *SnapshotController) processNextWorkItem() bool {
+	key, quit := sc.queue.Get()
+	if quit {
+		return false
+	}
+	defer sc.queue.Done(key)
+
+	err := sc.syncSnapshot(key.(string))
+	sc.handleErr(err, key)
+
+	return true
+}
+
+func (sc *SnapshotController) handleErr(err error, key interface{}) {
+	if err == nil {
+		sc.queue.Forget(key)
+		return
+	}
+
+	log := sc.logger.WithField("snapshot", key)
+	if sc.queue.NumRequeues(key) < maxRetries {
+		log.WithError(err).Warn("Error syncing Longhorn snapshot")
+		sc.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	log.WithError(err).Warn("Dropping Longhorn snapshot out of the queue")
+	sc.queue.Forget(key)
+}
+
+func (sc *SnapshotController) enqueueSnapshot(obj interface{}) {
+	key, err := controller.KeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", obj, err))
+		return
+	}
+
+	sc.queue.AddRateLimited(key)
+}
+
+// enqueueSnapshotsForEngine enqueues all snapshots of the engine's volume so
+// the CRs can be kept in sync with the snapshot state reported by the engine
+func (sc *SnapshotController) enqueueSnapshotsForEngine(obj interface{}) {
+	e, isEngine := obj.(*longhorn.Engine)
+	if !isEngine {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+			return
+		}
+
+		// use the last known state, to enqueue the dependent snapshots
+		e, ok = deletedState.Obj.(*longhorn.Engine)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("DeletedFinalStateUnknown contained non Engine object: %#v", deletedState.Obj))
+			return
+		}
+	}
+
+	snapshots, err := sc.ds.ListVolumeSnapshotsRO(e.Spec.VolumeName)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list snapshots of volume %v: %v", e.Spec.VolumeName, err))
+		return
+	}
+	for _, snap := range snapshots {
+		sc.enqueueSnapshot(snap)
+	}
+}
+
+func getLoggerForSnapshot(logger logrus.FieldLogger, snap *longhorn.Snapshot) *logrus.Entry {
+	return logger.WithFields(
+		logrus.Fields{
+			"snapshot": snap.Name,
+			"volume":   snap.Spec.Volume,
+		},
+	)
+}
+
+func (sc *SnapshotController) syncSnapshot(key string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "fail to sync snapshot for %v", key)
+	}()
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	if namespace != sc.namespace {
+		// Not ours, don't do anything
+		return nil
+	}
+
+	if disabled, err := sc.ds.IsControllerDisabled(sc.name); err != nil {
+		return err
+	} else if disabled {
+		return nil
+	}
+
+	snapshot, err := sc.ds.GetSnapshot(name)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			sc.logger.WithField("snapshot", name).Info("Snapshot has been deleted")
+			return nil
+		}
+		return err
+	}
+
+	log := getLoggerForSnapshot(sc.logger, snapshot)
+
+	if !sc.isResponsibleFor(snapshot) {
+		return nil
+	}
+	if snapshot.Status.OwnerID != sc.controllerID {
+		snapshot.Status.OwnerID = sc.controllerID
+		snapshot, err = sc.ds.UpdateSnapshotStatus(snapshot)
+		if err != nil {
+			// we don't mind others coming first
+			if apierrors.IsConflict(errors.Cause(err)) {
+				return nil
+			}
+			return err
+		}
+		log.Infof("Snapshot got new owner %v", sc.controllerID)
+	}
+
+	engine, err := sc.ds.GetVolumeCurrentEngine(snapshot.Spec.Volume)
+	if err != nil {
+		if !datastore.ErrorIsNotFound(err) {
+			return err
+		}
+		engine = nil
+	}
+
+	if snapshot.DeletionTimestamp != nil {
+		return sc.reconcileSnapshotDeletion(snapshot, engine, log)
+	}
+
+	existingSnapshot := snapshot.DeepCopy()
+	defer func() {
+		if err == nil && !reflect.DeepEqual(existingSnapshot.Status, snapshot.Status) {
+			_, err = sc.ds.UpdateSnapshotStatus(snapshot)
+		}
+		// requeue if it's conflict
+		if apierrors.IsConflict(errors.Cause(err)) {
+			log.WithError(err).Debug("Requeue snapshot due to conflict")
+			sc.enqueueSnapshot(snapshot)
+			err = nil
+		}
+	}()
+
+	var info *longhorn.SnapshotInfo
+	if engine != nil {
+		info = engine.Status.Snapshots[snapshot.Name]
+	}
+
+	if info == nil {
+		// The snapshot is not reported by the engine yet. Take a new
+		// point-in-time snapshot if the CR asks for one and it has not been
+		// taken before.
+		if snapshot.Spec.CreateSnapshot && snapshot.Status.CreationTime == "" {
+			if engine == nil || engine.Status.CurrentState != longhorn.InstanceStateRunning {
+				log.Debug("Cannot take the requested snapshot since the volume engine is not running")
+				return nil
+			}
+			client, err := sc.getEngineClient(engine)
+			if err != nil {
+				return err
+			}
+			if _, err := client.SnapshotCreate(snapshot.Name, snapshot.Spec.Labels); err != nil {
+				return err
+			}
+			sc.eventRecorder.Eventf(snapshot, v1.EventTypeNormal, EventReasonCreate,
+				"Created snapshot %v for volume %v", snapshot.Name, snapshot.Spec.Volume)
+		}
+		snapshot.Status.ReadyToUse = false
+		return nil
+	}
+
+	sc.syncSnapshotStatusWithInfo(snapshot, info)
+
+	if err := sc.hashSnapshotIfNeeded(snapshot, info, engine); err != nil {
+		return err
+	}
+
+	return sc.cleanupSnapshotBeforeRiskyOperation(snapshot, log)
+}
+
+// cleanupSnapshotBeforeRiskyOperation deletes the safety snapshot that was
+// automatically taken before a risky operation once the cleanup wait interval
+// has passed. The snapshots are kept forever if the interval is set to 0. The
+// periodic resync of the informer guarantees the expiration gets re-evaluated.
+func (sc *SnapshotController) cleanupSnapshotBeforeRiskyOperation(snapshot *longhorn.Snapshot, log *logrus.Entry) error {
+	operation, exist := snapshot.Status.Labels[types.GetLonghornLabelKey(types.LonghornLabelSnapshotBeforeRiskyOperation)]
+	if !exist {
+		return nil
+	}
+	waitInterval, err := sc.ds.GetSettingAsInt(types.SettingNameAutoSnapshotCleanupWaitInterval)
+	if err != nil {
+		return err
+	}
+	if waitInterval <= 0 || snapshot.Status.CreationTime == "" {
+		return nil
+	}
+	creationTime, err := util.ParseTime(snapshot.Status.CreationTime)
+	if err != nil {
+		return err
+	}
+	if time.Since(creationTime) < time.Duration(waitInterval)*time.Minute {
+		return nil
+	}
+	log.Infof("Cleaning up the safety snapshot taken before the %v since the cleanup wait interval has passed", operation)
+	return sc.ds.DeleteSnapshot(snapshot.Name)
+}
+
+func (sc *SnapshotController) reconcileSnapshotDeletion(snapshot *longhorn.Snapshot, engine *longhorn.Engine, log *logrus.Entry) error {
+	if engine == nil || engine.Status.CurrentState != longhorn.InstanceStateRunning {
+		// without a running engine there is no snapshot state left to clean up
+		return sc.ds.RemoveFinalizerForSnapshot(snapshot)
+	}
+
+	info := engine.Status.Snapshots[snapshot.Name]
+	if info == nil {
+		return sc.ds.RemoveFinalizerForSnapshot(snapshot)
+	}
+
+	if !info.Removed {
+		client, err := sc.getEngineClient(engine)
+		if err != nil {
+			return err
+		}
+		if err := client.SnapshotDelete(snapshot.Name); err != nil {
+			return err
+		}
+		log.Infof("Requested deletion of snapshot %v in the engine", snapshot.Name)
+	}
+	// wait for the engine monitor to refresh the snapshot map, the engine
+	// update will requeue this snapshot
+	return nil
+}
+
+// syncSnapshotStatusWithInfo mirrors the snapshot state reported by the engine
+// into the snapshot CR status
+func (sc *SnapshotController) syncSnapshotStatusWithInfo(snapshot *longhorn.Snapshot, info *longhorn.SnapshotInfo) {
+	snapshot.Status.Parent = info.Parent
+	snapshot.Status.Children = info.Children
+	snapshot.Status.MarkRemoved = info.Removed
+	snapshot.Status.UserCreated = info.UserCreated
+	snapshot.Status.CreationTime = info.Created
+	snapshot.Status.Labels = info.Labels
+	if size, err := strconv.ParseInt(info.Size, 10, 64); err == nil {
+		snapshot.Status.Size = size
+		snapshot.Status.RestoreSize = size
+	}
+	snapshot.Status.ReadyToUse = !info.Removed
+	snapshot.Status.Error = ""
+	snapshot.Status.Checksum = info.Checksum
+}
+
+// hashSnapshotIfNeeded asks the engine to calculate the checksum of the
+// snapshot data when the fast replica rebuilding is enabled, so the rebuild
+// can skip transferring the snapshots whose checksums already match on the
+// rebuilding replica. The hashing is asynchronous and the engine reports the
+// result via the snapshot info once it is done.
+func (sc *SnapshotController) hashSnapshotIfNeeded(snapshot *longhorn.Snapshot, info *longhorn.SnapshotInfo, engine *longhorn.Engine) error {
+	fastRebuildEnabled, err := sc.ds.GetSettingAsBool(types.SettingNameFastReplicaRebuildEnabled)
+	if err != nil {
+		return err
+	}
+	if !fastRebuildEnabled {
+		return nil
+	}
+	if info.Checksum != "" || info.Removed {
+		return nil
+	}
+	if engine.Status.CurrentState != longhorn.InstanceStateRunning {
+		return nil
+	}
+	client, err := sc.getEngineClient(engine)
+	if err != nil {
+		return err
+	}
+	return client.SnapshotHash(snapshot.Name)
+}
+
+func (sc *SnapshotController) getEngineClient(e *longhorn.Engine) (engineapi.EngineClient, error) {
+	return sc.engines.NewEngineClient(&engineapi.EngineClientRequest{
+		VolumeName:  e.Spec.VolumeName,
+		EngineImage: e.Status.CurrentImage,
+		IP:          e.Status.IP,
+		Port:        e.Status.Port,
+	})
+}
+
+func (sc *SnapshotController) isResponsibleFor(snapshot *longhorn.Snapshot) bool {
+	return isControllerResponsibleFor(sc.controllerID, sc.ds, snapshot.Name, "", snapshot.Status.OwnerID)
+}
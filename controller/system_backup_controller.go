@@ -0,0 +1,383 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+const (
+	systemBackupBundleSettingsKey      = "settings.json"
+	systemBackupBundleVolumesKey       = "volumes.json"
+	systemBackupBundleBackingImagesKey = "backingimages.json"
+	systemBackupBundleRecurringJobsKey = "recurringjobs.json"
+)
+
+type SystemBackupController struct {
+	*baseController
+
+	// which namespace controller is running with
+	namespace string
+	// use as the OwnerID of the controller
+	controllerID string
+	// the manager image and version recorded in the system backup
+	managerImage string
+	version      string
+
+	kubeClient    clientset.Interface
+	eventRecorder record.EventRecorder
+
+	ds *datastore.DataStore
+
+	cacheSyncs []cache.InformerSynced
+}
+
+func NewSystemBackupController(
+	logger logrus.FieldLogger,
+	ds *datastore.DataStore,
+	scheme *runtime.Scheme,
+	kubeClient clientset.Interface,
+	namespace string,
+	controllerID string,
+	managerImage string,
+	version string) *SystemBackupController {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logrus.Infof)
+	// TODO: remove the wrapper when every clients have moved to use the clientset.
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{
+		Interface: v1core.New(kubeClient.CoreV1().RESTClient()).Events(""),
+	})
+
+	sbc := &SystemBackupController{
+		baseController: newBaseController("longhorn-system-backup", logger),
+
+		namespace:    namespace,
+		controllerID: controllerID,
+		managerImage: managerImage,
+		version:      version,
+
+		ds: ds,
+
+		kubeClient:    kubeClient,
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, v1.EventSource{Component: "longhorn-system-backup-controller"}),
+	}
+
+	ds.SystemBackupInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    sbc.enqueueSystemBackup,
+		UpdateFunc: func(old, cur interface{}) { sbc.enqueueSystemBackup(cur) },
+		DeleteFunc: sbc.enqueueSystemBackup,
+	})
+	sbc.cacheSyncs = append(sbc.cacheSyncs, ds.SystemBackupInformer.HasSynced)
+
+	return sbc
+}
+
+func (sbc *SystemBackupController) enqueueSystemBackup(obj interface{}) {
+	key, err := controller.KeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", obj, err))
+		return
+	}
+
+	sbc.queue.AddRateLimited(key)
+}
+
+func (sbc *SystemBackupController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer sbc.queue.ShutDown()
+
+	sbc.logger.Infof("Start Longhorn System Backup controller")
+	defer sbc.logger.Infof("Shutting down Longhorn System Backup controller")
+
+	if !cache.WaitForNamedCacheSync(sbc.name, stopCh, sbc.cacheSyncs...) {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.Until(sbc.worker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+func (sbc *SystemBackupController) worker() {
+	for sbc.processNextWorkItem() {
+	}
+}
+
+func (sbc *SystemBackupController) processNextWorkItem() bool {
+	key, quit := sbc.queue.Get()
+	if quit {
+		return false
+	}
+	defer sbc.queue.Done(key)
+	err := sbc.syncHandler(key.(string))
+	sbc.handleErr(err, key)
+	return true
+}
+
+func (sbc *SystemBackupController) syncHandler(key string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "%v: fail to sync system backup %v", sbc.name, key)
+	}()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	if namespace != sbc.namespace {
+		// Not ours, skip it
+		return nil
+	}
+
+	if disabled, err := sbc.ds.IsControllerDisabled(sbc.name); err != nil {
+		return err
+	} else if disabled {
+		return nil
+	}
+	return sbc.reconcile(name)
+}
+
+func (sbc *SystemBackupController) handleErr(err error, key interface{}) {
+	if err == nil {
+		sbc.queue.Forget(key)
+		return
+	}
+
+	if sbc.queue.NumRequeues(key) < maxRetries {
+		sbc.logger.WithError(err).Warnf("Error syncing Longhorn system backup %v", key)
+		sbc.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	sbc.logger.WithError(err).Warnf("Dropping Longhorn system backup %v out of the queue", key)
+	sbc.queue.Forget(key)
+}
+
+func getLoggerForSystemBackup(logger logrus.FieldLogger, systemBackup *longhorn.SystemBackup) *logrus.Entry {
+	return logger.WithFields(
+		logrus.Fields{
+			"systemBackup": systemBackup.Name,
+		},
+	)
+}
+
+// systemBackupBundle holds the Longhorn resources captured in a system backup
+type systemBackupBundle struct {
+	Settings      []*longhorn.Setting      `json:"settings"`
+	Volumes       []*longhorn.Volume       `json:"volumes"`
+	BackingImages []*longhorn.BackingImage `json:"backingImages"`
+	RecurringJobs []*longhorn.RecurringJob `json:"recurringJobs"`
+}
+
+func (sbc *SystemBackupController) reconcile(systemBackupName string) (err error) {
+	systemBackup, err := sbc.ds.GetSystemBackup(systemBackupName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	if !sbc.isResponsibleFor(systemBackup) {
+		return nil
+	}
+	if systemBackup.Status.OwnerID != sbc.controllerID {
+		systemBackup.Status.OwnerID = sbc.controllerID
+		systemBackup, err = sbc.ds.UpdateSystemBackupStatus(systemBackup)
+		if err != nil {
+			// we don't mind others coming first
+			if apierrors.IsConflict(errors.Cause(err)) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	log := getLoggerForSystemBackup(sbc.logger, systemBackup)
+
+	// Examine DeletionTimestamp to determine if object is under deletion
+	if !systemBackup.DeletionTimestamp.IsZero() {
+		if err := sbc.ds.DeleteConfigMap(types.GetSystemBackupConfigMapName(systemBackupName)); err != nil && !apierrors.IsNotFound(err) {
+			log.WithError(err).Error("Error deleting system backup bundle config map")
+			return err
+		}
+		return sbc.ds.RemoveFinalizerForSystemBackup(systemBackup)
+	}
+
+	existingSystemBackup := systemBackup.DeepCopy()
+	defer func() {
+		if err != nil {
+			return
+		}
+		if reflect.DeepEqual(existingSystemBackup.Status, systemBackup.Status) {
+			return
+		}
+		if _, err := sbc.ds.UpdateSystemBackupStatus(systemBackup); err != nil && apierrors.IsConflict(errors.Cause(err)) {
+			log.WithError(err).Debugf("Requeue %v due to conflict", systemBackupName)
+			sbc.enqueueSystemBackup(systemBackup)
+		}
+	}()
+
+	switch systemBackup.Status.State {
+	case longhorn.SystemBackupStateNone:
+		systemBackup.Status.State = longhorn.SystemBackupStateGenerating
+		systemBackup.Status.Version = sbc.version
+		systemBackup.Status.ManagerImage = sbc.managerImage
+		sbc.eventRecorder.Eventf(systemBackup, v1.EventTypeNormal, EventReasonStart,
+			"Generating system backup %v", systemBackup.Name)
+		return nil
+	case longhorn.SystemBackupStateGenerating:
+		if err := sbc.generateSystemBackup(systemBackup); err != nil {
+			systemBackup.Status.State = longhorn.SystemBackupStateError
+			systemBackup.Status.Error = err.Error()
+			sbc.eventRecorder.Eventf(systemBackup, v1.EventTypeWarning, EventReasonFailedCreating,
+				"Failed to generate system backup %v: %v", systemBackup.Name, err)
+			return nil
+		}
+		systemBackup.Status.State = longhorn.SystemBackupStateReady
+		systemBackup.Status.CreatedAt = metav1.Time{Time: time.Now().UTC()}
+		systemBackup.Status.Error = ""
+		sbc.eventRecorder.Eventf(systemBackup, v1.EventTypeNormal, EventReasonCreate,
+			"Generated system backup %v", systemBackup.Name)
+		return nil
+	default:
+		return nil
+	}
+}
+
+// generateSystemBackup captures the Longhorn settings, volumes, backing images
+// and recurring jobs into a bundle config map the system restore can rebuild
+// the cluster state from
+func (sbc *SystemBackupController) generateSystemBackup(systemBackup *longhorn.SystemBackup) error {
+	bundle, err := sbc.collectBundle()
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{}
+	for key, obj := range map[string]interface{}{
+		systemBackupBundleSettingsKey:      bundle.Settings,
+		systemBackupBundleVolumesKey:       bundle.Volumes,
+		systemBackupBundleBackingImagesKey: bundle.BackingImages,
+		systemBackupBundleRecurringJobsKey: bundle.RecurringJobs,
+	} {
+		marshaled, err := json.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %v for system backup %v", key, systemBackup.Name)
+		}
+		data[key] = string(marshaled)
+	}
+
+	cmName := types.GetSystemBackupConfigMapName(systemBackup.Name)
+	cm, err := sbc.ds.GetConfigMap(sbc.namespace, cmName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            cmName,
+				Labels:          types.GetSystemBackupLabels(systemBackup.Name),
+				OwnerReferences: datastore.GetOwnerReferencesForSystemBackup(systemBackup),
+			},
+			Data: data,
+		}
+		_, err = sbc.ds.CreateConfigMap(cm)
+		return err
+	}
+
+	cm.Data = data
+	_, err = sbc.ds.UpdateConfigMap(cm)
+	return err
+}
+
+func (sbc *SystemBackupController) collectBundle() (*systemBackupBundle, error) {
+	bundle := &systemBackupBundle{}
+
+	settings, err := sbc.ds.ListSettings()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list settings")
+	}
+	for _, setting := range settings {
+		bundle.Settings = append(bundle.Settings, &longhorn.Setting{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: setting.Name,
+			},
+			Value: setting.Value,
+		})
+	}
+
+	volumes, err := sbc.ds.ListVolumesRO()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list volumes")
+	}
+	for _, volume := range volumes {
+		bundle.Volumes = append(bundle.Volumes, &longhorn.Volume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   volume.Name,
+				Labels: volume.Labels,
+			},
+			Spec: *volume.Spec.DeepCopy(),
+			// Keep the last backup so the system restore can optionally
+			// re-create the volume from it
+			Status: longhorn.VolumeStatus{
+				LastBackup: volume.Status.LastBackup,
+			},
+		})
+	}
+
+	backingImages, err := sbc.ds.ListBackingImages()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list backing images")
+	}
+	for _, backingImage := range backingImages {
+		bundle.BackingImages = append(bundle.BackingImages, &longhorn.BackingImage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   backingImage.Name,
+				Labels: backingImage.Labels,
+			},
+			Spec: *backingImage.Spec.DeepCopy(),
+		})
+	}
+
+	recurringJobs, err := sbc.ds.ListRecurringJobs()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list recurring jobs")
+	}
+	for _, recurringJob := range recurringJobs {
+		bundle.RecurringJobs = append(bundle.RecurringJobs, &longhorn.RecurringJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   recurringJob.Name,
+				Labels: recurringJob.Labels,
+			},
+			Spec: *recurringJob.Spec.DeepCopy(),
+		})
+	}
+
+	return bundle, nil
+}
+
+func (sbc *SystemBackupController) isResponsibleFor(systemBackup *longhorn.SystemBackup) bool {
+	return isControllerResponsibleFor(sbc.controllerID, sbc.ds, systemBackup.Name, "", systemBackup.Status.OwnerID)
+}
@@ -0,0 +1,394 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+type SystemRestoreController struct {
+	*baseController
+
+	// which namespace controller is running with
+	namespace string
+	// use as the OwnerID of the controller
+	controllerID string
+
+	kubeClient    clientset.Interface
+	eventRecorder record.EventRecorder
+
+	ds *datastore.DataStore
+
+	cacheSyncs []cache.InformerSynced
+}
+
+func NewSystemRestoreController(
+	logger logrus.FieldLogger,
+	ds *datastore.DataStore,
+	scheme *runtime.Scheme,
+	kubeClient clientset.Interface,
+	namespace string,
+	controllerID string) *SystemRestoreController {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logrus.Infof)
+	// TODO: remove the wrapper when every clients have moved to use the clientset.
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{
+		Interface: v1core.New(kubeClient.CoreV1().RESTClient()).Events(""),
+	})
+
+	src := &SystemRestoreController{
+		baseController: newBaseController("longhorn-system-restore", logger),
+
+		namespace:    namespace,
+		controllerID: controllerID,
+
+		ds: ds,
+
+		kubeClient:    kubeClient,
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, v1.EventSource{Component: "longhorn-system-restore-controller"}),
+	}
+
+	ds.SystemRestoreInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    src.enqueueSystemRestore,
+		UpdateFunc: func(old, cur interface{}) { src.enqueueSystemRestore(cur) },
+		DeleteFunc: src.enqueueSystemRestore,
+	})
+	src.cacheSyncs = append(src.cacheSyncs, ds.SystemRestoreInformer.HasSynced)
+
+	return src
+}
+
+func (src *SystemRestoreController) enqueueSystemRestore(obj interface{}) {
+	key, err := controller.KeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", obj, err))
+		return
+	}
+
+	src.queue.AddRateLimited(key)
+}
+
+func (src *SystemRestoreController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer src.queue.ShutDown()
+
+	src.logger.Infof("Start Longhorn System Restore controller")
+	defer src.logger.Infof("Shutting down Longhorn System Restore controller")
+
+	if !cache.WaitForNamedCacheSync(src.name, stopCh, src.cacheSyncs...) {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.Until(src.worker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+func (src *SystemRestoreController) worker() {
+	for src.processNextWorkItem() {
+	}
+}
+
+func (src *SystemRestoreController) processNextWorkItem() bool {
+	key, quit := src.queue.Get()
+	if quit {
+		return false
+	}
+	defer src.queue.Done(key)
+	err := src.syncHandler(key.(string))
+	src.handleErr(err, key)
+	return true
+}
+
+func (src *SystemRestoreController) syncHandler(key string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "%v: fail to sync system restore %v", src.name, key)
+	}()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	if namespace != src.namespace {
+		// Not ours, skip it
+		return nil
+	}
+
+	if disabled, err := src.ds.IsControllerDisabled(src.name); err != nil {
+		return err
+	} else if disabled {
+		return nil
+	}
+	return src.reconcile(name)
+}
+
+func (src *SystemRestoreController) handleErr(err error, key interface{}) {
+	if err == nil {
+		src.queue.Forget(key)
+		return
+	}
+
+	if src.queue.NumRequeues(key) < maxRetries {
+		src.logger.WithError(err).Warnf("Error syncing Longhorn system restore %v", key)
+		src.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	src.logger.WithError(err).Warnf("Dropping Longhorn system restore %v out of the queue", key)
+	src.queue.Forget(key)
+}
+
+func getLoggerForSystemRestore(logger logrus.FieldLogger, systemRestore *longhorn.SystemRestore) *logrus.Entry {
+	return logger.WithFields(
+		logrus.Fields{
+			"systemRestore": systemRestore.Name,
+			"systemBackup":  systemRestore.Spec.SystemBackup,
+		},
+	)
+}
+
+func (src *SystemRestoreController) reconcile(systemRestoreName string) (err error) {
+	systemRestore, err := src.ds.GetSystemRestore(systemRestoreName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	if !src.isResponsibleFor(systemRestore) {
+		return nil
+	}
+	if systemRestore.Status.OwnerID != src.controllerID {
+		systemRestore.Status.OwnerID = src.controllerID
+		systemRestore, err = src.ds.UpdateSystemRestoreStatus(systemRestore)
+		if err != nil {
+			// we don't mind others coming first
+			if apierrors.IsConflict(errors.Cause(err)) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	log := getLoggerForSystemRestore(src.logger, systemRestore)
+
+	// Examine DeletionTimestamp to determine if object is under deletion
+	if !systemRestore.DeletionTimestamp.IsZero() {
+		return src.ds.RemoveFinalizerForSystemRestore(systemRestore)
+	}
+
+	existingSystemRestore := systemRestore.DeepCopy()
+	defer func() {
+		if err != nil {
+			return
+		}
+		if reflect.DeepEqual(existingSystemRestore.Status, systemRestore.Status) {
+			return
+		}
+		if _, err := src.ds.UpdateSystemRestoreStatus(systemRestore); err != nil && apierrors.IsConflict(errors.Cause(err)) {
+			log.WithError(err).Debugf("Requeue %v due to conflict", systemRestoreName)
+			src.enqueueSystemRestore(systemRestore)
+		}
+	}()
+
+	switch systemRestore.Status.State {
+	case longhorn.SystemRestoreStateNone:
+		systemRestore.Status.State = longhorn.SystemRestoreStateRestoring
+		src.eventRecorder.Eventf(systemRestore, v1.EventTypeNormal, EventReasonStart,
+			"Restoring Longhorn system from system backup %v", systemRestore.Spec.SystemBackup)
+		return nil
+	case longhorn.SystemRestoreStateRestoring:
+		if err := src.restoreSystemBackup(systemRestore, log); err != nil {
+			systemRestore.Status.State = longhorn.SystemRestoreStateError
+			systemRestore.Status.Error = err.Error()
+			src.eventRecorder.Eventf(systemRestore, v1.EventTypeWarning, EventReasonFailedRestore,
+				"Failed to restore Longhorn system from system backup %v: %v", systemRestore.Spec.SystemBackup, err)
+			return nil
+		}
+		systemRestore.Status.State = longhorn.SystemRestoreStateCompleted
+		systemRestore.Status.Error = ""
+		src.eventRecorder.Eventf(systemRestore, v1.EventTypeNormal, EventReasonRestored,
+			"Restored Longhorn system from system backup %v", systemRestore.Spec.SystemBackup)
+		return nil
+	default:
+		return nil
+	}
+}
+
+// restoreSystemBackup rebuilds the Longhorn resources recorded in the system
+// backup bundle. Resources that already exist in the cluster are left untouched
+// except for settings, which are reverted to the recorded values.
+func (src *SystemRestoreController) restoreSystemBackup(systemRestore *longhorn.SystemRestore, log logrus.FieldLogger) error {
+	cm, err := src.ds.GetConfigMapRO(src.namespace, types.GetSystemBackupConfigMapName(systemRestore.Spec.SystemBackup))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("cannot find the bundle of system backup %v", systemRestore.Spec.SystemBackup)
+		}
+		return err
+	}
+
+	bundle := &systemBackupBundle{}
+	for key, obj := range map[string]interface{}{
+		systemBackupBundleSettingsKey:      &bundle.Settings,
+		systemBackupBundleVolumesKey:       &bundle.Volumes,
+		systemBackupBundleBackingImagesKey: &bundle.BackingImages,
+		systemBackupBundleRecurringJobsKey: &bundle.RecurringJobs,
+	} {
+		if err := json.Unmarshal([]byte(cm.Data[key]), obj); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal %v of system backup %v", key, systemRestore.Spec.SystemBackup)
+		}
+	}
+
+	if err := src.restoreSettings(bundle.Settings); err != nil {
+		return err
+	}
+	if err := src.restoreRecurringJobs(bundle.RecurringJobs); err != nil {
+		return err
+	}
+	if err := src.restoreBackingImages(bundle.BackingImages, systemRestore.Spec.DiskMappings); err != nil {
+		return err
+	}
+	return src.restoreVolumes(bundle.Volumes, systemRestore, log)
+}
+
+// remapIdentities translates the recorded node/disk identities through the
+// restore mappings, the entries without a mapping are kept as is
+func remapIdentities(identities []string, mappings map[string]string) []string {
+	if len(identities) == 0 || len(mappings) == 0 {
+		return identities
+	}
+	remapped := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		if mapped, ok := mappings[identity]; ok {
+			identity = mapped
+		}
+		remapped = append(remapped, identity)
+	}
+	return remapped
+}
+
+func (src *SystemRestoreController) restoreSettings(settings []*longhorn.Setting) error {
+	for _, recorded := range settings {
+		setting, err := src.ds.GetSetting(types.SettingName(recorded.Name))
+		if err != nil {
+			return errors.Wrapf(err, "failed to get setting %v", recorded.Name)
+		}
+		if setting.Value == recorded.Value {
+			continue
+		}
+		setting.Value = recorded.Value
+		if _, err := src.ds.UpdateSetting(setting); err != nil {
+			return errors.Wrapf(err, "failed to restore setting %v", recorded.Name)
+		}
+	}
+	return nil
+}
+
+func (src *SystemRestoreController) restoreRecurringJobs(recurringJobs []*longhorn.RecurringJob) error {
+	for _, recorded := range recurringJobs {
+		if _, err := src.ds.GetRecurringJob(recorded.Name); err == nil {
+			continue
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if _, err := src.ds.CreateRecurringJob(recorded.DeepCopy()); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to restore recurring job %v", recorded.Name)
+		}
+	}
+	return nil
+}
+
+func (src *SystemRestoreController) restoreBackingImages(backingImages []*longhorn.BackingImage, diskMappings map[string]string) error {
+	for _, recorded := range backingImages {
+		if _, err := src.ds.GetBackingImage(recorded.Name); err == nil {
+			continue
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+		backingImage := recorded.DeepCopy()
+		if len(diskMappings) != 0 && len(backingImage.Spec.Disks) != 0 {
+			disks := map[string]string{}
+			for diskUUID, value := range backingImage.Spec.Disks {
+				if mapped, ok := diskMappings[diskUUID]; ok {
+					diskUUID = mapped
+				}
+				disks[diskUUID] = value
+			}
+			backingImage.Spec.Disks = disks
+		}
+		if _, err := src.ds.CreateBackingImage(backingImage); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to restore backing image %v", recorded.Name)
+		}
+	}
+	return nil
+}
+
+func (src *SystemRestoreController) restoreVolumes(volumes []*longhorn.Volume, systemRestore *longhorn.SystemRestore, log logrus.FieldLogger) error {
+	restoreFromBackup := systemRestore.Spec.RestoreVolumes
+	for _, recorded := range volumes {
+		if _, err := src.ds.GetVolume(recorded.Name); err == nil {
+			continue
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		volume := &longhorn.Volume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   recorded.Name,
+				Labels: recorded.Labels,
+			},
+			Spec: *recorded.Spec.DeepCopy(),
+		}
+		// The node the volume was attached to may not exist anymore
+		volume.Spec.NodeID = ""
+		volume.Spec.MigrationNodeID = ""
+		// The selector tags may refer to the old cluster identities
+		volume.Spec.NodeSelector = remapIdentities(volume.Spec.NodeSelector, systemRestore.Spec.NodeMappings)
+		volume.Spec.DiskSelector = remapIdentities(volume.Spec.DiskSelector, systemRestore.Spec.DiskMappings)
+
+		if restoreFromBackup && volume.Spec.FromBackup == "" && recorded.Status.LastBackup != "" {
+			backup, err := src.ds.GetBackupRO(recorded.Status.LastBackup)
+			switch {
+			case err != nil && !apierrors.IsNotFound(err):
+				return err
+			case err == nil && backup.Status.URL != "":
+				volume.Spec.FromBackup = backup.Status.URL
+			default:
+				log.Warnf("Cannot find the last backup %v of volume %v, restoring an empty volume",
+					recorded.Status.LastBackup, recorded.Name)
+			}
+		}
+
+		if _, err := src.ds.CreateVolume(volume); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to restore volume %v", recorded.Name)
+		}
+	}
+	return nil
+}
+
+func (src *SystemRestoreController) isResponsibleFor(systemRestore *longhorn.SystemRestore) bool {
+	return isControllerResponsibleFor(src.controllerID, src.ds, systemRestore.Name, "", systemRestore.Status.OwnerID)
+}
@@ -40,6 +40,9 @@ const (
 	CRDBackupVolumeName           = "backupvolumes.longhorn.io"
 	CRDBackupName                 = "backups.longhorn.io"
 	CRDRecurringJobName           = "recurringjobs.longhorn.io"
+	CRDSnapshotName               = "snapshots.longhorn.io"
+	CRDSystemBackupName           = "systembackups.longhorn.io"
+	CRDSystemRestoreName          = "systemrestores.longhorn.io"
 
 	LonghornNamespace = "longhorn-system"
 )
@@ -82,10 +85,12 @@ func NewUninstallController(
 	ds.CSIDriverInformer.AddEventHandler(c.controlleeHandler())
 	ds.DaemonSetInformer.AddEventHandler(c.namespacedControlleeHandler())
 	ds.DeploymentInformer.AddEventHandler(c.namespacedControlleeHandler())
+	ds.VolumeAttachmentInformer.AddEventHandler(c.controlleeHandler())
 	cacheSyncs := []cache.InformerSynced{
 		ds.CSIDriverInformer.HasSynced,
 		ds.DaemonSetInformer.HasSynced,
 		ds.DeploymentInformer.HasSynced,
+		ds.VolumeAttachmentInformer.HasSynced,
 	}
 
 	if _, err := extensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), CRDEngineName, metav1.GetOptions{}); err == nil {
@@ -144,6 +149,18 @@ func NewUninstallController(
 		ds.RecurringJobInformer.AddEventHandler(c.controlleeHandler())
 		cacheSyncs = append(cacheSyncs, ds.RecurringJobInformer.HasSynced)
 	}
+	if _, err := extensionsClient.ApiextensionsV1beta1().CustomResourceDefinitions().Get(context.TODO(), CRDSnapshotName, metav1.GetOptions{}); err == nil {
+		ds.SnapshotInformer.AddEventHandler(c.controlleeHandler())
+		cacheSyncs = append(cacheSyncs, ds.SnapshotInformer.HasSynced)
+	}
+	if _, err := extensionsClient.ApiextensionsV1beta1().CustomResourceDefinitions().Get(context.TODO(), CRDSystemBackupName, metav1.GetOptions{}); err == nil {
+		ds.SystemBackupInformer.AddEventHandler(c.controlleeHandler())
+		cacheSyncs = append(cacheSyncs, ds.SystemBackupInformer.HasSynced)
+	}
+	if _, err := extensionsClient.ApiextensionsV1beta1().CustomResourceDefinitions().Get(context.TODO(), CRDSystemRestoreName, metav1.GetOptions{}); err == nil {
+		ds.SystemRestoreInformer.AddEventHandler(c.controlleeHandler())
+		cacheSyncs = append(cacheSyncs, ds.SystemRestoreInformer.HasSynced)
+	}
 
 	c.cacheSyncs = cacheSyncs
 
@@ -438,6 +455,20 @@ func (c *UninstallController) deleteCRDs() (bool, error) {
 		return true, c.deleteRecurringJobs(recurringJobs)
 	}
 
+	if systemRestores, err := c.ds.ListSystemRestoresRO(); err != nil {
+		return true, err
+	} else if len(systemRestores) > 0 {
+		c.logger.Infof("Found %d system restores remaining", len(systemRestores))
+		return true, c.deleteSystemRestores(systemRestores)
+	}
+
+	if systemBackups, err := c.ds.ListSystemBackupsRO(); err != nil {
+		return true, err
+	} else if len(systemBackups) > 0 {
+		c.logger.Infof("Found %d system backups remaining", len(systemBackups))
+		return true, c.deleteSystemBackups(systemBackups)
+	}
+
 	if nodes, err := c.ds.ListNodes(); err != nil {
 		return true, err
 	} else if len(nodes) > 0 {
@@ -727,6 +758,52 @@ func (c *UninstallController) deleteRecurringJobs(recurringJobs map[string]*long
 	return nil
 }
 
+func (c *UninstallController) deleteSystemBackups(systemBackups map[string]*longhorn.SystemBackup) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "Failed to delete system backups")
+	}()
+	for _, sb := range systemBackups {
+		log := c.logger.WithField("systemBackup", sb.Name)
+
+		timeout := metav1.NewTime(time.Now().Add(-gracePeriod))
+		if sb.DeletionTimestamp == nil {
+			if err = c.ds.DeleteSystemBackup(sb.Name); err != nil {
+				return errors.Wrapf(err, "Failed to mark for deletion")
+			}
+			log.Info("Marked for deletion")
+		} else if sb.DeletionTimestamp.Before(&timeout) {
+			if err = c.ds.RemoveFinalizerForSystemBackup(sb.DeepCopy()); err != nil {
+				return errors.Wrapf(err, "Failed to remove finalizer")
+			}
+			log.Info("Removed finalizer")
+		}
+	}
+	return nil
+}
+
+func (c *UninstallController) deleteSystemRestores(systemRestores map[string]*longhorn.SystemRestore) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "Failed to delete system restores")
+	}()
+	for _, sr := range systemRestores {
+		log := c.logger.WithField("systemRestore", sr.Name)
+
+		timeout := metav1.NewTime(time.Now().Add(-gracePeriod))
+		if sr.DeletionTimestamp == nil {
+			if err = c.ds.DeleteSystemRestore(sr.Name); err != nil {
+				return errors.Wrapf(err, "Failed to mark for deletion")
+			}
+			log.Info("Marked for deletion")
+		} else if sr.DeletionTimestamp.Before(&timeout) {
+			if err = c.ds.RemoveFinalizerForSystemRestore(sr.DeepCopy()); err != nil {
+				return errors.Wrapf(err, "Failed to remove finalizer")
+			}
+			log.Info("Removed finalizer")
+		}
+	}
+	return nil
+}
+
 func (c *UninstallController) deleteManager() (bool, error) {
 	log := getLoggerForUninstallDaemonSet(c.logger, types.LonghornManagerDaemonSetName)
 
@@ -831,6 +908,14 @@ func (c *UninstallController) deleteDriver() (bool, error) {
 		wait = true
 	}
 
+	// The CSI plugin has to outlive the last published volume on each node,
+	// otherwise the remaining workloads can no longer unmount their Longhorn
+	// volumes.
+	publishedCount, err := c.countPublishedLonghornVolumeAttachments()
+	if err != nil {
+		return true, err
+	}
+
 	daemonSetsToClean := []string{
 		types.CSIPluginName,
 	}
@@ -845,6 +930,11 @@ func (c *UninstallController) deleteDriver() (bool, error) {
 			wait = true
 			continue
 		} else if driver.DeletionTimestamp == nil {
+			if publishedCount > 0 {
+				log.Warnf("Waiting for %v attached Longhorn VolumeAttachment(s) to be cleaned up before deletion", publishedCount)
+				wait = true
+				continue
+			}
 			if err := c.ds.DeleteDaemonSet(name); err != nil {
 				log.WithError(err).Warn("Failed to mark for deletion")
 				wait = true
@@ -868,3 +958,20 @@ func (c *UninstallController) deleteDriver() (bool, error) {
 
 	return wait, nil
 }
+
+// countPublishedLonghornVolumeAttachments counts the VolumeAttachments the
+// Longhorn CSI driver is still responsible for. As long as there is one, a
+// workload may have a Longhorn volume mounted on the referenced node.
+func (c *UninstallController) countPublishedLonghornVolumeAttachments() (int, error) {
+	vas, err := c.ds.ListVolumeAttachmentsRO()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, va := range vas {
+		if va.Spec.Attacher == types.LonghornDriverName && va.Status.Attached {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -142,6 +142,11 @@ func NewVolumeController(
 	})
 	vc.cacheSyncs = append(vc.cacheSyncs, ds.BackupVolumeInformer.HasSynced)
 
+	ds.NodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: vc.enqueueVolumesForNodeBackOnline,
+	})
+	vc.cacheSyncs = append(vc.cacheSyncs, ds.NodeInformer.HasSynced)
+
 	ds.BackingImageDataSourceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    vc.enqueueVolumesForBackingImageDataSource,
 		UpdateFunc: func(old, cur interface{}) { vc.enqueueVolumesForBackingImageDataSource(cur) },
@@ -532,6 +537,24 @@ func (vc *VolumeController) ReconcileEngineReplicaState(v *longhorn.Volume, es m
 		}
 	}
 
+	// Reflect the aggregated replica restore progress in the restore
+	// condition so the restore can be followed on the volume itself.
+	if v.Status.RestoreRequired || v.Status.IsStandby {
+		restoringCount := 0
+		totalProgress := 0
+		for _, status := range restoreStatusMap {
+			if status.IsRestoring {
+				restoringCount++
+				totalProgress += status.Progress
+			}
+		}
+		if restoringCount > 0 {
+			v.Status.Conditions = types.SetCondition(v.Status.Conditions,
+				longhorn.VolumeConditionTypeRestore, longhorn.ConditionStatusTrue, longhorn.VolumeConditionReasonRestoreInProgress,
+				fmt.Sprintf("%v of %v replicas are restoring, average progress %v%%", restoringCount, len(rs), totalProgress/restoringCount))
+		}
+	}
+
 	// 1. remove ERR replicas
 	// 2. count RW replicas
 	healthyCount := 0
@@ -575,6 +598,7 @@ func (vc *VolumeController) ReconcileEngineReplicaState(v *longhorn.Volume, es m
 	for _, r := range rs {
 		if r.Spec.FailedAt == "" && r.Status.CurrentState == longhorn.InstanceStateError {
 			log.Warnf("Replica %v that not in the engine mode map is marked as failed, current state %v, engine name %v, active %v", r.Name, r.Status.CurrentState, r.Spec.EngineName, r.Spec.Active)
+			vc.eventRecorder.Eventf(v, v1.EventTypeWarning, EventReasonFaulted, "replica %v of volume %v failed before registering with the engine", r.Name, v.Name)
 			e.Spec.LogRequested = true
 			r.Spec.LogRequested = true
 			r.Spec.FailedAt = vc.nowHandler()
@@ -625,7 +649,10 @@ func (vc *VolumeController) ReconcileEngineReplicaState(v *longhorn.Volume, es m
 		v.Status.Robustness = longhorn.VolumeRobustnessDegraded
 		if oldRobustness != longhorn.VolumeRobustnessDegraded {
 			v.Status.LastDegradedAt = vc.nowHandler()
-			vc.eventRecorder.Eventf(v, v1.EventTypeNormal, EventReasonDegraded, "volume %v became degraded", v.Name)
+			// The degradation is expected during a maintenance pause, so don't alert on it
+			if !vc.isVolumeUnderMaintenance(v) {
+				vc.eventRecorder.Eventf(v, v1.EventTypeNormal, EventReasonDegraded, "volume %v became degraded", v.Name)
+			}
 		}
 
 		cliAPIVersion, err := vc.ds.GetEngineImageCLIAPIVersion(e.Status.CurrentImage)
@@ -650,9 +677,11 @@ func (vc *VolumeController) ReconcileEngineReplicaState(v *longhorn.Volume, es m
 		if status != nil && status.State == engineapi.ProcessStateComplete && v.Status.CloneStatus.State != longhorn.VolumeCloneStateCompleted {
 			v.Status.CloneStatus.State = longhorn.VolumeCloneStateCompleted
 			vc.eventRecorder.Eventf(v, v1.EventTypeNormal, EventReasonVolumeCloneCompleted, "finished cloning snapshot %v from source volume %v", v.Status.CloneStatus.Snapshot, v.Status.CloneStatus.SourceVolume)
+			vc.cleanupSnapshotForCloning(v)
 		} else if status != nil && status.State == engineapi.ProcessStateError && v.Status.CloneStatus.State != longhorn.VolumeCloneStateFailed {
 			v.Status.CloneStatus.State = longhorn.VolumeCloneStateFailed
 			vc.eventRecorder.Eventf(v, v1.EventTypeWarning, EventReasonVolumeCloneFailed, "failed to clone snapshot %v from source volume %v: %v", v.Status.CloneStatus.Snapshot, v.Status.CloneStatus.SourceVolume, status.Error)
+			vc.cleanupSnapshotForCloning(v)
 		}
 	}
 
@@ -790,6 +819,9 @@ func (vc *VolumeController) cleanupFailedToScheduledReplicas(v *longhorn.Volume,
 func (vc *VolumeController) cleanupExtraHealthyReplicas(v *longhorn.Volume, e *longhorn.Engine, rs map[string]*longhorn.Replica) (err error) {
 	healthyCount := getHealthyAndActiveReplicaCount(rs)
 	if healthyCount <= v.Spec.NumberOfReplicas {
+		// revert pending cleanups once the replicas are not redundant anymore,
+		// e.g. the replica count was scaled back up within the grace period
+		vc.unmarkCleanupPendingReplicas(rs)
 		return nil
 	}
 
@@ -860,10 +892,10 @@ func (vc *VolumeController) cleanupAutoBalancedReplicas(v *longhorn.Volume, e *l
 	// we always delete the replica with the smallest name.
 	sort.Strings(rNames)
 	r := rs[rNames[0]]
-	if err := vc.deleteReplica(r, rs); err != nil {
+	if err := vc.deleteOrMarkCleanupPendingReplica(r, rs); err != nil {
 		return false, err
 	}
-	log.Debugf("Deleted replica %v", r.Name)
+	log.Debugf("Cleaning up replica %v", r.Name)
 	return true, nil
 }
 
@@ -883,7 +915,7 @@ func (vc *VolumeController) cleanupDataLocalityReplicas(v *longhorn.Volume, e *l
 		for _, rName := range rNames {
 			r := rs[rName]
 			if r.Spec.NodeID != e.Spec.NodeID {
-				if err := vc.deleteReplica(r, rs); err != nil {
+				if err := vc.deleteOrMarkCleanupPendingReplica(r, rs); err != nil {
 					return false, err
 				}
 				return true, nil
@@ -893,6 +925,45 @@ func (vc *VolumeController) cleanupDataLocalityReplicas(v *longhorn.Volume, e *l
 	return false, nil
 }
 
+// deleteOrMarkCleanupPendingReplica deletes the redundant replica right away
+// when the replica-cleanup-grace-period setting is 0. Otherwise the replica is
+// kept around and only marked as cleanup-pending first, then deleted after the
+// grace period elapsed, which leaves a window to revert a mistaken scale-down.
+func (vc *VolumeController) deleteOrMarkCleanupPendingReplica(r *longhorn.Replica, rs map[string]*longhorn.Replica) error {
+	gracePeriod, err := vc.ds.GetSettingAsInt(types.SettingNameReplicaCleanupGracePeriod)
+	if err != nil {
+		return err
+	}
+	if gracePeriod <= 0 {
+		return vc.deleteReplica(r, rs)
+	}
+
+	if r.Spec.CleanupRequestedAt == "" {
+		logrus.Infof("Marking redundant replica %v as cleanup-pending, it will be deleted after %v seconds", r.Name, gracePeriod)
+		r.Spec.CleanupRequestedAt = vc.nowHandler()
+		return nil
+	}
+
+	cleanupRequestedAt, err := util.ParseTime(r.Spec.CleanupRequestedAt)
+	if err != nil {
+		return err
+	}
+	if time.Since(cleanupRequestedAt) < time.Duration(gracePeriod)*time.Second {
+		return nil
+	}
+	return vc.deleteReplica(r, rs)
+}
+
+// unmarkCleanupPendingReplicas cancels pending replica cleanups.
+func (vc *VolumeController) unmarkCleanupPendingReplicas(rs map[string]*longhorn.Replica) {
+	for _, r := range rs {
+		if r.Spec.CleanupRequestedAt != "" {
+			logrus.Infof("Unmarking cleanup-pending replica %v since it is needed again", r.Name)
+			r.Spec.CleanupRequestedAt = ""
+		}
+	}
+}
+
 func (vc *VolumeController) getAutoBalancedReplicasSetting(v *longhorn.Volume) (longhorn.ReplicaAutoBalance, error) {
 	var setting longhorn.ReplicaAutoBalance
 
@@ -919,6 +990,18 @@ func (vc *VolumeController) getAutoBalancedReplicasSetting(v *longhorn.Volume) (
 	return setting, errors.Wrapf(err, "replica auto-balance is disabled")
 }
 
+// getAutoSalvageSetting resolves the effective auto-salvage behavior for the
+// volume, letting the per-volume override win over the global setting.
+func (vc *VolumeController) getAutoSalvageSetting(v *longhorn.Volume) (bool, error) {
+	switch v.Spec.AutoSalvage {
+	case longhorn.AutoSalvageEnabled:
+		return true, nil
+	case longhorn.AutoSalvageDisabled:
+		return false, nil
+	}
+	return vc.ds.GetSettingAsBool(types.SettingNameAutoSalvage)
+}
+
 // ReconcileVolumeState handles the attaching and detaching of volume
 func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[string]*longhorn.Engine, rs map[string]*longhorn.Replica) (err error) {
 	defer func() {
@@ -936,6 +1019,9 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 	if v.Status.CurrentImage == "" {
 		v.Status.CurrentImage = v.Spec.EngineImage
 	}
+	if e != nil && e.Status.CurrentImageVersionDetails != v.Status.CurrentImageVersionDetails {
+		v.Status.CurrentImageVersionDetails = e.Status.CurrentImageVersionDetails
+	}
 
 	if err := vc.checkAndInitVolumeRestore(v); err != nil {
 		return err
@@ -992,6 +1078,7 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 
 	if len(e.Status.Snapshots) != 0 {
 		actualSize := int64(0)
+		snapshotsSize := int64(0)
 		for _, snapshot := range e.Status.Snapshots {
 			size, err := util.ConvertSize(snapshot.Size)
 			if err != nil {
@@ -999,8 +1086,12 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 				// continue checking for other snapshots
 			}
 			actualSize += size
+			if snapshot.Name != engineapi.VolumeHeadName {
+				snapshotsSize += size
+			}
 		}
 		v.Status.ActualSize = actualSize
+		v.Status.SnapshotsSize = snapshotsSize
 	}
 
 	if len(e.Status.Snapshots) > VolumeSnapshotsWarningThreshold {
@@ -1013,6 +1104,11 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 			"", "")
 	}
 
+	if err := vc.pruneSnapshotsOverLimit(v, e); err != nil {
+		// The pruning failure shouldn't block the volume reconciliation
+		log.WithError(err).Warn("Failed to prune the snapshots exceeding the volume snapshot limits")
+	}
+
 	if len(rs) == 0 {
 		// first time creation
 		if err := vc.replenishReplicas(v, e, rs, ""); err != nil {
@@ -1110,13 +1206,21 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 
 	isAutoSalvageNeeded := getHealthyAndActiveReplicaCount(rs) == 0 && getFailedReplicaCount(rs) > 0
 	if isAutoSalvageNeeded {
+		if v.Status.Robustness != longhorn.VolumeRobustnessFaulted {
+			vc.eventRecorder.Eventf(v, v1.EventTypeWarning, EventReasonFaulted, "volume %v became faulted: all replicas failed", v.Name)
+		}
 		v.Status.Robustness = longhorn.VolumeRobustnessFaulted
 		v.Status.CurrentNodeID = ""
 
-		autoSalvage, err := vc.ds.GetSettingAsBool(types.SettingNameAutoSalvage)
+		autoSalvage, err := vc.getAutoSalvageSetting(v)
 		if err != nil {
 			return err
 		}
+		// A maintenance pause suppresses the automatic salvage so that planned
+		// failure experiments don't heal themselves right away.
+		if autoSalvage && (vc.isVolumeUnderMaintenance(v) || RecoveryMode) {
+			autoSalvage = false
+		}
 		// To make sure that we don't miss the `isAutoSalvageNeeded` event, This IF statement makes sure the `e.Spec.SalvageRequested=true`
 		// persist in ETCD before Longhorn salvages the failed replicas in the IF statement below it.
 		// More explanation: when all replicas fails, Longhorn tries to set `e.Spec.SalvageRequested=true`
@@ -1147,6 +1251,9 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 				lastFailedAt := time.Time{}
 				failedUsableReplicas := map[string]*longhorn.Replica{}
 				dataExists := false
+				// Remember why replicas that once held data were left out so
+				// the salvage decision can be explained in events.
+				skippedReplicas := map[string]string{}
 
 				for _, r := range rs {
 					if r.Spec.HealthyAt == "" {
@@ -1160,6 +1267,7 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 						log.WithField("replica", r.Name).WithError(err).Errorf("Unable to check if node %v is still running for failed replica", r.Spec.NodeID)
 						continue
 					} else if isDownOrDeleted {
+						skippedReplicas[r.Name] = fmt.Sprintf("its node %v is down or deleted", r.Spec.NodeID)
 						continue
 					}
 					node, err := vc.ds.GetNode(r.Spec.NodeID)
@@ -1176,6 +1284,7 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 						}
 					}
 					if !diskSchedulable {
+						skippedReplicas[r.Name] = fmt.Sprintf("its disk %v is not schedulable", r.Spec.DiskID)
 						continue
 					}
 					failedAt, err := util.ParseTime(r.Spec.FailedAt)
@@ -1197,14 +1306,21 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 					// Bring up the replicas for auto-salvage
 					for _, r := range failedUsableReplicas {
 						if util.TimestampWithinLimit(lastFailedAt, r.Spec.FailedAt, AutoSalvageTimeLimit) {
+							failedAt := r.Spec.FailedAt
 							r.Spec.FailedAt = ""
 							log.WithField("replica", r.Name).Warn("Automatically salvaging volume replica")
-							msg := fmt.Sprintf("Replica %v of volume %v will be automatically salvaged", r.Name, v.Name)
+							msg := fmt.Sprintf("Replica %v of volume %v will be automatically salvaged since it failed at %v, close to the most recent replica failure", r.Name, v.Name, failedAt)
 							vc.eventRecorder.Event(v, v1.EventTypeWarning, EventReasonAutoSalvaged, msg)
 							salvaged = true
+						} else {
+							skippedReplicas[r.Name] = fmt.Sprintf("its data became stale more than %v before the most recent replica failure at %v", AutoSalvageTimeLimit, lastFailedAt.Format(time.RFC3339))
 						}
 					}
 					if salvaged {
+						for rName, reason := range skippedReplicas {
+							vc.eventRecorder.Eventf(v, v1.EventTypeWarning, EventReasonAutoSalvaged,
+								"Replica %v of volume %v was not salvaged because %v", rName, v.Name, reason)
+						}
 						// remount the reattached volume later if possible
 						// For the auto-salvaged volume, `v.Status.CurrentNodeID` is empty but `v.Spec.NodeID` shouldn't be empty.
 						// There shouldn't be any problems if v.Spec.NodeID is empty, since the volume is desired to be detached
@@ -1241,6 +1357,9 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 					rs[r.Name] = r
 				}
 			}
+			if v.Status.Robustness != longhorn.VolumeRobustnessFaulted {
+				vc.eventRecorder.Eventf(v, v1.EventTypeWarning, EventReasonFaulted, "volume %v became faulted: engine %v failed unexpectedly", v.Name, e.Name)
+			}
 			v.Status.Robustness = longhorn.VolumeRobustnessFaulted
 			v.Status.CurrentNodeID = ""
 		}
@@ -1449,6 +1568,8 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 			v.Status.ExpansionRequired = false
 			v.Status.FrontendDisabled = false
 			e.Spec.DisableFrontend = false
+			vc.eventRecorder.Eventf(v, v1.EventTypeNormal, EventReasonSucceededExpansion,
+				"volume %v has been expanded to size %v", v.Name, e.Status.CurrentSize)
 		}
 
 		v.Status.State = longhorn.VolumeStateAttached
@@ -1459,6 +1580,67 @@ func (vc *VolumeController) ReconcileVolumeState(v *longhorn.Volume, es map[stri
 	return nil
 }
 
+// pruneSnapshotsOverLimit enforces the volume snapshot space policy. Once the
+// snapshot count or the total snapshot size of the volume exceeds the
+// user-specified limit, the oldest removable snapshots are deleted and a purge
+// is requested to reclaim the space.
+func (vc *VolumeController) pruneSnapshotsOverLimit(v *longhorn.Volume, e *longhorn.Engine) error {
+	if v.Spec.SnapshotMaxCount <= 0 && v.Spec.SnapshotMaxSize <= 0 {
+		return nil
+	}
+	if e.Status.CurrentState != longhorn.InstanceStateRunning || len(e.Status.Snapshots) == 0 {
+		return nil
+	}
+
+	// Collect the snapshots not marked as removed yet, the oldest first.
+	// The volume head is not a snapshot and cannot be pruned.
+	candidates := []*longhorn.SnapshotInfo{}
+	totalSize := int64(0)
+	for _, snapshot := range e.Status.Snapshots {
+		if snapshot.Name == engineapi.VolumeHeadName || snapshot.Removed {
+			continue
+		}
+		size, err := util.ConvertSize(snapshot.Size)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse size %v of snapshot %v", snapshot.Size, snapshot.Name)
+		}
+		totalSize += size
+		candidates = append(candidates, snapshot)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Created < candidates[j].Created })
+
+	pruned := []*longhorn.SnapshotInfo{}
+	for _, snapshot := range candidates {
+		overCount := v.Spec.SnapshotMaxCount > 0 && len(candidates)-len(pruned) > v.Spec.SnapshotMaxCount
+		overSize := v.Spec.SnapshotMaxSize > 0 && totalSize > v.Spec.SnapshotMaxSize
+		if !overCount && !overSize {
+			break
+		}
+		size, err := util.ConvertSize(snapshot.Size)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse size %v of snapshot %v", snapshot.Size, snapshot.Name)
+		}
+		totalSize -= size
+		pruned = append(pruned, snapshot)
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	engineClient, err := GetClientForEngine(e, &engineapi.EngineCollection{}, e.Status.CurrentImage)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range pruned {
+		if err := engineClient.SnapshotDelete(snapshot.Name); err != nil {
+			return errors.Wrapf(err, "failed to delete snapshot %v over the volume snapshot limits", snapshot.Name)
+		}
+		vc.eventRecorder.Eventf(v, v1.EventTypeNormal, EventReasonDelete,
+			"Pruned snapshot %v since the volume exceeds the snapshot limits", snapshot.Name)
+	}
+	return engineClient.SnapshotPurge()
+}
+
 func (vc *VolumeController) canInstanceManagerLaunchReplica(r *longhorn.Replica) (bool, error) {
 	nodeDown, err := vc.ds.IsNodeDownOrDeleted(r.Spec.NodeID)
 	if err != nil {
@@ -1566,6 +1748,21 @@ func (vc *VolumeController) replenishReplicas(v *longhorn.Volume, e *longhorn.En
 		return nil
 	}
 
+	// Defer the non-critical rebuild to the configured off-peak window. A volume
+	// down to its last healthy replica is always replenished immediately.
+	if len(rs) != 0 && getHealthyAndActiveReplicaCount(rs) >= 2 {
+		if vc.isVolumeUnderMaintenance(v) || RecoveryMode {
+			return nil
+		}
+		deferred, err := vc.isRebuildDeferredByWindow(v)
+		if err != nil {
+			return err
+		}
+		if deferred {
+			return nil
+		}
+	}
+
 	if vc.isVolumeMigrating(v) {
 		return nil
 	}
@@ -1936,6 +2133,51 @@ func (vc *VolumeController) getReplicaCountForAutoBalanceNode(v *longhorn.Volume
 	return adjustCount, nodeExtraRs, err
 }
 
+// isRebuildDeferredByWindow checks if the replica rebuild of the volume needs to
+// wait for the time window specified by setting SettingNameReplicaRebuildWindow.
+// The callers should guarantee the volume still has enough healthy replicas
+// before deferring the rebuild.
+func (vc *VolumeController) isRebuildDeferredByWindow(v *longhorn.Volume) (bool, error) {
+	setting, err := vc.ds.GetSetting(types.SettingNameReplicaRebuildWindow)
+	if err != nil {
+		return false, err
+	}
+	window := setting.Value
+	if window == "" {
+		return false, nil
+	}
+	now, err := util.ParseTime(vc.nowHandler())
+	if err != nil {
+		return false, err
+	}
+	inWindow, err := util.IsTimeInDailyWindow(now, window)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check the replica rebuild window for volume %v", v.Name)
+	}
+	if !inWindow {
+		getLoggerForVolume(vc.logger, v).Debugf("Deferring the replica rebuild until the rebuild window %v", window)
+	}
+	return !inWindow, nil
+}
+
+// isVolumeUnderMaintenance returns true while the user-requested maintenance
+// pause on the volume is in effect. A malformed expiry time is treated as
+// already expired so that a typo cannot suppress self-healing forever.
+func (vc *VolumeController) isVolumeUnderMaintenance(v *longhorn.Volume) bool {
+	if !v.Spec.Maintenance {
+		return false
+	}
+	if v.Spec.MaintenanceExpiresAt == "" {
+		return true
+	}
+	expiry, err := util.ParseTime(v.Spec.MaintenanceExpiresAt)
+	if err != nil {
+		getLoggerForVolume(vc.logger, v).WithError(err).Warnf("Unable to parse the maintenance expiry time %v, considering the maintenance expired", v.Spec.MaintenanceExpiresAt)
+		return false
+	}
+	return time.Now().Before(expiry)
+}
+
 func (vc *VolumeController) getReplenishReplicasCount(v *longhorn.Volume, rs map[string]*longhorn.Replica, e *longhorn.Engine) (int, string) {
 	usableCount := 0
 	for _, r := range rs {
@@ -2328,6 +2570,12 @@ func (vc *VolumeController) updateRequestedBackupForVolumeRestore(v *longhorn.Vo
 		return nil
 	}
 
+	// Surface the restore progress of the engine so the lag of a standby
+	// volume can be read from the volume itself.
+	if e.Status.LastRestoredBackup != v.Status.LastRestoredBackup {
+		v.Status.LastRestoredBackup = e.Status.LastRestoredBackup
+	}
+
 	// For DR volume, we set RequestedBackupRestore to the LastBackup
 	if v.Status.IsStandby {
 		if v.Status.LastBackup != "" && v.Status.LastBackup != e.Spec.RequestedBackupRestore {
@@ -2466,6 +2714,34 @@ func (vc *VolumeController) checkAndInitVolumeClone(v *longhorn.Volume) (err err
 	return nil
 }
 
+// cleanupSnapshotForCloning deletes the snapshot that was automatically
+// created on the source volume for cloning v. Snapshots picked by the user are
+// left alone; they are recognized by the missing cloning label. The cleanup is
+// best effort since the cloning itself already finished.
+func (vc *VolumeController) cleanupSnapshotForCloning(v *longhorn.Volume) {
+	snapshotName := v.Status.CloneStatus.Snapshot
+	if snapshotName == "" {
+		return
+	}
+	log := vc.logger.WithField("volume", v.Name)
+	engineClient, err := vc.getEngineClient(v.Status.CloneStatus.SourceVolume)
+	if err != nil {
+		log.WithError(err).Warnf("Failed to get the engine client of the source volume %v to clean up the snapshot %v for cloning", v.Status.CloneStatus.SourceVolume, snapshotName)
+		return
+	}
+	snapshot, err := engineClient.SnapshotGet(snapshotName)
+	if err != nil {
+		log.WithError(err).Warnf("Failed to get the snapshot %v of the source volume %v to clean up for cloning", snapshotName, v.Status.CloneStatus.SourceVolume)
+		return
+	}
+	if snapshot == nil || snapshot.Labels[types.GetLonghornLabelKey(types.LonghornLabelSnapshotForCloningVolume)] != v.Name {
+		return
+	}
+	if err := engineClient.SnapshotDelete(snapshotName); err != nil {
+		log.WithError(err).Warnf("Failed to delete the snapshot %v of the source volume %v created for cloning", snapshotName, v.Status.CloneStatus.SourceVolume)
+	}
+}
+
 // isTargetVolumeOfCloning checks if the input volume is the target volume of an on-going cloning process
 func isTargetVolumeOfCloning(v *longhorn.Volume) bool {
 	isCloningDesired := types.IsDataFromVolume(v.Spec.DataSource)
@@ -2548,6 +2824,7 @@ func (vc *VolumeController) checkForAutoAttachment(v *longhorn.Volume, e *longho
 	//   4. The target volume of a cloning
 	//   5. The source volume of a cloning
 	//   6, Export data as a backing image
+	//   7. Offline replica rebuilding
 	isRestoringDRVol := v.Status.RestoreRequired || v.Status.IsStandby
 	isOfflineExpansionVol := v.Status.ExpansionRequired
 	isEvictionRequestedOnVol := vc.hasReplicaEvictionRequested(rs)
@@ -2557,8 +2834,13 @@ func (vc *VolumeController) checkForAutoAttachment(v *longhorn.Volume, e *longho
 	if err != nil {
 		return err
 	}
+	isOfflineRebuildVol, err := vc.isOfflineReplicaRebuildingNeeded(v, rs)
+	if err != nil {
+		return err
+	}
 	if isRestoringDRVol || isOfflineExpansionVol || isEvictionRequestedOnVol ||
-		isTargetVolOfCloning || sourceVolumeOfCloning || isExportingBackingImage {
+		isTargetVolOfCloning || sourceVolumeOfCloning || isExportingBackingImage ||
+		isOfflineRebuildVol {
 		// Should use vc.controllerID or v.Status.OwnerID as CurrentNodeID,
 		// otherwise they may be not equal
 		v.Status.CurrentNodeID = v.Status.OwnerID
@@ -2567,6 +2849,24 @@ func (vc *VolumeController) checkForAutoAttachment(v *longhorn.Volume, e *longho
 	return nil
 }
 
+// isOfflineReplicaRebuildingNeeded checks if the volume is missing some
+// replicas while it still has healthy ones to rebuild from. The volume is
+// then temporarily attached without frontend so the regular replenishment
+// logic can rebuild the missing replicas, and detached again once it becomes
+// healthy. The whole workflow is controlled by the setting
+// `offline-replica-rebuilding`.
+func (vc *VolumeController) isOfflineReplicaRebuildingNeeded(v *longhorn.Volume, rs map[string]*longhorn.Replica) (bool, error) {
+	enabled, err := vc.ds.GetSettingAsBool(types.SettingNameOfflineReplicaRebuilding)
+	if err != nil {
+		return false, err
+	}
+	if !enabled {
+		return false, nil
+	}
+	healthyCount := getHealthyAndActiveReplicaCount(rs)
+	return healthyCount > 0 && healthyCount < int(v.Spec.NumberOfReplicas), nil
+}
+
 func (vc *VolumeController) checkForAutoDetachment(v *longhorn.Volume, e *longhorn.Engine, rs map[string]*longhorn.Replica) error {
 	log := getLoggerForVolume(vc.logger, v)
 
@@ -2602,6 +2902,14 @@ func (vc *VolumeController) checkForAutoDetachment(v *longhorn.Volume, e *longho
 		return nil
 	}
 
+	// Keep the auto attached volume until the offline replica rebuilding
+	// is finished.
+	if isOfflineRebuildVol, err := vc.isOfflineReplicaRebuildingNeeded(v, rs); err != nil {
+		return err
+	} else if isOfflineRebuildVol {
+		return nil
+	}
+
 	// Do auto-detachment for non-restore/DR volumes.
 	if !v.Status.RestoreRequired && !v.Status.IsStandby {
 		v.Status.CurrentNodeID = ""
@@ -2815,16 +3123,82 @@ func (vc *VolumeController) updateRecurringJobs(v *longhorn.Volume) (err error)
 	}()
 
 	existingVolume := v.DeepCopy()
+	if err = vc.translateDeprecatedRecurringJobs(v); err != nil {
+		return err
+	}
 	if err = datastore.FixupRecurringJob(v); err != nil {
 		return err
 	}
-	if err == nil && !reflect.DeepEqual(existingVolume.Labels, v.Labels) {
+	if !reflect.DeepEqual(existingVolume.Labels, v.Labels) ||
+		!reflect.DeepEqual(existingVolume.Spec.RecurringJobs, v.Spec.RecurringJobs) {
 		_, err = vc.ds.UpdateVolume(v)
 	}
 
 	return nil
 }
 
+// translateDeprecatedRecurringJobs converts the deprecated field
+// volume.Spec.RecurringJobs into RecurringJob CRs and the corresponding
+// volume labels, then cleans up the field. This keeps volumes created
+// directly with the old field working after the recurring jobs were moved
+// to the RecurringJob CRD.
+func (vc *VolumeController) translateDeprecatedRecurringJobs(v *longhorn.Volume) error {
+	if len(v.Spec.RecurringJobs) == 0 {
+		return nil
+	}
+
+	if v.Labels == nil {
+		v.Labels = map[string]string{}
+	}
+	for _, job := range v.Spec.RecurringJobs {
+		spec := longhorn.RecurringJobSpec{
+			Task:        job.Task,
+			Cron:        job.Cron,
+			Retain:      job.Retain,
+			Concurrency: types.DefaultRecurringJobConcurrency,
+			Labels:      job.Labels,
+		}
+		id, err := getRecurringJobID(spec)
+		if err != nil {
+			return err
+		}
+		if _, err := vc.ds.GetRecurringJob(id); err != nil {
+			if !datastore.ErrorIsNotFound(err) {
+				return err
+			}
+			spec.Name = id
+			if _, err := vc.ds.CreateRecurringJob(&longhorn.RecurringJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: id,
+				},
+				Spec: spec,
+			}); err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+		v.Labels[types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJob, id)] = types.LonghornLabelValueEnabled
+	}
+
+	getLoggerForVolume(vc.logger, v).Infof("Translated %v deprecated spec recurring jobs to recurring job labels", len(v.Spec.RecurringJobs))
+	v.Spec.RecurringJobs = nil
+	return nil
+}
+
+// getRecurringJobID generates a deterministic RecurringJob name from the job
+// parameters so identical schedules of different volumes share one CR
+func getRecurringJobID(recurringJob longhorn.RecurringJobSpec) (string, error) {
+	labelJSON, err := json.Marshal(recurringJob.Labels)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal JSON %v", recurringJob.Labels)
+	}
+	return fmt.Sprintf("%v-%v-%v-%v",
+		recurringJob.Task,
+		recurringJob.Retain,
+		util.GetStringHash(recurringJob.Cron),
+		util.GetStringHash(string(labelJSON)),
+	), nil
+}
+
 func (vc *VolumeController) isVolumeUpgrading(v *longhorn.Volume) bool {
 	return v.Status.CurrentImage != v.Spec.EngineImage
 }
@@ -2920,7 +3294,7 @@ func (vc *VolumeController) processMigration(v *longhorn.Volume, es map[string]*
 		err = errors.Wrapf(err, "fail to process migration for %v", v.Name)
 	}()
 
-	if !v.Spec.Migratable || v.Spec.AccessMode != longhorn.AccessModeReadWriteMany {
+	if !v.Spec.Migratable {
 		return nil
 	}
 
@@ -3294,6 +3668,36 @@ func (vc *VolumeController) deleteEngine(e *longhorn.Engine, es map[string]*long
 	return nil
 }
 
+// enqueueVolumesForNodeBackOnline enqueues all volumes once a node transitions
+// back to ready, so that the replica auto-balance logic can correct the replica
+// distribution skew introduced while the node was down instead of waiting for
+// the next unrelated volume event.
+func (vc *VolumeController) enqueueVolumesForNodeBackOnline(old, cur interface{}) {
+	oldNode, ok := old.(*longhorn.Node)
+	if !ok {
+		return
+	}
+	curNode, ok := cur.(*longhorn.Node)
+	if !ok {
+		return
+	}
+
+	oldReadyCondition := types.GetCondition(oldNode.Status.Conditions, longhorn.NodeConditionTypeReady)
+	curReadyCondition := types.GetCondition(curNode.Status.Conditions, longhorn.NodeConditionTypeReady)
+	if curReadyCondition.Status != longhorn.ConditionStatusTrue || oldReadyCondition.Status == curReadyCondition.Status {
+		return
+	}
+
+	volumes, err := vc.ds.ListVolumesRO()
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list volumes when node %v became ready: %v", curNode.Name, err))
+		return
+	}
+	for _, v := range volumes {
+		vc.enqueueVolume(v)
+	}
+}
+
 // enqueueVolumesForShareManager enqueues all volumes that are currently claimed by this share manager
 func (vc *VolumeController) enqueueVolumesForShareManager(obj interface{}) {
 	sm, isShareManager := obj.(*longhorn.ShareManager)
@@ -3498,7 +3902,7 @@ func (vc *VolumeController) ReconcileBackupVolumeState(volume *longhorn.Volume)
 // TODO: this block of code is duplicated of CreateSnapshot in MANAGER package.
 // Once we have Snapshot CR, we should refactor this
 
-func (vc *VolumeController) createSnapshot(snapshotName string, labels map[string]string, volume *longhorn.Volume) (*longhorn.Snapshot, error) {
+func (vc *VolumeController) createSnapshot(snapshotName string, labels map[string]string, volume *longhorn.Volume) (*longhorn.SnapshotInfo, error) {
 	if volume.Name == "" {
 		return nil, fmt.Errorf("volume name required")
 	}
@@ -542,6 +542,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	}
 	tc.expectVolume.Status.Robustness = longhorn.VolumeRobustnessDegraded
 	tc.expectVolume.Status.LastDegradedAt = getTestNow()
+	tc.expectVolume.Status.LastRestoredBackup = TestBackupName
 	testCases["the restored volume keeps and wait for the rebuild after the restoration completed"] = tc
 
 	// try to update the volume as Faulted if all replicas failed to restore data
@@ -689,6 +690,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.copyCurrentToExpect()
 	tc.expectVolume.Status.State = longhorn.VolumeStateAttached
 	tc.expectVolume.Status.Robustness = longhorn.VolumeRobustnessHealthy
+	tc.expectVolume.Status.LastRestoredBackup = TestBackupName
 	testCases["standby volume is not automatically detached"] = tc
 
 	// volume detaching - stop engine
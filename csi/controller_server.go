@@ -90,15 +90,30 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 		switch volumeSource.Type.(type) {
 		case *csi.VolumeContentSource_Snapshot:
 			if snapshot := volumeSource.GetSnapshot(); snapshot != nil {
-				_, backupVolume, backupName := decodeSnapshotID(snapshot.SnapshotId)
-				bv, err := cs.apiClient.BackupVolume.ById(backupVolume)
+				snapshotType, sourceVolumeName, id := decodeSnapshotID(snapshot.SnapshotId)
+				if snapshotType == csiSnapshotTypeLonghornSnapshot {
+					// An in-cluster engine snapshot adopted via a
+					// pre-provisioned VolumeSnapshotContent, restore it by
+					// cloning from the snapshot
+					longhornSrcVol, err := cs.apiClient.Volume.ById(sourceVolumeName)
+					if err != nil || longhornSrcVol == nil {
+						return nil, status.Errorf(codes.NotFound, "cannot restore csi snapshot %s source volume %s unavailable", snapshot.SnapshotId, sourceVolumeName)
+					}
+
+					dataSource, _ := types.NewVolumeDataSource(longhorn.VolumeDataSourceTypeSnapshot,
+						map[string]string{types.VolumeNameKey: sourceVolumeName, types.SnapshotNameKey: id})
+					volumeParameters["dataSource"] = string(dataSource)
+					break
+				}
+
+				bv, err := cs.apiClient.BackupVolume.ById(sourceVolumeName)
 				if err != nil {
-					return nil, status.Errorf(codes.NotFound, "cannot restore csi snapshot %s backup volume %s unavailable", snapshot.SnapshotId, backupVolume)
+					return nil, status.Errorf(codes.NotFound, "cannot restore csi snapshot %s backup volume %s unavailable", snapshot.SnapshotId, sourceVolumeName)
 				}
 
-				backup, err := cs.apiClient.BackupVolume.ActionBackupGet(bv, &longhornclient.BackupInput{Name: backupName})
+				backup, err := cs.apiClient.BackupVolume.ActionBackupGet(bv, &longhornclient.BackupInput{Name: id})
 				if err != nil {
-					return nil, status.Errorf(codes.NotFound, "cannot restore csi snapshot %v backup %s unavailable", snapshot.SnapshotId, backupName)
+					return nil, status.Errorf(codes.NotFound, "cannot restore csi snapshot %v backup %s unavailable", snapshot.SnapshotId, id)
 				}
 
 				// use the fromBackup method for the csi snapshot restores as well
@@ -649,11 +664,22 @@ func createSnapshotResponse(volumeName, backupName, snapshotTime, volumeSize str
 	}
 }
 
+const (
+	// csiSnapshotTypeLonghornBackup identifies a CSI snapshot backed by a
+	// Longhorn backup in the backupstore
+	csiSnapshotTypeLonghornBackup = "bs"
+	// csiSnapshotTypeLonghornSnapshot identifies a CSI snapshot backed by an
+	// in-cluster engine snapshot. It is only used by pre-provisioned
+	// VolumeSnapshotContents that adopt snapshots taken outside of CSI,
+	// e.g. via the Longhorn UI or a recurring snapshot job
+	csiSnapshotTypeLonghornSnapshot = "snap"
+)
+
 // encodeSnapshotID encodes the backup volume as part of the snapshotID
 // so we don't need to iterate over all backup volumes,
 // when trying to find a backup for deletion or restoration
 func encodeSnapshotID(volumeName, backupName string) string {
-	return fmt.Sprintf("bs://%s/%s", volumeName, backupName)
+	return fmt.Sprintf("%s://%s/%s", csiSnapshotTypeLonghornBackup, volumeName, backupName)
 }
 
 // decodeSnapshotID splits up the snapshotID back into it's components
@@ -666,6 +692,11 @@ func decodeSnapshotID(snapshotID string) (backupType, volumeName, backupName str
 	backupType = split[0]
 
 	split = strings.Split(split[1], "/")
+	// A pre-provisioned snapshotHandle is written by the operator, so a
+	// malformed one must not crash the CSI server
+	if len(split) < 2 {
+		return backupType, "", ""
+	}
 	volumeName = split[0]
 	backupName = split[1]
 
@@ -678,7 +709,20 @@ func (cs *ControllerServer) DeleteSnapshot(ctx context.Context, req *csi.DeleteS
 		return nil, status.Error(codes.InvalidArgument, "missing snapshot id in request")
 	}
 
-	_, backupVolumeName, backupName := decodeSnapshotID(snapshotID)
+	snapshotType, backupVolumeName, backupName := decodeSnapshotID(snapshotID)
+	if snapshotType == csiSnapshotTypeLonghornSnapshot {
+		volume, err := cs.apiClient.Volume.ById(backupVolumeName)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if volume != nil && volume.Name != "" {
+			if _, err := cs.apiClient.Volume.ActionSnapshotDelete(volume, &longhornclient.SnapshotInput{Name: backupName}); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		return &csi.DeleteSnapshotResponse{}, nil
+	}
+
 	backupVolume, err := cs.apiClient.BackupVolume.ById(backupVolumeName)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
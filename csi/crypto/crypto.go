@@ -41,6 +41,17 @@ func OpenVolume(volume, devicePath, passphrase string) error {
 	return err
 }
 
+// RotateVolumeKey rotates the LUKS key slot of the device from the old passphrase
+// to the new passphrase in place, so the encrypted data doesn't need to be migrated.
+// The rotation is safe while the device is open.
+func RotateVolumeKey(devicePath, oldPassphrase, newPassphrase string) error {
+	logrus.Debugf("Rotating LUKS key slot of device %s", devicePath)
+	if _, err := luksChangeKey(devicePath, oldPassphrase, newPassphrase); err != nil {
+		return fmt.Errorf("failed to rotate LUKS key slot of device %s: %w", devicePath, err)
+	}
+	return nil
+}
+
 // CloseVolume closes encrypted volume so it can be detached.
 func CloseVolume(volume string) error {
 	logrus.Debugf("Closing LUKS device %s", volume)
@@ -34,6 +34,13 @@ func luksStatus(volume string) (stdout string, err error) {
 	return cryptSetup("status", volume)
 }
 
+func luksChangeKey(devicePath, oldPassphrase, newPassphrase string) (stdout string, err error) {
+	// In the batch mode cryptsetup reads the passphrase of the key slot to be
+	// changed and the new passphrase from the consecutive lines of stdin
+	return cryptSetupWithPassphrase(oldPassphrase+"\n"+newPassphrase+"\n",
+		"-q", "luksChangeKey", devicePath)
+}
+
 func cryptSetup(args ...string) (stdout string, err error) {
 	return cryptSetupWithPassphrase("", args...)
 }
@@ -45,13 +45,31 @@ var (
 	MountPropagationBidirectional = v1.MountPropagationBidirectional
 )
 
+// LeaderElectionTimings carries the lease tuning shared by the leader elected
+// CSI sidecar components. Lower values speed up the failover after a node
+// failure at the cost of more API server load.
+type LeaderElectionTimings struct {
+	LeaseDurationSec int
+	RenewDeadlineSec int
+	RetryPeriodSec   int
+}
+
+func (t LeaderElectionTimings) args() []string {
+	return []string{
+		fmt.Sprintf("--leader-election-lease-duration=%ds", t.LeaseDurationSec),
+		fmt.Sprintf("--leader-election-renew-deadline=%ds", t.RenewDeadlineSec),
+		fmt.Sprintf("--leader-election-retry-period=%ds", t.RetryPeriodSec),
+	}
+}
+
 type AttacherDeployment struct {
 	service    *v1.Service
 	deployment *appsv1.Deployment
 }
 
 func NewAttacherDeployment(namespace, serviceAccount, attacherImage, rootDir string, replicaCount int, tolerations []v1.Toleration,
-	tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string) *AttacherDeployment {
+	tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string,
+	leaderElection LeaderElectionTimings) *AttacherDeployment {
 
 	service := getCommonService(types.CSIAttacherName, namespace)
 
@@ -61,13 +79,13 @@ func NewAttacherDeployment(namespace, serviceAccount, attacherImage, rootDir str
 		serviceAccount,
 		attacherImage,
 		rootDir,
-		[]string{
+		append([]string{
 			"--v=2",
 			"--csi-address=$(ADDRESS)",
 			"--timeout=1m50s",
 			"--leader-election",
 			"--leader-election-namespace=$(POD_NAMESPACE)",
-		},
+		}, leaderElection.args()...),
 		int32(replicaCount),
 		tolerations,
 		tolerationsString,
@@ -117,7 +135,8 @@ type ProvisionerDeployment struct {
 }
 
 func NewProvisionerDeployment(namespace, serviceAccount, provisionerImage, rootDir string, replicaCount int, tolerations []v1.Toleration,
-	tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string) *ProvisionerDeployment {
+	tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string,
+	leaderElection LeaderElectionTimings) *ProvisionerDeployment {
 
 	service := getCommonService(types.CSIProvisionerName, namespace)
 
@@ -127,14 +146,14 @@ func NewProvisionerDeployment(namespace, serviceAccount, provisionerImage, rootD
 		serviceAccount,
 		provisionerImage,
 		rootDir,
-		[]string{
+		append([]string{
 			"--v=2",
 			"--csi-address=$(ADDRESS)",
 			"--timeout=1m50s",
 			"--leader-election",
 			"--leader-election-namespace=$(POD_NAMESPACE)",
 			"--default-fstype=ext4",
-		},
+		}, leaderElection.args()...),
 		int32(replicaCount),
 		tolerations,
 		tolerationsString,
@@ -184,7 +203,8 @@ type ResizerDeployment struct {
 }
 
 func NewResizerDeployment(namespace, serviceAccount, resizerImage, rootDir string, replicaCount int, tolerations []v1.Toleration,
-	tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string) *ResizerDeployment {
+	tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string,
+	leaderElection LeaderElectionTimings) *ResizerDeployment {
 
 	service := getCommonService(types.CSIResizerName, namespace)
 
@@ -194,7 +214,7 @@ func NewResizerDeployment(namespace, serviceAccount, resizerImage, rootDir strin
 		serviceAccount,
 		resizerImage,
 		rootDir,
-		[]string{
+		append([]string{
 			"--v=2",
 			"--csi-address=$(ADDRESS)",
 			"--timeout=1m50s",
@@ -204,7 +224,7 @@ func NewResizerDeployment(namespace, serviceAccount, resizerImage, rootDir strin
 			// Issue: https://github.com/longhorn/longhorn/issues/3303
 			// TODO: Remove this after upgrading the CSI resizer version that contains the fix of https://github.com/kubernetes-csi/external-resizer/issues/175
 			"--handle-volume-inuse-error=false",
-		},
+		}, leaderElection.args()...),
 		int32(replicaCount),
 		tolerations,
 		tolerationsString,
@@ -254,7 +274,8 @@ type SnapshotterDeployment struct {
 }
 
 func NewSnapshotterDeployment(namespace, serviceAccount, snapshotterImage, rootDir string, replicaCount int, tolerations []v1.Toleration,
-	tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string) *SnapshotterDeployment {
+	tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string,
+	leaderElection LeaderElectionTimings) *SnapshotterDeployment {
 	service := getCommonService(types.CSISnapshotterName, namespace)
 
 	deployment := getCommonDeployment(
@@ -263,13 +284,13 @@ func NewSnapshotterDeployment(namespace, serviceAccount, snapshotterImage, rootD
 		serviceAccount,
 		snapshotterImage,
 		rootDir,
-		[]string{
+		append([]string{
 			"--v=2",
 			"--csi-address=$(ADDRESS)",
 			"--timeout=1m50s",
 			"--leader-election",
 			"--leader-election-namespace=$(POD_NAMESPACE)",
-		},
+		}, leaderElection.args()...),
 		int32(replicaCount),
 		tolerations,
 		tolerationsString,
@@ -318,7 +339,7 @@ type PluginDeployment struct {
 }
 
 func NewPluginDeployment(namespace, serviceAccount, nodeDriverRegistrarImage, managerImage, managerURL, rootDir string,
-	tolerations []v1.Toleration, tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string) *PluginDeployment {
+	tolerations []v1.Toleration, tolerationsString, priorityClass, registrySecret string, imagePullPolicy v1.PullPolicy, nodeSelector map[string]string, hostNetwork bool) *PluginDeployment {
 
 	daemonSet := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -346,6 +367,7 @@ func NewPluginDeployment(namespace, serviceAccount, nodeDriverRegistrarImage, ma
 					NodeSelector:       nodeSelector,
 					PriorityClassName:  priorityClass,
 					HostPID:            true,
+					HostNetwork:        hostNetwork,
 					Containers: []v1.Container{
 						{
 							Name:  "node-driver-registrar",
@@ -550,6 +572,10 @@ func NewPluginDeployment(namespace, serviceAccount, nodeDriverRegistrarImage, ma
 		}
 	}
 
+	if hostNetwork {
+		daemonSet.Spec.Template.Spec.DNSPolicy = v1.DNSClusterFirstWithHostNet
+	}
+
 	return &PluginDeployment{
 		daemonSet: daemonSet,
 	}
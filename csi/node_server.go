@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/pkg/errors"
@@ -25,6 +26,7 @@ import (
 	"github.com/longhorn/longhorn-manager/csi/crypto"
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
 )
 
 const (
@@ -32,6 +34,9 @@ const (
 	// We currently only support passphrase retrieval via direct secret values
 	CryptoKeyProvider = "CRYPTO_KEY_PROVIDER"
 	CryptoKeyValue    = "CRYPTO_KEY_VALUE"
+	// CryptoKeyRotate holds the previous passphrase during a key rotation,
+	// CryptoKeyValue is already updated to the new passphrase at that point
+	CryptoKeyRotate = "CRYPTO_KEY_ROTATE"
 )
 
 type NodeServer struct {
@@ -404,6 +409,20 @@ func (ns *NodeServer) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 			}
 		}
 
+		// rotate the LUKS key slot to the new passphrase when the previous
+		// passphrase is handed over via the secret
+		if previousPassphrase := secrets[CryptoKeyRotate]; diskFormat == "crypto_LUKS" &&
+			previousPassphrase != "" && previousPassphrase != passphrase {
+			if err := crypto.RotateVolumeKey(devicePath, previousPassphrase, passphrase); err != nil {
+				// the rotation could have been done during a previous staging already,
+				// in that case opening the volume with the new passphrase below succeeds
+				logrus.Warnf("failed to rotate encryption key of volume %v: %v", volumeID, err)
+			} else if _, err := ns.apiClient.Volume.ActionUpdateLastKeyRotatedAt(volume,
+				&longhornclient.UpdateLastKeyRotatedAtInput{LastKeyRotatedAt: util.Now()}); err != nil {
+				logrus.Warnf("failed to record the encryption key rotation time of volume %v: %v", volumeID, err)
+			}
+		}
+
 		cryptoDevice := crypto.VolumeMapper(volumeID)
 		logrus.Debugf("volume %s requires crypto device %s", volumeID, cryptoDevice)
 
@@ -459,7 +478,10 @@ func (ns *NodeServer) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstag
 
 	// CO owns the staging_path so we only unmount but not remove the path
 	if err := unmount(targetPath, mount.New("")); err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to unmount volume %s mount point %v error %v", volumeID, targetPath, err))
+		logrus.Warnf("NodeUnstageVolume: volume %s failed to unmount %v, starting the stale mount cleanup: %v", volumeID, targetPath, err)
+		if err := forceCleanupMountPoint(targetPath, mount.New("")); err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to unmount volume %s mount point %v error %v", volumeID, targetPath, err))
+		}
 	}
 
 	// optionally try to retrieve the volume and check if it's an RWX volume
@@ -472,9 +494,19 @@ func (ns *NodeServer) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstag
 		if isOpen, err := crypto.IsDeviceOpen(cryptoDevice); err != nil {
 			return nil, status.Error(codes.Internal, err.Error())
 		} else if isOpen {
-			logrus.Debugf("NodeUnstagehVolume: volume %s has active crypto device %s", volumeID, cryptoDevice)
-			if err := crypto.CloseVolume(volumeID); err != nil {
-				return nil, status.Error(codes.Internal, err.Error())
+			logrus.Debugf("NodeUnstageVolume: volume %s has active crypto device %s", volumeID, cryptoDevice)
+			// Retry the close, a lingering filesystem reference from the
+			// just removed mount can keep the device busy for a moment.
+			var closeErr error
+			for i := 0; i < staleMountCleanupRetries; i++ {
+				if closeErr = crypto.CloseVolume(volumeID); closeErr == nil {
+					break
+				}
+				logrus.Warnf("NodeUnstageVolume: volume %s failed to close crypto device %s, attempt %v/%v: %v", volumeID, cryptoDevice, i+1, staleMountCleanupRetries, closeErr)
+				time.Sleep(staleMountCleanupRetryInterval)
+			}
+			if closeErr != nil {
+				return nil, status.Error(codes.Internal, closeErr.Error())
 			}
 			logrus.Infof("NodeUnstageVolume: volume %s closed active crypto device %s", volumeID, cryptoDevice)
 		}
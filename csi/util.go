@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/pkg/errors"
@@ -27,6 +28,9 @@ import (
 const (
 	// defaultStaleReplicaTimeout set to 48 hours (2880 minutes)
 	defaultStaleReplicaTimeout = 2880
+
+	staleMountCleanupRetries       = 3
+	staleMountCleanupRetryInterval = time.Second
 )
 
 // NewForcedParamsExec creates a osExecutor that allows for adding additional params to later occurring Run calls
@@ -104,12 +108,9 @@ func getVolumeOptions(volOptions map[string]string) (*longhornclient.Volume, err
 			return nil, errors.Wrap(err, "Invalid parameter migratable")
 		}
 
-		if isMigratable && vol.AccessMode != string(longhorn.AccessModeReadWriteMany) {
-			logrus.Infof("Cannot mark volume %v as migratable, "+
-				"since access mode is not RWX proceeding with RWO non migratable volume creation.", vol.Name)
-			volOptions["migratable"] = strconv.FormatBool(false)
-			isMigratable = false
-		}
+		// A migratable RWO volume is temporarily multi node capable during a
+		// live migration, the engine controller fences off the second frontend
+		// so that there is never more than one writable frontend at a time.
 		vol.Migratable = isMigratable
 	}
 
@@ -152,6 +153,18 @@ func getVolumeOptions(volOptions map[string]string) (*longhornclient.Volume, err
 		vol.RevisionCounterDisabled = revCounterDisabled
 	}
 
+	if strictProvisioning, ok := volOptions["strictProvisioning"]; ok {
+		isStrict, err := strconv.ParseBool(strictProvisioning)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid parameter strictProvisioning")
+		}
+
+		// With strict provisioning the manager refuses to create the volume
+		// when its replicas cannot currently be placed, so the PVC fails fast
+		// instead of staying pending on an unschedulable volume.
+		vol.StrictProvisioning = isStrict
+	}
+
 	if fromBackup, ok := volOptions["fromBackup"]; ok {
 		vol.FromBackup = fromBackup
 	}
@@ -185,6 +198,10 @@ func getVolumeOptions(volOptions map[string]string) (*longhornclient.Volume, err
 		vol.DiskSelector = strings.Split(diskSelector, ",")
 	}
 
+	if diskGroup, ok := volOptions["diskGroup"]; ok {
+		vol.DiskGroup = diskGroup
+	}
+
 	if nodeSelector, ok := volOptions["nodeSelector"]; ok {
 		vol.NodeSelector = strings.Split(nodeSelector, ",")
 	}
@@ -268,6 +285,29 @@ func cleanupMountPoint(targetPath string, mounter mount.Interface) error {
 	return mount.CleanupMountPoint(targetPath, mounter, true)
 }
 
+// forceCleanupMountPoint unmounts the targetPath with retries and falls back
+// to a lazy unmount, so an unstage that failed mid-way cannot leave a stale
+// mount point behind that blocks every following attach on the node.
+func forceCleanupMountPoint(targetPath string, mounter mount.Interface) error {
+	var err error
+	for i := 0; i < staleMountCleanupRetries; i++ {
+		if err = unmount(targetPath, mounter); err == nil {
+			return nil
+		}
+		logrus.Warnf("Failed to unmount %v, attempt %v/%v: %v", targetPath, i+1, staleMountCleanupRetries, err)
+		time.Sleep(staleMountCleanupRetryInterval)
+	}
+
+	// A lazy unmount detaches the mount point immediately and lets the
+	// kernel release it once it is no longer busy, which unblocks the next
+	// attach even when a process still holds the old mount.
+	logrus.Warnf("Falling back to the lazy unmount of %v", targetPath)
+	if out, lazyErr := utilexec.New().Command("umount", "-l", targetPath).CombinedOutput(); lazyErr != nil {
+		return errors.Wrapf(err, "failed to unmount %v even with the lazy fallback: %v, output %s", targetPath, lazyErr, out)
+	}
+	return nil
+}
+
 // isBlockDevice return true if volumePath file is a block device, false otherwise.
 func isBlockDevice(volumePath string) (bool, error) {
 	var stat unix.Stat_t
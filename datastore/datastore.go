@@ -5,6 +5,7 @@ import (
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
 	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters_v1 "k8s.io/client-go/listers/batch/v1"
 	batchlisters_v1beta1 "k8s.io/client-go/listers/batch/v1beta1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	policylisters "k8s.io/client-go/listers/policy/v1beta1"
@@ -59,6 +60,12 @@ type DataStore struct {
 	BackupInformer                 cache.SharedInformer
 	rjLister                       lhlisters.RecurringJobLister
 	RecurringJobInformer           cache.SharedInformer
+	snapLister                     lhlisters.SnapshotLister
+	SnapshotInformer               cache.SharedInformer
+	sbLister                       lhlisters.SystemBackupLister
+	SystemBackupInformer           cache.SharedInformer
+	srLister                       lhlisters.SystemRestoreLister
+	SystemRestoreInformer          cache.SharedInformer
 
 	kubeClient                    clientset.Interface
 	pLister                       corelisters.PodLister
@@ -69,6 +76,10 @@ type DataStore struct {
 	DaemonSetInformer             cache.SharedInformer
 	dpLister                      appslisters.DeploymentLister
 	DeploymentInformer            cache.SharedInformer
+	rsLister                      appslisters.ReplicaSetLister
+	ReplicaSetInformer            cache.SharedInformer
+	jLister                       batchlisters_v1.JobLister
+	JobInformer                   cache.SharedInformer
 	pvLister                      corelisters.PersistentVolumeLister
 	PersistentVolumeInformer      cache.SharedInformer
 	pvcLister                     corelisters.PersistentVolumeClaimLister
@@ -85,6 +96,8 @@ type DataStore struct {
 	CSIDriverInformer             cache.SharedInformer
 	storageclassLister            storagelisters_v1.StorageClassLister
 	StorageClassInformer          cache.SharedInformer
+	vaLister                      storagelisters_v1.VolumeAttachmentLister
+	VolumeAttachmentInformer      cache.SharedInformer
 	pdbLister                     policylisters.PodDisruptionBudgetLister
 	PodDistrptionBudgetInformer   cache.SharedInformer
 	svLister                      corelisters.ServiceLister
@@ -131,6 +144,12 @@ func NewDataStore(
 	cacheSyncs = append(cacheSyncs, bInformer.Informer().HasSynced)
 	rjInformer := lhInformerFactory.Longhorn().V1beta2().RecurringJobs()
 	cacheSyncs = append(cacheSyncs, rjInformer.Informer().HasSynced)
+	snapInformer := lhInformerFactory.Longhorn().V1beta2().Snapshots()
+	cacheSyncs = append(cacheSyncs, snapInformer.Informer().HasSynced)
+	sbInformer := lhInformerFactory.Longhorn().V1beta2().SystemBackups()
+	cacheSyncs = append(cacheSyncs, sbInformer.Informer().HasSynced)
+	srInformer := lhInformerFactory.Longhorn().V1beta2().SystemRestores()
+	cacheSyncs = append(cacheSyncs, srInformer.Informer().HasSynced)
 
 	podInformer := kubeInformerFactory.Core().V1().Pods()
 	cacheSyncs = append(cacheSyncs, podInformer.Informer().HasSynced)
@@ -150,12 +169,18 @@ func NewDataStore(
 	cacheSyncs = append(cacheSyncs, daemonSetInformer.Informer().HasSynced)
 	deploymentInformer := kubeInformerFactory.Apps().V1().Deployments()
 	cacheSyncs = append(cacheSyncs, deploymentInformer.Informer().HasSynced)
+	replicaSetInformer := kubeInformerFactory.Apps().V1().ReplicaSets()
+	cacheSyncs = append(cacheSyncs, replicaSetInformer.Informer().HasSynced)
+	jobInformer := kubeInformerFactory.Batch().V1().Jobs()
+	cacheSyncs = append(cacheSyncs, jobInformer.Informer().HasSynced)
 	priorityClassInformer := kubeInformerFactory.Scheduling().V1().PriorityClasses()
 	cacheSyncs = append(cacheSyncs, priorityClassInformer.Informer().HasSynced)
 	csiDriverInformer := kubeInformerFactory.Storage().V1().CSIDrivers()
 	cacheSyncs = append(cacheSyncs, csiDriverInformer.Informer().HasSynced)
 	storageclassInformer := kubeInformerFactory.Storage().V1().StorageClasses()
 	cacheSyncs = append(cacheSyncs, storageclassInformer.Informer().HasSynced)
+	volumeAttachmentInformer := kubeInformerFactory.Storage().V1().VolumeAttachments()
+	cacheSyncs = append(cacheSyncs, volumeAttachmentInformer.Informer().HasSynced)
 	pdbInformer := kubeInformerFactory.Policy().V1beta1().PodDisruptionBudgets()
 	cacheSyncs = append(cacheSyncs, pdbInformer.Informer().HasSynced)
 	serviceInformer := kubeInformerFactory.Core().V1().Services()
@@ -197,6 +222,12 @@ func NewDataStore(
 		BackupInformer:                 bInformer.Informer(),
 		rjLister:                       rjInformer.Lister(),
 		RecurringJobInformer:           rjInformer.Informer(),
+		snapLister:                     snapInformer.Lister(),
+		SnapshotInformer:               snapInformer.Informer(),
+		sbLister:                       sbInformer.Lister(),
+		SystemBackupInformer:           sbInformer.Informer(),
+		srLister:                       srInformer.Lister(),
+		SystemRestoreInformer:          srInformer.Informer(),
 
 		kubeClient:                    kubeClient,
 		pLister:                       podInformer.Lister(),
@@ -207,6 +238,10 @@ func NewDataStore(
 		DaemonSetInformer:             daemonSetInformer.Informer(),
 		dpLister:                      deploymentInformer.Lister(),
 		DeploymentInformer:            deploymentInformer.Informer(),
+		rsLister:                      replicaSetInformer.Lister(),
+		ReplicaSetInformer:            replicaSetInformer.Informer(),
+		jLister:                       jobInformer.Lister(),
+		JobInformer:                   jobInformer.Informer(),
 		pvLister:                      persistentVolumeInformer.Lister(),
 		PersistentVolumeInformer:      persistentVolumeInformer.Informer(),
 		pvcLister:                     persistentVolumeClaimInformer.Lister(),
@@ -223,6 +258,8 @@ func NewDataStore(
 		CSIDriverInformer:             csiDriverInformer.Informer(),
 		storageclassLister:            storageclassInformer.Lister(),
 		StorageClassInformer:          storageclassInformer.Informer(),
+		vaLister:                      volumeAttachmentInformer.Lister(),
+		VolumeAttachmentInformer:      volumeAttachmentInformer.Informer(),
 		pdbLister:                     pdbInformer.Lister(),
 		PodDistrptionBudgetInformer:   pdbInformer.Informer(),
 		svLister:                      serviceInformer.Lister(),
@@ -3,11 +3,13 @@ package datastore
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -241,6 +243,30 @@ func (s *DataStore) CreateStorageClass(sc *storagev1.StorageClass) (*storagev1.S
 	return s.kubeClient.StorageV1().StorageClasses().Create(context.TODO(), sc, metav1.CreateOptions{})
 }
 
+// GetVolumeAttachmentRO gets the VolumeAttachment with the given name
+// This function returns direct reference to the internal cache object and should not be mutated.
+// Consider using this function when you can guarantee read only access and don't want the overhead of deep copies
+func (s *DataStore) GetVolumeAttachmentRO(name string) (*storagev1.VolumeAttachment, error) {
+	return s.vaLister.Get(name)
+}
+
+// ListVolumeAttachmentsRO returns a list of all VolumeAttachments,
+// the list contains direct references to the internal cache objects and should not be mutated.
+// Consider using this function when you can guarantee read only access and don't want the overhead of deep copies
+func (s *DataStore) ListVolumeAttachmentsRO() ([]*storagev1.VolumeAttachment, error) {
+	return s.vaLister.List(labels.Everything())
+}
+
+// UpdateVolumeAttachment updates the VolumeAttachment with the given object
+func (s *DataStore) UpdateVolumeAttachment(va *storagev1.VolumeAttachment) (*storagev1.VolumeAttachment, error) {
+	return s.kubeClient.StorageV1().VolumeAttachments().Update(context.TODO(), va, metav1.UpdateOptions{})
+}
+
+// DeleteVolumeAttachment deletes the VolumeAttachment with the given name
+func (s *DataStore) DeleteVolumeAttachment(name string) error {
+	return s.kubeClient.StorageV1().VolumeAttachments().Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
+
 // ListPodsRO returns a list of all Pods for the given namespace,
 // the list contains direct references to the internal cache objects and should not be mutated.
 // Consider using this function when you can guarantee read only access and don't want the overhead of deep copies
@@ -274,6 +300,19 @@ func (s *DataStore) GetPodContainerLog(podName, containerName string) ([]byte, e
 	return s.kubeClient.CoreV1().Pods(s.namespace).GetLogs(podName, podLogOpts).DoRaw(context.TODO())
 }
 
+// GetPodContainerLogStream opens a stream over the last tailLines lines of
+// the log of a container in a Pod object for the given name and namespace.
+// Be careful that this function will directly talk with the API server.
+func (s *DataStore) GetPodContainerLogStream(podName, containerName string, tailLines int64) (io.ReadCloser, error) {
+	podLogOpts := &corev1.PodLogOptions{
+		TailLines: &tailLines,
+	}
+	if containerName != "" {
+		podLogOpts.Container = containerName
+	}
+	return s.kubeClient.CoreV1().Pods(s.namespace).GetLogs(podName, podLogOpts).Stream(context.TODO())
+}
+
 // GetDaemonSet gets the DaemonSet for the given name and namespace
 func (s *DataStore) GetDaemonSet(name string) (*appsv1.DaemonSet, error) {
 	return s.dsLister.DaemonSets(s.namespace).Get(name)
@@ -309,6 +348,29 @@ func (s *DataStore) GetDeployment(name string) (*appsv1.Deployment, error) {
 	return s.dpLister.Deployments(s.namespace).Get(name)
 }
 
+// GetReplicaSetRO gets the ReplicaSet for the given name and namespace,
+// the returned object contains a direct reference to the internal cache object and should not be mutated.
+// Consider using this function when you can guarantee read only access and don't want the overhead of deep copies
+func (s *DataStore) GetReplicaSetRO(namespace, name string) (*appsv1.ReplicaSet, error) {
+	return s.rsLister.ReplicaSets(namespace).Get(name)
+}
+
+// GetJobRO gets the Job for the given name and namespace,
+// the returned object contains a direct reference to the internal cache object and should not be mutated.
+// Consider using this function when you can guarantee read only access and don't want the overhead of deep copies
+func (s *DataStore) GetJobRO(namespace, name string) (*batchv1.Job, error) {
+	return s.jLister.Jobs(namespace).Get(name)
+}
+
+// ListJobsWithLabels gets a list of all Jobs with the given labels
+func (s *DataStore) ListJobsWithLabels(labels map[string]string) ([]*batchv1.Job, error) {
+	selector, err := labelMapToLabelSelector(labels)
+	if err != nil {
+		return nil, err
+	}
+	return s.jLister.Jobs(s.namespace).List(selector)
+}
+
 // ListDeployment gets a list of all Deployment for the given namespace
 func (s *DataStore) ListDeployment() ([]*appsv1.Deployment, error) {
 	return s.dpLister.Deployments(s.namespace).List(labels.Everything())
@@ -398,6 +460,18 @@ func (s *DataStore) ListBackingImageManagerPods() ([]*corev1.Pod, error) {
 	return s.ListPodsBySelector(selector)
 }
 
+// ListVolumeSystemManagedPods returns all pods Longhorn created on behalf of the
+// given volume, selected by the uniform volume label rather than the pod name prefix.
+func (s *DataStore) ListVolumeSystemManagedPods(volumeName string) ([]*corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels: types.GetVolumeSystemManagedComponentLabels(volumeName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.ListPodsBySelector(selector)
+}
+
 func (s *DataStore) ListPodsBySelector(selector labels.Selector) ([]*corev1.Pod, error) {
 	podList, err := s.pLister.Pods(s.namespace).List(selector)
 	if err != nil {
@@ -512,6 +586,21 @@ func (s *DataStore) GetConfigMap(namespace, name string) (*corev1.ConfigMap, err
 	return resultRO.DeepCopy(), nil
 }
 
+// CreateConfigMap creates a ConfigMap resource
+func (s *DataStore) CreateConfigMap(cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	return s.kubeClient.CoreV1().ConfigMaps(s.namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+}
+
+// UpdateConfigMap updates the ConfigMap resource
+func (s *DataStore) UpdateConfigMap(cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	return s.kubeClient.CoreV1().ConfigMaps(s.namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+}
+
+// DeleteConfigMap deletes the ConfigMap for the given name in s.namespace
+func (s *DataStore) DeleteConfigMap(name string) error {
+	return s.kubeClient.CoreV1().ConfigMaps(s.namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
+
 // GetSecretRO gets Secret with the given namespace and name
 // This function returns direct reference to the internal cache object and should not be mutated.
 // Consider using this function when you can guarantee read only access and don't want the overhead of deep copies
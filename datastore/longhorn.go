@@ -161,6 +161,16 @@ func (s *DataStore) ValidateSetting(name, value string) (err error) {
 				return fmt.Errorf("cannot modify node selector for managed components setting before all volumes are detached")
 			}
 		}
+	case types.SettingNameStorageNetwork:
+		list, err := s.ListVolumesRO()
+		if err != nil {
+			return errors.Wrapf(err, "failed to list volumes before modifying storage network setting")
+		}
+		for _, v := range list {
+			if v.Status.State != longhorn.VolumeStateDetached {
+				return fmt.Errorf("cannot modify storage network setting before all volumes are detached")
+			}
+		}
 	case types.SettingNamePriorityClass:
 		if value != "" {
 			if _, err := s.GetPriorityClass(value); err != nil {
@@ -2095,6 +2105,28 @@ func (s *DataStore) IsNodeDownOrDeleted(name string) (bool, error) {
 	return false, nil
 }
 
+// IsNodeUnderResourcePressure returns true if the Kubernetes node reports
+// memory, PID, or disk pressure, so that callers can throttle the storage
+// operations on the node before the kubelet starts evicting pods.
+func (s *DataStore) IsNodeUnderResourcePressure(name string) (bool, error) {
+	kubeNode, err := s.GetKubernetesNode(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, con := range kubeNode.Status.Conditions {
+		switch con.Type {
+		case corev1.NodeMemoryPressure, corev1.NodePIDPressure, corev1.NodeDiskPressure:
+			if con.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func (s *DataStore) IsNodeSchedulable(name string) bool {
 	node, err := s.GetNodeRO(name)
 	if err != nil {
@@ -2364,6 +2396,24 @@ func (s *DataStore) GetSettingAsBool(settingName types.SettingName) (bool, error
 	return false, fmt.Errorf("the %v setting value couldn't be converted to bool, value is %v ", string(settingName), value)
 }
 
+// IsControllerDisabled checks if the given controller is listed in the
+// disabled-controllers setting so its reconciliation should be skipped
+func (s *DataStore) IsControllerDisabled(controllerName string) (bool, error) {
+	setting, err := s.GetSetting(types.SettingNameDisabledControllers)
+	if err != nil {
+		return false, err
+	}
+	if setting.Value == "" {
+		return false, nil
+	}
+	for _, name := range strings.Split(setting.Value, ",") {
+		if strings.TrimSpace(name) == controllerName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // GetSettingImagePullPolicy get the setting and return one of Kubernetes ImagePullPolicy definition
 // Returns error if the ImagePullPolicy is invalid
 func (s *DataStore) GetSettingImagePullPolicy() (corev1.PullPolicy, error) {
@@ -2902,6 +2952,17 @@ func (s *DataStore) GetBackupTargetRO(backupTargetName string) (*longhorn.Backup
 	return s.btLister.BackupTargets(s.namespace).Get(backupTargetName)
 }
 
+// GetVolumeBackupTargetRO returns the BackupTarget the volume is configured to
+// back up to, falling back to the default backup target when the volume does
+// not specify one
+func (s *DataStore) GetVolumeBackupTargetRO(v *longhorn.Volume) (*longhorn.BackupTarget, error) {
+	backupTargetName := v.Spec.BackupTargetName
+	if backupTargetName == "" {
+		backupTargetName = types.DefaultBackupTargetName
+	}
+	return s.GetBackupTargetRO(backupTargetName)
+}
+
 // GetBackupTarget returns a copy of BackupTarget with the given backup target name in the cluster
 func (s *DataStore) GetBackupTarget(name string) (*longhorn.BackupTarget, error) {
 	resultRO, err := s.GetBackupTargetRO(name)
@@ -2981,6 +3042,29 @@ func (s *DataStore) ListBackupVolumes() (map[string]*longhorn.BackupVolume, erro
 	return itemMap, nil
 }
 
+// ListBackupVolumesForBackupTarget returns an object contains all backup
+// volumes in the cluster that belong to the given backup target. Backup
+// volumes without a backup target name are considered owned by the default
+// backup target.
+func (s *DataStore) ListBackupVolumesForBackupTarget(backupTargetName string) (map[string]*longhorn.BackupVolume, error) {
+	list, err := s.ListBackupVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	itemMap := map[string]*longhorn.BackupVolume{}
+	for name, bv := range list {
+		bvTargetName := bv.Spec.BackupTargetName
+		if bvTargetName == "" {
+			bvTargetName = types.DefaultBackupTargetName
+		}
+		if bvTargetName == backupTargetName {
+			itemMap[name] = bv
+		}
+	}
+	return itemMap, nil
+}
+
 func getBackupVolumeSelector(backupVolumeName string) (labels.Selector, error) {
 	return metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
 		MatchLabels: types.GetBackupVolumeLabels(backupVolumeName),
@@ -3348,3 +3432,316 @@ func ValidateRecurringJobs(jobs []longhorn.RecurringJobSpec) error {
 	}
 	return nil
 }
+
+// CreateSnapshot creates a Longhorn Snapshot resource and verifies creation
+func (s *DataStore) CreateSnapshot(snapshot *longhorn.Snapshot) (*longhorn.Snapshot, error) {
+	if snapshot.Spec.Volume == "" {
+		return nil, fmt.Errorf("BUG: missing volume name for snapshot %v", snapshot.Name)
+	}
+	if snapshot.Labels == nil {
+		snapshot.Labels = map[string]string{}
+	}
+	for k, v := range types.GetVolumeLabels(snapshot.Spec.Volume) {
+		snapshot.Labels[k] = v
+	}
+	if err := util.AddFinalizer(longhornFinalizerKey, snapshot); err != nil {
+		return nil, err
+	}
+
+	ret, err := s.lhClient.LonghornV1beta2().Snapshots(s.namespace).Create(context.TODO(), snapshot, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if SkipListerCheck {
+		return ret, nil
+	}
+
+	obj, err := verifyCreation(snapshot.Name, "snapshot", func(name string) (runtime.Object, error) {
+		return s.GetSnapshotRO(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := obj.(*longhorn.Snapshot)
+	if !ok {
+		return nil, fmt.Errorf("BUG: datastore: verifyCreation returned wrong type for snapshot")
+	}
+
+	return ret.DeepCopy(), nil
+}
+
+// GetSnapshotRO returns the Snapshot with the given name in the cluster
+func (s *DataStore) GetSnapshotRO(name string) (*longhorn.Snapshot, error) {
+	return s.snapLister.Snapshots(s.namespace).Get(name)
+}
+
+// GetSnapshot returns a copy of Snapshot with the given name in the cluster
+func (s *DataStore) GetSnapshot(name string) (*longhorn.Snapshot, error) {
+	resultRO, err := s.GetSnapshotRO(name)
+	if err != nil {
+		return nil, err
+	}
+	// Cannot use cached object from lister
+	return resultRO.DeepCopy(), nil
+}
+
+// ListSnapshotsRO returns a map of Snapshots indexed by name
+func (s *DataStore) ListSnapshotsRO(selector labels.Selector) (map[string]*longhorn.Snapshot, error) {
+	list, err := s.snapLister.Snapshots(s.namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	snapshotMap := make(map[string]*longhorn.Snapshot, len(list))
+	for _, snap := range list {
+		snapshotMap[snap.Name] = snap
+	}
+	return snapshotMap, nil
+}
+
+// ListVolumeSnapshotsRO returns a map of Snapshots of the given volume indexed by name
+func (s *DataStore) ListVolumeSnapshotsRO(volumeName string) (map[string]*longhorn.Snapshot, error) {
+	selector, err := getVolumeSelector(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListSnapshotsRO(selector)
+}
+
+// UpdateSnapshotStatus updates Longhorn Snapshot status and verifies update
+func (s *DataStore) UpdateSnapshotStatus(snap *longhorn.Snapshot) (*longhorn.Snapshot, error) {
+	obj, err := s.lhClient.LonghornV1beta2().Snapshots(s.namespace).UpdateStatus(context.TODO(), snap, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	verifyUpdate(snap.Name, obj, func(name string) (runtime.Object, error) {
+		return s.GetSnapshotRO(name)
+	})
+	return obj, nil
+}
+
+// RemoveFinalizerForSnapshot will result in deletion if DeletionTimestamp was set
+func (s *DataStore) RemoveFinalizerForSnapshot(snapshot *longhorn.Snapshot) error {
+	if !util.FinalizerExists(longhornFinalizerKey, snapshot) {
+		// finalizer already removed
+		return nil
+	}
+	if err := util.RemoveFinalizer(longhornFinalizerKey, snapshot); err != nil {
+		return err
+	}
+	_, err := s.lhClient.LonghornV1beta2().Snapshots(s.namespace).Update(context.TODO(), snapshot, metav1.UpdateOptions{})
+	if err != nil {
+		// workaround `StorageError: invalid object, Code: 4` due to empty object
+		if snapshot.DeletionTimestamp != nil {
+			return nil
+		}
+		return errors.Wrapf(err, "unable to remove finalizer for snapshot %v", snapshot.Name)
+	}
+	return nil
+}
+
+// DeleteSnapshot deletes the Snapshot for the given name
+func (s *DataStore) DeleteSnapshot(snapshotName string) error {
+	return s.lhClient.LonghornV1beta2().Snapshots(s.namespace).Delete(context.TODO(), snapshotName, metav1.DeleteOptions{})
+}
+
+// GetOwnerReferencesForSystemBackup returns a list contains single OwnerReference
+// for the given system backup UID and name
+func GetOwnerReferencesForSystemBackup(systemBackup *longhorn.SystemBackup) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion: longhorn.SchemeGroupVersion.String(),
+			Kind:       types.LonghornKindSystemBackup,
+			UID:        systemBackup.UID,
+			Name:       systemBackup.Name,
+		},
+	}
+}
+
+// CreateSystemBackup creates a Longhorn SystemBackup resource and verifies creation
+func (s *DataStore) CreateSystemBackup(systemBackup *longhorn.SystemBackup) (*longhorn.SystemBackup, error) {
+	if err := util.AddFinalizer(longhornFinalizerKey, systemBackup); err != nil {
+		return nil, err
+	}
+
+	ret, err := s.lhClient.LonghornV1beta2().SystemBackups(s.namespace).Create(context.TODO(), systemBackup, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if SkipListerCheck {
+		return ret, nil
+	}
+
+	obj, err := verifyCreation(systemBackup.Name, "system backup", func(name string) (runtime.Object, error) {
+		return s.GetSystemBackupRO(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := obj.(*longhorn.SystemBackup)
+	if !ok {
+		return nil, fmt.Errorf("BUG: datastore: verifyCreation returned wrong type for system backup")
+	}
+
+	return ret.DeepCopy(), nil
+}
+
+// GetSystemBackupRO returns the SystemBackup with the given name in the cluster
+func (s *DataStore) GetSystemBackupRO(name string) (*longhorn.SystemBackup, error) {
+	return s.sbLister.SystemBackups(s.namespace).Get(name)
+}
+
+// GetSystemBackup returns a copy of SystemBackup with the given name in the cluster
+func (s *DataStore) GetSystemBackup(name string) (*longhorn.SystemBackup, error) {
+	resultRO, err := s.GetSystemBackupRO(name)
+	if err != nil {
+		return nil, err
+	}
+	// Cannot use cached object from lister
+	return resultRO.DeepCopy(), nil
+}
+
+// ListSystemBackupsRO returns a map of SystemBackups indexed by name
+func (s *DataStore) ListSystemBackupsRO() (map[string]*longhorn.SystemBackup, error) {
+	list, err := s.sbLister.SystemBackups(s.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	systemBackupMap := make(map[string]*longhorn.SystemBackup, len(list))
+	for _, systemBackup := range list {
+		systemBackupMap[systemBackup.Name] = systemBackup
+	}
+	return systemBackupMap, nil
+}
+
+// UpdateSystemBackupStatus updates Longhorn SystemBackup status and verifies update
+func (s *DataStore) UpdateSystemBackupStatus(systemBackup *longhorn.SystemBackup) (*longhorn.SystemBackup, error) {
+	obj, err := s.lhClient.LonghornV1beta2().SystemBackups(s.namespace).UpdateStatus(context.TODO(), systemBackup, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	verifyUpdate(systemBackup.Name, obj, func(name string) (runtime.Object, error) {
+		return s.GetSystemBackupRO(name)
+	})
+	return obj, nil
+}
+
+// RemoveFinalizerForSystemBackup will result in deletion if DeletionTimestamp was set
+func (s *DataStore) RemoveFinalizerForSystemBackup(systemBackup *longhorn.SystemBackup) error {
+	if !util.FinalizerExists(longhornFinalizerKey, systemBackup) {
+		// finalizer already removed
+		return nil
+	}
+	if err := util.RemoveFinalizer(longhornFinalizerKey, systemBackup); err != nil {
+		return err
+	}
+	_, err := s.lhClient.LonghornV1beta2().SystemBackups(s.namespace).Update(context.TODO(), systemBackup, metav1.UpdateOptions{})
+	if err != nil {
+		// workaround `StorageError: invalid object, Code: 4` due to empty object
+		if systemBackup.DeletionTimestamp != nil {
+			return nil
+		}
+		return errors.Wrapf(err, "unable to remove finalizer for system backup %v", systemBackup.Name)
+	}
+	return nil
+}
+
+// DeleteSystemBackup deletes the SystemBackup for the given name
+func (s *DataStore) DeleteSystemBackup(name string) error {
+	return s.lhClient.LonghornV1beta2().SystemBackups(s.namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
+
+// CreateSystemRestore creates a Longhorn SystemRestore resource and verifies creation
+func (s *DataStore) CreateSystemRestore(systemRestore *longhorn.SystemRestore) (*longhorn.SystemRestore, error) {
+	if systemRestore.Spec.SystemBackup == "" {
+		return nil, fmt.Errorf("BUG: missing system backup name for system restore %v", systemRestore.Name)
+	}
+	if err := util.AddFinalizer(longhornFinalizerKey, systemRestore); err != nil {
+		return nil, err
+	}
+
+	ret, err := s.lhClient.LonghornV1beta2().SystemRestores(s.namespace).Create(context.TODO(), systemRestore, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if SkipListerCheck {
+		return ret, nil
+	}
+
+	obj, err := verifyCreation(systemRestore.Name, "system restore", func(name string) (runtime.Object, error) {
+		return s.GetSystemRestoreRO(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := obj.(*longhorn.SystemRestore)
+	if !ok {
+		return nil, fmt.Errorf("BUG: datastore: verifyCreation returned wrong type for system restore")
+	}
+
+	return ret.DeepCopy(), nil
+}
+
+// GetSystemRestoreRO returns the SystemRestore with the given name in the cluster
+func (s *DataStore) GetSystemRestoreRO(name string) (*longhorn.SystemRestore, error) {
+	return s.srLister.SystemRestores(s.namespace).Get(name)
+}
+
+// GetSystemRestore returns a copy of SystemRestore with the given name in the cluster
+func (s *DataStore) GetSystemRestore(name string) (*longhorn.SystemRestore, error) {
+	resultRO, err := s.GetSystemRestoreRO(name)
+	if err != nil {
+		return nil, err
+	}
+	// Cannot use cached object from lister
+	return resultRO.DeepCopy(), nil
+}
+
+// ListSystemRestoresRO returns a map of SystemRestores indexed by name
+func (s *DataStore) ListSystemRestoresRO() (map[string]*longhorn.SystemRestore, error) {
+	list, err := s.srLister.SystemRestores(s.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	systemRestoreMap := make(map[string]*longhorn.SystemRestore, len(list))
+	for _, systemRestore := range list {
+		systemRestoreMap[systemRestore.Name] = systemRestore
+	}
+	return systemRestoreMap, nil
+}
+
+// UpdateSystemRestoreStatus updates Longhorn SystemRestore status and verifies update
+func (s *DataStore) UpdateSystemRestoreStatus(systemRestore *longhorn.SystemRestore) (*longhorn.SystemRestore, error) {
+	obj, err := s.lhClient.LonghornV1beta2().SystemRestores(s.namespace).UpdateStatus(context.TODO(), systemRestore, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	verifyUpdate(systemRestore.Name, obj, func(name string) (runtime.Object, error) {
+		return s.GetSystemRestoreRO(name)
+	})
+	return obj, nil
+}
+
+// RemoveFinalizerForSystemRestore will result in deletion if DeletionTimestamp was set
+func (s *DataStore) RemoveFinalizerForSystemRestore(systemRestore *longhorn.SystemRestore) error {
+	if !util.FinalizerExists(longhornFinalizerKey, systemRestore) {
+		// finalizer already removed
+		return nil
+	}
+	if err := util.RemoveFinalizer(longhornFinalizerKey, systemRestore); err != nil {
+		return err
+	}
+	_, err := s.lhClient.LonghornV1beta2().SystemRestores(s.namespace).Update(context.TODO(), systemRestore, metav1.UpdateOptions{})
+	if err != nil {
+		// workaround `StorageError: invalid object, Code: 4` due to empty object
+		if systemRestore.DeletionTimestamp != nil {
+			return nil
+		}
+		return errors.Wrapf(err, "unable to remove finalizer for system restore %v", systemRestore.Name)
+	}
+	return nil
+}
+
+// DeleteSystemRestore deletes the SystemRestore for the given name
+func (s *DataStore) DeleteSystemRestore(name string) error {
+	return s.lhClient.LonghornV1beta2().SystemRestores(s.namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
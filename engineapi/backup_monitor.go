@@ -38,7 +38,8 @@ type BackupMonitor struct {
 
 func NewBackupMonitor(logger logrus.FieldLogger,
 	backup *longhorn.Backup, volume *longhorn.Volume, backupTargetClient *BackupTargetClient,
-	biChecksum string, engineClient EngineClient, syncCallback func(key string)) (*BackupMonitor, error) {
+	biChecksum, compressionMethod string, compressionLevel int,
+	engineClient EngineClient, syncCallback func(key string)) (*BackupMonitor, error) {
 	ctx, quit := context.WithCancel(context.Background())
 	m := &BackupMonitor{
 		logger: logger,
@@ -61,6 +62,7 @@ func NewBackupMonitor(logger logrus.FieldLogger,
 	if backup.Status.State == longhorn.BackupStateNew {
 		_, replicaAddress, err := engineClient.SnapshotBackup(backup.Name, backup.Spec.SnapshotName,
 			backupTargetClient.URL, volume.Spec.BackingImage, biChecksum,
+			compressionMethod, compressionLevel,
 			backup.Spec.Labels, backupTargetClient.Credential)
 		if err != nil {
 			m.logger.WithError(err).Warn("Cannot take snapshot backup")
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -74,6 +75,11 @@ func getBackupCredentialEnv(backupTarget string, credential map[string]string) (
 	envs = append(envs, fmt.Sprintf("%s=%s", types.HTTPProxy, credential[types.HTTPProxy]))
 	envs = append(envs, fmt.Sprintf("%s=%s", types.NOProxy, credential[types.NOProxy]))
 	envs = append(envs, fmt.Sprintf("%s=%s", types.VirtualHostedStyle, credential[types.VirtualHostedStyle]))
+	for _, tuningEnv := range []string{types.BackupUploadConcurrency, types.BackupUploadPartSize, types.BackupUploadMaxRetries} {
+		if credential[tuningEnv] != "" {
+			envs = append(envs, fmt.Sprintf("%s=%s", tuningEnv, credential[tuningEnv]))
+		}
+	}
 	return envs, nil
 }
 
@@ -247,7 +253,7 @@ func (btc *BackupTargetClient) DeleteBackup(backupURL string) error {
 	return nil
 }
 
-func (e *Engine) SnapshotBackup(backupName, snapName, backupTarget, backingImageName, backingImageChecksum string, labels, credential map[string]string) (string, string, error) {
+func (e *Engine) SnapshotBackup(backupName, snapName, backupTarget, backingImageName, backingImageChecksum, compressionMethod string, compressionLevel int, labels, credential map[string]string) (string, string, error) {
 	if snapName == VolumeHeadName {
 		return "", "", fmt.Errorf("invalid operation: cannot backup %v", VolumeHeadName)
 	}
@@ -275,6 +281,15 @@ func (e *Engine) SnapshotBackup(backupName, snapName, backupTarget, backingImage
 	if backupName != "" && version.ClientVersion.CLIAPIVersion > CLIVersionFour {
 		args = append(args, "--backup-name", backupName)
 	}
+	// The compression flags are understood by the newer engines only.
+	if version.ClientVersion.CLIAPIVersion >= CLIVersionFive {
+		if compressionMethod != "" {
+			args = append(args, "--compression-method", compressionMethod)
+		}
+		if compressionLevel > 0 {
+			args = append(args, "--compression-level", strconv.Itoa(compressionLevel))
+		}
+	}
 	for k, v := range labels {
 		args = append(args, "--label", k+"="+v)
 	}
@@ -112,7 +112,7 @@ func (e *Engine) ReplicaList() (map[string]*Replica, error) {
 	return replicas, nil
 }
 
-func (e *Engine) ReplicaAdd(url string, isRestoreVolume bool) error {
+func (e *Engine) ReplicaAdd(url string, isRestoreVolume, fastSync bool) error {
 	if err := ValidateReplicaURL(url); err != nil {
 		return err
 	}
@@ -120,6 +120,9 @@ func (e *Engine) ReplicaAdd(url string, isRestoreVolume bool) error {
 	if isRestoreVolume {
 		cmd = append(cmd, "--restore")
 	}
+	if fastSync {
+		cmd = append(cmd, "--fast-sync")
+	}
 	if _, err := e.ExecuteEngineBinaryWithoutTimeout([]string{}, cmd...); err != nil {
 		return errors.Wrapf(err, "failed to add replica address='%s' to controller '%s'", url, e.name)
 	}
@@ -214,6 +217,37 @@ func (e *Engine) FrontendShutdown() error {
 	return nil
 }
 
+func (e *Engine) FreezeIO() error {
+	if _, err := e.ExecuteEngineBinary("freeze"); err != nil {
+		return errors.Wrapf(err, "error freezing the volume IO")
+	}
+
+	return nil
+}
+
+func (e *Engine) UnfreezeIO() error {
+	if _, err := e.ExecuteEngineBinary("unfreeze"); err != nil {
+		return errors.Wrapf(err, "error unfreezing the volume IO")
+	}
+
+	return nil
+}
+
+// UnmapMarkSnapChainRemovedSet controls if the engine marks the snapshot chain
+// as removed for the data blocks freed by the UNMAP requests passed through
+// from the filesystem
+func (e *Engine) UnmapMarkSnapChainRemovedSet(enabled bool) error {
+	flag := "--disable"
+	if enabled {
+		flag = "--enable"
+	}
+	if _, err := e.ExecuteEngineBinary("unmap-mark-snap-chain-removed", flag); err != nil {
+		return errors.Wrapf(err, "error setting unmap-mark-snap-chain-removed to %v", enabled)
+	}
+
+	return nil
+}
+
 func (e *Engine) ReplicaRebuildVerify(url string) error {
 	if err := ValidateReplicaURL(url); err != nil {
 		return err
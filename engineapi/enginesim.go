@@ -14,6 +14,8 @@ type EngineSimulatorRequest struct {
 	ReplicaAddrs   []string
 }
 
+// EngineSimulatorCollection is a deterministic in-memory implementation of
+// EngineClientCollection for tests.
 type EngineSimulatorCollection struct {
 	simulators map[string]*EngineSimulator
 	mutex      *sync.Mutex
@@ -42,7 +44,7 @@ func (c *EngineSimulatorCollection) CreateEngineSimulator(request *EngineSimulat
 		mutex:          &sync.RWMutex{},
 	}
 	for _, addr := range request.ReplicaAddrs {
-		if err := s.ReplicaAdd(addr, false); err != nil {
+		if err := s.ReplicaAdd(addr, false, false); err != nil {
 			return err
 		}
 	}
@@ -81,6 +83,8 @@ func (c *EngineSimulatorCollection) NewEngineClient(request *EngineClientRequest
 	return engine, nil
 }
 
+// EngineSimulator is a fake EngineClient operating on an in-memory replica
+// map, so controller behaviors can be unit-tested without a running engine.
 type EngineSimulator struct {
 	volumeName     string
 	volumeSize     int64
@@ -106,7 +110,7 @@ func (e *EngineSimulator) ReplicaList() (map[string]*Replica, error) {
 	return ret, nil
 }
 
-func (e *EngineSimulator) ReplicaAdd(url string, isRestoreVolume bool) error {
+func (e *EngineSimulator) ReplicaAdd(url string, isRestoreVolume, fastSync bool) error {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
@@ -151,11 +155,11 @@ func (e *EngineSimulator) SnapshotCreate(name string, labels map[string]string)
 	return "", fmt.Errorf("not implemented")
 }
 
-func (e *EngineSimulator) SnapshotList() (map[string]*longhorn.Snapshot, error) {
+func (e *EngineSimulator) SnapshotList() (map[string]*longhorn.SnapshotInfo, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (e *EngineSimulator) SnapshotGet(name string) (*longhorn.Snapshot, error) {
+func (e *EngineSimulator) SnapshotGet(name string) (*longhorn.SnapshotInfo, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 
@@ -167,6 +171,10 @@ func (e *EngineSimulator) SnapshotRevert(name string) error {
 	return fmt.Errorf("not implemented")
 }
 
+func (e *EngineSimulator) SnapshotHash(name string) error {
+	return fmt.Errorf("not implemented")
+}
+
 func (e *EngineSimulator) SnapshotPurge() error {
 	return fmt.Errorf("not implemented")
 }
@@ -175,7 +183,7 @@ func (e *EngineSimulator) SnapshotPurgeStatus() (map[string]*longhorn.PurgeStatu
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (e *EngineSimulator) SnapshotBackup(backupName, snapName, backupTarget, backingImageName, backingImageChecksum string, labels, credential map[string]string) (string, string, error) {
+func (e *EngineSimulator) SnapshotBackup(backupName, snapName, backupTarget, backingImageName, backingImageChecksum, compressionMethod string, compressionLevel int, labels, credential map[string]string) (string, string, error) {
 	return "", "", fmt.Errorf("not implemented")
 }
 
@@ -222,6 +230,18 @@ func (e *EngineSimulator) FrontendShutdown() error {
 	return fmt.Errorf("not implemented")
 }
 
+func (e *EngineSimulator) FreezeIO() error {
+	return fmt.Errorf("not implemented")
+}
+
+func (e *EngineSimulator) UnfreezeIO() error {
+	return fmt.Errorf("not implemented")
+}
+
+func (e *EngineSimulator) UnmapMarkSnapChainRemovedSet(enabled bool) error {
+	return fmt.Errorf("not implemented")
+}
+
 func (e *EngineSimulator) ReplicaRebuildVerify(url string) error {
 	return fmt.Errorf("not implemented")
 }
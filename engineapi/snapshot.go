@@ -29,19 +29,19 @@ func (e *Engine) SnapshotCreate(name string, labels map[string]string) (string,
 	return strings.TrimSpace(output), nil
 }
 
-func (e *Engine) SnapshotList() (map[string]*longhorn.Snapshot, error) {
+func (e *Engine) SnapshotList() (map[string]*longhorn.SnapshotInfo, error) {
 	output, err := e.ExecuteEngineBinary("snapshot", "info")
 	if err != nil {
 		return nil, errors.Wrapf(err, "error listing snapshot")
 	}
-	data := map[string]*longhorn.Snapshot{}
+	data := map[string]*longhorn.SnapshotInfo{}
 	if err := json.Unmarshal([]byte(output), &data); err != nil {
 		return nil, errors.Wrapf(err, "error parsing snapshot list")
 	}
 	return data, nil
 }
 
-func (e *Engine) SnapshotGet(name string) (*longhorn.Snapshot, error) {
+func (e *Engine) SnapshotGet(name string) (*longhorn.SnapshotInfo, error) {
 	data, err := e.SnapshotList()
 	if err != nil {
 		return nil, err
@@ -69,6 +69,20 @@ func (e *Engine) SnapshotRevert(name string) error {
 	return nil
 }
 
+// SnapshotHash asks the engine to hash the snapshot data and store the
+// checksum in the snapshot metadata. The hashing is done asynchronously and
+// the result is reported via the snapshot info once it is finished.
+func (e *Engine) SnapshotHash(name string) error {
+	if name == VolumeHeadName {
+		return fmt.Errorf("invalid operation: cannot hash %v", VolumeHeadName)
+	}
+	if _, err := e.ExecuteEngineBinaryWithoutTimeout([]string{}, "snapshot", "hash", name); err != nil {
+		return errors.Wrapf(err, "error starting hash for snapshot '%s'", name)
+	}
+	logrus.Debugf("Volume %v snapshot %v hash started", e.Name(), name)
+	return nil
+}
+
 func (e *Engine) SnapshotPurge() error {
 	if _, err := e.ExecuteEngineBinaryWithoutTimeout([]string{}, "snapshot", "purge", "--skip-if-in-progress"); err != nil {
 		return errors.Wrapf(err, "error starting snapshot purge")
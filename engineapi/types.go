@@ -58,20 +58,26 @@ type EngineClient interface {
 	FrontendStart(volumeFrontend longhorn.VolumeFrontend) error
 	FrontendShutdown() error
 
+	FreezeIO() error
+	UnfreezeIO() error
+
+	UnmapMarkSnapChainRemovedSet(enabled bool) error
+
 	ReplicaList() (map[string]*Replica, error)
-	ReplicaAdd(url string, isRestoreVolume bool) error
+	ReplicaAdd(url string, isRestoreVolume, fastSync bool) error
 	ReplicaRemove(url string) error
 	ReplicaRebuildStatus() (map[string]*longhorn.RebuildStatus, error)
 	ReplicaRebuildVerify(url string) error
 
 	SnapshotCreate(name string, labels map[string]string) (string, error)
-	SnapshotList() (map[string]*longhorn.Snapshot, error)
-	SnapshotGet(name string) (*longhorn.Snapshot, error)
+	SnapshotList() (map[string]*longhorn.SnapshotInfo, error)
+	SnapshotGet(name string) (*longhorn.SnapshotInfo, error)
 	SnapshotDelete(name string) error
 	SnapshotRevert(name string) error
+	SnapshotHash(name string) error
 	SnapshotPurge() error
 	SnapshotPurgeStatus() (map[string]*longhorn.PurgeStatus, error)
-	SnapshotBackup(backupName, snapName, backupTarget, backingImageName, backingImageChecksum string, labels, credential map[string]string) (string, string, error)
+	SnapshotBackup(backupName, snapName, backupTarget, backingImageName, backingImageChecksum, compressionMethod string, compressionLevel int, labels, credential map[string]string) (string, string, error)
 	SnapshotBackupStatus(backupName, replicaAddress string) (*longhorn.EngineBackupStatus, error)
 	SnapshotCloneStatus() (map[string]*longhorn.SnapshotCloneStatus, error)
 	SnapshotClone(snapshotName, fromControllerAddress string) error
@@ -119,6 +119,14 @@ type EngineSpec struct {
 	RequestedDataSource VolumeDataSource `json:"requestedDataSource"`
 	// +optional
 	DisableFrontend bool `json:"disableFrontend"`
+	// RequestedIOFreezeAt is the timestamp of the latest IO freeze request.
+	// An empty value means the engine IO should not be frozen.
+	// +optional
+	RequestedIOFreezeAt string `json:"requestedIOFreezeAt"`
+	// IOFreezeTimeout is the period in seconds after RequestedIOFreezeAt when
+	// the engine controller automatically unfreezes the IO again.
+	// +optional
+	IOFreezeTimeout int64 `json:"ioFreezeTimeout"`
 	// +optional
 	RevisionCounterDisabled bool `json:"revisionCounterDisabled"`
 	// +optional
@@ -162,6 +170,8 @@ type EngineStatus struct {
 	// +optional
 	SnapshotsError string `json:"snapshotsError"`
 	// +optional
+	IsIOFrozen bool `json:"isIOFrozen"`
+	// +optional
 	IsExpanding bool `json:"isExpanding"`
 	// +optional
 	LastExpansionError string `json:"lastExpansionError"`
@@ -42,6 +42,8 @@ type DiskSpec struct {
 	StorageReserved int64 `json:"storageReserved"`
 	// +optional
 	Tags []string `json:"tags"`
+	// +optional
+	Group string `json:"group"`
 }
 
 type DiskStatus struct {
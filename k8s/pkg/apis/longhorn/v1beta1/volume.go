@@ -67,6 +67,24 @@ const (
 	ReplicaAutoBalanceBestEffort  = ReplicaAutoBalance("best-effort")
 )
 
+// +kubebuilder:validation:Enum=ignored;enabled;disabled
+type ReplicaSoftAntiAffinity string
+
+const (
+	ReplicaSoftAntiAffinityIgnored  = ReplicaSoftAntiAffinity("ignored")
+	ReplicaSoftAntiAffinityEnabled  = ReplicaSoftAntiAffinity("enabled")
+	ReplicaSoftAntiAffinityDisabled = ReplicaSoftAntiAffinity("disabled")
+)
+
+// +kubebuilder:validation:Enum=ignored;enabled;disabled
+type AutoSalvage string
+
+const (
+	AutoSalvageIgnored  = AutoSalvage("ignored")
+	AutoSalvageEnabled  = AutoSalvage("enabled")
+	AutoSalvageDisabled = AutoSalvage("disabled")
+)
+
 type VolumeCloneState string
 
 const (
@@ -159,6 +177,10 @@ type VolumeSpec struct {
 	Frontend VolumeFrontend `json:"frontend"`
 	// +optional
 	FromBackup string `json:"fromBackup"`
+	// The name of the backup target the volume backs up to. An empty value
+	// means the default backup target.
+	// +optional
+	BackupTargetName string `json:"backupTargetName"`
 	// +optional
 	DataSource VolumeDataSource `json:"dataSource"`
 	// +optional
@@ -178,6 +200,8 @@ type VolumeSpec struct {
 	// +optional
 	DiskSelector []string `json:"diskSelector"`
 	// +optional
+	DiskGroup string `json:"diskGroup"`
+	// +optional
 	NodeSelector []string `json:"nodeSelector"`
 	// +optional
 	DisableFrontend bool `json:"disableFrontend"`
@@ -196,6 +220,10 @@ type VolumeSpec struct {
 	NumberOfReplicas int `json:"numberOfReplicas"`
 	// +optional
 	ReplicaAutoBalance ReplicaAutoBalance `json:"replicaAutoBalance"`
+	// +optional
+	ReplicaSoftAntiAffinity ReplicaSoftAntiAffinity `json:"replicaSoftAntiAffinity"`
+	// +optional
+	AutoSalvage AutoSalvage `json:"autoSalvage"`
 	// Deprecated. Rename to BackingImage
 	// +optional
 	BaseImage string `json:"baseImage"`
@@ -8,6 +8,10 @@ type BackupVolumeSpec struct {
 	// +optional
 	// +nullable
 	SyncRequestedAt metav1.Time `json:"syncRequestedAt"`
+	// The name of the backup target on which the backup volume is stored.
+	// An empty value means the default backup target.
+	// +optional
+	BackupTargetName string `json:"backupTargetName"`
 }
 
 // BackupVolumeStatus defines the observed state of the Longhorn backup volume
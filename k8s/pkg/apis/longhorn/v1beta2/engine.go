@@ -83,7 +83,7 @@ type SnapshotCloneStatus struct {
 	SnapshotName string `json:"snapshotName"`
 }
 
-type Snapshot struct {
+type SnapshotInfo struct {
 	// +optional
 	Name string `json:"name"`
 	// +optional
@@ -100,6 +100,8 @@ type Snapshot struct {
 	Size string `json:"size"`
 	// +optional
 	Labels map[string]string `json:"labels"`
+	// +optional
+	Checksum string `json:"checksum"`
 }
 
 // EngineSpec defines the desired state of the Longhorn engine
@@ -119,6 +121,14 @@ type EngineSpec struct {
 	RequestedDataSource VolumeDataSource `json:"requestedDataSource"`
 	// +optional
 	DisableFrontend bool `json:"disableFrontend"`
+	// RequestedIOFreezeAt is the timestamp of the latest IO freeze request.
+	// An empty value means the engine IO should not be frozen.
+	// +optional
+	RequestedIOFreezeAt string `json:"requestedIOFreezeAt"`
+	// IOFreezeTimeout is the period in seconds after RequestedIOFreezeAt when
+	// the engine controller automatically unfreezes the IO again.
+	// +optional
+	IOFreezeTimeout int64 `json:"ioFreezeTimeout"`
 	// +optional
 	RevisionCounterDisabled bool `json:"revisionCounterDisabled"`
 	// +optional
@@ -158,15 +168,21 @@ type EngineStatus struct {
 	CloneStatus map[string]*SnapshotCloneStatus `json:"cloneStatus"`
 	// +optional
 	// +nullable
-	Snapshots map[string]*Snapshot `json:"snapshots"`
+	Snapshots map[string]*SnapshotInfo `json:"snapshots"`
 	// +optional
 	SnapshotsError string `json:"snapshotsError"`
 	// +optional
+	IsIOFrozen bool `json:"isIOFrozen"`
+	// +optional
 	IsExpanding bool `json:"isExpanding"`
 	// +optional
 	LastExpansionError string `json:"lastExpansionError"`
 	// +optional
 	LastExpansionFailedAt string `json:"lastExpansionFailedAt"`
+	// Version details of the engine image the engine is currently running,
+	// including the CLI/controller API versions and the data format version
+	// +optional
+	CurrentImageVersionDetails EngineVersionDetails `json:"currentImageVersionDetails"`
 }
 
 // +genclient
@@ -6,17 +6,21 @@ const (
 	NodeConditionTypeReady            = "Ready"
 	NodeConditionTypeMountPropagation = "MountPropagation"
 	NodeConditionTypeSchedulable      = "Schedulable"
+	NodeConditionTypeSanityCheck      = "SanityCheck"
+	NodeConditionTypeInstanceManager  = "InstanceManager"
 )
 
 const (
-	NodeConditionReasonManagerPodDown            = "ManagerPodDown"
-	NodeConditionReasonManagerPodMissing         = "ManagerPodMissing"
-	NodeConditionReasonKubernetesNodeGone        = "KubernetesNodeGone"
-	NodeConditionReasonKubernetesNodeNotReady    = "KubernetesNodeNotReady"
-	NodeConditionReasonKubernetesNodePressure    = "KubernetesNodePressure"
-	NodeConditionReasonUnknownNodeConditionTrue  = "UnknownNodeConditionTrue"
-	NodeConditionReasonNoMountPropagationSupport = "NoMountPropagationSupport"
-	NodeConditionReasonKubernetesNodeCordoned    = "KubernetesNodeCordoned"
+	NodeConditionReasonManagerPodDown                  = "ManagerPodDown"
+	NodeConditionReasonManagerPodMissing               = "ManagerPodMissing"
+	NodeConditionReasonKubernetesNodeGone              = "KubernetesNodeGone"
+	NodeConditionReasonKubernetesNodeNotReady          = "KubernetesNodeNotReady"
+	NodeConditionReasonKubernetesNodePressure          = "KubernetesNodePressure"
+	NodeConditionReasonUnknownNodeConditionTrue        = "UnknownNodeConditionTrue"
+	NodeConditionReasonNoMountPropagationSupport       = "NoMountPropagationSupport"
+	NodeConditionReasonKubernetesNodeCordoned          = "KubernetesNodeCordoned"
+	NodeConditionReasonSanityCheckFailed               = "SanityCheckFailed"
+	NodeConditionReasonInstanceManagerCrashLoopBackOff = "InstanceManagerCrashLoopBackOff"
 )
 
 const (
@@ -29,6 +33,7 @@ const (
 	DiskConditionReasonDiskFilesystemChanged = "DiskFilesystemChanged"
 	DiskConditionReasonNoDiskInfo            = "NoDiskInfo"
 	DiskConditionReasonDiskNotReady          = "DiskNotReady"
+	DiskConditionReasonUnsupportedFilesystem = "UnsupportedFilesystem"
 )
 
 type DiskSpec struct {
@@ -42,6 +47,8 @@ type DiskSpec struct {
 	StorageReserved int64 `json:"storageReserved"`
 	// +optional
 	Tags []string `json:"tags"`
+	// +optional
+	Group string `json:"group"`
 }
 
 type DiskStatus struct {
@@ -57,8 +64,18 @@ type DiskStatus struct {
 	// +optional
 	// +nullable
 	ScheduledReplica map[string]int64 `json:"scheduledReplica"`
+	// The number of replicas on the disk that are requested to be evicted but not relocated yet
+	// +optional
+	EvictionRequestedReplicaCount int64 `json:"evictionRequestedReplicaCount"`
 	// +optional
 	DiskUUID string `json:"diskUUID"`
+	// The filesystem type of the disk path as reported by stat, e.g.
+	// ext2/ext3 (also reported for ext4), xfs, or nfs
+	// +optional
+	FSType string `json:"fsType"`
+	// The block size of the disk filesystem in bytes
+	// +optional
+	BlockSize int64 `json:"blockSize"`
 }
 
 // NodeSpec defines the desired state of the Longhorn node
@@ -93,6 +110,9 @@ type NodeStatus struct {
 	Region string `json:"region"`
 	// +optional
 	Zone string `json:"zone"`
+	// The time when the node last refreshed its status, according to the node's own clock
+	// +optional
+	LastHeartbeatTime string `json:"lastHeartbeatTime"`
 }
 
 // +genclient
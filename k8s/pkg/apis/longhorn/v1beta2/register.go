@@ -51,6 +51,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&BackupList{},
 		&RecurringJob{},
 		&RecurringJobList{},
+		&Snapshot{},
+		&SnapshotList{},
+		&SystemBackup{},
+		&SystemBackupList{},
+		&SystemRestore{},
+		&SystemRestoreList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
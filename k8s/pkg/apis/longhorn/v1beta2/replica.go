@@ -27,6 +27,8 @@ type ReplicaSpec struct {
 	RevisionCounterDisabled bool `json:"revisionCounterDisabled"`
 	// +optional
 	RebuildRetryCount int `json:"rebuildRetryCount"`
+	// +optional
+	CleanupRequestedAt string `json:"cleanupRequestedAt"`
 	// Deprecated
 	// +optional
 	DataPath string `json:"dataPath"`
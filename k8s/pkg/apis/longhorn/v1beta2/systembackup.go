@@ -0,0 +1,67 @@
+package v1beta2
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type SystemBackupState string
+
+const (
+	SystemBackupStateNone       = SystemBackupState("")
+	SystemBackupStateGenerating = SystemBackupState("Generating")
+	SystemBackupStateReady      = SystemBackupState("Ready")
+	SystemBackupStateError      = SystemBackupState("Error")
+)
+
+// SystemBackupSpec defines the desired state of the Longhorn system backup
+type SystemBackupSpec struct {
+}
+
+// SystemBackupStatus defines the observed state of the Longhorn system backup
+type SystemBackupStatus struct {
+	// The node ID of the responsible controller
+	// +optional
+	OwnerID string `json:"ownerID"`
+	// The system backup state
+	// +optional
+	State SystemBackupState `json:"state"`
+	// The Longhorn manager version when the system backup was taken
+	// +optional
+	Version string `json:"version"`
+	// The Longhorn manager image when the system backup was taken
+	// +optional
+	ManagerImage string `json:"managerImage"`
+	// The time the system backup bundle was generated
+	// +optional
+	// +nullable
+	CreatedAt metav1.Time `json:"createdAt"`
+	// The error message when the system backup generation fails
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=lhsb
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`,description="The Longhorn manager version when the system backup was taken"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,description="The system backup state"
+// +kubebuilder:printcolumn:name="Created",type=string,JSONPath=`.status.createdAt`,description="The time the system backup bundle was generated"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SystemBackup is the Schema for the systembackups API
+type SystemBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SystemBackupSpec   `json:"spec,omitempty"`
+	Status SystemBackupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SystemBackupList contains a list of SystemBackup
+type SystemBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SystemBackup `json:"items"`
+}
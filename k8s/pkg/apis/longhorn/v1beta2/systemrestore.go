@@ -0,0 +1,74 @@
+package v1beta2
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type SystemRestoreState string
+
+const (
+	SystemRestoreStateNone      = SystemRestoreState("")
+	SystemRestoreStateRestoring = SystemRestoreState("Restoring")
+	SystemRestoreStateCompleted = SystemRestoreState("Completed")
+	SystemRestoreStateError     = SystemRestoreState("Error")
+)
+
+// SystemRestoreSpec defines the desired state of the Longhorn system restore
+type SystemRestoreSpec struct {
+	// The system backup the Longhorn resources are restored from
+	SystemBackup string `json:"systemBackup"`
+	// Restore the volumes from their latest backup recorded in the system
+	// backup bundle
+	// +optional
+	RestoreVolumes bool `json:"restoreVolumes"`
+	// NodeMappings translates the node names recorded in the system backup
+	// to the node names of the current cluster, so the node tags and the
+	// replica placement hints keep working after restoring to a cluster
+	// with different node names
+	// +optional
+	// +nullable
+	NodeMappings map[string]string `json:"nodeMappings,omitempty"`
+	// DiskMappings translates the disk identities recorded in the system
+	// backup to the ones of the current cluster, analogous to NodeMappings
+	// +optional
+	// +nullable
+	DiskMappings map[string]string `json:"diskMappings,omitempty"`
+}
+
+// SystemRestoreStatus defines the observed state of the Longhorn system restore
+type SystemRestoreStatus struct {
+	// The node ID of the responsible controller
+	// +optional
+	OwnerID string `json:"ownerID"`
+	// The system restore state
+	// +optional
+	State SystemRestoreState `json:"state"`
+	// The error message when the system restore fails
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=lhsr
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="SystemBackup",type=string,JSONPath=`.spec.systemBackup`,description="The system backup the Longhorn resources are restored from"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,description="The system restore state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SystemRestore is the Schema for the systemrestores API
+type SystemRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SystemRestoreSpec   `json:"spec,omitempty"`
+	Status SystemRestoreStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SystemRestoreList contains a list of SystemRestore
+type SystemRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SystemRestore `json:"items"`
+}
@@ -67,6 +67,33 @@ const (
 	ReplicaAutoBalanceBestEffort  = ReplicaAutoBalance("best-effort")
 )
 
+// +kubebuilder:validation:Enum=ignored;enabled;disabled
+type ReplicaSoftAntiAffinity string
+
+const (
+	ReplicaSoftAntiAffinityIgnored  = ReplicaSoftAntiAffinity("ignored")
+	ReplicaSoftAntiAffinityEnabled  = ReplicaSoftAntiAffinity("enabled")
+	ReplicaSoftAntiAffinityDisabled = ReplicaSoftAntiAffinity("disabled")
+)
+
+// +kubebuilder:validation:Enum=ignored;enabled;disabled
+type AutoSalvage string
+
+const (
+	AutoSalvageIgnored  = AutoSalvage("ignored")
+	AutoSalvageEnabled  = AutoSalvage("enabled")
+	AutoSalvageDisabled = AutoSalvage("disabled")
+)
+
+// +kubebuilder:validation:Enum=none;lz4;zstd
+type BackupCompressionMethod string
+
+const (
+	BackupCompressionMethodNone = BackupCompressionMethod("none")
+	BackupCompressionMethodLz4  = BackupCompressionMethod("lz4")
+	BackupCompressionMethodZstd = BackupCompressionMethod("zstd")
+)
+
 type VolumeCloneState string
 
 const (
@@ -159,6 +186,10 @@ type VolumeSpec struct {
 	Frontend VolumeFrontend `json:"frontend"`
 	// +optional
 	FromBackup string `json:"fromBackup"`
+	// The name of the backup target the volume backs up to. An empty value
+	// means the default backup target.
+	// +optional
+	BackupTargetName string `json:"backupTargetName"`
 	// +optional
 	DataSource VolumeDataSource `json:"dataSource"`
 	// +optional
@@ -178,6 +209,8 @@ type VolumeSpec struct {
 	// +optional
 	DiskSelector []string `json:"diskSelector"`
 	// +optional
+	DiskGroup string `json:"diskGroup"`
+	// +optional
 	NodeSelector []string `json:"nodeSelector"`
 	// +optional
 	DisableFrontend bool `json:"disableFrontend"`
@@ -191,11 +224,45 @@ type VolumeSpec struct {
 	Migratable bool `json:"migratable"`
 	// +optional
 	Encrypted bool `json:"encrypted"`
+	// The time of the last encryption key rotation of the volume
+	// +optional
+	LastKeyRotatedAt string `json:"lastKeyRotatedAt"`
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	NumberOfReplicas int `json:"numberOfReplicas"`
 	// +optional
 	ReplicaAutoBalance ReplicaAutoBalance `json:"replicaAutoBalance"`
+	// Override of the global setting `replica-soft-anti-affinity` for the
+	// volume. "ignored" means the global setting is applied.
+	// +optional
+	ReplicaSoftAntiAffinity ReplicaSoftAntiAffinity `json:"replicaSoftAntiAffinity"`
+	// Override of the global setting `auto-salvage` for the volume.
+	// "ignored" means the global setting is applied.
+	// +optional
+	AutoSalvage AutoSalvage `json:"autoSalvage"`
+	// The maximum count of the snapshots of the volume. Once the limit is
+	// exceeded, the oldest removable snapshots are pruned automatically.
+	// 0 means no limit.
+	// +optional
+	SnapshotMaxCount int `json:"snapshotMaxCount"`
+	// The maximum total size of the snapshots of the volume. Once the limit
+	// is exceeded, the oldest removable snapshots are pruned automatically.
+	// 0 means no limit.
+	// +kubebuilder:validation:Type=string
+	// +optional
+	SnapshotMaxSize int64 `json:"snapshotMaxSize,string"`
+	// The compression method used by the backups of the volume. An empty
+	// value means the global setting `backup-compression-method` is applied.
+	// +optional
+	BackupCompressionMethod BackupCompressionMethod `json:"backupCompressionMethod"`
+	// Pause auto-salvage, replica rebuild triggering, and degraded-volume
+	// events for the volume during planned maintenance
+	// +optional
+	Maintenance bool `json:"maintenance"`
+	// The time when the maintenance pause expires automatically.
+	// An empty value means the pause lasts until Maintenance is unset.
+	// +optional
+	MaintenanceExpiresAt string `json:"maintenanceExpiresAt"`
 	// Deprecated. Rename to BackingImage
 	// +optional
 	BaseImage string `json:"baseImage"`
@@ -216,6 +283,11 @@ type VolumeStatus struct {
 	CurrentNodeID string `json:"currentNodeID"`
 	// +optional
 	CurrentImage string `json:"currentImage"`
+	// Version details reported for the current engine image, so clients can
+	// check the binary version and the CLI/controller/data format
+	// compatibility without resolving the engine image resource
+	// +optional
+	CurrentImageVersionDetails EngineVersionDetails `json:"currentImageVersionDetails"`
 	// +optional
 	KubernetesStatus KubernetesStatus `json:"kubernetesStatus"`
 	// +optional
@@ -241,8 +313,17 @@ type VolumeStatus struct {
 	ExpansionRequired bool `json:"expansionRequired"`
 	// +optional
 	IsStandby bool `json:"isStandby"`
+	// The last backup that was applied by the restoring or standby volume.
+	// For a standby volume, comparing it with LastBackup shows how far the
+	// volume lags behind the backupstore.
+	// +optional
+	LastRestoredBackup string `json:"lastRestoredBackup"`
 	// +optional
 	ActualSize int64 `json:"actualSize"`
+	// The total actual space used by the snapshots of the volume, not
+	// including the volume head
+	// +optional
+	SnapshotsSize int64 `json:"snapshotsSize"`
 	// +optional
 	LastDegradedAt string `json:"lastDegradedAt"`
 	// +optional
@@ -72,6 +72,18 @@ func (c *FakeLonghornV1beta2) RecurringJobs(namespace string) v1beta2.RecurringJ
 	return &FakeRecurringJobs{c, namespace}
 }
 
+func (c *FakeLonghornV1beta2) Snapshots(namespace string) v1beta2.SnapshotInterface {
+	return &FakeSnapshots{c, namespace}
+}
+
+func (c *FakeLonghornV1beta2) SystemBackups(namespace string) v1beta2.SystemBackupInterface {
+	return &FakeSystemBackups{c, namespace}
+}
+
+func (c *FakeLonghornV1beta2) SystemRestores(namespace string) v1beta2.SystemRestoreInterface {
+	return &FakeSystemRestores{c, namespace}
+}
+
 func (c *FakeLonghornV1beta2) Replicas(namespace string) v1beta2.ReplicaInterface {
 	return &FakeReplicas{c, namespace}
 }
@@ -40,6 +40,12 @@ type NodeExpansion interface{}
 
 type RecurringJobExpansion interface{}
 
+type SnapshotExpansion interface{}
+
+type SystemBackupExpansion interface{}
+
+type SystemRestoreExpansion interface{}
+
 type ReplicaExpansion interface{}
 
 type SettingExpansion interface{}
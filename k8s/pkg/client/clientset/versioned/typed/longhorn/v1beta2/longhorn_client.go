@@ -37,6 +37,9 @@ type LonghornV1beta2Interface interface {
 	InstanceManagersGetter
 	NodesGetter
 	RecurringJobsGetter
+	SnapshotsGetter
+	SystemBackupsGetter
+	SystemRestoresGetter
 	ReplicasGetter
 	SettingsGetter
 	ShareManagersGetter
@@ -92,6 +95,18 @@ func (c *LonghornV1beta2Client) RecurringJobs(namespace string) RecurringJobInte
 	return newRecurringJobs(c, namespace)
 }
 
+func (c *LonghornV1beta2Client) Snapshots(namespace string) SnapshotInterface {
+	return newSnapshots(c, namespace)
+}
+
+func (c *LonghornV1beta2Client) SystemBackups(namespace string) SystemBackupInterface {
+	return newSystemBackups(c, namespace)
+}
+
+func (c *LonghornV1beta2Client) SystemRestores(namespace string) SystemRestoreInterface {
+	return newSystemRestores(c, namespace)
+}
+
 func (c *LonghornV1beta2Client) Replicas(namespace string) ReplicaInterface {
 	return newReplicas(c, namespace)
 }
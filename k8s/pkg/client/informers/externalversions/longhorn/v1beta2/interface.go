@@ -46,12 +46,18 @@ type Interface interface {
 	Nodes() NodeInformer
 	// RecurringJobs returns a RecurringJobInformer.
 	RecurringJobs() RecurringJobInformer
+	// Snapshots returns a SnapshotInformer.
+	Snapshots() SnapshotInformer
 	// Replicas returns a ReplicaInformer.
 	Replicas() ReplicaInformer
 	// Settings returns a SettingInformer.
 	Settings() SettingInformer
 	// ShareManagers returns a ShareManagerInformer.
 	ShareManagers() ShareManagerInformer
+	// SystemBackups returns a SystemBackupInformer.
+	SystemBackups() SystemBackupInformer
+	// SystemRestores returns a SystemRestoreInformer.
+	SystemRestores() SystemRestoreInformer
 	// Volumes returns a VolumeInformer.
 	Volumes() VolumeInformer
 }
@@ -122,6 +128,11 @@ func (v *version) RecurringJobs() RecurringJobInformer {
 	return &recurringJobInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// Snapshots returns a SnapshotInformer.
+func (v *version) Snapshots() SnapshotInformer {
+	return &snapshotInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Replicas returns a ReplicaInformer.
 func (v *version) Replicas() ReplicaInformer {
 	return &replicaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -137,6 +148,16 @@ func (v *version) ShareManagers() ShareManagerInformer {
 	return &shareManagerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// SystemBackups returns a SystemBackupInformer.
+func (v *version) SystemBackups() SystemBackupInformer {
+	return &systemBackupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SystemRestores returns a SystemRestoreInformer.
+func (v *version) SystemRestores() SystemRestoreInformer {
+	return &systemRestoreInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Volumes returns a VolumeInformer.
 func (v *version) Volumes() VolumeInformer {
 	return &volumeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
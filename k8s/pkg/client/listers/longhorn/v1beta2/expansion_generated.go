@@ -106,6 +106,30 @@ type RecurringJobListerExpansion interface{}
 // RecurringJobNamespaceLister.
 type RecurringJobNamespaceListerExpansion interface{}
 
+// SnapshotListerExpansion allows custom methods to be added to
+// SnapshotLister.
+type SnapshotListerExpansion interface{}
+
+// SnapshotNamespaceListerExpansion allows custom methods to be added to
+// SnapshotNamespaceLister.
+type SnapshotNamespaceListerExpansion interface{}
+
+// SystemBackupListerExpansion allows custom methods to be added to
+// SystemBackupLister.
+type SystemBackupListerExpansion interface{}
+
+// SystemBackupNamespaceListerExpansion allows custom methods to be added to
+// SystemBackupNamespaceLister.
+type SystemBackupNamespaceListerExpansion interface{}
+
+// SystemRestoreListerExpansion allows custom methods to be added to
+// SystemRestoreLister.
+type SystemRestoreListerExpansion interface{}
+
+// SystemRestoreNamespaceListerExpansion allows custom methods to be added to
+// SystemRestoreNamespaceLister.
+type SystemRestoreNamespaceListerExpansion interface{}
+
 // ReplicaListerExpansion allows custom methods to be added to
 // ReplicaLister.
 type ReplicaListerExpansion interface{}
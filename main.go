@@ -54,8 +54,11 @@ func main() {
 		app.RecurringJobCmd(),
 		app.DeployDriverCmd(),
 		app.CSICommand(),
+		app.ConversionWebhookCmd(),
+		app.AdmissionWebhookCmd(),
 		app.PostUpgradeCmd(),
 		app.UninstallCmd(),
+		app.AdoptDiskCmd(),
 		// TODO: Remove MigrateForPre070VolumesCmd() after v0.8.1
 		app.MigrateForPre070VolumesCmd(),
 	}
@@ -12,6 +12,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/longhorn/longhorn-manager/engineapi"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
 
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 )
@@ -20,7 +22,7 @@ const (
 	BackupStatusQueryInterval = 2 * time.Second
 )
 
-func (m *VolumeManager) ListSnapshots(volumeName string) (map[string]*longhorn.Snapshot, error) {
+func (m *VolumeManager) ListSnapshots(volumeName string) (map[string]*longhorn.SnapshotInfo, error) {
 	if volumeName == "" {
 		return nil, fmt.Errorf("volume name required")
 	}
@@ -31,7 +33,7 @@ func (m *VolumeManager) ListSnapshots(volumeName string) (map[string]*longhorn.S
 	return engine.SnapshotList()
 }
 
-func (m *VolumeManager) GetSnapshot(snapshotName, volumeName string) (*longhorn.Snapshot, error) {
+func (m *VolumeManager) GetSnapshot(snapshotName, volumeName string) (*longhorn.SnapshotInfo, error) {
 	if volumeName == "" || snapshotName == "" {
 		return nil, fmt.Errorf("volume and snapshot name required")
 	}
@@ -49,7 +51,7 @@ func (m *VolumeManager) GetSnapshot(snapshotName, volumeName string) (*longhorn.
 	return snapshot, nil
 }
 
-func (m *VolumeManager) CreateSnapshot(snapshotName string, labels map[string]string, volumeName string) (*longhorn.Snapshot, error) {
+func (m *VolumeManager) CreateSnapshot(snapshotName string, labels map[string]string, volumeName string) (*longhorn.SnapshotInfo, error) {
 	if volumeName == "" {
 		return nil, fmt.Errorf("volume name required")
 	}
@@ -64,6 +66,10 @@ func (m *VolumeManager) CreateSnapshot(snapshotName string, labels map[string]st
 		return nil, err
 	}
 
+	if err := m.checkSnapshotSpaceAvailable(volumeName); err != nil {
+		return nil, err
+	}
+
 	engine, err := m.GetEngineClient(volumeName)
 	if err != nil {
 		return nil, err
@@ -103,6 +109,60 @@ func (m *VolumeManager) DeleteSnapshot(snapshotName, volumeName string) error {
 	return nil
 }
 
+// SnapshotBeforeRiskyOperation takes a safety snapshot of the volume before an
+// operation that may corrupt the volume data, so the user has a point to roll
+// back to if the operation goes wrong. The snapshot is taken synchronously and
+// then tracked with a snapshot CR so the snapshot controller can clean it up
+// once the cleanup wait interval has passed. It is a no-op if the setting
+// `auto-snapshot-before-risky-operation` is disabled or the volume is not
+// attached.
+func (m *VolumeManager) SnapshotBeforeRiskyOperation(volumeName, operation string) error {
+	enabled, err := m.ds.GetSettingAsBool(types.SettingNameAutoSnapshotBeforeRiskyOperation)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	v, err := m.ds.GetVolume(volumeName)
+	if err != nil {
+		return err
+	}
+	if v.Status.State != longhorn.VolumeStateAttached {
+		// A snapshot can be taken via the running volume engine only
+		return nil
+	}
+
+	engine, err := m.GetEngineClient(volumeName)
+	if err != nil {
+		return err
+	}
+	snapshotName := fmt.Sprintf("%s-%s", operation, util.RandomID())
+	labels := map[string]string{
+		types.GetLonghornLabelKey(types.LonghornLabelSnapshotBeforeRiskyOperation): operation,
+	}
+	if _, err := engine.SnapshotCreate(snapshotName, labels); err != nil {
+		return err
+	}
+
+	snapshotCR := &longhorn.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: snapshotName,
+		},
+		Spec: longhorn.SnapshotSpec{
+			Volume: volumeName,
+			Labels: labels,
+		},
+	}
+	if _, err := m.ds.CreateSnapshot(snapshotCR); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	logrus.Infof("Created safety snapshot %v before the %v of volume %v", snapshotName, operation, volumeName)
+	return nil
+}
+
 func (m *VolumeManager) RevertSnapshot(snapshotName, volumeName string) error {
 	if volumeName == "" || snapshotName == "" {
 		return fmt.Errorf("volume and snapshot name required")
@@ -123,6 +183,9 @@ func (m *VolumeManager) RevertSnapshot(snapshotName, volumeName string) error {
 	if snapshot == nil {
 		return fmt.Errorf("not found snapshot '%s', for volume '%s'", snapshotName, volumeName)
 	}
+	if err := m.SnapshotBeforeRiskyOperation(volumeName, "snapshot-revert"); err != nil {
+		return err
+	}
 	if err := engine.SnapshotRevert(snapshotName); err != nil {
 		return err
 	}
@@ -147,6 +210,7 @@ func (m *VolumeManager) PurgeSnapshot(volumeName string) error {
 	if err := engine.SnapshotPurge(); err != nil {
 		return err
 	}
+	m.trackSnapshotPurge(volumeName)
 	logrus.Debugf("Started snapshot purge for volume %v", volumeName)
 	return nil
 }
@@ -160,6 +224,10 @@ func (m *VolumeManager) BackupSnapshot(backupName, volumeName, snapshotName stri
 		return err
 	}
 
+	if err := m.checkSnapshotSpaceAvailable(volumeName); err != nil {
+		return err
+	}
+
 	backupCR := &longhorn.Backup{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: backupName,
@@ -169,8 +237,51 @@ func (m *VolumeManager) BackupSnapshot(backupName, volumeName, snapshotName stri
 			Labels:       labels,
 		},
 	}
-	_, err := m.ds.CreateBackup(backupCR, volumeName)
-	return err
+	if _, err := m.ds.CreateBackup(backupCR, volumeName); err != nil {
+		return err
+	}
+	m.trackBackup(backupName, volumeName, snapshotName)
+	return nil
+}
+
+// checkSnapshotSpaceAvailable refuses new snapshot or backup creation when a
+// disk hosting one of the volume replicas is used above the critical
+// threshold, so a runaway snapshot chain cannot fill the disk and fail every
+// replica on it. A backup still needs a snapshot to be taken first, which is
+// why the backup path shares the check.
+func (m *VolumeManager) checkSnapshotSpaceAvailable(volumeName string) error {
+	threshold, err := m.ds.GetSettingAsInt(types.SettingNameSnapshotDiskUsageCriticalPercentage)
+	if err != nil {
+		return err
+	}
+	if threshold <= 0 {
+		return nil
+	}
+
+	replicas, err := m.ds.ListVolumeReplicas(volumeName)
+	if err != nil {
+		return err
+	}
+	for _, r := range replicas {
+		if r.Spec.NodeID == "" || r.Spec.DiskID == "" || r.Spec.FailedAt != "" {
+			continue
+		}
+		node, err := m.ds.GetNodeRO(r.Spec.NodeID)
+		if err != nil {
+			continue
+		}
+		for diskName, diskStatus := range node.Status.DiskStatus {
+			if diskStatus.DiskUUID != r.Spec.DiskID || diskStatus.StorageMaximum <= 0 {
+				continue
+			}
+			usage := (diskStatus.StorageMaximum - diskStatus.StorageAvailable) * 100 / diskStatus.StorageMaximum
+			if usage >= threshold {
+				return fmt.Errorf("cannot create a new snapshot for volume %v: disk %v on node %v is %v%% used, above the critical threshold of %v%%; free up space or adjust the setting %v",
+					volumeName, diskName, r.Spec.NodeID, usage, threshold, types.SettingNameSnapshotDiskUsageCriticalPercentage)
+			}
+		}
+	}
+	return nil
 }
 
 func (m *VolumeManager) checkVolumeNotInMigration(volumeName string) error {
@@ -238,6 +349,60 @@ func (m *VolumeManager) ListBackupTargetsSorted() ([]*longhorn.BackupTarget, err
 	return backupTargets, nil
 }
 
+func (m *VolumeManager) GetBackupTarget(name string) (*longhorn.BackupTarget, error) {
+	return m.ds.GetBackupTarget(name)
+}
+
+// CreateBackupTarget registers an additional backup target besides the
+// default one managed through the backup target settings
+func (m *VolumeManager) CreateBackupTarget(name string, spec *longhorn.BackupTargetSpec) (*longhorn.BackupTarget, error) {
+	if name == types.DefaultBackupTargetName {
+		return nil, fmt.Errorf("the %s backup target is managed through the backup target settings", name)
+	}
+	backupTarget, err := m.ds.CreateBackupTarget(&longhorn.BackupTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: *spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("Created backup target %v with URL %v", name, spec.BackupTargetURL)
+	return backupTarget, nil
+}
+
+func (m *VolumeManager) UpdateBackupTarget(name string, spec *longhorn.BackupTargetSpec) (*longhorn.BackupTarget, error) {
+	if name == types.DefaultBackupTargetName {
+		return nil, fmt.Errorf("the %s backup target is managed through the backup target settings", name)
+	}
+	existing, err := m.ds.GetBackupTarget(name)
+	if err != nil {
+		return nil, err
+	}
+	existing.Spec.BackupTargetURL = spec.BackupTargetURL
+	existing.Spec.CredentialSecret = spec.CredentialSecret
+	existing.Spec.PollInterval = spec.PollInterval
+	existing.Spec.SyncRequestedAt = metav1.Time{Time: time.Now().UTC()}
+	backupTarget, err := m.ds.UpdateBackupTarget(existing)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("Updated backup target %v with URL %v", name, spec.BackupTargetURL)
+	return backupTarget, nil
+}
+
+func (m *VolumeManager) DeleteBackupTarget(name string) error {
+	if name == types.DefaultBackupTargetName {
+		return fmt.Errorf("cannot delete the %s backup target", name)
+	}
+	if err := m.ds.DeleteBackupTarget(name); err != nil {
+		return err
+	}
+	logrus.Infof("Deleted backup target %v", name)
+	return nil
+}
+
 func (m *VolumeManager) ListBackupVolumes() (map[string]*longhorn.BackupVolume, error) {
 	return m.ds.ListBackupVolumes()
 }
@@ -273,6 +438,18 @@ func (m *VolumeManager) GetBackupVolume(volumeName string) (*longhorn.BackupVolu
 	return backupVolume, err
 }
 
+func (m *VolumeManager) SyncBackupVolume(volumeName string) (*longhorn.BackupVolume, error) {
+	backupVolume, err := m.ds.GetBackupVolume(volumeName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Request the backup volume controller to pull the latest backups
+	// from the remote backup target
+	backupVolume.Spec.SyncRequestedAt = metav1.Time{Time: time.Now().UTC()}
+	return m.ds.UpdateBackupVolume(backupVolume)
+}
+
 func (m *VolumeManager) DeleteBackupVolume(volumeName string) error {
 	return m.ds.DeleteBackupVolume(volumeName)
 }
@@ -52,6 +52,35 @@ func (m *VolumeManager) GetEngineImageByImage(image string) (*longhorn.EngineIma
 	return m.ds.GetEngineImage(name)
 }
 
+// ListVolumesForEngineImage returns the volumes still referencing the engine
+// image sorted by name, i.e. the volumes blocking the image's deprecation
+// and removal.
+func (m *VolumeManager) ListVolumesForEngineImage(name string) ([]*longhorn.Volume, error) {
+	ei, err := m.GetEngineImageByName(name)
+	if err != nil {
+		return nil, err
+	}
+	volumeMap, err := m.ds.ListVolumes()
+	if err != nil {
+		return nil, err
+	}
+	for volumeName, v := range volumeMap {
+		if v.Spec.EngineImage != ei.Spec.Image && v.Status.CurrentImage != ei.Spec.Image {
+			delete(volumeMap, volumeName)
+		}
+	}
+
+	volumes := make([]*longhorn.Volume, len(volumeMap))
+	volumeNames, err := sortKeys(volumeMap)
+	if err != nil {
+		return nil, err
+	}
+	for i, volumeName := range volumeNames {
+		volumes[i] = volumeMap[volumeName]
+	}
+	return volumes, nil
+}
+
 func (m *VolumeManager) CreateEngineImage(image string) (*longhorn.EngineImage, error) {
 	image = strings.TrimSpace(image)
 	if image == "" {
@@ -112,3 +141,45 @@ func (m *VolumeManager) DeployEngineImage(image string) error {
 	}
 	return nil
 }
+
+// checkDefaultEngineImageReadiness refuses the given engine image for new
+// volumes until it has been deployed on the configured percentage of the
+// ready nodes. Right after the default engine image setting changed, the new
+// image is still being pulled on most nodes and a volume created with it
+// could not be attached anywhere.
+func (m *VolumeManager) checkDefaultEngineImageReadiness(image string) error {
+	requiredPercentage, err := m.ds.GetSettingAsInt(types.SettingNameDefaultEngineImageReadinessPercentage)
+	if err != nil {
+		return err
+	}
+	if requiredPercentage <= 0 {
+		return nil
+	}
+
+	readyNodes, err := m.ds.ListReadyNodes()
+	if err != nil {
+		return err
+	}
+	// Without any ready node the creation fails for other reasons anyway.
+	if len(readyNodes) == 0 {
+		return nil
+	}
+	deployedNodes, err := m.ds.ListReadyNodesWithEngineImage(image)
+	if err != nil {
+		return err
+	}
+	deployedCount := 0
+	for name := range readyNodes {
+		if _, exists := deployedNodes[name]; exists {
+			deployedCount++
+		}
+	}
+	deployedPercentage := int64(deployedCount * 100 / len(readyNodes))
+	if deployedPercentage < requiredPercentage {
+		return fmt.Errorf("cannot use the default engine image %v for new volumes: "+
+			"it is deployed on %v%% of the ready nodes but setting %v requires %v%%; "+
+			"wait for the engine image deployment to finish or lower the setting",
+			image, deployedPercentage, types.SettingNameDefaultEngineImageReadinessPercentage, requiredPercentage)
+	}
+	return nil
+}
@@ -132,3 +132,7 @@ func (m *VolumeManager) PVCCreate(name, namespace, pvcName string) (v *longhorn.
 func (m *VolumeManager) GetDaemonSetRO(name string) (*appsv1.DaemonSet, error) {
 	return m.ds.GetDaemonSet(name)
 }
+
+func (m *VolumeManager) GetSecretRO(namespace, name string) (*apiv1.Secret, error) {
+	return m.ds.GetSecretRO(namespace, name)
+}
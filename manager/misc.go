@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
@@ -92,22 +93,27 @@ type BundleMeta struct {
 // 2. YAMLs of pods, services, daemonset, deployment in longhorn namespace
 // 3. All the logs of pods in the longhorn namespace
 // 4. Recent events happens in the longhorn namespace
+// 5. Disk info of the Longhorn disks on the current node
 //
 // Directories are organized like this:
 // root
 // |- yamls
-//   |- events.yaml
-//   |- crds
-//     |- <list of top level CRD objects>
-//       |- <name of each crd>.yaml
-//   |- pods
-//     |- <name of each pod>.yaml
-//   |- <other workloads>...
+//
+//	|- events.yaml
+//	|- crds
+//	  |- <list of top level CRD objects>
+//	    |- <name of each crd>.yaml
+//	|- pods
+//	  |- <name of each pod>.yaml
+//	|- <other workloads>...
+//
 // |- logs
-//   |- <name of each pod>.log
-//   |- <directory by the name of pod, if multiple containers exists>
-//     |- <container1>.log
-//     |- ...
+//
+//	|- <name of each pod>.log
+//	|- <directory by the name of pod, if multiple containers exists>
+//	  |- <container1>.log
+//	  |- ...
+//
 // The bundle would be compressed to a zip file for download.
 func (m *VolumeManager) GenerateSupportBundle(issueURL string, description string) (*SupportBundle, error) {
 	namespace, err := m.ds.GetLonghornNamespace()
@@ -184,6 +190,9 @@ func (m *VolumeManager) generateSupportBundle(bundleDir string, bundleMeta *Bund
 	m.generateSupportBundleYAMLs(yamlsDir, errLog)
 	sb.ProgressPercentage = BundleProgressPercentageYaml
 
+	nodesDir := filepath.Join(bundleDir, "nodes")
+	m.generateSupportBundleNodeDiskInfo(nodesDir, errLog)
+
 	logsDir := filepath.Join(bundleDir, "logs")
 	m.generateSupportBundleLogs(logsDir, errLog, sb)
 	sb.ProgressPercentage = BundleProgressPercentageYaml + BundleProgressPercentageLogs
@@ -317,6 +326,28 @@ func encodeToYAMLFile(obj interface{}, path string, errLog io.Writer) {
 	}
 }
 
+// generateSupportBundleNodeDiskInfo dumps the filesystem level information of
+// the Longhorn disks on the current node. The node CR YAMLs already cover the
+// other nodes; the live disk info can only be read locally.
+func (m *VolumeManager) generateSupportBundleNodeDiskInfo(nodesDir string, errLog io.Writer) {
+	node, err := m.ds.GetNode(m.currentNodeID)
+	if err != nil {
+		fmt.Fprintf(errLog, "Support Bundle: cannot get node %v: %v\n", m.currentNodeID, err)
+		return
+	}
+	diskInfoMap := map[string]*util.DiskInfo{}
+	for diskName, disk := range node.Spec.Disks {
+		info, err := util.GetDiskInfo(disk.Path)
+		if err != nil {
+			fmt.Fprintf(errLog, "Support Bundle: cannot get disk info of disk %v (%v) on node %v: %v\n",
+				diskName, disk.Path, m.currentNodeID, err)
+			continue
+		}
+		diskInfoMap[diskName] = info
+	}
+	encodeToYAMLFile(diskInfoMap, filepath.Join(nodesDir, m.currentNodeID, "diskinfo.yaml"), errLog)
+}
+
 func (m *VolumeManager) generateSupportBundleLogs(logsDir string, errLog io.Writer, sb *SupportBundle) {
 	list, err := m.ds.GetAllPodsList()
 	if err != nil {
@@ -384,3 +415,96 @@ func (m *VolumeManager) InitSupportBundle(issueURL string, description string) (
 	m.sb = sb
 	return m.sb, nil
 }
+
+const (
+	// ActivityLogTailLines caps how many log lines are fetched from each
+	// container for the volume activity log.
+	ActivityLogTailLines = 10000
+	// ActivityLogMaxLineSize is the longest log line the volume activity
+	// log accepts before marking the container log as truncated.
+	ActivityLogMaxLineSize = 1024 * 1024
+)
+
+// GetVolumeActivityLog aggregates the log lines of the manager and instance
+// manager pods that mention the given volume or one of its engines/replicas
+// into a single text stream, so a single volume can be debugged without
+// generating a full support bundle.
+func (m *VolumeManager) GetVolumeActivityLog(volumeName string) (io.ReadCloser, error) {
+	if _, err := m.ds.GetVolume(volumeName); err != nil {
+		return nil, err
+	}
+
+	matchTerms := []string{volumeName}
+	engines, err := m.ds.ListVolumeEngines(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	for name := range engines {
+		matchTerms = append(matchTerms, name)
+	}
+	replicas, err := m.ds.ListVolumeReplicas(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	for name := range replicas {
+		matchTerms = append(matchTerms, name)
+	}
+
+	managerPods, err := m.ds.ListManagerPods()
+	if err != nil {
+		return nil, err
+	}
+	instanceManagerPods, err := m.ds.ListInstanceManagerPods()
+	if err != nil {
+		return nil, err
+	}
+
+	pods := append(managerPods, instanceManagerPods...)
+	reader, writer := io.Pipe()
+	go func() {
+		defer writer.Close()
+		for _, pod := range pods {
+			for _, container := range pod.Spec.Containers {
+				if err := m.writeVolumeActivityLog(writer, pod.Name, container.Name, matchTerms); err != nil {
+					// The reader went away, stop streaming.
+					return
+				}
+			}
+		}
+	}()
+
+	return reader, nil
+}
+
+// writeVolumeActivityLog copies the matching lines of one container log to
+// the writer. Only the tail of the log is fetched and it is scanned as a
+// stream, so an instance manager log of hundreds of MB never ends up in a
+// buffer of the manager. A returned error means the writer rejected the
+// output; log fetch problems are reported inline as comment lines instead.
+func (m *VolumeManager) writeVolumeActivityLog(w io.Writer, podName, containerName string, matchTerms []string) error {
+	stream, err := m.ds.GetPodContainerLogStream(podName, containerName, ActivityLogTailLines)
+	if err != nil {
+		_, werr := fmt.Fprintf(w, "# cannot get log for pod %v container %v: %v\n", podName, containerName, err)
+		return werr
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, bufio.MaxScanTokenSize), ActivityLogMaxLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, term := range matchTerms {
+			if strings.Contains(line, term) {
+				if _, err := fmt.Fprintf(w, "%s/%s: %s\n", podName, containerName, line); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_, werr := fmt.Fprintf(w, "# log of pod %v container %v truncated: %v\n", podName, containerName, err)
+		return werr
+	}
+	return nil
+}
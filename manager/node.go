@@ -3,12 +3,17 @@ package manager
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 
+	"github.com/longhorn/longhorn-manager/engineapi"
 	"github.com/longhorn/longhorn-manager/types"
 	"github.com/longhorn/longhorn-manager/util"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	imapi "github.com/longhorn/longhorn-instance-manager/pkg/api"
+
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 )
 
@@ -20,6 +25,27 @@ func (m *VolumeManager) ListInstanceManagers() (map[string]*longhorn.InstanceMan
 	return m.ds.ListInstanceManagers()
 }
 
+// GetInstanceManagerProcessLog opens a log stream of one engine or replica
+// process running in the given instance manager, proxying the instance
+// manager log API. The caller owns the returned stream and must close it.
+func (m *VolumeManager) GetInstanceManagerProcessLog(imName, processName string) (*imapi.LogStream, error) {
+	im, err := m.ds.GetInstanceManager(imName)
+	if err != nil {
+		return nil, err
+	}
+	if im.Status.CurrentState != longhorn.InstanceManagerStateRunning {
+		return nil, fmt.Errorf("instance manager %v is not running", imName)
+	}
+	c, err := engineapi.NewInstanceManagerClient(im)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.ProcessGet(processName); err != nil {
+		return nil, errors.Wrapf(err, "cannot find process %v in instance manager %v", processName, imName)
+	}
+	return c.ProcessLog(processName)
+}
+
 func (m *VolumeManager) GetNode(name string) (*longhorn.Node, error) {
 	return m.ds.GetNode(name)
 }
@@ -45,6 +71,28 @@ func (m *VolumeManager) GetDiskTags() ([]string, error) {
 	return tags, nil
 }
 
+func (m *VolumeManager) GetDiskGroups() ([]string, error) {
+	foundGroups := make(map[string]struct{})
+	var groups []string
+
+	nodeList, err := m.ListNodesSorted()
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to list nodes")
+	}
+	for _, node := range nodeList {
+		for _, disk := range node.Spec.Disks {
+			if disk.Group == "" {
+				continue
+			}
+			if _, ok := foundGroups[disk.Group]; !ok {
+				foundGroups[disk.Group] = struct{}{}
+				groups = append(groups, disk.Group)
+			}
+		}
+	}
+	return groups, nil
+}
+
 func (m *VolumeManager) GetNodeTags() ([]string, error) {
 	foundTags := make(map[string]struct{})
 	var tags []string
@@ -207,3 +255,116 @@ func (m *VolumeManager) DeleteNode(name string) error {
 	logrus.Debugf("Deleted node %v", name)
 	return nil
 }
+
+// evacuationRebuildBytesPerSecond is the assumed replica rebuild throughput
+// used to estimate how long an evacuation will take. The real rebuild speed
+// depends on the disks and the network, so the estimate is indicative only.
+const evacuationRebuildBytesPerSecond = 50 * 1024 * 1024
+
+type EvacuationReplica struct {
+	Name                     string `json:"name"`
+	VolumeName               string `json:"volumeName"`
+	Size                     int64  `json:"size"`
+	EstimatedDurationSeconds int64  `json:"estimatedDurationSeconds"`
+}
+
+type EvacuationPlan struct {
+	NodeName                 string              `json:"nodeName"`
+	Replicas                 []EvacuationReplica `json:"replicas"`
+	VolumesToDetach          []string            `json:"volumesToDetach"`
+	Blockers                 []string            `json:"blockers"`
+	EstimatedDurationSeconds int64               `json:"estimatedDurationSeconds"`
+	Executing                bool                `json:"executing"`
+}
+
+// GetNodeEvacuationPlan computes the steps required to evacuate the given
+// node: the replicas that have to be rebuilt elsewhere with a duration
+// estimate, the volumes that must be detached from the node, and anything
+// blocking a safe evacuation. With execute set the plan is started by
+// requesting the eviction of the node, and subsequent calls reflect the
+// remaining work as the eviction progresses.
+func (m *VolumeManager) GetNodeEvacuationPlan(name string, execute bool) (*EvacuationPlan, error) {
+	node, err := m.GetNode(name)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &EvacuationPlan{
+		NodeName:  name,
+		Executing: node.Spec.EvictionRequested,
+	}
+
+	readyNodes, err := m.ds.ListReadyNodes()
+	if err != nil {
+		return nil, err
+	}
+	delete(readyNodes, name)
+	if len(readyNodes) == 0 {
+		plan.Blockers = append(plan.Blockers, "no other ready node is available to rebuild the replicas on")
+	}
+
+	replicaMap, err := m.ds.ListReplicasByNode(name)
+	if err != nil {
+		return nil, err
+	}
+	replicaNames, err := sortKeys(replicaMap)
+	if err != nil {
+		return nil, err
+	}
+	volumesWithoutRedundancy := map[string]bool{}
+	for _, replicaName := range replicaNames {
+		r := replicaMap[replicaName]
+		estimate := r.Spec.VolumeSize / evacuationRebuildBytesPerSecond
+		plan.Replicas = append(plan.Replicas, EvacuationReplica{
+			Name:                     r.Name,
+			VolumeName:               r.Spec.VolumeName,
+			Size:                     r.Spec.VolumeSize,
+			EstimatedDurationSeconds: estimate,
+		})
+		plan.EstimatedDurationSeconds += estimate
+
+		if volumesWithoutRedundancy[r.Spec.VolumeName] {
+			continue
+		}
+		volumeReplicas, err := m.ds.ListVolumeReplicas(r.Spec.VolumeName)
+		if err != nil {
+			return nil, err
+		}
+		healthyElsewhere := 0
+		for _, vr := range volumeReplicas {
+			if vr.Spec.NodeID != name && vr.Spec.HealthyAt != "" && vr.Spec.FailedAt == "" {
+				healthyElsewhere++
+			}
+		}
+		if healthyElsewhere == 0 {
+			volumesWithoutRedundancy[r.Spec.VolumeName] = true
+			plan.Blockers = append(plan.Blockers,
+				fmt.Sprintf("volume %v has no healthy replica on any other node; losing this node before the rebuild finishes loses the volume data", r.Spec.VolumeName))
+		}
+	}
+
+	volumes, err := m.ds.ListVolumesRO()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range volumes {
+		if v.Spec.NodeID == name && v.Status.State == longhorn.VolumeStateAttached {
+			plan.VolumesToDetach = append(plan.VolumesToDetach, v.Name)
+		}
+	}
+	sort.Strings(plan.VolumesToDetach)
+
+	if execute && !node.Spec.EvictionRequested {
+		if len(plan.Blockers) > 0 {
+			return nil, fmt.Errorf("cannot start the evacuation of node %v: %v", name, strings.Join(plan.Blockers, "; "))
+		}
+		node.Spec.EvictionRequested = true
+		if _, err := m.ds.UpdateNode(node); err != nil {
+			return nil, errors.Wrapf(err, "unable to request the eviction of node %v", name)
+		}
+		logrus.Infof("Requested the eviction of node %v to execute the evacuation plan", name)
+		plan.Executing = true
+	}
+
+	return plan, nil
+}
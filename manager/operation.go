@@ -0,0 +1,239 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/longhorn/longhorn-manager/util"
+)
+
+type OperationState string
+
+const (
+	OperationStateRunning   = OperationState("running")
+	OperationStateCompleted = OperationState("completed")
+	OperationStateError     = OperationState("error")
+)
+
+const (
+	OperationTypeSnapshotPurge = "snapshotPurge"
+	OperationTypeBackup        = "backup"
+	OperationTypeExpand        = "expand"
+
+	operationPollInterval = 2 * time.Second
+	operationTimeout      = 24 * time.Hour
+
+	// operationRetentionLimit caps how many finished operations are kept
+	// around for inspection before the oldest ones get pruned.
+	operationRetentionLimit = 1000
+)
+
+// Operation is the handle of one long-running action triggered through the
+// API. Operations live in the memory of the manager that accepted the action
+// and disappear when that manager restarts; they are an observation aid, not
+// the source of truth for the action itself.
+type Operation struct {
+	ID       string
+	Type     string
+	Volume   string
+	Snapshot string
+	Created  string
+	Finished string
+	State    OperationState
+	Progress int
+	Error    string
+}
+
+// registerOperation records a long-running action that was just started and
+// polls its progress in the background until it finishes, fails, or times
+// out. The returned copy reflects the state at registration time.
+func (m *VolumeManager) registerOperation(opType, volume, snapshot string, poll func() (progress int, done bool, err error)) *Operation {
+	op := &Operation{
+		ID:       util.UUID(),
+		Type:     opType,
+		Volume:   volume,
+		Snapshot: snapshot,
+		Created:  util.Now(),
+		State:    OperationStateRunning,
+	}
+
+	m.opLock.Lock()
+	m.pruneOperations()
+	m.operations[op.ID] = op
+	m.opLock.Unlock()
+
+	go m.pollOperation(op, poll)
+
+	result := *op
+	return &result
+}
+
+func (m *VolumeManager) pollOperation(op *Operation, poll func() (progress int, done bool, err error)) {
+	deadline := time.Now().Add(operationTimeout)
+	for {
+		time.Sleep(operationPollInterval)
+		progress, done, err := poll()
+
+		m.opLock.Lock()
+		switch {
+		case err != nil:
+			op.State = OperationStateError
+			op.Error = err.Error()
+			op.Finished = util.Now()
+		case done:
+			op.State = OperationStateCompleted
+			op.Progress = 100
+			op.Finished = util.Now()
+		default:
+			// Progress only moves forward so a poll hitting a stale cache
+			// cannot make the operation appear to regress.
+			if progress > op.Progress {
+				op.Progress = progress
+			}
+		}
+		finished := op.State != OperationStateRunning
+		m.opLock.Unlock()
+
+		if finished {
+			return
+		}
+		if time.Now().After(deadline) {
+			m.opLock.Lock()
+			op.State = OperationStateError
+			op.Error = fmt.Sprintf("operation did not finish within %v", operationTimeout)
+			op.Finished = util.Now()
+			m.opLock.Unlock()
+			logrus.Warnf("Gave up tracking %v operation %v of volume %v after %v", op.Type, op.ID, op.Volume, operationTimeout)
+			return
+		}
+	}
+}
+
+// pruneOperations drops the oldest finished operations once the retention
+// limit is exceeded. The caller must hold opLock.
+func (m *VolumeManager) pruneOperations() {
+	if len(m.operations) < operationRetentionLimit {
+		return
+	}
+	finished := []*Operation{}
+	for _, op := range m.operations {
+		if op.State != OperationStateRunning {
+			finished = append(finished, op)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].Finished < finished[j].Finished })
+	for _, op := range finished {
+		if len(m.operations) < operationRetentionLimit {
+			return
+		}
+		delete(m.operations, op.ID)
+	}
+}
+
+func (m *VolumeManager) GetOperation(id string) (*Operation, error) {
+	m.opLock.RLock()
+	defer m.opLock.RUnlock()
+	op, exists := m.operations[id]
+	if !exists {
+		return nil, fmt.Errorf("cannot find operation %v", id)
+	}
+	result := *op
+	return &result, nil
+}
+
+func (m *VolumeManager) ListOperations() []*Operation {
+	m.opLock.RLock()
+	defer m.opLock.RUnlock()
+	result := []*Operation{}
+	for _, op := range m.operations {
+		copied := *op
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Created != result[j].Created {
+			return result[i].Created < result[j].Created
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// trackSnapshotPurge follows the purge statuses reported by the volume
+// engines until no replica is purging anymore.
+func (m *VolumeManager) trackSnapshotPurge(volumeName string) *Operation {
+	return m.registerOperation(OperationTypeSnapshotPurge, volumeName, "", func() (int, bool, error) {
+		engines, err := m.ds.ListVolumeEngines(volumeName)
+		if err != nil {
+			return 0, false, nil
+		}
+		progress := 0
+		count := 0
+		purging := false
+		for _, e := range engines {
+			for replica, status := range e.Status.PurgeStatus {
+				if status.Error != "" {
+					return 0, false, fmt.Errorf("purge of replica %v failed: %v", replica, status.Error)
+				}
+				count++
+				progress += status.Progress
+				if status.IsPurging {
+					purging = true
+				}
+			}
+		}
+		if !purging {
+			return 100, true, nil
+		}
+		if count > 0 {
+			progress /= count
+		}
+		return progress, false, nil
+	})
+}
+
+// trackBackup follows the backup CR of a backup that was just requested.
+func (m *VolumeManager) trackBackup(backupName, volumeName, snapshotName string) *Operation {
+	return m.registerOperation(OperationTypeBackup, volumeName, snapshotName, func() (int, bool, error) {
+		backup, err := m.ds.GetBackupRO(backupName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return 0, false, fmt.Errorf("backup %v was removed before it completed", backupName)
+			}
+			return 0, false, nil
+		}
+		switch backup.Status.State {
+		case longhorn.BackupStateCompleted:
+			return 100, true, nil
+		case longhorn.BackupStateError, longhorn.BackupStateUnknown:
+			return 0, false, fmt.Errorf("backup %v failed: %v", backupName, backup.Status.Error)
+		}
+		return backup.Status.Progress, false, nil
+	})
+}
+
+// trackExpansion follows a requested volume expansion until the controller
+// reports the new size as no longer pending.
+func (m *VolumeManager) trackExpansion(volumeName string, size int64) *Operation {
+	return m.registerOperation(OperationTypeExpand, volumeName, "", func() (int, bool, error) {
+		v, err := m.ds.GetVolume(volumeName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return 0, false, fmt.Errorf("volume %v was removed before the expansion completed", volumeName)
+			}
+			return 0, false, nil
+		}
+		if v.Spec.Size != size {
+			return 0, false, fmt.Errorf("expansion to size %v was canceled or superseded", size)
+		}
+		if v.Status.ExpansionRequired {
+			return 50, false, nil
+		}
+		return 100, true, nil
+	})
+}
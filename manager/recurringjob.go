@@ -4,14 +4,19 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/robfig/cron"
 	"github.com/sirupsen/logrus"
 
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/longhorn/longhorn-manager/datastore"
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/longhorn/longhorn-manager/types"
 	"github.com/longhorn/longhorn-manager/util"
 )
 
@@ -113,6 +118,120 @@ func (m *VolumeManager) UpdateRecurringJob(spec longhorn.RecurringJobSpec) (*lon
 	return m.ds.UpdateRecurringJob(recurringJob)
 }
 
+// RecurringJobScheduleInfo holds the computed scheduling state of a recurring
+// job, so users can verify a cron expression without waiting for the next run.
+type RecurringJobScheduleInfo struct {
+	// NextRunAt is the next execution time computed from the cron expression.
+	NextRunAt string
+	// LastScheduledAt is the last time the cron job scheduled a run.
+	LastScheduledAt string
+	// LastRunAt, LastRunState and LastRunError describe the most recent run.
+	LastRunAt    string
+	LastRunState string
+	LastRunError string
+	// SkipReason explains why upcoming runs would be skipped or do nothing.
+	SkipReason string
+}
+
+const (
+	RecurringJobRunStateCompleted = "Completed"
+	RecurringJobRunStateFailed    = "Failed"
+	RecurringJobRunStateRunning   = "Running"
+)
+
+// GetRecurringJobScheduleInfo computes the scheduling state of the given
+// recurring job from its cron expression, the backing cron job, and the most
+// recent kubernetes job it spawned.
+func (m *VolumeManager) GetRecurringJobScheduleInfo(job *longhorn.RecurringJob) (*RecurringJobScheduleInfo, error) {
+	info := &RecurringJobScheduleInfo{}
+
+	schedule, err := cron.ParseStandard(job.Spec.Cron)
+	if err != nil {
+		info.SkipReason = fmt.Sprintf("invalid cron expression %v: %v", job.Spec.Cron, err)
+		return info, nil
+	}
+	info.NextRunAt = schedule.Next(time.Now().UTC()).Format(time.RFC3339)
+
+	cronJob, err := m.ds.GetCronJobROByRecurringJob(job)
+	if err != nil {
+		return nil, err
+	}
+	if cronJob != nil {
+		if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+			info.SkipReason = "the cron job is suspended"
+		}
+		if cronJob.Status.LastScheduleTime != nil {
+			info.LastScheduledAt = cronJob.Status.LastScheduleTime.UTC().Format(time.RFC3339)
+		}
+	}
+
+	kubeJobs, err := m.ds.ListJobsWithLabels(types.GetCronJobLabels(&longhorn.RecurringJobSpec{
+		Name: job.Name,
+		Task: job.Spec.Task,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	var lastRun *batchv1.Job
+	for _, kubeJob := range kubeJobs {
+		if lastRun == nil || kubeJob.CreationTimestamp.After(lastRun.CreationTimestamp.Time) {
+			lastRun = kubeJob
+		}
+	}
+	if lastRun != nil {
+		info.LastRunAt = lastRun.CreationTimestamp.UTC().Format(time.RFC3339)
+		switch {
+		case lastRun.Status.Succeeded > 0:
+			info.LastRunState = RecurringJobRunStateCompleted
+		case lastRun.Status.Failed > 0:
+			info.LastRunState = RecurringJobRunStateFailed
+			for _, condition := range lastRun.Status.Conditions {
+				if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+					info.LastRunError = condition.Message
+				}
+			}
+		case lastRun.Status.Active > 0:
+			info.LastRunState = RecurringJobRunStateRunning
+		}
+	}
+
+	if info.SkipReason == "" {
+		hasVolumes, err := m.hasVolumesForRecurringJob(job)
+		if err != nil {
+			return nil, err
+		}
+		if !hasVolumes {
+			info.SkipReason = "no volume is labeled for this job or its groups, so the runs would do nothing"
+		}
+	}
+
+	return info, nil
+}
+
+// hasVolumesForRecurringJob checks whether any volume carries the label of
+// the job itself or of one of its groups.
+func (m *VolumeManager) hasVolumesForRecurringJob(job *longhorn.RecurringJob) (bool, error) {
+	labelKeys := []string{
+		types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJob, job.Name),
+	}
+	for _, group := range job.Spec.Groups {
+		labelKeys = append(labelKeys, types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJobGroup, group))
+	}
+
+	volumes, err := m.ds.ListVolumesRO()
+	if err != nil {
+		return false, err
+	}
+	for _, v := range volumes {
+		for _, labelKey := range labelKeys {
+			if v.Labels[labelKey] == types.LonghornLabelValueEnabled {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func (m *VolumeManager) DeleteRecurringJob(name string) error {
 	if err := m.ds.DeleteRecurringJob(name); err != nil {
 		return err
@@ -1,11 +1,21 @@
 package manager
 
 import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
 	"github.com/sirupsen/logrus"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
+	"github.com/longhorn/longhorn-manager/controller"
 	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
 
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 )
@@ -54,3 +64,302 @@ func (m *VolumeManager) CreateOrUpdateSetting(s *longhorn.Setting) (*longhorn.Se
 	logrus.Debugf("Updated setting %v to %v", s.Name, setting.Value)
 	return setting, nil
 }
+
+// SettingChangePreview describes what would happen if a setting were changed
+// to a proposed value: which system managed workloads would get a rolling
+// redeployment, which pods would be deleted and recreated, and which volumes
+// are served by those pods. Nothing is committed while computing it.
+type SettingChangePreview struct {
+	Changed               bool
+	RedeployedDeployments []string
+	RedeployedDaemonSets  []string
+	RestartedPods         []string
+	AffectedVolumes       []string
+}
+
+// PreviewSettingChange dry-runs the change of the given setting to value.
+// The impact lists mirror what the setting controller would do when the new
+// value is committed; settings that do not redeploy any component yield an
+// empty preview.
+func (m *VolumeManager) PreviewSettingChange(sName types.SettingName, value string) (*SettingChangePreview, error) {
+	if err := m.ds.ValidateSetting(string(sName), value); err != nil {
+		return nil, err
+	}
+	s, err := m.GetSetting(sName)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &SettingChangePreview{}
+	if s.Value == value {
+		return preview, nil
+	}
+	preview.Changed = true
+
+	switch sName {
+	case types.SettingNameTaintToleration:
+		err = m.previewTolerationChange(value, preview)
+	case types.SettingNameSystemManagedComponentsNodeSelector:
+		err = m.previewNodeSelectorChange(value, preview)
+	case types.SettingNamePriorityClass:
+		err = m.previewPriorityClassChange(value, preview)
+	case types.SettingNameGuaranteedEngineManagerCPU, types.SettingNameGuaranteedReplicaManagerCPU:
+		err = m.previewInstanceManagerCPUChange(sName, value, preview)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.fillAffectedVolumes(preview); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(preview.RedeployedDeployments)
+	sort.Strings(preview.RedeployedDaemonSets)
+	sort.Strings(preview.RestartedPods)
+	return preview, nil
+}
+
+func (m *VolumeManager) previewTolerationChange(value string, preview *SettingChangePreview) error {
+	newTolerationsList, err := types.UnmarshalTolerations(value)
+	if err != nil {
+		return err
+	}
+	newTolerationsMap := util.TolerationListToMap(newTolerationsList)
+
+	deploymentList, daemonsetList, podList, err := m.listSystemManagedComponents()
+	if err != nil {
+		return err
+	}
+
+	for _, dp := range deploymentList {
+		lastAppliedTolerationsList, err := controller.GetLastAppliedTolerationsList(dp)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(util.TolerationListToMap(lastAppliedTolerationsList), newTolerationsMap) {
+			continue
+		}
+		preview.RedeployedDeployments = append(preview.RedeployedDeployments, dp.Name)
+	}
+	for _, ds := range daemonsetList {
+		lastAppliedTolerationsList, err := controller.GetLastAppliedTolerationsList(ds)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(util.TolerationListToMap(lastAppliedTolerationsList), newTolerationsMap) {
+			continue
+		}
+		preview.RedeployedDaemonSets = append(preview.RedeployedDaemonSets, ds.Name)
+	}
+	for _, pod := range podList {
+		lastAppliedTolerationsList, err := controller.GetLastAppliedTolerationsList(pod)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(util.TolerationListToMap(lastAppliedTolerationsList), newTolerationsMap) {
+			continue
+		}
+		preview.RestartedPods = append(preview.RestartedPods, pod.Name)
+	}
+	return nil
+}
+
+func (m *VolumeManager) previewNodeSelectorChange(value string, preview *SettingChangePreview) error {
+	newNodeSelector, err := types.UnmarshalNodeSelector(value)
+	if err != nil {
+		return err
+	}
+
+	deploymentList, daemonsetList, podList, err := m.listSystemManagedComponents()
+	if err != nil {
+		return err
+	}
+
+	for _, dp := range deploymentList {
+		if nodeSelectorUnchanged(dp.Spec.Template.Spec.NodeSelector, newNodeSelector) {
+			continue
+		}
+		preview.RedeployedDeployments = append(preview.RedeployedDeployments, dp.Name)
+	}
+	for _, ds := range daemonsetList {
+		if nodeSelectorUnchanged(ds.Spec.Template.Spec.NodeSelector, newNodeSelector) {
+			continue
+		}
+		preview.RedeployedDaemonSets = append(preview.RedeployedDaemonSets, ds.Name)
+	}
+	for _, pod := range podList {
+		if nodeSelectorUnchanged(pod.Spec.NodeSelector, newNodeSelector) || pod.DeletionTimestamp != nil {
+			continue
+		}
+		preview.RestartedPods = append(preview.RestartedPods, pod.Name)
+	}
+	return nil
+}
+
+func (m *VolumeManager) previewPriorityClassChange(value string, preview *SettingChangePreview) error {
+	deploymentList, daemonsetList, podList, err := m.listSystemManagedComponents()
+	if err != nil {
+		return err
+	}
+
+	for _, dp := range deploymentList {
+		if dp.Spec.Template.Spec.PriorityClassName == value {
+			continue
+		}
+		preview.RedeployedDeployments = append(preview.RedeployedDeployments, dp.Name)
+	}
+	for _, ds := range daemonsetList {
+		if ds.Spec.Template.Spec.PriorityClassName == value {
+			continue
+		}
+		preview.RedeployedDaemonSets = append(preview.RedeployedDaemonSets, ds.Name)
+	}
+	for _, pod := range podList {
+		if pod.Spec.PriorityClassName == value {
+			continue
+		}
+		preview.RestartedPods = append(preview.RestartedPods, pod.Name)
+	}
+	return nil
+}
+
+func (m *VolumeManager) previewInstanceManagerCPUChange(sName types.SettingName, value string, preview *SettingChangePreview) error {
+	imPodList, err := m.ds.ListInstanceManagerPods()
+	if err != nil {
+		return err
+	}
+	imMap, err := m.ds.ListInstanceManagers()
+	if err != nil {
+		return err
+	}
+
+	imType := longhorn.InstanceManagerTypeEngine
+	if sName == types.SettingNameGuaranteedReplicaManagerCPU {
+		imType = longhorn.InstanceManagerTypeReplica
+	}
+
+	percentage, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+
+	for _, imPod := range imPodList {
+		im, exists := imMap[imPod.Name]
+		if !exists || im.Spec.Type != imType {
+			continue
+		}
+		lhNode, err := m.ds.GetNode(imPod.Spec.NodeName)
+		if err != nil {
+			return err
+		}
+		if types.GetCondition(lhNode.Status.Conditions, longhorn.NodeConditionTypeReady).Status != longhorn.ConditionStatusTrue {
+			continue
+		}
+		// The per-node CPU request overrides the setting, so pods on those
+		// nodes would be left alone.
+		if imType == longhorn.InstanceManagerTypeEngine && lhNode.Spec.EngineManagerCPURequest > 0 {
+			continue
+		}
+		if imType == longhorn.InstanceManagerTypeReplica && lhNode.Spec.ReplicaManagerCPURequest > 0 {
+			continue
+		}
+
+		kubeNode, err := m.ds.GetKubernetesNode(imPod.Spec.NodeName)
+		if err != nil {
+			return err
+		}
+		milliCPU := int(math.Round(float64(kubeNode.Status.Allocatable.Cpu().MilliValue()) * percentage / 100.0))
+		resourceReq, err := controller.ParseResourceRequirement(fmt.Sprintf("%dm", milliCPU))
+		if err != nil {
+			return err
+		}
+		podResourceReq := imPod.Spec.Containers[0].Resources
+		if controller.IsSameGuaranteedCPURequirement(resourceReq, &podResourceReq) {
+			continue
+		}
+		preview.RestartedPods = append(preview.RestartedPods, imPod.Name)
+	}
+	return nil
+}
+
+func (m *VolumeManager) listSystemManagedComponents() ([]*appsv1.Deployment, []*appsv1.DaemonSet, []*corev1.Pod, error) {
+	deploymentList, err := m.ds.ListDeploymentWithLabels(types.GetBaseLabelsForSystemManagedComponent())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	daemonsetList, err := m.ds.ListDaemonSetWithLabels(types.GetBaseLabelsForSystemManagedComponent())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	imPodList, err := m.ds.ListInstanceManagerPods()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	smPodList, err := m.ds.ListShareManagerPods()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	bimPodList, err := m.ds.ListBackingImageManagerPods()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	podList := append(imPodList, smPodList...)
+	podList = append(podList, bimPodList...)
+	return deploymentList, daemonsetList, podList, nil
+}
+
+// fillAffectedVolumes resolves the pods that would be restarted to the
+// volumes they serve: the instances running in an instance manager pod and
+// the share volume exported by a share manager pod.
+func (m *VolumeManager) fillAffectedVolumes(preview *SettingChangePreview) error {
+	if len(preview.RestartedPods) == 0 {
+		return nil
+	}
+	restarted := map[string]struct{}{}
+	for _, podName := range preview.RestartedPods {
+		restarted[podName] = struct{}{}
+	}
+	volumes := map[string]struct{}{}
+
+	engines, err := m.ds.ListEngines()
+	if err != nil {
+		return err
+	}
+	for _, e := range engines {
+		if _, ok := restarted[e.Status.InstanceManagerName]; ok {
+			volumes[e.Spec.VolumeName] = struct{}{}
+		}
+	}
+	replicas, err := m.ds.ListReplicas()
+	if err != nil {
+		return err
+	}
+	for _, r := range replicas {
+		if _, ok := restarted[r.Status.InstanceManagerName]; ok {
+			volumes[r.Spec.VolumeName] = struct{}{}
+		}
+	}
+	smPodList, err := m.ds.ListShareManagerPods()
+	if err != nil {
+		return err
+	}
+	for _, pod := range smPodList {
+		if _, ok := restarted[pod.Name]; ok {
+			volumes[types.GetShareManagerNameFromShareManagerPodName(pod.Name)] = struct{}{}
+		}
+	}
+
+	for volume := range volumes {
+		preview.AffectedVolumes = append(preview.AffectedVolumes, volume)
+	}
+	sort.Strings(preview.AffectedVolumes)
+	return nil
+}
+
+func nodeSelectorUnchanged(existing, proposed map[string]string) bool {
+	if existing == nil && len(proposed) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(existing, proposed)
+}
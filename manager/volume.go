@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -32,6 +33,12 @@ type VolumeManager struct {
 
 	currentNodeID string
 	sb            *SupportBundle
+
+	statsLock   sync.RWMutex
+	volumeStats map[string]*volumeStatsRing
+
+	opLock     sync.RWMutex
+	operations map[string]*Operation
 }
 
 func NewVolumeManager(currentNodeID string, ds *datastore.DataStore) *VolumeManager {
@@ -40,6 +47,9 @@ func NewVolumeManager(currentNodeID string, ds *datastore.DataStore) *VolumeMana
 		scheduler: scheduler.NewReplicaScheduler(ds),
 
 		currentNodeID: currentNodeID,
+
+		volumeStats: map[string]*volumeStatsRing{},
+		operations:  map[string]*Operation{},
 	}
 }
 
@@ -56,7 +66,11 @@ func (m *VolumeManager) Node2APIAddress(nodeID string) (string, error) {
 	if !exists {
 		return "", fmt.Errorf("cannot find longhorn manager on node %v", nodeID)
 	}
-	return types.GetAPIServerAddressFromIP(ip), nil
+	apiPort, err := m.ds.GetSettingAsInt(types.SettingNameAPIPort)
+	if err != nil {
+		return "", err
+	}
+	return types.GetAPIServerAddress(ip, int(apiPort)), nil
 }
 
 func (m *VolumeManager) List() (map[string]*longhorn.Volume, error) {
@@ -155,6 +169,26 @@ func (m *VolumeManager) getDefaultReplicaCount() (int, error) {
 	return int(c), nil
 }
 
+// CheckVolumeSchedulable runs the scheduler pre-check against a volume spec
+// that has not been created yet. It resolves the same defaults Create would
+// apply, so that strict provisioning judges the volume as it will actually be
+// scheduled.
+func (m *VolumeManager) CheckVolumeSchedulable(name string, spec *longhorn.VolumeSpec) error {
+	v := &longhorn.Volume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       *spec,
+	}
+	v.Spec.Size = util.RoundUpSize(v.Spec.Size)
+	if v.Spec.NumberOfReplicas == 0 {
+		count, err := m.getDefaultReplicaCount()
+		if err != nil {
+			return errors.Wrap(err, "cannot get valid number for setting default replica count")
+		}
+		v.Spec.NumberOfReplicas = count
+	}
+	return m.scheduler.CheckVolumeSchedulable(v)
+}
+
 func (m *VolumeManager) Create(name string, spec *longhorn.VolumeSpec, recurringJobSelector []longhorn.VolumeRecurringJob) (v *longhorn.Volume, err error) {
 	defer func() {
 		err = errors.Wrapf(err, "unable to create volume %v", name)
@@ -232,6 +266,20 @@ func (m *VolumeManager) Create(name string, spec *longhorn.VolumeSpec, recurring
 		return nil, errors.Wrapf(err, "cannot create volume with replica auto-balance %v", spec.ReplicaAutoBalance)
 	}
 
+	if string(spec.ReplicaSoftAntiAffinity) == "" {
+		spec.ReplicaSoftAntiAffinity = longhorn.ReplicaSoftAntiAffinityIgnored
+	}
+	if err := types.ValidateReplicaSoftAntiAffinity(spec.ReplicaSoftAntiAffinity); err != nil {
+		return nil, errors.Wrapf(err, "cannot create volume with replica soft anti-affinity %v", spec.ReplicaSoftAntiAffinity)
+	}
+
+	if string(spec.AutoSalvage) == "" {
+		spec.AutoSalvage = longhorn.AutoSalvageIgnored
+	}
+	if err := types.ValidateAutoSalvage(spec.AutoSalvage); err != nil {
+		return nil, errors.Wrapf(err, "cannot create volume with auto-salvage %v", spec.AutoSalvage)
+	}
+
 	if string(spec.DataLocality) == "" {
 		defaultDataLocality, err := m.GetSettingValueExisted(types.SettingNameDefaultDataLocality)
 		if err != nil {
@@ -243,12 +291,19 @@ func (m *VolumeManager) Create(name string, spec *longhorn.VolumeSpec, recurring
 		return nil, errors.Wrapf(err, "cannot create volume with data locality %v", spec.DataLocality)
 	}
 
-	if string(spec.AccessMode) == "" {
-		spec.AccessMode = longhorn.AccessModeReadWriteOnce
+	if string(spec.BackupCompressionMethod) == "" {
+		defaultCompressionMethod, err := m.GetSettingValueExisted(types.SettingNameBackupCompressionMethod)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get the default backup compression method for volume: %v", name)
+		}
+		spec.BackupCompressionMethod = longhorn.BackupCompressionMethod(defaultCompressionMethod)
+	}
+	if err := types.ValidateBackupCompressionMethod(spec.BackupCompressionMethod); err != nil {
+		return nil, errors.Wrapf(err, "cannot create volume with backup compression method %v", spec.BackupCompressionMethod)
 	}
 
-	if spec.Migratable && spec.AccessMode != longhorn.AccessModeReadWriteMany {
-		return nil, fmt.Errorf("migratable volumes are only supported in ReadWriteMany (rwx) access mode")
+	if string(spec.AccessMode) == "" {
+		spec.AccessMode = longhorn.AccessModeReadWriteOnce
 	}
 
 	defaultEngineImage, err := m.GetSettingValueExisted(types.SettingNameDefaultEngineImage)
@@ -256,6 +311,10 @@ func (m *VolumeManager) Create(name string, spec *longhorn.VolumeSpec, recurring
 		return nil, fmt.Errorf("BUG: Invalid empty Setting.EngineImage")
 	}
 
+	if err := m.checkDefaultEngineImageReadiness(defaultEngineImage); err != nil {
+		return nil, err
+	}
+
 	// Check engine version before disable revision counter
 	if spec.RevisionCounterDisabled {
 		if ok, err := m.canDisableRevisionCounter(defaultEngineImage); !ok {
@@ -304,14 +363,19 @@ func (m *VolumeManager) Create(name string, spec *longhorn.VolumeSpec, recurring
 			Frontend:                spec.Frontend,
 			EngineImage:             defaultEngineImage,
 			FromBackup:              spec.FromBackup,
+			BackupTargetName:        spec.BackupTargetName,
 			DataSource:              spec.DataSource,
 			NumberOfReplicas:        spec.NumberOfReplicas,
 			ReplicaAutoBalance:      spec.ReplicaAutoBalance,
+			ReplicaSoftAntiAffinity: spec.ReplicaSoftAntiAffinity,
+			AutoSalvage:             spec.AutoSalvage,
 			DataLocality:            spec.DataLocality,
+			BackupCompressionMethod: spec.BackupCompressionMethod,
 			StaleReplicaTimeout:     spec.StaleReplicaTimeout,
 			BackingImage:            spec.BackingImage,
 			Standby:                 spec.Standby,
 			DiskSelector:            spec.DiskSelector,
+			DiskGroup:               spec.DiskGroup,
 			NodeSelector:            spec.NodeSelector,
 			RevisionCounterDisabled: spec.RevisionCounterDisabled,
 		},
@@ -353,6 +417,59 @@ func (m *VolumeManager) verifyDataSourceForVolumeCreation(dataSource longhorn.Vo
 	return nil
 }
 
+// Clone creates a new volume seeded from a snapshot of an existing volume.
+// When no snapshot is given, the volume controller takes a fresh snapshot of
+// the source volume once it is attached. The cloning progress is reported in
+// the clone status of the new volume.
+func (m *VolumeManager) Clone(newVolumeName, sourceVolumeName, snapshotName string) (v *longhorn.Volume, err error) {
+	defer func() {
+		err = errors.Wrapf(err, "unable to clone volume %v", sourceVolumeName)
+	}()
+
+	sourceVolume, err := m.ds.GetVolume(sourceVolumeName)
+	if err != nil {
+		return nil, err
+	}
+	if sourceVolume.Status.IsStandby {
+		return nil, fmt.Errorf("cannot clone the standby volume %v", sourceVolumeName)
+	}
+
+	dataSourceType := longhorn.VolumeDataSourceTypeVolume
+	parameters := map[string]string{types.VolumeNameKey: sourceVolumeName}
+	if snapshotName != "" {
+		dataSourceType = longhorn.VolumeDataSourceTypeSnapshot
+		parameters[types.SnapshotNameKey] = snapshotName
+	}
+	dataSource, err := types.NewVolumeDataSource(dataSourceType, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &longhorn.VolumeSpec{
+		Size:                    sourceVolume.Spec.Size,
+		Frontend:                sourceVolume.Spec.Frontend,
+		AccessMode:              sourceVolume.Spec.AccessMode,
+		Migratable:              sourceVolume.Spec.Migratable,
+		Encrypted:               sourceVolume.Spec.Encrypted,
+		DataSource:              dataSource,
+		NumberOfReplicas:        sourceVolume.Spec.NumberOfReplicas,
+		ReplicaAutoBalance:      sourceVolume.Spec.ReplicaAutoBalance,
+		ReplicaSoftAntiAffinity: sourceVolume.Spec.ReplicaSoftAntiAffinity,
+		AutoSalvage:             sourceVolume.Spec.AutoSalvage,
+		SnapshotMaxCount:        sourceVolume.Spec.SnapshotMaxCount,
+		SnapshotMaxSize:         sourceVolume.Spec.SnapshotMaxSize,
+		DataLocality:            sourceVolume.Spec.DataLocality,
+		BackupCompressionMethod: sourceVolume.Spec.BackupCompressionMethod,
+		StaleReplicaTimeout:     sourceVolume.Spec.StaleReplicaTimeout,
+		BackingImage:            sourceVolume.Spec.BackingImage,
+		RevisionCounterDisabled: sourceVolume.Spec.RevisionCounterDisabled,
+		DiskSelector:            sourceVolume.Spec.DiskSelector,
+		DiskGroup:               sourceVolume.Spec.DiskGroup,
+		NodeSelector:            sourceVolume.Spec.NodeSelector,
+	}
+	return m.Create(newVolumeName, spec, nil)
+}
+
 func (m *VolumeManager) Delete(name string) error {
 	if err := m.ds.DeleteVolume(name); err != nil {
 		return err
@@ -406,7 +523,9 @@ func (m *VolumeManager) Attach(name, nodeID string, disableFrontend bool, attach
 		return v, nil
 	}
 
-	if v.Spec.AccessMode != longhorn.AccessModeReadWriteMany && v.Status.State != longhorn.VolumeStateDetached {
+	// a migratable RWO volume is allowed a second attachment so that it can be
+	// live migrated, the fencing of the extra frontend is handled by the engine controller
+	if v.Spec.AccessMode != longhorn.AccessModeReadWriteMany && !v.Spec.Migratable && v.Status.State != longhorn.VolumeStateDetached {
 		return nil, fmt.Errorf("invalid state %v to attach RWO volume %v", v.Status.State, name)
 	}
 
@@ -714,6 +833,13 @@ func (m *VolumeManager) Expand(volumeName string, size int64) (v *longhorn.Volum
 		return nil, err
 	}
 
+	// Since only the off-line expansion is supported for now, this call is
+	// effectively a no-op. Keep the hook here so the expansion is covered
+	// once the on-line expansion is supported.
+	if err := m.SnapshotBeforeRiskyOperation(volumeName, "expansion"); err != nil {
+		return nil, err
+	}
+
 	logrus.Infof("Volume %v expansion from %v to %v requested", v.Name, v.Spec.Size, size)
 	v.Spec.Size = size
 
@@ -724,6 +850,7 @@ func (m *VolumeManager) Expand(volumeName string, size int64) (v *longhorn.Volum
 	if err != nil {
 		return nil, err
 	}
+	m.trackExpansion(volumeName, size)
 
 	return v, nil
 }
@@ -826,6 +953,119 @@ func (m *VolumeManager) CancelExpansion(volumeName string) (v *longhorn.Volume,
 	return v, nil
 }
 
+// FreezeVolumeIO requests an IO freeze on the current engine of the volume so
+// external tools can quiesce it briefly, e.g. before taking crash-consistent
+// snapshots across multiple volumes. The engine controller automatically
+// unfreezes the IO after the timeout in seconds expires.
+func (m *VolumeManager) FreezeVolumeIO(volumeName string, timeout int64) (v *longhorn.Volume, err error) {
+	defer func() {
+		err = errors.Wrapf(err, "unable to freeze IO for volume %v", volumeName)
+	}()
+
+	if timeout <= 0 {
+		timeout = types.DefaultIOFreezeTimeout
+	}
+	if timeout > types.MaxIOFreezeTimeout {
+		return nil, fmt.Errorf("IO freeze timeout %v seconds exceeds the maximum %v seconds", timeout, types.MaxIOFreezeTimeout)
+	}
+
+	v, err = m.ds.GetVolume(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	if v.Status.State != longhorn.VolumeStateAttached {
+		return nil, fmt.Errorf("invalid volume state %v to freeze IO", v.Status.State)
+	}
+
+	engine, err := m.ds.GetVolumeCurrentEngine(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	if engine.Status.CurrentState != longhorn.InstanceStateRunning {
+		return nil, fmt.Errorf("invalid engine state %v to freeze IO", engine.Status.CurrentState)
+	}
+
+	engine.Spec.RequestedIOFreezeAt = util.Now()
+	engine.Spec.IOFreezeTimeout = timeout
+	if _, err := m.ds.UpdateEngine(engine); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Requested IO freeze for volume %v with timeout %v seconds", volumeName, timeout)
+	return v, nil
+}
+
+// UnfreezeVolumeIO withdraws an outstanding IO freeze request of the volume
+func (m *VolumeManager) UnfreezeVolumeIO(volumeName string) (v *longhorn.Volume, err error) {
+	defer func() {
+		err = errors.Wrapf(err, "unable to unfreeze IO for volume %v", volumeName)
+	}()
+
+	v, err = m.ds.GetVolume(volumeName)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := m.ds.GetVolumeCurrentEngine(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	if engine.Spec.RequestedIOFreezeAt == "" {
+		return v, nil
+	}
+
+	engine.Spec.RequestedIOFreezeAt = ""
+	engine.Spec.IOFreezeTimeout = 0
+	if _, err := m.ds.UpdateEngine(engine); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Requested IO unfreeze for volume %v", volumeName)
+	return v, nil
+}
+
+// TrimFilesystem trims the filesystem on the block device of the volume so the
+// engine can reclaim the space of the blocks freed by the filesystem
+func (m *VolumeManager) TrimFilesystem(volumeName string) (v *longhorn.Volume, err error) {
+	defer func() {
+		err = errors.Wrapf(err, "unable to trim filesystem for volume %v", volumeName)
+	}()
+
+	v, err = m.ds.GetVolume(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	if v.Status.State != longhorn.VolumeStateAttached {
+		return nil, fmt.Errorf("invalid volume state %v to trim filesystem", v.Status.State)
+	}
+	if v.Spec.Frontend != longhorn.VolumeFrontendBlockDev {
+		return nil, fmt.Errorf("invalid frontend %v to trim filesystem", v.Spec.Frontend)
+	}
+	if v.Spec.DisableFrontend || v.Status.FrontendDisabled {
+		return nil, fmt.Errorf("cannot trim filesystem while the frontend is disabled")
+	}
+
+	removeSnapshots, err := m.ds.GetSettingAsBool(types.SettingNameRemoveSnapshotsDuringFilesystemTrim)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := m.GetEngineClient(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	if err := engine.UnmapMarkSnapChainRemovedSet(removeSnapshots); err != nil {
+		return nil, err
+	}
+
+	if err := util.TrimFilesystem(volumeName); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Trimmed filesystem for volume %v", volumeName)
+	return v, nil
+}
+
 func (m *VolumeManager) AddVolumeRecurringJob(volumeName string, name string, isGroup bool) (volumeRecurringJob map[string]*longhorn.VolumeRecurringJob, err error) {
 	defer func() {
 		err = errors.Wrapf(err, "failed to add volume recurring jobs for %v", volumeName)
@@ -1026,6 +1266,14 @@ func (m *VolumeManager) EngineUpgrade(volumeName, image string) (v *longhorn.Vol
 		return nil, fmt.Errorf("cannot do live upgrade for a unhealthy volume %v", v.Name)
 	}
 
+	// Rolling back to the current image does not touch the volume data,
+	// hence a safety snapshot is only needed for a real live upgrade.
+	if v.Status.State == longhorn.VolumeStateAttached && image != v.Status.CurrentImage {
+		if err := m.SnapshotBeforeRiskyOperation(volumeName, "live-upgrade"); err != nil {
+			return nil, err
+		}
+	}
+
 	oldImage := v.Spec.EngineImage
 	v.Spec.EngineImage = image
 
@@ -1077,6 +1325,56 @@ func (m *VolumeManager) UpdateReplicaCount(name string, count int) (v *longhorn.
 	return v, nil
 }
 
+func (m *VolumeManager) UpdateSnapshotMaxCount(name string, count int) (v *longhorn.Volume, err error) {
+	defer func() {
+		err = errors.Wrapf(err, "unable to update snapshot max count for volume %v", name)
+	}()
+
+	if count < 0 {
+		return nil, fmt.Errorf("invalid snapshot max count %v", count)
+	}
+
+	v, err = m.ds.GetVolume(name)
+	if err != nil {
+		return nil, err
+	}
+
+	oldCount := v.Spec.SnapshotMaxCount
+	v.Spec.SnapshotMaxCount = count
+
+	v, err = m.ds.UpdateVolume(v)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Debugf("Updated volume %v snapshot max count from %v to %v", v.Name, oldCount, v.Spec.SnapshotMaxCount)
+	return v, nil
+}
+
+func (m *VolumeManager) UpdateSnapshotMaxSize(name string, size int64) (v *longhorn.Volume, err error) {
+	defer func() {
+		err = errors.Wrapf(err, "unable to update snapshot max size for volume %v", name)
+	}()
+
+	if size < 0 {
+		return nil, fmt.Errorf("invalid snapshot max size %v", size)
+	}
+
+	v, err = m.ds.GetVolume(name)
+	if err != nil {
+		return nil, err
+	}
+
+	oldSize := v.Spec.SnapshotMaxSize
+	v.Spec.SnapshotMaxSize = size
+
+	v, err = m.ds.UpdateVolume(v)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Debugf("Updated volume %v snapshot max size from %v to %v", v.Name, oldSize, v.Spec.SnapshotMaxSize)
+	return v, nil
+}
+
 func (m *VolumeManager) UpdateReplicaAutoBalance(name string, inputSpec longhorn.ReplicaAutoBalance) (v *longhorn.Volume, err error) {
 	defer func() {
 		err = errors.Wrapf(err, "unable to update replica auto-balance for volume %v", name)
@@ -1171,6 +1469,34 @@ func (m *VolumeManager) UpdateAccessMode(name string, accessMode longhorn.Access
 	return v, nil
 }
 
+func (m *VolumeManager) UpdateLastKeyRotatedAt(name, rotatedAt string) (v *longhorn.Volume, err error) {
+	defer func() {
+		err = errors.Wrapf(err, "unable to update the last key rotation time for volume %v", name)
+	}()
+
+	if _, err := util.ParseTime(rotatedAt); err != nil {
+		return nil, err
+	}
+
+	v, err = m.ds.GetVolume(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !v.Spec.Encrypted {
+		return nil, fmt.Errorf("volume %v is not encrypted", v.Name)
+	}
+
+	v.Spec.LastKeyRotatedAt = rotatedAt
+	v, err = m.ds.UpdateVolume(v)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Updated volume %v last key rotation time to %v", v.Name, rotatedAt)
+	return v, nil
+}
+
 func (m *VolumeManager) canDisableRevisionCounter(engineImage string) (bool, error) {
 	cliAPIVersion, err := m.ds.GetEngineImageCLIAPIVersion(engineImage)
 	if err != nil {
@@ -0,0 +1,220 @@
+package manager
+
+import (
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/engineapi"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+// VolumeDiagnostics aggregates the runtime details support usually has to
+// collect by hand from the engine and instance-manager pods: the version
+// details of the running engine, the snapshot disk chain, the rebuild
+// progress per replica and the state of the backing instance-manager
+// processes. Everything is read from the existing CR statuses, so fetching
+// the diagnostics never touches the data path.
+type VolumeDiagnostics struct {
+	VolumeName string               `json:"volumeName"`
+	Engines    []EngineDiagnostics  `json:"engines"`
+	Replicas   []ReplicaDiagnostics `json:"replicas"`
+}
+
+// EngineDiagnostics describes one engine of the volume, including the version
+// details of the image it is currently running and the snapshot chain it
+// reports. A detached volume still lists its engine, just with an empty
+// runtime part.
+type EngineDiagnostics struct {
+	Name           string                          `json:"name"`
+	NodeID         string                          `json:"nodeID"`
+	State          longhorn.InstanceState          `json:"state"`
+	CurrentImage   string                          `json:"currentImage"`
+	Endpoint       string                          `json:"endpoint"`
+	VersionDetails longhorn.EngineVersionDetails   `json:"versionDetails"`
+	SnapshotChain  []SnapshotDiskInfo              `json:"snapshotChain"`
+	SnapshotsError string                          `json:"snapshotsError"`
+	Process        InstanceProcessDiagnostics      `json:"process"`
+	ReplicaModeMap map[string]longhorn.ReplicaMode `json:"replicaModeMap"`
+}
+
+// ReplicaDiagnostics describes one replica of the volume, including where its
+// data lives on disk and the progress of an ongoing rebuild if there is one.
+type ReplicaDiagnostics struct {
+	Name              string                     `json:"name"`
+	NodeID            string                     `json:"nodeID"`
+	State             longhorn.InstanceState     `json:"state"`
+	CurrentImage      string                     `json:"currentImage"`
+	Mode              longhorn.ReplicaMode       `json:"mode"`
+	DiskID            string                     `json:"diskID"`
+	DiskPath          string                     `json:"diskPath"`
+	DataDirectoryName string                     `json:"dataDirectoryName"`
+	FailedAt          string                     `json:"failedAt"`
+	RebuildRetryCount int                        `json:"rebuildRetryCount"`
+	Rebuild           *ReplicaRebuildDiagnostics `json:"rebuild,omitempty"`
+	Process           InstanceProcessDiagnostics `json:"process"`
+}
+
+// SnapshotDiskInfo is one link of the snapshot disk chain, ordered from the
+// volume head towards the root. It mirrors the on-disk layout of a healthy
+// replica.
+type SnapshotDiskInfo struct {
+	Name        string `json:"name"`
+	Parent      string `json:"parent"`
+	Size        string `json:"size"`
+	Created     string `json:"created"`
+	Removed     bool   `json:"removed"`
+	UserCreated bool   `json:"userCreated"`
+}
+
+// ReplicaRebuildDiagnostics is the rebuild progress of a single replica as
+// reported by the engine.
+type ReplicaRebuildDiagnostics struct {
+	IsRebuilding bool   `json:"isRebuilding"`
+	Progress     int    `json:"progress"`
+	State        string `json:"state"`
+	Error        string `json:"error"`
+	FromReplica  string `json:"fromReplica"`
+}
+
+// InstanceProcessDiagnostics is the state of the instance-manager process
+// backing an engine or replica instance.
+type InstanceProcessDiagnostics struct {
+	InstanceManagerName  string                        `json:"instanceManagerName"`
+	InstanceManagerState longhorn.InstanceManagerState `json:"instanceManagerState"`
+	ProcessState         longhorn.InstanceState        `json:"processState"`
+	ProcessErrorMsg      string                        `json:"processErrorMsg"`
+}
+
+// GetVolumeDiagnostics collects the runtime details of the volume from the
+// engine, replica and instance-manager CR statuses.
+func (m *VolumeManager) GetVolumeDiagnostics(volumeName string) (*VolumeDiagnostics, error) {
+	if _, err := m.ds.GetVolume(volumeName); err != nil {
+		return nil, err
+	}
+	engines, err := m.GetEnginesSorted(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := m.GetReplicasSorted(volumeName)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := &VolumeDiagnostics{
+		VolumeName: volumeName,
+		Engines:    []EngineDiagnostics{},
+		Replicas:   []ReplicaDiagnostics{},
+	}
+
+	// The mode and rebuild maps of the current engine annotate the replicas
+	// below. A volume has multiple engines only during migration; the first
+	// running one is authoritative enough for diagnostics.
+	var currentEngine *longhorn.Engine
+	for _, e := range engines {
+		diagnostics.Engines = append(diagnostics.Engines, EngineDiagnostics{
+			Name:           e.Name,
+			NodeID:         e.Spec.NodeID,
+			State:          e.Status.CurrentState,
+			CurrentImage:   e.Status.CurrentImage,
+			Endpoint:       e.Status.Endpoint,
+			VersionDetails: e.Status.CurrentImageVersionDetails,
+			SnapshotChain:  getSnapshotChain(e.Status.Snapshots),
+			SnapshotsError: e.Status.SnapshotsError,
+			Process:        m.getInstanceProcessDiagnostics(e.Name, e.Status.InstanceManagerName),
+			ReplicaModeMap: e.Status.ReplicaModeMap,
+		})
+		if currentEngine == nil && e.Status.CurrentState == longhorn.InstanceStateRunning {
+			currentEngine = e
+		}
+	}
+
+	for _, r := range replicas {
+		replicaDiagnostics := ReplicaDiagnostics{
+			Name:              r.Name,
+			NodeID:            r.Spec.NodeID,
+			State:             r.Status.CurrentState,
+			CurrentImage:      r.Status.CurrentImage,
+			DiskID:            r.Spec.DiskID,
+			DiskPath:          r.Spec.DiskPath,
+			DataDirectoryName: r.Spec.DataDirectoryName,
+			FailedAt:          r.Spec.FailedAt,
+			RebuildRetryCount: r.Spec.RebuildRetryCount,
+			Process:           m.getInstanceProcessDiagnostics(r.Name, r.Status.InstanceManagerName),
+		}
+		if currentEngine != nil {
+			replicaDiagnostics.Mode = currentEngine.Status.ReplicaModeMap[r.Name]
+			replicaDiagnostics.Rebuild = getReplicaRebuildDiagnostics(currentEngine, r, replicas)
+		}
+		diagnostics.Replicas = append(diagnostics.Replicas, replicaDiagnostics)
+	}
+
+	return diagnostics, nil
+}
+
+// getSnapshotChain walks the snapshot map of the engine from the volume head
+// towards the root, so the result reads in the same order as the disk files
+// of a replica.
+func getSnapshotChain(snapshots map[string]*longhorn.SnapshotInfo) []SnapshotDiskInfo {
+	chain := []SnapshotDiskInfo{}
+	name := engineapi.VolumeHeadName
+	for name != "" {
+		snapshot, ok := snapshots[name]
+		if !ok {
+			break
+		}
+		chain = append(chain, SnapshotDiskInfo{
+			Name:        snapshot.Name,
+			Parent:      snapshot.Parent,
+			Size:        snapshot.Size,
+			Created:     snapshot.Created,
+			Removed:     snapshot.Removed,
+			UserCreated: snapshot.UserCreated,
+		})
+		if len(chain) > len(snapshots) {
+			// A corrupted parent link must not hang the request.
+			break
+		}
+		name = snapshot.Parent
+	}
+	return chain
+}
+
+func getReplicaRebuildDiagnostics(e *longhorn.Engine, r *longhorn.Replica, replicas []*longhorn.Replica) *ReplicaRebuildDiagnostics {
+	for address, rebuildStatus := range e.Status.RebuildStatus {
+		if rebuildStatus == nil {
+			continue
+		}
+		if datastore.ReplicaAddressToReplicaName(address, replicas) != r.Name {
+			continue
+		}
+		return &ReplicaRebuildDiagnostics{
+			IsRebuilding: rebuildStatus.IsRebuilding,
+			Progress:     rebuildStatus.Progress,
+			State:        rebuildStatus.State,
+			Error:        rebuildStatus.Error,
+			FromReplica:  datastore.ReplicaAddressToReplicaName(rebuildStatus.FromReplicaAddress, replicas),
+		}
+	}
+	return nil
+}
+
+// getInstanceProcessDiagnostics looks the instance up in its instance
+// manager. A missing instance manager is normal for a stopped instance and
+// results in empty fields rather than an error.
+func (m *VolumeManager) getInstanceProcessDiagnostics(instanceName, imName string) InstanceProcessDiagnostics {
+	process := InstanceProcessDiagnostics{
+		InstanceManagerName: imName,
+	}
+	if imName == "" {
+		return process
+	}
+	im, err := m.ds.GetInstanceManager(imName)
+	if err != nil {
+		return process
+	}
+	process.InstanceManagerState = im.Status.CurrentState
+	if instanceProcess, exists := im.Status.Instances[instanceName]; exists {
+		process.ProcessState = instanceProcess.Status.State
+		process.ProcessErrorMsg = instanceProcess.Status.ErrorMsg
+	}
+	return process
+}
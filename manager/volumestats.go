@@ -0,0 +1,277 @@
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"golang.org/x/sys/unix"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+const (
+	// VolumeStatsSamplingInterval is how often the I/O counters of the
+	// locally attached volumes are sampled.
+	VolumeStatsSamplingInterval = 30 * time.Second
+
+	// VolumeStatsHistoryLength bounds the number of retained samples per
+	// volume, which covers one hour at the sampling interval.
+	VolumeStatsHistoryLength = 120
+
+	diskStatsSectorSize = 512
+
+	diskStatsFilePath = "/proc/diskstats"
+)
+
+// VolumeStatsSample holds the I/O rates of one volume averaged over a single
+// sampling interval. Throughput is in bytes per second, latency is the
+// average time per request in milliseconds.
+type VolumeStatsSample struct {
+	Timestamp       string  `json:"timestamp"`
+	ReadIOPS        float64 `json:"readIOPS"`
+	WriteIOPS       float64 `json:"writeIOPS"`
+	ReadThroughput  float64 `json:"readThroughput"`
+	WriteThroughput float64 `json:"writeThroughput"`
+	ReadLatency     float64 `json:"readLatency"`
+	WriteLatency    float64 `json:"writeLatency"`
+}
+
+// diskStatsCounters are the cumulative block device counters of one volume at
+// one point in time, taken from /proc/diskstats.
+type diskStatsCounters struct {
+	readIOs      uint64
+	readSectors  uint64
+	readTicks    uint64
+	writeIOs     uint64
+	writeSectors uint64
+	writeTicks   uint64
+
+	sampledAt time.Time
+}
+
+// volumeStatsRing is a fixed-size ring of the most recent samples of one
+// volume. When the ring is full the next sample overwrites the oldest one.
+type volumeStatsRing struct {
+	samples [VolumeStatsHistoryLength]VolumeStatsSample
+	next    int
+	full    bool
+
+	lastRaw *diskStatsCounters
+}
+
+func (r *volumeStatsRing) append(sample VolumeStatsSample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % VolumeStatsHistoryLength
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// list returns the retained samples in chronological order.
+func (r *volumeStatsRing) list() []VolumeStatsSample {
+	samples := []VolumeStatsSample{}
+	if r.full {
+		samples = append(samples, r.samples[r.next:]...)
+	}
+	samples = append(samples, r.samples[:r.next]...)
+	return samples
+}
+
+// RunVolumeStatsSampling periodically samples the I/O counters of the volumes
+// attached to the current node until the stop channel is closed. It is meant
+// to be run in a dedicated goroutine next to the API server.
+func (m *VolumeManager) RunVolumeStatsSampling(stopCh <-chan struct{}) {
+	wait.Until(m.sampleVolumeStats, VolumeStatsSamplingInterval, stopCh)
+}
+
+func (m *VolumeManager) sampleVolumeStats() {
+	volumes, err := m.ds.ListVolumesRO()
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to list volumes for stats sampling")
+		return
+	}
+
+	attached := map[string]*longhorn.Volume{}
+	for _, v := range volumes {
+		if v.Status.State == longhorn.VolumeStateAttached && v.Status.CurrentNodeID == m.currentNodeID {
+			attached[v.Name] = v
+		}
+	}
+
+	m.statsLock.Lock()
+	defer m.statsLock.Unlock()
+
+	// The history of a volume that got detached or moved to another node is
+	// dropped rather than kept stale.
+	for name := range m.volumeStats {
+		if _, exists := attached[name]; !exists {
+			delete(m.volumeStats, name)
+		}
+	}
+
+	for name := range attached {
+		counters, err := readVolumeDiskStats(name)
+		if err != nil {
+			logrus.WithError(err).Debugf("Failed to read disk stats for volume %v", name)
+			continue
+		}
+		ring := m.volumeStats[name]
+		if ring == nil {
+			ring = &volumeStatsRing{}
+			m.volumeStats[name] = ring
+		}
+		if sample, ok := computeVolumeStatsSample(ring.lastRaw, counters); ok {
+			ring.append(sample)
+		}
+		ring.lastRaw = counters
+	}
+}
+
+// computeVolumeStatsSample converts two consecutive counter readings into the
+// average rates over the interval between them. It reports ok false for the
+// first reading of a volume and when a counter went backwards, which happens
+// when the block device got recreated during a reattach.
+func computeVolumeStatsSample(last, current *diskStatsCounters) (sample VolumeStatsSample, ok bool) {
+	if last == nil {
+		return sample, false
+	}
+	interval := current.sampledAt.Sub(last.sampledAt).Seconds()
+	if interval <= 0 {
+		return sample, false
+	}
+	if current.readIOs < last.readIOs || current.writeIOs < last.writeIOs ||
+		current.readSectors < last.readSectors || current.writeSectors < last.writeSectors {
+		return sample, false
+	}
+
+	readIOs := current.readIOs - last.readIOs
+	writeIOs := current.writeIOs - last.writeIOs
+
+	sample.Timestamp = current.sampledAt.UTC().Format(time.RFC3339)
+	sample.ReadIOPS = float64(readIOs) / interval
+	sample.WriteIOPS = float64(writeIOs) / interval
+	sample.ReadThroughput = float64((current.readSectors-last.readSectors)*diskStatsSectorSize) / interval
+	sample.WriteThroughput = float64((current.writeSectors-last.writeSectors)*diskStatsSectorSize) / interval
+	if readIOs > 0 && current.readTicks >= last.readTicks {
+		sample.ReadLatency = float64(current.readTicks-last.readTicks) / float64(readIOs)
+	}
+	if writeIOs > 0 && current.writeTicks >= last.writeTicks {
+		sample.WriteLatency = float64(current.writeTicks-last.writeTicks) / float64(writeIOs)
+	}
+	return sample, true
+}
+
+// readVolumeDiskStats looks up the /proc/diskstats entry of the volume's
+// block device under the Longhorn device directory.
+func readVolumeDiskStats(volumeName string) (*diskStatsCounters, error) {
+	devicePath := filepath.Join(util.DeviceDirectory, volumeName)
+	fileInfo, err := os.Stat(devicePath)
+	if err != nil {
+		return nil, err
+	}
+	stat, ok := fileInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, fmt.Errorf("cannot get the device number of %v", devicePath)
+	}
+	major := unix.Major(uint64(stat.Rdev))
+	minor := unix.Minor(uint64(stat.Rdev))
+
+	f, err := os.Open(diskStatsFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		counters, matched, err := parseDiskStatsLine(scanner.Text(), major, minor)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			return counters, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("cannot find device %v:%v of %v in %v", major, minor, devicePath, diskStatsFilePath)
+}
+
+func parseDiskStatsLine(line string, major, minor uint32) (*diskStatsCounters, bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 11 {
+		return nil, false, nil
+	}
+	if fields[0] != strconv.FormatUint(uint64(major), 10) || fields[1] != strconv.FormatUint(uint64(minor), 10) {
+		return nil, false, nil
+	}
+
+	// The field layout is documented in the kernel's
+	// Documentation/admin-guide/iostats.rst.
+	values := make([]uint64, 6)
+	for i, fieldIndex := range []int{3, 5, 6, 7, 9, 10} {
+		value, err := strconv.ParseUint(fields[fieldIndex], 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid %v field %v: %v", diskStatsFilePath, fieldIndex, err)
+		}
+		values[i] = value
+	}
+	return &diskStatsCounters{
+		readIOs:      values[0],
+		readSectors:  values[1],
+		readTicks:    values[2],
+		writeIOs:     values[3],
+		writeSectors: values[4],
+		writeTicks:   values[5],
+		sampledAt:    time.Now(),
+	}, true, nil
+}
+
+// GetLatestVolumeStats returns the most recent I/O sample of the volume. It
+// reports ok false until the volume has been attached to the current node for
+// at least two sampling intervals.
+func (m *VolumeManager) GetLatestVolumeStats(volumeName string) (sample VolumeStatsSample, ok bool) {
+	m.statsLock.RLock()
+	defer m.statsLock.RUnlock()
+
+	ring := m.volumeStats[volumeName]
+	if ring == nil {
+		return sample, false
+	}
+	samples := ring.list()
+	if len(samples) == 0 {
+		return sample, false
+	}
+	return samples[len(samples)-1], true
+}
+
+// GetVolumeStatsHistory returns the retained I/O samples of the volume in
+// chronological order. The history is empty until the volume has been
+// attached to the current node for at least two sampling intervals.
+func (m *VolumeManager) GetVolumeStatsHistory(volumeName string) ([]VolumeStatsSample, error) {
+	if _, err := m.ds.GetVolume(volumeName); err != nil {
+		return nil, err
+	}
+
+	m.statsLock.RLock()
+	defer m.statsLock.RUnlock()
+
+	ring := m.volumeStats[volumeName]
+	if ring == nil {
+		return []VolumeStatsSample{}, nil
+	}
+	return ring.list(), nil
+}
@@ -0,0 +1,30 @@
+package monitoring
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+var apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: longhornName,
+	Subsystem: subsystemAPI,
+	Name:      "request_duration_seconds",
+	Help:      "Latency of the manager REST API requests, partitioned by method, route template, and status code",
+	Buckets:   prometheus.DefBuckets,
+}, []string{methodLabel, pathLabel, codeLabel})
+
+func initAPIMetrics(logger logrus.FieldLogger) {
+	if err := Register(apiRequestDuration); err != nil {
+		logger.WithError(err).Warn("failed to register API request metric")
+	}
+}
+
+// ObserveAPIRequest records one finished API request. The path must be the
+// route template rather than the raw URL, so the label cardinality stays
+// bounded by the number of routes.
+func ObserveAPIRequest(method, path string, code int, duration time.Duration) {
+	apiRequestDuration.WithLabelValues(method, path, strconv.Itoa(code)).Observe(duration.Seconds())
+}
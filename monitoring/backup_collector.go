@@ -0,0 +1,239 @@
+package monitoring
+
+import (
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+type BackupCollector struct {
+	*baseCollector
+
+	sizeMetric            metricInfo
+	stateMetric           metricInfo
+	progressMetric        metricInfo
+	lastCompletedMetric   metricInfo
+	errorCountMetric      metricInfo
+	restoreProgressMetric metricInfo
+	restoreErrorMetric    metricInfo
+}
+
+func NewBackupCollector(
+	logger logrus.FieldLogger,
+	nodeID string,
+	ds *datastore.DataStore) *BackupCollector {
+
+	bc := &BackupCollector{
+		baseCollector: newBaseCollector(subsystemBackup, logger, nodeID, ds),
+	}
+
+	bc.sizeMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemBackup, "actual_size_bytes"),
+			"Actual size of this backup",
+			[]string{volumeLabel, backupLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	bc.stateMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemBackup, "state"),
+			"State of this backup",
+			[]string{volumeLabel, backupLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	bc.progressMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemBackup, "progress"),
+			"Progress of this backup in percent",
+			[]string{volumeLabel, backupLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	bc.lastCompletedMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemBackup, "last_completed_timestamp"),
+			"Unix timestamp of the most recent completed backup of this volume",
+			[]string{volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	bc.errorCountMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemBackup, "error_count"),
+			"Number of backups of this volume currently in the Error state",
+			[]string{volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	bc.restoreProgressMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "restore_progress"),
+			"Average restore progress of the restoring replicas of this volume in percent",
+			[]string{nodeLabel, volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	bc.restoreErrorMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "restore_error"),
+			"Whether a replica of this volume hit an error during the restore",
+			[]string{nodeLabel, volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	return bc
+}
+
+func (bc *BackupCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- bc.sizeMetric.Desc
+	ch <- bc.stateMetric.Desc
+	ch <- bc.progressMetric.Desc
+	ch <- bc.lastCompletedMetric.Desc
+	ch <- bc.errorCountMetric.Desc
+	ch <- bc.restoreProgressMetric.Desc
+	ch <- bc.restoreErrorMetric.Desc
+}
+
+func (bc *BackupCollector) Collect(ch chan<- prometheus.Metric) {
+	bc.collectBackups(ch)
+	bc.collectRestores(ch)
+}
+
+func (bc *BackupCollector) collectBackups(ch chan<- prometheus.Metric) {
+	defer func() {
+		if err := recover(); err != nil {
+			bc.logger.WithField("error", err).Warn("panic during collecting metrics")
+		}
+	}()
+
+	backupVolumes, err := bc.ds.ListBackupVolumes()
+	if err != nil {
+		bc.logger.WithError(err).Warn("error during scrape")
+		return
+	}
+
+	for _, bv := range backupVolumes {
+		// Every manager serves the metrics endpoint, so each backup volume is
+		// reported by its owner only to avoid duplicated series.
+		if bv.Status.OwnerID != bc.currentNodeID {
+			continue
+		}
+
+		backups, err := bc.ds.ListBackupsWithBackupVolumeName(bv.Name)
+		if err != nil {
+			bc.logger.WithError(err).Warn("error during scrape")
+			continue
+		}
+
+		var lastCompleted float64
+		errorCount := 0
+		for backupName, b := range backups {
+			ch <- prometheus.MustNewConstMetric(bc.stateMetric.Desc, bc.stateMetric.Type, float64(getBackupStateValue(b)), bv.Name, backupName)
+			ch <- prometheus.MustNewConstMetric(bc.progressMetric.Desc, bc.progressMetric.Type, float64(b.Status.Progress), bv.Name, backupName)
+			if size, err := strconv.ParseFloat(b.Status.Size, 64); err == nil {
+				ch <- prometheus.MustNewConstMetric(bc.sizeMetric.Desc, bc.sizeMetric.Type, size, bv.Name, backupName)
+			}
+
+			switch b.Status.State {
+			case longhorn.BackupStateCompleted:
+				if t, err := util.ParseTime(b.Status.BackupCreatedAt); err == nil {
+					if timestamp := float64(t.Unix()); timestamp > lastCompleted {
+						lastCompleted = timestamp
+					}
+				}
+			case longhorn.BackupStateError:
+				errorCount++
+			}
+		}
+
+		if lastCompleted > 0 {
+			ch <- prometheus.MustNewConstMetric(bc.lastCompletedMetric.Desc, bc.lastCompletedMetric.Type, lastCompleted, bv.Name)
+		}
+		ch <- prometheus.MustNewConstMetric(bc.errorCountMetric.Desc, bc.errorCountMetric.Type, float64(errorCount), bv.Name)
+	}
+}
+
+func (bc *BackupCollector) collectRestores(ch chan<- prometheus.Metric) {
+	defer func() {
+		if err := recover(); err != nil {
+			bc.logger.WithField("error", err).Warn("panic during collecting metrics")
+		}
+	}()
+
+	engines, err := bc.ds.ListEnginesRO()
+	if err != nil {
+		bc.logger.WithError(err).Warn("error during scrape")
+		return
+	}
+
+	for _, e := range engines {
+		if e.Status.OwnerID != bc.currentNodeID {
+			continue
+		}
+
+		restoringCount := 0
+		totalProgress := 0
+		restoreError := 0
+		for _, restoreStatus := range e.Status.RestoreStatus {
+			if restoreStatus == nil {
+				continue
+			}
+			if restoreStatus.Error != "" {
+				restoreError = 1
+			}
+			if !restoreStatus.IsRestoring {
+				continue
+			}
+			restoringCount++
+			totalProgress += restoreStatus.Progress
+		}
+		if restoringCount == 0 && restoreError == 0 {
+			continue
+		}
+
+		progress := 0
+		if restoringCount > 0 {
+			progress = totalProgress / restoringCount
+		}
+		ch <- prometheus.MustNewConstMetric(bc.restoreProgressMetric.Desc, bc.restoreProgressMetric.Type, float64(progress), bc.currentNodeID, e.Spec.VolumeName)
+		ch <- prometheus.MustNewConstMetric(bc.restoreErrorMetric.Desc, bc.restoreErrorMetric.Type, float64(restoreError), bc.currentNodeID, e.Spec.VolumeName)
+	}
+}
+
+func getBackupStateValue(b *longhorn.Backup) int {
+	stateValue := 0
+	switch b.Status.State {
+	case longhorn.BackupStateInProgress:
+		stateValue = 1
+	case longhorn.BackupStateCompleted:
+		stateValue = 2
+	case longhorn.BackupStateError:
+		stateValue = 3
+	case longhorn.BackupStateUnknown:
+		stateValue = 4
+	}
+	return stateValue
+}
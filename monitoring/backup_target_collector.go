@@ -0,0 +1,132 @@
+package monitoring
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+// credentialErrorKeywords are matched against the unavailable condition
+// message to tell authentication problems apart from plain connectivity
+// failures.
+var credentialErrorKeywords = []string{
+	"accessdenied",
+	"access denied",
+	"invalidaccesskeyid",
+	"signaturedoesnotmatch",
+	"credential",
+	"forbidden",
+	"permission denied",
+	"401",
+	"403",
+}
+
+type BackupTargetCollector struct {
+	*baseCollector
+
+	reachableMetric       metricInfo
+	lastSyncedMetric      metricInfo
+	credentialErrorMetric metricInfo
+}
+
+func NewBackupTargetCollector(
+	logger logrus.FieldLogger,
+	nodeID string,
+	ds *datastore.DataStore) *BackupTargetCollector {
+
+	btc := &BackupTargetCollector{
+		baseCollector: newBaseCollector(subsystemBackupTarget, logger, nodeID, ds),
+	}
+
+	btc.reachableMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemBackupTarget, "reachable"),
+			"Whether the remote backup target is reachable (1 reachable, 0 unreachable)",
+			[]string{backupTargetLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	btc.lastSyncedMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemBackupTarget, "last_synced_timestamp"),
+			"Unix timestamp of the last successful backup target poll",
+			[]string{backupTargetLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	btc.credentialErrorMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemBackupTarget, "credential_error"),
+			"Whether the backup target is unavailable due to an authentication failure (1 authentication failure, 0 otherwise)",
+			[]string{backupTargetLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	return btc
+}
+
+func (btc *BackupTargetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- btc.reachableMetric.Desc
+	ch <- btc.lastSyncedMetric.Desc
+	ch <- btc.credentialErrorMetric.Desc
+}
+
+func (btc *BackupTargetCollector) Collect(ch chan<- prometheus.Metric) {
+	defer func() {
+		if err := recover(); err != nil {
+			btc.logger.WithField("error", err).Warn("panic during collecting metrics")
+		}
+	}()
+
+	backupTargets, err := btc.ds.ListBackupTargets()
+	if err != nil {
+		btc.logger.WithError(err).Warn("error during scrape")
+		return
+	}
+
+	for _, bt := range backupTargets {
+		// Only the owner node reports the backup target, so each target
+		// produces a single series.
+		if bt.Status.OwnerID != btc.currentNodeID {
+			continue
+		}
+
+		reachable := float64(0)
+		if bt.Status.Available {
+			reachable = 1
+		}
+		ch <- prometheus.MustNewConstMetric(btc.reachableMetric.Desc, btc.reachableMetric.Type, reachable, bt.Name)
+
+		if !bt.Status.LastSyncedAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(btc.lastSyncedMetric.Desc, btc.lastSyncedMetric.Type, float64(bt.Status.LastSyncedAt.Unix()), bt.Name)
+		}
+
+		credentialError := float64(0)
+		if !bt.Status.Available && isCredentialErrorMessage(types.GetCondition(bt.Status.Conditions, longhorn.BackupTargetConditionTypeUnavailable).Message) {
+			credentialError = 1
+		}
+		ch <- prometheus.MustNewConstMetric(btc.credentialErrorMetric.Desc, btc.credentialErrorMetric.Type, credentialError, bt.Name)
+	}
+}
+
+func isCredentialErrorMessage(message string) bool {
+	message = strings.ToLower(message)
+	for _, keyword := range credentialErrorKeywords {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,11 +1,14 @@
 package monitoring
 
 import (
+	"strings"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/longhorn/longhorn-manager/datastore"
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 )
 
 type DiskCollector struct {
@@ -14,6 +17,8 @@ type DiskCollector struct {
 	capacityMetric    metricInfo
 	usageMetric       metricInfo
 	reservationMetric metricInfo
+	scheduledMetric   metricInfo
+	statusMetric      metricInfo
 }
 
 func NewDiskCollector(
@@ -55,6 +60,26 @@ func NewDiskCollector(
 		Type: prometheus.GaugeValue,
 	}
 
+	dc.scheduledMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemDisk, "scheduled_bytes"),
+			"The storage scheduled for replicas on this disk",
+			[]string{nodeLabel, diskLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	dc.statusMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemDisk, "status"),
+			"Status of this disk",
+			[]string{nodeLabel, diskLabel, conditionLabel, conditionReasonLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
 	return dc
 }
 
@@ -62,6 +87,8 @@ func (dc *DiskCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- dc.capacityMetric.Desc
 	ch <- dc.usageMetric.Desc
 	ch <- dc.reservationMetric.Desc
+	ch <- dc.scheduledMetric.Desc
+	ch <- dc.statusMetric.Desc
 }
 
 func (dc *DiskCollector) Collect(ch chan<- prometheus.Metric) {
@@ -90,5 +117,21 @@ func (dc *DiskCollector) collectDiskStorage(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(dc.capacityMetric.Desc, dc.capacityMetric.Type, float64(storageCapacity), dc.currentNodeID, diskName)
 		ch <- prometheus.MustNewConstMetric(dc.usageMetric.Desc, dc.usageMetric.Type, float64(storageUsage), dc.currentNodeID, diskName)
 		ch <- prometheus.MustNewConstMetric(dc.reservationMetric.Desc, dc.reservationMetric.Type, float64(storageReservation), dc.currentNodeID, diskName)
+		ch <- prometheus.MustNewConstMetric(dc.scheduledMetric.Desc, dc.scheduledMetric.Type, float64(disk.StorageScheduled), dc.currentNodeID, diskName)
+
+		for _, condition := range disk.Conditions {
+			val := 0
+			if condition.Status == longhorn.ConditionStatusTrue {
+				val = 1
+			}
+			ch <- prometheus.MustNewConstMetric(dc.statusMetric.Desc, dc.statusMetric.Type, float64(val), dc.currentNodeID, diskName, strings.ToLower(condition.Type), condition.Reason)
+		}
+
+		// Get the allowScheduling value to determine whether this disk is disabled by user
+		allowSchedulingVal := 0
+		if disk.AllowScheduling {
+			allowSchedulingVal = 1
+		}
+		ch <- prometheus.MustNewConstMetric(dc.statusMetric.Desc, dc.statusMetric.Type, float64(allowSchedulingVal), dc.currentNodeID, diskName, "allowScheduling", "")
 	}
 }
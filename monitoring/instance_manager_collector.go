@@ -27,6 +27,7 @@ type InstanceManagerCollector struct {
 	cpuRequestMetric    metricInfo
 	memoryUsageMetric   metricInfo
 	memoryRequestMetric metricInfo
+	processCountMetric  metricInfo
 }
 
 func NewInstanceManagerCollector(
@@ -82,6 +83,16 @@ func NewInstanceManagerCollector(
 		Type: prometheus.GaugeValue,
 	}
 
+	imc.processCountMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemInstanceManager, "proc_count"),
+			"Number of engine or replica processes running in this Longhorn instance manager",
+			[]string{nodeLabel, instanceManagerLabel, instanceManagerType},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
 	return imc
 }
 
@@ -90,6 +101,7 @@ func (imc *InstanceManagerCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- imc.cpuRequestMetric.Desc
 	ch <- imc.memoryUsageMetric.Desc
 	ch <- imc.memoryRequestMetric.Desc
+	ch <- imc.processCountMetric.Desc
 }
 
 func (imc *InstanceManagerCollector) Collect(ch chan<- prometheus.Metric) {
@@ -107,6 +119,12 @@ func (imc *InstanceManagerCollector) Collect(ch chan<- prometheus.Metric) {
 		imc.collectRequestValues(ch)
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		imc.collectProcessCounts(ch)
+	}()
+
 	wg.Wait()
 }
 
@@ -155,6 +173,27 @@ func getInstanceManagerTypeFromInstanceManagerName(imName string) string {
 	}
 }
 
+func (imc *InstanceManagerCollector) collectProcessCounts(ch chan<- prometheus.Metric) {
+	defer func() {
+		if err := recover(); err != nil {
+			imc.logger.WithField("error", err).Warn("panic during collecting metrics")
+		}
+	}()
+
+	imMap, err := imc.ds.ListInstanceManagers()
+	if err != nil {
+		imc.logger.WithError(err).Warn("error during scrape")
+		return
+	}
+
+	for _, im := range imMap {
+		if im.Spec.NodeID != imc.currentNodeID {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(imc.processCountMetric.Desc, imc.processCountMetric.Type, float64(len(im.Status.Instances)), imc.currentNodeID, im.GetName(), string(im.Spec.Type))
+	}
+}
+
 func (imc *InstanceManagerCollector) collectRequestValues(ch chan<- prometheus.Metric) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/manager"
 	"github.com/longhorn/longhorn-manager/types"
 )
 
@@ -32,9 +33,13 @@ func Handler() http.Handler {
 	return promhttp.HandlerFor(longhornCustomRegistry, promhttp.HandlerOpts{})
 }
 
-func InitMonitoringSystem(logger logrus.FieldLogger, currentNodeID string, ds *datastore.DataStore, kubeconfigPath string) {
-	vc := NewVolumeCollector(logger, currentNodeID, ds)
+func InitMonitoringSystem(logger logrus.FieldLogger, currentNodeID string, ds *datastore.DataStore, kubeconfigPath string, vm *manager.VolumeManager) {
+	initAPIMetrics(logger)
+
+	vc := NewVolumeCollector(logger, currentNodeID, ds, vm)
 	dc := NewDiskCollector(logger, currentNodeID, ds)
+	bc := NewBackupCollector(logger, currentNodeID, ds)
+	btc := NewBackupTargetCollector(logger, currentNodeID, ds)
 
 	if err := Register(vc); err != nil {
 		logger.WithField("collector", subsystemVolume).WithError(err).Warn("failed to register collector")
@@ -44,6 +49,14 @@ func InitMonitoringSystem(logger logrus.FieldLogger, currentNodeID string, ds *d
 		logger.WithField("collector", subsystemDisk).WithError(err).Warn("failed to register collector")
 	}
 
+	if err := Register(bc); err != nil {
+		logger.WithField("collector", subsystemBackup).WithError(err).Warn("failed to register collector")
+	}
+
+	if err := Register(btc); err != nil {
+		logger.WithField("collector", subsystemBackupTarget).WithError(err).Warn("failed to register collector")
+	}
+
 	namespace := os.Getenv(types.EnvPodNamespace)
 	if namespace == "" {
 		logger.Warnf("Cannot detect pod namespace, environment variable %v is missing, "+
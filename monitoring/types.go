@@ -12,17 +12,27 @@ const (
 	subsystemVolume          = "volume"
 	subsystemNode            = "node"
 	subsystemDisk            = "disk"
+	subsystemBackup          = "backup"
+	subsystemBackupTarget    = "backup_target"
 	subsystemInstanceManager = "instance_manager"
 	subsystemManager         = "manager"
+	subsystemAPI             = "api"
+	subsystemWorkqueue       = "workqueue"
 
 	nodeLabel            = "node"
 	diskLabel            = "disk"
 	volumeLabel          = "volume"
+	backupLabel          = "backup"
+	backupTargetLabel    = "backup_target"
 	conditionLabel       = "condition"
 	conditionReasonLabel = "condition_reason"
 	instanceManagerLabel = "instance_manager"
 	instanceManagerType  = "instance_manager_type"
 	managerLabel         = "manager"
+	methodLabel          = "method"
+	pathLabel            = "path"
+	codeLabel            = "code"
+	workqueueLabel       = "name"
 )
 
 type metricInfo struct {
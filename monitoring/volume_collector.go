@@ -7,24 +7,36 @@ import (
 
 	"github.com/longhorn/longhorn-manager/datastore"
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/longhorn/longhorn-manager/manager"
 )
 
 type VolumeCollector struct {
 	*baseCollector
 
+	vm *manager.VolumeManager
+
 	capacityMetric   metricInfo
 	sizeMetric       metricInfo
 	stateMetric      metricInfo
 	robustnessMetric metricInfo
+
+	readIOPSMetric        metricInfo
+	writeIOPSMetric       metricInfo
+	readThroughputMetric  metricInfo
+	writeThroughputMetric metricInfo
+	readLatencyMetric     metricInfo
+	writeLatencyMetric    metricInfo
 }
 
 func NewVolumeCollector(
 	logger logrus.FieldLogger,
 	nodeID string,
-	ds *datastore.DataStore) *VolumeCollector {
+	ds *datastore.DataStore,
+	vm *manager.VolumeManager) *VolumeCollector {
 
 	vc := &VolumeCollector{
 		baseCollector: newBaseCollector(subsystemVolume, logger, nodeID, ds),
+		vm:            vm,
 	}
 
 	vc.capacityMetric = metricInfo{
@@ -67,6 +79,66 @@ func NewVolumeCollector(
 		Type: prometheus.GaugeValue,
 	}
 
+	vc.readIOPSMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "read_iops"),
+			"Read IOPS of this volume",
+			[]string{nodeLabel, volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	vc.writeIOPSMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "write_iops"),
+			"Write IOPS of this volume",
+			[]string{nodeLabel, volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	vc.readThroughputMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "read_throughput"),
+			"Read throughput of this volume (Bytes/s)",
+			[]string{nodeLabel, volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	vc.writeThroughputMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "write_throughput"),
+			"Write throughput of this volume (Bytes/s)",
+			[]string{nodeLabel, volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	vc.readLatencyMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "read_latency"),
+			"Average read latency of this volume (ms)",
+			[]string{nodeLabel, volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	vc.writeLatencyMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "write_latency"),
+			"Average write latency of this volume (ms)",
+			[]string{nodeLabel, volumeLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
 	return vc
 }
 
@@ -75,6 +147,12 @@ func (vc *VolumeCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- vc.sizeMetric.Desc
 	ch <- vc.stateMetric.Desc
 	ch <- vc.robustnessMetric.Desc
+	ch <- vc.readIOPSMetric.Desc
+	ch <- vc.writeIOPSMetric.Desc
+	ch <- vc.readThroughputMetric.Desc
+	ch <- vc.writeThroughputMetric.Desc
+	ch <- vc.readLatencyMetric.Desc
+	ch <- vc.writeLatencyMetric.Desc
 }
 
 func (vc *VolumeCollector) Collect(ch chan<- prometheus.Metric) {
@@ -96,6 +174,17 @@ func (vc *VolumeCollector) Collect(ch chan<- prometheus.Metric) {
 			ch <- prometheus.MustNewConstMetric(vc.sizeMetric.Desc, vc.sizeMetric.Type, float64(v.Status.ActualSize), vc.currentNodeID, v.Name)
 			ch <- prometheus.MustNewConstMetric(vc.stateMetric.Desc, vc.stateMetric.Type, float64(getVolumeStateValue(v)), vc.currentNodeID, v.Name)
 			ch <- prometheus.MustNewConstMetric(vc.robustnessMetric.Desc, vc.robustnessMetric.Type, float64(getVolumeRobustnessValue(v)), vc.currentNodeID, v.Name)
+
+			// The I/O rates are only known for the volumes the local
+			// sampler has seen attached long enough.
+			if sample, ok := vc.vm.GetLatestVolumeStats(v.Name); ok {
+				ch <- prometheus.MustNewConstMetric(vc.readIOPSMetric.Desc, vc.readIOPSMetric.Type, sample.ReadIOPS, vc.currentNodeID, v.Name)
+				ch <- prometheus.MustNewConstMetric(vc.writeIOPSMetric.Desc, vc.writeIOPSMetric.Type, sample.WriteIOPS, vc.currentNodeID, v.Name)
+				ch <- prometheus.MustNewConstMetric(vc.readThroughputMetric.Desc, vc.readThroughputMetric.Type, sample.ReadThroughput, vc.currentNodeID, v.Name)
+				ch <- prometheus.MustNewConstMetric(vc.writeThroughputMetric.Desc, vc.writeThroughputMetric.Type, sample.WriteThroughput, vc.currentNodeID, v.Name)
+				ch <- prometheus.MustNewConstMetric(vc.readLatencyMetric.Desc, vc.readLatencyMetric.Type, sample.ReadLatency, vc.currentNodeID, v.Name)
+				ch <- prometheus.MustNewConstMetric(vc.writeLatencyMetric.Desc, vc.writeLatencyMetric.Type, sample.WriteLatency, vc.currentNodeID, v.Name)
+			}
 		}
 	}
 }
@@ -0,0 +1,123 @@
+package monitoring
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+var initWorkqueueMetricsOnce sync.Once
+
+// InitWorkqueueMetrics plugs a Prometheus-backed metrics provider into the
+// client-go workqueue package, exposing the depth, latency, work duration and
+// retry counters of every controller queue. It must be called before any
+// controller creates its queue, since the provider of a queue is fixed at
+// creation time.
+func InitWorkqueueMetrics() {
+	initWorkqueueMetricsOnce.Do(func() {
+		provider := newWorkqueueMetricsProvider()
+		for _, collector := range provider.collectors() {
+			if err := Register(collector); err != nil {
+				logrus.WithError(err).Warn("failed to register workqueue metric")
+			}
+		}
+		workqueue.SetProvider(provider)
+	})
+}
+
+type workqueueMetricsProvider struct {
+	depth                   *prometheus.GaugeVec
+	adds                    *prometheus.CounterVec
+	latency                 *prometheus.HistogramVec
+	workDuration            *prometheus.HistogramVec
+	unfinished              *prometheus.GaugeVec
+	longestRunningProcessor *prometheus.GaugeVec
+	retries                 *prometheus.CounterVec
+}
+
+func newWorkqueueMetricsProvider() *workqueueMetricsProvider {
+	return &workqueueMetricsProvider{
+		depth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: longhornName,
+			Subsystem: subsystemWorkqueue,
+			Name:      "depth",
+			Help:      "Current number of items in the workqueue",
+		}, []string{workqueueLabel}),
+		adds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: longhornName,
+			Subsystem: subsystemWorkqueue,
+			Name:      "adds_total",
+			Help:      "Total number of items added to the workqueue",
+		}, []string{workqueueLabel}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: longhornName,
+			Subsystem: subsystemWorkqueue,
+			Name:      "queue_duration_seconds",
+			Help:      "How long an item stays in the workqueue before being processed",
+			Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 10),
+		}, []string{workqueueLabel}),
+		workDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: longhornName,
+			Subsystem: subsystemWorkqueue,
+			Name:      "work_duration_seconds",
+			Help:      "How long processing an item from the workqueue takes",
+			Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 10),
+		}, []string{workqueueLabel}),
+		unfinished: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: longhornName,
+			Subsystem: subsystemWorkqueue,
+			Name:      "unfinished_work_seconds",
+			Help:      "Seconds of work in progress that hasn't been observed by work_duration_seconds yet",
+		}, []string{workqueueLabel}),
+		longestRunningProcessor: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: longhornName,
+			Subsystem: subsystemWorkqueue,
+			Name:      "longest_running_processor_seconds",
+			Help:      "Seconds the longest running processor of the workqueue has been running",
+		}, []string{workqueueLabel}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: longhornName,
+			Subsystem: subsystemWorkqueue,
+			Name:      "retries_total",
+			Help:      "Total number of items re-queued after a failed sync",
+		}, []string{workqueueLabel}),
+	}
+}
+
+func (p *workqueueMetricsProvider) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		p.depth, p.adds, p.latency, p.workDuration,
+		p.unfinished, p.longestRunningProcessor, p.retries,
+	}
+}
+
+func (p *workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.depth.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return p.adds.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return p.latency.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return p.workDuration.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.unfinished.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.longestRunningProcessor.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return p.retries.WithLabelValues(name)
+}
@@ -0,0 +1,18 @@
+package rpc
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// NewVolumeManagerClient dials the gRPC API at the given address and returns
+// a client for it, together with the connection to close when done.
+func NewVolumeManagerClient(address string) (VolumeManagerServiceClient, io.Closer, error) {
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to connect to the gRPC API at %v", address)
+	}
+	return NewVolumeManagerServiceClient(conn), conn, nil
+}
@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/longhorn/longhorn-manager/manager"
+)
+
+const (
+	// DefaultPort is the port the gRPC API listens on, next to the REST API
+	// on types.DefaultAPIPort.
+	DefaultPort = 9510
+)
+
+// Server exposes the volume lifecycle operations of the volume manager over
+// gRPC. It is a thin shim: all validation and the actual work happen in the
+// manager, the server only translates between the protobuf messages and the
+// manager types.
+type Server struct {
+	m *manager.VolumeManager
+}
+
+func NewServer(m *manager.VolumeManager) *Server {
+	return &Server{m: m}
+}
+
+// ListenAndServe blocks serving the gRPC API on the given address.
+func (s *Server) ListenAndServe(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %v for the gRPC API", address)
+	}
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(s.authUnaryInterceptor))
+	RegisterVolumeManagerServiceServer(grpcServer, s)
+	return grpcServer.Serve(listener)
+}
+
+func (s *Server) VolumeGet(ctx context.Context, req *VolumeGetRequest) (*Volume, error) {
+	v, err := s.m.Get(req.Name)
+	if err != nil {
+		return nil, toStatusError(err, "failed to get volume %v", req.Name)
+	}
+	return toVolume(v), nil
+}
+
+func (s *Server) VolumeList(ctx context.Context, req *VolumeListRequest) (*VolumeListResponse, error) {
+	list, err := s.m.ListSorted()
+	if err != nil {
+		return nil, toStatusError(err, "failed to list volumes")
+	}
+	resp := &VolumeListResponse{}
+	for _, v := range list {
+		resp.Volumes = append(resp.Volumes, toVolume(v))
+	}
+	return resp, nil
+}
+
+func (s *Server) VolumeCreate(ctx context.Context, req *VolumeCreateRequest) (*Volume, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume name is required")
+	}
+	spec := &longhorn.VolumeSpec{
+		Size:             req.Size,
+		NumberOfReplicas: int(req.NumberOfReplicas),
+		Frontend:         longhorn.VolumeFrontend(req.Frontend),
+		AccessMode:       longhorn.AccessMode(req.AccessMode),
+		BackingImage:     req.BackingImage,
+	}
+	v, err := s.m.Create(req.Name, spec, nil)
+	if err != nil {
+		return nil, toStatusError(err, "failed to create volume %v", req.Name)
+	}
+	return toVolume(v), nil
+}
+
+func (s *Server) VolumeDelete(ctx context.Context, req *VolumeDeleteRequest) (*VolumeDeleteResponse, error) {
+	if err := s.m.Delete(req.Name); err != nil {
+		return nil, toStatusError(err, "failed to delete volume %v", req.Name)
+	}
+	return &VolumeDeleteResponse{}, nil
+}
+
+func (s *Server) VolumeAttach(ctx context.Context, req *VolumeAttachRequest) (*Volume, error) {
+	v, err := s.m.Attach(req.Name, req.HostId, req.DisableFrontend, req.AttachedBy)
+	if err != nil {
+		return nil, toStatusError(err, "failed to attach volume %v", req.Name)
+	}
+	return toVolume(v), nil
+}
+
+func (s *Server) VolumeDetach(ctx context.Context, req *VolumeDetachRequest) (*Volume, error) {
+	v, err := s.m.Detach(req.Name, req.HostId)
+	if err != nil {
+		return nil, toStatusError(err, "failed to detach volume %v", req.Name)
+	}
+	return toVolume(v), nil
+}
+
+func toVolume(v *longhorn.Volume) *Volume {
+	return &Volume{
+		Name:             v.Name,
+		Size:             v.Spec.Size,
+		State:            string(v.Status.State),
+		Robustness:       string(v.Status.Robustness),
+		Frontend:         string(v.Spec.Frontend),
+		AccessMode:       string(v.Spec.AccessMode),
+		NumberOfReplicas: int32(v.Spec.NumberOfReplicas),
+		NodeId:           v.Status.CurrentNodeID,
+		BackingImage:     v.Spec.BackingImage,
+		Created:          v.CreationTimestamp.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// toStatusError wraps the error with the given message and maps it to the
+// matching gRPC status code, so clients can distinguish a missing volume
+// from an internal failure.
+func toStatusError(err error, format string, args ...interface{}) error {
+	err = errors.Wrapf(err, format, args...)
+	if datastore.ErrorIsNotFound(errors.Cause(err)) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,232 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+const serviceName = "longhorn.manager.VolumeManagerService"
+
+// VolumeManagerServiceServer is the server side of the volume lifecycle gRPC
+// service defined in manager.proto.
+type VolumeManagerServiceServer interface {
+	VolumeGet(context.Context, *VolumeGetRequest) (*Volume, error)
+	VolumeList(context.Context, *VolumeListRequest) (*VolumeListResponse, error)
+	VolumeCreate(context.Context, *VolumeCreateRequest) (*Volume, error)
+	VolumeDelete(context.Context, *VolumeDeleteRequest) (*VolumeDeleteResponse, error)
+	VolumeAttach(context.Context, *VolumeAttachRequest) (*Volume, error)
+	VolumeDetach(context.Context, *VolumeDetachRequest) (*Volume, error)
+}
+
+func RegisterVolumeManagerServiceServer(s *grpc.Server, srv VolumeManagerServiceServer) {
+	s.RegisterService(&volumeManagerServiceDesc, srv)
+}
+
+// VolumeManagerServiceClient is the client side of the volume lifecycle gRPC
+// service.
+type VolumeManagerServiceClient interface {
+	VolumeGet(ctx context.Context, in *VolumeGetRequest, opts ...grpc.CallOption) (*Volume, error)
+	VolumeList(ctx context.Context, in *VolumeListRequest, opts ...grpc.CallOption) (*VolumeListResponse, error)
+	VolumeCreate(ctx context.Context, in *VolumeCreateRequest, opts ...grpc.CallOption) (*Volume, error)
+	VolumeDelete(ctx context.Context, in *VolumeDeleteRequest, opts ...grpc.CallOption) (*VolumeDeleteResponse, error)
+	VolumeAttach(ctx context.Context, in *VolumeAttachRequest, opts ...grpc.CallOption) (*Volume, error)
+	VolumeDetach(ctx context.Context, in *VolumeDetachRequest, opts ...grpc.CallOption) (*Volume, error)
+}
+
+type volumeManagerServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewVolumeManagerServiceClient(cc *grpc.ClientConn) VolumeManagerServiceClient {
+	return &volumeManagerServiceClient{cc}
+}
+
+func (c *volumeManagerServiceClient) VolumeGet(ctx context.Context, in *VolumeGetRequest, opts ...grpc.CallOption) (*Volume, error) {
+	out := new(Volume)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/VolumeGet", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volumeManagerServiceClient) VolumeList(ctx context.Context, in *VolumeListRequest, opts ...grpc.CallOption) (*VolumeListResponse, error) {
+	out := new(VolumeListResponse)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/VolumeList", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volumeManagerServiceClient) VolumeCreate(ctx context.Context, in *VolumeCreateRequest, opts ...grpc.CallOption) (*Volume, error) {
+	out := new(Volume)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/VolumeCreate", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volumeManagerServiceClient) VolumeDelete(ctx context.Context, in *VolumeDeleteRequest, opts ...grpc.CallOption) (*VolumeDeleteResponse, error) {
+	out := new(VolumeDeleteResponse)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/VolumeDelete", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volumeManagerServiceClient) VolumeAttach(ctx context.Context, in *VolumeAttachRequest, opts ...grpc.CallOption) (*Volume, error) {
+	out := new(Volume)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/VolumeAttach", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volumeManagerServiceClient) VolumeDetach(ctx context.Context, in *VolumeDetachRequest, opts ...grpc.CallOption) (*Volume, error) {
+	out := new(Volume)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/VolumeDetach", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _VolumeManagerService_VolumeGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeManagerServiceServer).VolumeGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/VolumeGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeManagerServiceServer).VolumeGet(ctx, req.(*VolumeGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolumeManagerService_VolumeList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeManagerServiceServer).VolumeList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/VolumeList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeManagerServiceServer).VolumeList(ctx, req.(*VolumeListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolumeManagerService_VolumeCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeManagerServiceServer).VolumeCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/VolumeCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeManagerServiceServer).VolumeCreate(ctx, req.(*VolumeCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolumeManagerService_VolumeDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeManagerServiceServer).VolumeDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/VolumeDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeManagerServiceServer).VolumeDelete(ctx, req.(*VolumeDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolumeManagerService_VolumeAttach_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeAttachRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeManagerServiceServer).VolumeAttach(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/VolumeAttach",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeManagerServiceServer).VolumeAttach(ctx, req.(*VolumeAttachRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolumeManagerService_VolumeDetach_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeDetachRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeManagerServiceServer).VolumeDetach(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/VolumeDetach",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeManagerServiceServer).VolumeDetach(ctx, req.(*VolumeDetachRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var volumeManagerServiceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*VolumeManagerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "VolumeGet",
+			Handler:    _VolumeManagerService_VolumeGet_Handler,
+		},
+		{
+			MethodName: "VolumeList",
+			Handler:    _VolumeManagerService_VolumeList_Handler,
+		},
+		{
+			MethodName: "VolumeCreate",
+			Handler:    _VolumeManagerService_VolumeCreate_Handler,
+		},
+		{
+			MethodName: "VolumeDelete",
+			Handler:    _VolumeManagerService_VolumeDelete_Handler,
+		},
+		{
+			MethodName: "VolumeAttach",
+			Handler:    _VolumeManagerService_VolumeAttach_Handler,
+		},
+		{
+			MethodName: "VolumeDetach",
+			Handler:    _VolumeManagerService_VolumeDetach_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "manager.proto",
+}
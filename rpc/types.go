@@ -0,0 +1,109 @@
+package rpc
+
+// The message types below are the Go bindings of manager.proto. They are
+// maintained by hand since the struct tags are all the protobuf runtime
+// needs for the wire format; keep them in sync with the proto definition.
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+type Volume struct {
+	Name             string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Size             int64  `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
+	State            string `protobuf:"bytes,3,opt,name=state" json:"state,omitempty"`
+	Robustness       string `protobuf:"bytes,4,opt,name=robustness" json:"robustness,omitempty"`
+	Frontend         string `protobuf:"bytes,5,opt,name=frontend" json:"frontend,omitempty"`
+	AccessMode       string `protobuf:"bytes,6,opt,name=access_mode,json=accessMode" json:"access_mode,omitempty"`
+	NumberOfReplicas int32  `protobuf:"varint,7,opt,name=number_of_replicas,json=numberOfReplicas" json:"number_of_replicas,omitempty"`
+	NodeId           string `protobuf:"bytes,8,opt,name=node_id,json=nodeId" json:"node_id,omitempty"`
+	BackingImage     string `protobuf:"bytes,9,opt,name=backing_image,json=backingImage" json:"backing_image,omitempty"`
+	Created          string `protobuf:"bytes,10,opt,name=created" json:"created,omitempty"`
+}
+
+func (m *Volume) Reset()         { *m = Volume{} }
+func (m *Volume) String() string { return proto.CompactTextString(m) }
+func (*Volume) ProtoMessage()    {}
+
+type VolumeGetRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *VolumeGetRequest) Reset()         { *m = VolumeGetRequest{} }
+func (m *VolumeGetRequest) String() string { return proto.CompactTextString(m) }
+func (*VolumeGetRequest) ProtoMessage()    {}
+
+type VolumeListRequest struct {
+}
+
+func (m *VolumeListRequest) Reset()         { *m = VolumeListRequest{} }
+func (m *VolumeListRequest) String() string { return proto.CompactTextString(m) }
+func (*VolumeListRequest) ProtoMessage()    {}
+
+type VolumeListResponse struct {
+	Volumes []*Volume `protobuf:"bytes,1,rep,name=volumes" json:"volumes,omitempty"`
+}
+
+func (m *VolumeListResponse) Reset()         { *m = VolumeListResponse{} }
+func (m *VolumeListResponse) String() string { return proto.CompactTextString(m) }
+func (*VolumeListResponse) ProtoMessage()    {}
+
+type VolumeCreateRequest struct {
+	Name             string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Size             int64  `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
+	NumberOfReplicas int32  `protobuf:"varint,3,opt,name=number_of_replicas,json=numberOfReplicas" json:"number_of_replicas,omitempty"`
+	Frontend         string `protobuf:"bytes,4,opt,name=frontend" json:"frontend,omitempty"`
+	AccessMode       string `protobuf:"bytes,5,opt,name=access_mode,json=accessMode" json:"access_mode,omitempty"`
+	BackingImage     string `protobuf:"bytes,6,opt,name=backing_image,json=backingImage" json:"backing_image,omitempty"`
+}
+
+func (m *VolumeCreateRequest) Reset()         { *m = VolumeCreateRequest{} }
+func (m *VolumeCreateRequest) String() string { return proto.CompactTextString(m) }
+func (*VolumeCreateRequest) ProtoMessage()    {}
+
+type VolumeDeleteRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *VolumeDeleteRequest) Reset()         { *m = VolumeDeleteRequest{} }
+func (m *VolumeDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*VolumeDeleteRequest) ProtoMessage()    {}
+
+type VolumeDeleteResponse struct {
+}
+
+func (m *VolumeDeleteResponse) Reset()         { *m = VolumeDeleteResponse{} }
+func (m *VolumeDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*VolumeDeleteResponse) ProtoMessage()    {}
+
+type VolumeAttachRequest struct {
+	Name            string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	HostId          string `protobuf:"bytes,2,opt,name=host_id,json=hostId" json:"host_id,omitempty"`
+	DisableFrontend bool   `protobuf:"varint,3,opt,name=disable_frontend,json=disableFrontend" json:"disable_frontend,omitempty"`
+	AttachedBy      string `protobuf:"bytes,4,opt,name=attached_by,json=attachedBy" json:"attached_by,omitempty"`
+}
+
+func (m *VolumeAttachRequest) Reset()         { *m = VolumeAttachRequest{} }
+func (m *VolumeAttachRequest) String() string { return proto.CompactTextString(m) }
+func (*VolumeAttachRequest) ProtoMessage()    {}
+
+type VolumeDetachRequest struct {
+	Name   string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	HostId string `protobuf:"bytes,2,opt,name=host_id,json=hostId" json:"host_id,omitempty"`
+}
+
+func (m *VolumeDetachRequest) Reset()         { *m = VolumeDetachRequest{} }
+func (m *VolumeDetachRequest) String() string { return proto.CompactTextString(m) }
+func (*VolumeDetachRequest) ProtoMessage()    {}
+
+func init() {
+	proto.RegisterType((*Volume)(nil), "longhorn.manager.Volume")
+	proto.RegisterType((*VolumeGetRequest)(nil), "longhorn.manager.VolumeGetRequest")
+	proto.RegisterType((*VolumeListRequest)(nil), "longhorn.manager.VolumeListRequest")
+	proto.RegisterType((*VolumeListResponse)(nil), "longhorn.manager.VolumeListResponse")
+	proto.RegisterType((*VolumeCreateRequest)(nil), "longhorn.manager.VolumeCreateRequest")
+	proto.RegisterType((*VolumeDeleteRequest)(nil), "longhorn.manager.VolumeDeleteRequest")
+	proto.RegisterType((*VolumeDeleteResponse)(nil), "longhorn.manager.VolumeDeleteResponse")
+	proto.RegisterType((*VolumeAttachRequest)(nil), "longhorn.manager.VolumeAttachRequest")
+	proto.RegisterType((*VolumeDetachRequest)(nil), "longhorn.manager.VolumeDetachRequest")
+}
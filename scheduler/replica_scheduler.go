@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -138,6 +139,14 @@ func (rcs *ReplicaScheduler) getDiskCandidates(nodeInfo map[string]*longhorn.Nod
 	if err != nil {
 		logrus.Errorf("error getting replica soft anti-affinity setting: %v", err)
 	}
+	// The per-volume override wins over the global setting unless it is
+	// "ignored" or unset.
+	switch volume.Spec.ReplicaSoftAntiAffinity {
+	case longhorn.ReplicaSoftAntiAffinityEnabled:
+		nodeSoftAntiAffinity = true
+	case longhorn.ReplicaSoftAntiAffinityDisabled:
+		nodeSoftAntiAffinity = false
+	}
 
 	zoneSoftAntiAffinity, err :=
 		rcs.ds.GetSettingAsBool(types.SettingNameReplicaZoneSoftAntiAffinity)
@@ -145,6 +154,21 @@ func (rcs *ReplicaScheduler) getDiskCandidates(nodeInfo map[string]*longhorn.Nod
 		logrus.Errorf("Error getting replica zone soft anti-affinity setting: %v", err)
 	}
 
+	topologyKeys := rcs.getSpreadTopologyKeys()
+	spreadLevels := len(topologyKeys)
+	if spreadLevels == 0 {
+		spreadLevels = 1
+	}
+	failureDomains := map[string][]string{}
+	getFailureDomains := func(node *longhorn.Node) []string {
+		domains, ok := failureDomains[node.Name]
+		if !ok {
+			domains = rcs.getNodeFailureDomains(node, topologyKeys)
+			failureDomains[node.Name] = domains
+		}
+		return domains
+	}
+
 	getDiskCandidatesFromNodes := func(nodes map[string]*longhorn.Node) map[string]*Disk {
 		for _, node := range nodes {
 			if diskCandidates := rcs.filterNodeDisksForReplica(node, nodeDisksMap[node.Name], replicas, volume, requireSchedulingCheck); len(diskCandidates) > 0 {
@@ -155,21 +179,38 @@ func (rcs *ReplicaScheduler) getDiskCandidates(nodeInfo map[string]*longhorn.Nod
 	}
 
 	usedNodes := map[string]*longhorn.Node{}
-	usedZones := map[string]bool{}
+	usedDomains := make([]map[string]bool, spreadLevels)
+	for level := range usedDomains {
+		usedDomains[level] = map[string]bool{}
+	}
 	replicasCountPerNode := map[string]int{}
-	// Get current nodes and zones
+	// Get current nodes and failure domains
 	for _, r := range replicas {
 		if r.Spec.NodeID != "" && r.DeletionTimestamp == nil && r.Spec.FailedAt == "" {
 			if node, ok := nodeInfo[r.Spec.NodeID]; ok {
 				usedNodes[r.Spec.NodeID] = node
 				// For empty zone label, we treat them as
 				// one zone.
-				usedZones[node.Status.Zone] = true
+				for level, domain := range getFailureDomains(node) {
+					usedDomains[level][domain] = true
+				}
 				replicasCountPerNode[r.Spec.NodeID] = replicasCountPerNode[r.Spec.NodeID] + 1
 			}
 		}
 	}
 
+	// getNoveltyLevel returns the index of the highest-priority topology
+	// level whose domain is not occupied by any existing replica yet, or
+	// spreadLevels when the node shares all the levels with a replica.
+	getNoveltyLevel := func(node *longhorn.Node) int {
+		for level, domain := range getFailureDomains(node) {
+			if !usedDomains[level][domain] {
+				return level
+			}
+		}
+		return spreadLevels
+	}
+
 	filterNodesWithLessThanTwoReplicas := func(nodes map[string]*longhorn.Node) map[string]*longhorn.Node {
 		result := map[string]*longhorn.Node{}
 		for nodeName, node := range nodes {
@@ -180,9 +221,16 @@ func (rcs *ReplicaScheduler) getDiskCandidates(nodeInfo map[string]*longhorn.Nod
 		return result
 	}
 
+	// Candidates in fresher failure domains come first: tier 0 holds the
+	// nodes whose highest-priority domain is still unused, deeper tiers
+	// only differ from the existing replicas at a lower-priority level.
 	unusedNodes := map[string]*longhorn.Node{}
-	unusedNodesInNewZones := map[string]*longhorn.Node{}
-	nodesInUnusedZones := map[string]*longhorn.Node{}
+	unusedNodesInNewDomains := make([]map[string]*longhorn.Node, spreadLevels)
+	nodesInUnusedDomains := make([]map[string]*longhorn.Node, spreadLevels)
+	for level := 0; level < spreadLevels; level++ {
+		unusedNodesInNewDomains[level] = map[string]*longhorn.Node{}
+		nodesInUnusedDomains[level] = map[string]*longhorn.Node{}
+	}
 	nodesWithEvictingReplicas := getNodesWithEvictingReplicas(replicas, nodeInfo)
 
 	for nodeName, node := range nodeInfo {
@@ -190,27 +238,37 @@ func (rcs *ReplicaScheduler) getDiskCandidates(nodeInfo map[string]*longhorn.Nod
 		if !rcs.checkTagsAreFulfilled(node.Spec.Tags, volume.Spec.NodeSelector) {
 			continue
 		}
+		level := getNoveltyLevel(node)
 		if _, ok := usedNodes[nodeName]; !ok {
 			unusedNodes[nodeName] = node
-			if _, ok := usedZones[node.Status.Zone]; !ok {
-				unusedNodesInNewZones[nodeName] = node
+			if level < spreadLevels {
+				unusedNodesInNewDomains[level][nodeName] = node
 			}
 		}
-		if _, ok := usedZones[node.Status.Zone]; !ok {
-			nodesInUnusedZones[nodeName] = node
+		if level < spreadLevels {
+			nodesInUnusedDomains[level][nodeName] = node
 		}
 	}
 
+	getDiskCandidatesFromTiers := func(tiers []map[string]*longhorn.Node) map[string]*Disk {
+		for _, nodes := range tiers {
+			if diskCandidates := getDiskCandidatesFromNodes(nodes); len(diskCandidates) > 0 {
+				return diskCandidates
+			}
+		}
+		return map[string]*Disk{}
+	}
+
 	switch {
 	case !zoneSoftAntiAffinity && !nodeSoftAntiAffinity:
-		if diskCandidates := getDiskCandidatesFromNodes(unusedNodesInNewZones); len(diskCandidates) > 0 {
+		if diskCandidates := getDiskCandidatesFromTiers(unusedNodesInNewDomains); len(diskCandidates) > 0 {
 			return diskCandidates
 		}
 		if diskCandidates := getDiskCandidatesFromNodes(filterNodesWithLessThanTwoReplicas(nodesWithEvictingReplicas)); len(diskCandidates) > 0 {
 			return diskCandidates
 		}
 	case zoneSoftAntiAffinity && !nodeSoftAntiAffinity:
-		if diskCandidates := getDiskCandidatesFromNodes(unusedNodesInNewZones); len(diskCandidates) > 0 {
+		if diskCandidates := getDiskCandidatesFromTiers(unusedNodesInNewDomains); len(diskCandidates) > 0 {
 			return diskCandidates
 		}
 		if diskCandidates := getDiskCandidatesFromNodes(unusedNodes); len(diskCandidates) > 0 {
@@ -220,17 +278,17 @@ func (rcs *ReplicaScheduler) getDiskCandidates(nodeInfo map[string]*longhorn.Nod
 			return diskCandidates
 		}
 	case !zoneSoftAntiAffinity && nodeSoftAntiAffinity:
-		if diskCandidates := getDiskCandidatesFromNodes(unusedNodesInNewZones); len(diskCandidates) > 0 {
+		if diskCandidates := getDiskCandidatesFromTiers(unusedNodesInNewDomains); len(diskCandidates) > 0 {
 			return diskCandidates
 		}
-		if diskCandidates := getDiskCandidatesFromNodes(nodesInUnusedZones); len(diskCandidates) > 0 {
+		if diskCandidates := getDiskCandidatesFromTiers(nodesInUnusedDomains); len(diskCandidates) > 0 {
 			return diskCandidates
 		}
 		if diskCandidates := getDiskCandidatesFromNodes(nodesWithEvictingReplicas); len(diskCandidates) > 0 {
 			return diskCandidates
 		}
 	case zoneSoftAntiAffinity && nodeSoftAntiAffinity:
-		if diskCandidates := getDiskCandidatesFromNodes(unusedNodesInNewZones); len(diskCandidates) > 0 {
+		if diskCandidates := getDiskCandidatesFromTiers(unusedNodesInNewDomains); len(diskCandidates) > 0 {
 			return diskCandidates
 		}
 		if diskCandidates := getDiskCandidatesFromNodes(unusedNodes); len(diskCandidates) > 0 {
@@ -243,6 +301,51 @@ func (rcs *ReplicaScheduler) getDiskCandidates(nodeInfo map[string]*longhorn.Nod
 	return map[string]*Disk{}
 }
 
+// getSpreadTopologyKeys returns the node label keys configured as failure
+// domain topology, in priority order, or nil when the spread logic should
+// keep using the Kubernetes zone.
+func (rcs *ReplicaScheduler) getSpreadTopologyKeys() []string {
+	value, err := rcs.ds.GetSettingValueExisted(types.SettingNameReplicaSpreadTopologyKeys)
+	if err != nil {
+		return nil
+	}
+	keys := []string{}
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// getNodeFailureDomains returns the failure domains the spread logic files
+// the node under, one per topology key in priority order. The domain at a
+// level is composed from the Kubernetes node labels of that key and all the
+// higher-priority keys, so two nodes only share the level when the whole
+// label chain down to it matches. Without topology keys the single domain is
+// the zone of the node.
+func (rcs *ReplicaScheduler) getNodeFailureDomains(node *longhorn.Node, topologyKeys []string) []string {
+	if len(topologyKeys) == 0 {
+		return []string{node.Status.Zone}
+	}
+	kubeNode, err := rcs.ds.GetKubernetesNode(node.Name)
+	if err != nil {
+		logrus.Errorf("Failed to get the Kubernetes node %v for the topology spread, falling back to the zone: %v", node.Name, err)
+		domains := make([]string, len(topologyKeys))
+		for level := range domains {
+			domains[level] = node.Status.Zone
+		}
+		return domains
+	}
+	domains := make([]string, len(topologyKeys))
+	values := make([]string, 0, len(topologyKeys))
+	for level, key := range topologyKeys {
+		values = append(values, kubeNode.Labels[key])
+		domains[level] = strings.Join(values, "/")
+	}
+	return domains
+}
+
 func (rcs *ReplicaScheduler) filterNodeDisksForReplica(node *longhorn.Node, disks map[string]struct{}, replicas map[string]*longhorn.Replica, volume *longhorn.Volume, requireSchedulingCheck bool) map[string]*Disk {
 	preferredDisk := map[string]*Disk{}
 	// find disk that fit for current replica
@@ -290,6 +393,24 @@ func (rcs *ReplicaScheduler) filterNodeDisksForReplica(node *longhorn.Node, disk
 			continue
 		}
 
+		// Check if the disk is a member of the requested disk group, and if the
+		// group as a whole still has room for the replica.
+		if volume.Spec.DiskGroup != "" {
+			if diskSpec.Group != volume.Spec.DiskGroup {
+				continue
+			}
+			if requireSchedulingCheck {
+				groupInfo, err := rcs.getDiskGroupSchedulingInfo(node, volume.Spec.DiskGroup)
+				if err != nil {
+					logrus.Errorf("Fail to get disk group scheduling info when scheduling replica: %v", err)
+					return preferredDisk
+				}
+				if !rcs.IsSchedulableToDisk(volume.Spec.Size, volume.Status.ActualSize, groupInfo) {
+					continue
+				}
+			}
+		}
+
 		suggestDisk := &Disk{
 			DiskSpec:   diskSpec,
 			DiskStatus: diskStatus,
@@ -301,6 +422,30 @@ func (rcs *ReplicaScheduler) filterNodeDisksForReplica(node *longhorn.Node, disk
 	return preferredDisk
 }
 
+// getDiskGroupSchedulingInfo aggregates the scheduling info of all disks on the
+// node belonging to the given disk group, so that the capacity of a storage
+// pool is accounted as a whole rather than per member disk.
+func (rcs *ReplicaScheduler) getDiskGroupSchedulingInfo(node *longhorn.Node, group string) (*DiskSchedulingInfo, error) {
+	groupInfo := &DiskSchedulingInfo{}
+	for fsid, diskStatus := range node.Status.DiskStatus {
+		diskSpec, exists := node.Spec.Disks[fsid]
+		if !exists || diskSpec.Group != group {
+			continue
+		}
+		info, err := rcs.GetDiskSchedulingInfo(diskSpec, diskStatus)
+		if err != nil {
+			return nil, err
+		}
+		groupInfo.StorageAvailable += info.StorageAvailable
+		groupInfo.StorageMaximum += info.StorageMaximum
+		groupInfo.StorageReserved += info.StorageReserved
+		groupInfo.StorageScheduled += info.StorageScheduled
+		groupInfo.OverProvisioningPercentage = info.OverProvisioningPercentage
+		groupInfo.MinimalAvailablePercentage = info.MinimalAvailablePercentage
+	}
+	return groupInfo, nil
+}
+
 func (rcs *ReplicaScheduler) checkTagsAreFulfilled(itemTags, volumeTags []string) bool {
 	if !sort.StringsAreSorted(itemTags) {
 		logrus.Warnf("BUG: Tags are not sorted, sort now")
@@ -627,6 +772,65 @@ func (rcs *ReplicaScheduler) CheckReplicasSizeExpansion(v *longhorn.Volume, oldS
 	return nil
 }
 
+// CheckVolumeSchedulable verifies that the replicas of a volume that has not
+// been created yet could currently be placed, without reserving anything. It
+// is used by the strict provisioning mode to fail volume creation early
+// instead of leaving an unschedulable volume behind.
+func (rcs *ReplicaScheduler) CheckVolumeSchedulable(volume *longhorn.Volume) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "error while CheckVolumeSchedulable for volume %v", volume.Name)
+	}()
+
+	nodesInfo, err := rcs.getNodeInfo()
+	if err != nil {
+		return err
+	}
+
+	schedulableNodes := 0
+	for _, node := range nodesInfo {
+		if !rcs.checkTagsAreFulfilled(node.Spec.Tags, volume.Spec.NodeSelector) {
+			continue
+		}
+		disks := map[string]struct{}{}
+		for fsid, diskStatus := range node.Status.DiskStatus {
+			diskSpec, exists := node.Spec.Disks[fsid]
+			if !exists {
+				continue
+			}
+			if !diskSpec.AllowScheduling || diskSpec.EvictionRequested {
+				continue
+			}
+			if types.GetCondition(diskStatus.Conditions, longhorn.DiskConditionTypeSchedulable).Status != longhorn.ConditionStatusTrue {
+				continue
+			}
+			disks[diskStatus.DiskUUID] = struct{}{}
+		}
+		if len(rcs.filterNodeDisksForReplica(node, disks, map[string]*longhorn.Replica{}, volume, true)) > 0 {
+			schedulableNodes++
+		}
+	}
+
+	nodeSoftAntiAffinity, err := rcs.ds.GetSettingAsBool(types.SettingNameReplicaSoftAntiAffinity)
+	if err != nil {
+		return err
+	}
+	switch volume.Spec.ReplicaSoftAntiAffinity {
+	case longhorn.ReplicaSoftAntiAffinityEnabled:
+		nodeSoftAntiAffinity = true
+	case longhorn.ReplicaSoftAntiAffinityDisabled:
+		nodeSoftAntiAffinity = false
+	}
+	requiredNodes := volume.Spec.NumberOfReplicas
+	if nodeSoftAntiAffinity && requiredNodes > 1 {
+		requiredNodes = 1
+	}
+
+	if schedulableNodes < requiredNodes {
+		return fmt.Errorf("only %v node(s) can currently accommodate a replica of %v bytes, but %v are required", schedulableNodes, volume.Spec.Size, requiredNodes)
+	}
+	return nil
+}
+
 func findDiskSpecAndDiskStatusInNode(diskUUID string, node *longhorn.Node) (longhorn.DiskSpec, longhorn.DiskStatus, bool) {
 	for diskName, diskStatus := range node.Status.DiskStatus {
 		if diskStatus.DiskUUID == diskUUID {
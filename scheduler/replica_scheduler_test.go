@@ -734,3 +734,121 @@ func (s *TestSuite) TestReplicaScheduler(c *C) {
 		c.Assert(len(tc.expectedNodes), Equals, 0)
 	}
 }
+
+func (s *TestSuite) TestReplicaSchedulerTopologySpread(c *C) {
+	kubeClient := fake.NewSimpleClientset()
+	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+
+	lhClient := lhfake.NewSimpleClientset()
+	lhInformerFactory := lhinformerfactory.NewSharedInformerFactory(lhClient, controller.NoResyncPeriodFunc())
+
+	vIndexer := lhInformerFactory.Longhorn().V1beta2().Volumes().Informer().GetIndexer()
+	rIndexer := lhInformerFactory.Longhorn().V1beta2().Replicas().Informer().GetIndexer()
+	nIndexer := lhInformerFactory.Longhorn().V1beta2().Nodes().Informer().GetIndexer()
+	eiIndexer := lhInformerFactory.Longhorn().V1beta2().EngineImages().Informer().GetIndexer()
+	sIndexer := lhInformerFactory.Longhorn().V1beta2().Settings().Informer().GetIndexer()
+	pIndexer := kubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	knIndexer := kubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer()
+
+	sched := newReplicaScheduler(lhInformerFactory, kubeInformerFactory, lhClient, kubeClient)
+
+	rackKey := "topology.longhorn.io/rack"
+	chassisKey := "topology.longhorn.io/chassis"
+	topology := map[string]map[string]string{
+		TestNode1: {rackKey: "rack-1", chassisKey: "chassis-1"},
+		TestNode2: {rackKey: "rack-1", chassisKey: "chassis-2"},
+		TestNode3: {rackKey: "rack-2", chassisKey: "chassis-1"},
+	}
+	daemons := map[string]*v1.Pod{
+		TestNode1: newDaemonPod(v1.PodRunning, TestDaemon1, TestNamespace, TestNode1, TestIP1),
+		TestNode2: newDaemonPod(v1.PodRunning, TestDaemon2, TestNamespace, TestNode2, TestIP2),
+		TestNode3: newDaemonPod(v1.PodRunning, TestDaemon3, TestNamespace, TestNode3, TestIP3),
+	}
+	engineImage := newEngineImage(TestEngineImage, longhorn.EngineImageStateDeployed)
+	for nodeName, labels := range topology {
+		p, err := kubeClient.CoreV1().Pods(TestNamespace).Create(context.TODO(), daemons[nodeName], metav1.CreateOptions{})
+		c.Assert(err, IsNil)
+		err = pIndexer.Add(p)
+		c.Assert(err, IsNil)
+
+		node := newNode(nodeName, TestNamespace, true, longhorn.ConditionStatusTrue)
+		node.Spec.Disks = map[string]longhorn.DiskSpec{
+			getDiskID(nodeName, "1"): newDisk(TestDefaultDataPath, true, 0),
+		}
+		node.Status.DiskStatus = map[string]*longhorn.DiskStatus{
+			getDiskID(nodeName, "1"): {
+				StorageAvailable: TestDiskAvailableSize,
+				StorageScheduled: 0,
+				StorageMaximum:   TestDiskSize,
+				Conditions: []longhorn.Condition{
+					newCondition(longhorn.DiskConditionTypeSchedulable, longhorn.ConditionStatusTrue),
+				},
+				DiskUUID: getDiskID(nodeName, "1"),
+			},
+		}
+		engineImage.Status.NodeDeploymentMap[nodeName] = true
+		n, err := lhClient.LonghornV1beta2().Nodes(TestNamespace).Create(context.TODO(), node, metav1.CreateOptions{})
+		c.Assert(err, IsNil)
+		err = nIndexer.Add(n)
+		c.Assert(err, IsNil)
+
+		kubeNode := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   nodeName,
+				Labels: labels,
+			},
+		}
+		kn, err := kubeClient.CoreV1().Nodes().Create(context.TODO(), kubeNode, metav1.CreateOptions{})
+		c.Assert(err, IsNil)
+		err = knIndexer.Add(kn)
+		c.Assert(err, IsNil)
+	}
+	ei, err := lhClient.LonghornV1beta2().EngineImages(TestNamespace).Create(context.TODO(), engineImage, metav1.CreateOptions{})
+	c.Assert(err, IsNil)
+	err = eiIndexer.Add(ei)
+	c.Assert(err, IsNil)
+
+	setting := initSettings(string(types.SettingNameReplicaSpreadTopologyKeys), rackKey+","+chassisKey)
+	st, err := lhClient.LonghornV1beta2().Settings(TestNamespace).Create(context.TODO(), setting, metav1.CreateOptions{})
+	c.Assert(err, IsNil)
+	err = sIndexer.Add(st)
+	c.Assert(err, IsNil)
+
+	v := newVolume(TestVolumeName, 3)
+	volume, err := lhClient.LonghornV1beta2().Volumes(TestNamespace).Create(context.TODO(), v, metav1.CreateOptions{})
+	c.Assert(err, IsNil)
+	err = vIndexer.Add(volume)
+	c.Assert(err, IsNil)
+
+	createReplica := func() *longhorn.Replica {
+		replica := newReplicaForVolume(volume)
+		r, err := lhClient.LonghornV1beta2().Replicas(TestNamespace).Create(context.TODO(), replica, metav1.CreateOptions{})
+		c.Assert(err, IsNil)
+		err = rIndexer.Add(r)
+		c.Assert(err, IsNil)
+		return r
+	}
+
+	// The first replica already sits on node1, i.e. rack-1/chassis-1.
+	existing := createReplica()
+	existing.Spec.NodeID = TestNode1
+	replicas := map[string]*longhorn.Replica{existing.Name: existing}
+
+	// The second replica must land in the unused rack, not merely on an
+	// unused chassis within rack-1.
+	replica := createReplica()
+	replicas[replica.Name] = replica
+	sr, err := sched.ScheduleReplica(replica, replicas, volume)
+	c.Assert(err, IsNil)
+	c.Assert(sr, NotNil)
+	c.Assert(sr.Spec.NodeID, Equals, TestNode3)
+	replicas[sr.Name] = sr
+
+	// With both racks occupied the spread falls back to the unused chassis.
+	replica = createReplica()
+	replicas[replica.Name] = replica
+	sr, err = sched.ScheduleReplica(replica, replicas, volume)
+	c.Assert(err, IsNil)
+	c.Assert(sr, NotNil)
+	c.Assert(sr.Spec.NodeID, Equals, TestNode2)
+}
@@ -0,0 +1,58 @@
+package types
+
+// ErrorCode is a machine-readable identifier for a class of failures. It is
+// returned in API error bodies and can be used as the reason of CR conditions,
+// so automation and UIs can branch on the error type instead of parsing the
+// English message.
+type ErrorCode string
+
+const (
+	ErrorCodeNotFound        = ErrorCode("NOT_FOUND")
+	ErrorCodeConflict        = ErrorCode("CONFLICT")
+	ErrorCodeInvalidArgument = ErrorCode("INVALID_ARGUMENT")
+
+	ErrorCodeSchedulingFailed        = ErrorCode("SCHEDULING_FAILED")
+	ErrorCodeEngineTimeout           = ErrorCode("ENGINE_TIMEOUT")
+	ErrorCodeBackupTargetUnreachable = ErrorCode("BACKUP_TARGET_UNREACHABLE")
+	ErrorCodeVolumeFaulted           = ErrorCode("VOLUME_FAULTED")
+)
+
+// CodedError attaches an ErrorCode to an underlying error so the API layer can
+// surface the code while keeping the original message.
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+// Cause exposes the underlying error for github.com/pkg/errors.Cause.
+func (e *CodedError) Cause() error {
+	return e.Err
+}
+
+// NewCodedError wraps err with the given machine-readable code.
+func NewCodedError(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// GetErrorCode returns the ErrorCode attached anywhere in the error chain, or
+// an empty string when the error carries no code.
+func GetErrorCode(err error) ErrorCode {
+	for err != nil {
+		if coded, ok := err.(*CodedError); ok {
+			return coded.Code
+		}
+		causer, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = causer.Cause()
+	}
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -32,6 +33,7 @@ const (
 	SettingNameCreateDefaultDiskLabeledNodes                = SettingName("create-default-disk-labeled-nodes")
 	SettingNameDefaultDataPath                              = SettingName("default-data-path")
 	SettingNameDefaultEngineImage                           = SettingName("default-engine-image")
+	SettingNameDefaultEngineImageReadinessPercentage        = SettingName("default-engine-image-readiness-percentage")
 	SettingNameDefaultInstanceManagerImage                  = SettingName("default-instance-manager-image")
 	SettingNameDefaultShareManagerImage                     = SettingName("default-share-manager-image")
 	SettingNameDefaultBackingImageManagerImage              = SettingName("default-backing-image-manager-image")
@@ -40,6 +42,7 @@ const (
 	SettingNameStorageOverProvisioningPercentage            = SettingName("storage-over-provisioning-percentage")
 	SettingNameStorageMinimalAvailablePercentage            = SettingName("storage-minimal-available-percentage")
 	SettingNameUpgradeChecker                               = SettingName("upgrade-checker")
+	SettingNameCurrentLonghornVersion                       = SettingName("current-longhorn-version")
 	SettingNameLatestLonghornVersion                        = SettingName("latest-longhorn-version")
 	SettingNameStableLonghornVersions                       = SettingName("stable-longhorn-versions")
 	SettingNameDefaultReplicaCount                          = SettingName("default-replica-count")
@@ -52,25 +55,53 @@ const (
 	SettingNameCRDAPIVersion                                = SettingName("crd-api-version")
 	SettingNameAutoSalvage                                  = SettingName("auto-salvage")
 	SettingNameAutoDeletePodWhenVolumeDetachedUnexpectedly  = SettingName("auto-delete-pod-when-volume-detached-unexpectedly")
+	SettingNameAutoCleanupStaleVolumeAttachment             = SettingName("auto-cleanup-stale-volume-attachment")
 	SettingNameRegistrySecret                               = SettingName("registry-secret")
 	SettingNameDisableSchedulingOnCordonedNode              = SettingName("disable-scheduling-on-cordoned-node")
 	SettingNameReplicaZoneSoftAntiAffinity                  = SettingName("replica-zone-soft-anti-affinity")
+	SettingNameReplicaSpreadTopologyKeys                    = SettingName("replica-spread-topology-keys")
+	SettingNameAPIAuthenticationSecret                      = SettingName("api-authentication-secret")
+	SettingNameTLSCertificateSecret                         = SettingName("tls-certificate-secret")
+	SettingNameSnapshotDiskUsageCriticalPercentage          = SettingName("snapshot-disk-usage-critical-percentage")
 	SettingNameNodeDownPodDeletionPolicy                    = SettingName("node-down-pod-deletion-policy")
 	SettingNameAllowNodeDrainWithLastHealthyReplica         = SettingName("allow-node-drain-with-last-healthy-replica")
+	SettingNameNodeDrainPolicy                              = SettingName("node-drain-policy")
 	SettingNameMkfsExt4Parameters                           = SettingName("mkfs-ext4-parameters")
 	SettingNamePriorityClass                                = SettingName("priority-class")
+	SettingNameLeaderElectionLeaseDuration                  = SettingName("leader-election-lease-duration")
+	SettingNameLeaderElectionRenewDeadline                  = SettingName("leader-election-renew-deadline")
+	SettingNameLeaderElectionRetryPeriod                    = SettingName("leader-election-retry-period")
+	SettingNameAPIPort                                      = SettingName("api-port")
+	SettingNameCSIPluginHostNetwork                         = SettingName("csi-plugin-host-network")
 	SettingNameDisableRevisionCounter                       = SettingName("disable-revision-counter")
 	SettingNameDisableReplicaRebuild                        = SettingName("disable-replica-rebuild")
 	SettingNameReplicaReplenishmentWaitInterval             = SettingName("replica-replenishment-wait-interval")
+	SettingNameReplicaCleanupGracePeriod                    = SettingName("replica-cleanup-grace-period")
 	SettingNameConcurrentReplicaRebuildPerNodeLimit         = SettingName("concurrent-replica-rebuild-per-node-limit")
+	SettingNameReplicaRebuildWindow                         = SettingName("replica-rebuild-window")
 	SettingNameSystemManagedPodsImagePullPolicy             = SettingName("system-managed-pods-image-pull-policy")
 	SettingNameAllowVolumeCreationWithDegradedAvailability  = SettingName("allow-volume-creation-with-degraded-availability")
 	SettingNameAutoCleanupSystemGeneratedSnapshot           = SettingName("auto-cleanup-system-generated-snapshot")
+	SettingNameAutoCleanupSystemGeneratedSnapshotDelay      = SettingName("auto-cleanup-system-generated-snapshot-delay")
+	SettingNameRemoveSnapshotsDuringFilesystemTrim          = SettingName("remove-snapshots-during-filesystem-trim")
 	SettingNameConcurrentAutomaticEngineUpgradePerNodeLimit = SettingName("concurrent-automatic-engine-upgrade-per-node-limit")
 	SettingNameBackingImageCleanupWaitInterval              = SettingName("backing-image-cleanup-wait-interval")
+	SettingNameEngineImageCleanupWaitInterval               = SettingName("engine-image-cleanup-wait-interval")
 	SettingNameBackingImageRecoveryWaitInterval             = SettingName("backing-image-recovery-wait-interval")
 	SettingNameGuaranteedEngineManagerCPU                   = SettingName("guaranteed-engine-manager-cpu")
 	SettingNameGuaranteedReplicaManagerCPU                  = SettingName("guaranteed-replica-manager-cpu")
+	SettingNameDisabledControllers                          = SettingName("disabled-controllers")
+	SettingNameInstanceDriftAutoCorrection                  = SettingName("instance-drift-auto-correction")
+	SettingNameAutoSnapshotBeforeRiskyOperation             = SettingName("auto-snapshot-before-risky-operation")
+	SettingNameAutoSnapshotCleanupWaitInterval              = SettingName("auto-snapshot-cleanup-wait-interval")
+	SettingNameFastReplicaRebuildEnabled                    = SettingName("fast-replica-rebuild-enabled")
+	SettingNameOfflineReplicaRebuilding                     = SettingName("offline-replica-rebuilding")
+	SettingNameBackupCompressionMethod                      = SettingName("backup-compression-method")
+	SettingNameBackupCompressionLevel                       = SettingName("backup-compression-level")
+	SettingNameBackupUploadConcurrency                      = SettingName("backup-upload-concurrency")
+	SettingNameBackupUploadPartSize                         = SettingName("backup-upload-part-size")
+	SettingNameBackupUploadMaxRetries                       = SettingName("backup-upload-max-retries")
+	SettingNameStorageNetwork                               = SettingName("storage-network")
 )
 
 var (
@@ -81,6 +112,7 @@ var (
 		SettingNameCreateDefaultDiskLabeledNodes,
 		SettingNameDefaultDataPath,
 		SettingNameDefaultEngineImage,
+		SettingNameDefaultEngineImageReadinessPercentage,
 		SettingNameDefaultInstanceManagerImage,
 		SettingNameDefaultShareManagerImage,
 		SettingNameDefaultBackingImageManagerImage,
@@ -89,6 +121,7 @@ var (
 		SettingNameStorageOverProvisioningPercentage,
 		SettingNameStorageMinimalAvailablePercentage,
 		SettingNameUpgradeChecker,
+		SettingNameCurrentLonghornVersion,
 		SettingNameLatestLonghornVersion,
 		SettingNameStableLonghornVersions,
 		SettingNameDefaultReplicaCount,
@@ -101,25 +134,53 @@ var (
 		SettingNameCRDAPIVersion,
 		SettingNameAutoSalvage,
 		SettingNameAutoDeletePodWhenVolumeDetachedUnexpectedly,
+		SettingNameAutoCleanupStaleVolumeAttachment,
 		SettingNameRegistrySecret,
 		SettingNameDisableSchedulingOnCordonedNode,
 		SettingNameReplicaZoneSoftAntiAffinity,
+		SettingNameReplicaSpreadTopologyKeys,
+		SettingNameAPIAuthenticationSecret,
+		SettingNameTLSCertificateSecret,
+		SettingNameSnapshotDiskUsageCriticalPercentage,
 		SettingNameNodeDownPodDeletionPolicy,
 		SettingNameAllowNodeDrainWithLastHealthyReplica,
+		SettingNameNodeDrainPolicy,
 		SettingNameMkfsExt4Parameters,
 		SettingNamePriorityClass,
+		SettingNameLeaderElectionLeaseDuration,
+		SettingNameLeaderElectionRenewDeadline,
+		SettingNameLeaderElectionRetryPeriod,
+		SettingNameAPIPort,
+		SettingNameCSIPluginHostNetwork,
 		SettingNameDisableRevisionCounter,
 		SettingNameDisableReplicaRebuild,
 		SettingNameReplicaReplenishmentWaitInterval,
+		SettingNameReplicaCleanupGracePeriod,
 		SettingNameConcurrentReplicaRebuildPerNodeLimit,
+		SettingNameReplicaRebuildWindow,
 		SettingNameSystemManagedPodsImagePullPolicy,
 		SettingNameAllowVolumeCreationWithDegradedAvailability,
 		SettingNameAutoCleanupSystemGeneratedSnapshot,
+		SettingNameAutoCleanupSystemGeneratedSnapshotDelay,
+		SettingNameRemoveSnapshotsDuringFilesystemTrim,
 		SettingNameConcurrentAutomaticEngineUpgradePerNodeLimit,
 		SettingNameBackingImageCleanupWaitInterval,
+		SettingNameEngineImageCleanupWaitInterval,
 		SettingNameBackingImageRecoveryWaitInterval,
 		SettingNameGuaranteedEngineManagerCPU,
 		SettingNameGuaranteedReplicaManagerCPU,
+		SettingNameDisabledControllers,
+		SettingNameInstanceDriftAutoCorrection,
+		SettingNameAutoSnapshotBeforeRiskyOperation,
+		SettingNameAutoSnapshotCleanupWaitInterval,
+		SettingNameFastReplicaRebuildEnabled,
+		SettingNameOfflineReplicaRebuilding,
+		SettingNameBackupCompressionMethod,
+		SettingNameBackupCompressionLevel,
+		SettingNameBackupUploadConcurrency,
+		SettingNameBackupUploadPartSize,
+		SettingNameBackupUploadMaxRetries,
+		SettingNameStorageNetwork,
 	}
 )
 
@@ -140,7 +201,13 @@ type SettingDefinition struct {
 	Required    bool            `json:"required"`
 	ReadOnly    bool            `json:"readOnly"`
 	Default     string          `json:"default"`
-	Choices     []string        `json:"options,omitempty"` // +optional
+	Choices     []string        `json:"options,omitempty"`  // +optional
+	MinValue    *int64          `json:"minValue,omitempty"` // +optional, for int settings
+	MaxValue    *int64          `json:"maxValue,omitempty"` // +optional, for int settings
+}
+
+func settingBound(v int64) *int64 {
+	return &v
 }
 
 var (
@@ -151,6 +218,7 @@ var (
 		SettingNameCreateDefaultDiskLabeledNodes:                SettingDefinitionCreateDefaultDiskLabeledNodes,
 		SettingNameDefaultDataPath:                              SettingDefinitionDefaultDataPath,
 		SettingNameDefaultEngineImage:                           SettingDefinitionDefaultEngineImage,
+		SettingNameDefaultEngineImageReadinessPercentage:        SettingDefinitionDefaultEngineImageReadinessPercentage,
 		SettingNameDefaultInstanceManagerImage:                  SettingDefinitionDefaultInstanceManagerImage,
 		SettingNameDefaultShareManagerImage:                     SettingDefinitionDefaultShareManagerImage,
 		SettingNameDefaultBackingImageManagerImage:              SettingDefinitionDefaultBackingImageManagerImage,
@@ -159,6 +227,7 @@ var (
 		SettingNameStorageOverProvisioningPercentage:            SettingDefinitionStorageOverProvisioningPercentage,
 		SettingNameStorageMinimalAvailablePercentage:            SettingDefinitionStorageMinimalAvailablePercentage,
 		SettingNameUpgradeChecker:                               SettingDefinitionUpgradeChecker,
+		SettingNameCurrentLonghornVersion:                       SettingDefinitionCurrentLonghornVersion,
 		SettingNameLatestLonghornVersion:                        SettingDefinitionLatestLonghornVersion,
 		SettingNameStableLonghornVersions:                       SettingDefinitionStableLonghornVersions,
 		SettingNameDefaultReplicaCount:                          SettingDefinitionDefaultReplicaCount,
@@ -171,25 +240,53 @@ var (
 		SettingNameCRDAPIVersion:                                SettingDefinitionCRDAPIVersion,
 		SettingNameAutoSalvage:                                  SettingDefinitionAutoSalvage,
 		SettingNameAutoDeletePodWhenVolumeDetachedUnexpectedly:  SettingDefinitionAutoDeletePodWhenVolumeDetachedUnexpectedly,
+		SettingNameAutoCleanupStaleVolumeAttachment:             SettingDefinitionAutoCleanupStaleVolumeAttachment,
 		SettingNameRegistrySecret:                               SettingDefinitionRegistrySecret,
 		SettingNameDisableSchedulingOnCordonedNode:              SettingDefinitionDisableSchedulingOnCordonedNode,
 		SettingNameReplicaZoneSoftAntiAffinity:                  SettingDefinitionReplicaZoneSoftAntiAffinity,
+		SettingNameReplicaSpreadTopologyKeys:                    SettingDefinitionReplicaSpreadTopologyKeys,
+		SettingNameAPIAuthenticationSecret:                      SettingDefinitionAPIAuthenticationSecret,
+		SettingNameTLSCertificateSecret:                         SettingDefinitionTLSCertificateSecret,
+		SettingNameSnapshotDiskUsageCriticalPercentage:          SettingDefinitionSnapshotDiskUsageCriticalPercentage,
 		SettingNameNodeDownPodDeletionPolicy:                    SettingDefinitionNodeDownPodDeletionPolicy,
 		SettingNameAllowNodeDrainWithLastHealthyReplica:         SettingDefinitionAllowNodeDrainWithLastHealthyReplica,
+		SettingNameNodeDrainPolicy:                              SettingDefinitionNodeDrainPolicy,
 		SettingNameMkfsExt4Parameters:                           SettingDefinitionMkfsExt4Parameters,
 		SettingNamePriorityClass:                                SettingDefinitionPriorityClass,
+		SettingNameLeaderElectionLeaseDuration:                  SettingDefinitionLeaderElectionLeaseDuration,
+		SettingNameLeaderElectionRenewDeadline:                  SettingDefinitionLeaderElectionRenewDeadline,
+		SettingNameLeaderElectionRetryPeriod:                    SettingDefinitionLeaderElectionRetryPeriod,
+		SettingNameAPIPort:                                      SettingDefinitionAPIPort,
+		SettingNameCSIPluginHostNetwork:                         SettingDefinitionCSIPluginHostNetwork,
 		SettingNameDisableRevisionCounter:                       SettingDefinitionDisableRevisionCounter,
 		SettingNameDisableReplicaRebuild:                        SettingDefinitionDisableReplicaRebuild,
 		SettingNameReplicaReplenishmentWaitInterval:             SettingDefinitionReplicaReplenishmentWaitInterval,
+		SettingNameReplicaCleanupGracePeriod:                    SettingDefinitionReplicaCleanupGracePeriod,
 		SettingNameConcurrentReplicaRebuildPerNodeLimit:         SettingDefinitionConcurrentReplicaRebuildPerNodeLimit,
+		SettingNameReplicaRebuildWindow:                         SettingDefinitionReplicaRebuildWindow,
 		SettingNameSystemManagedPodsImagePullPolicy:             SettingDefinitionSystemManagedPodsImagePullPolicy,
 		SettingNameAllowVolumeCreationWithDegradedAvailability:  SettingDefinitionAllowVolumeCreationWithDegradedAvailability,
 		SettingNameAutoCleanupSystemGeneratedSnapshot:           SettingDefinitionAutoCleanupSystemGeneratedSnapshot,
+		SettingNameAutoCleanupSystemGeneratedSnapshotDelay:      SettingDefinitionAutoCleanupSystemGeneratedSnapshotDelay,
+		SettingNameRemoveSnapshotsDuringFilesystemTrim:          SettingDefinitionRemoveSnapshotsDuringFilesystemTrim,
 		SettingNameConcurrentAutomaticEngineUpgradePerNodeLimit: SettingDefinitionConcurrentAutomaticEngineUpgradePerNodeLimit,
 		SettingNameBackingImageCleanupWaitInterval:              SettingDefinitionBackingImageCleanupWaitInterval,
+		SettingNameEngineImageCleanupWaitInterval:               SettingDefinitionEngineImageCleanupWaitInterval,
 		SettingNameBackingImageRecoveryWaitInterval:             SettingDefinitionBackingImageRecoveryWaitInterval,
 		SettingNameGuaranteedEngineManagerCPU:                   SettingDefinitionGuaranteedEngineManagerCPU,
 		SettingNameGuaranteedReplicaManagerCPU:                  SettingDefinitionGuaranteedReplicaManagerCPU,
+		SettingNameDisabledControllers:                          SettingDefinitionDisabledControllers,
+		SettingNameInstanceDriftAutoCorrection:                  SettingDefinitionInstanceDriftAutoCorrection,
+		SettingNameAutoSnapshotBeforeRiskyOperation:             SettingDefinitionAutoSnapshotBeforeRiskyOperation,
+		SettingNameAutoSnapshotCleanupWaitInterval:              SettingDefinitionAutoSnapshotCleanupWaitInterval,
+		SettingNameFastReplicaRebuildEnabled:                    SettingDefinitionFastReplicaRebuildEnabled,
+		SettingNameOfflineReplicaRebuilding:                     SettingDefinitionOfflineReplicaRebuilding,
+		SettingNameBackupCompressionMethod:                      SettingDefinitionBackupCompressionMethod,
+		SettingNameBackupCompressionLevel:                       SettingDefinitionBackupCompressionLevel,
+		SettingNameBackupUploadConcurrency:                      SettingDefinitionBackupUploadConcurrency,
+		SettingNameBackupUploadPartSize:                         SettingDefinitionBackupUploadPartSize,
+		SettingNameBackupUploadMaxRetries:                       SettingDefinitionBackupUploadMaxRetries,
+		SettingNameStorageNetwork:                               SettingDefinitionStorageNetwork,
 	}
 
 	SettingDefinitionBackupTarget = SettingDefinition{
@@ -263,6 +360,18 @@ var (
 		ReadOnly:    true,
 	}
 
+	SettingDefinitionDefaultEngineImageReadinessPercentage = SettingDefinition{
+		DisplayName: "Default Engine Image Readiness Percentage",
+		Description: "New volumes can only be created once the default engine image has been deployed on at least this percentage of the ready nodes. This prevents creating volumes with an engine image that is still being pulled on most nodes after the default engine image changed. Set to 0 to disable the readiness gate.",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeInt,
+		Required:    true,
+		ReadOnly:    false,
+		Default:     "100",
+		MinValue:    settingBound(0),
+		MaxValue:    settingBound(100),
+	}
+
 	SettingDefinitionDefaultInstanceManagerImage = SettingDefinition{
 		DisplayName: "Default Instance Manager Image",
 		Description: "The default instance manager image used by the manager. Can be changed on the manager starting command line only",
@@ -343,6 +452,20 @@ var (
 		Required:    true,
 		ReadOnly:    false,
 		Default:     "25",
+		MinValue:    settingBound(0),
+		MaxValue:    settingBound(100),
+	}
+
+	SettingDefinitionSnapshotDiskUsageCriticalPercentage = SettingDefinition{
+		DisplayName: "Snapshot Disk Usage Critical Percentage",
+		Description: "Refuse to create new snapshots or backups for a volume when a disk hosting one of its replicas is used above this percentage, since every new snapshot grows the space the replica can consume. Set to 0 to disable the guardrail.",
+		Category:    SettingCategoryScheduling,
+		Type:        SettingTypeInt,
+		Required:    true,
+		ReadOnly:    false,
+		Default:     "90",
+		MinValue:    settingBound(0),
+		MaxValue:    settingBound(100),
 	}
 
 	SettingDefinitionUpgradeChecker = SettingDefinition{
@@ -355,6 +478,15 @@ var (
 		Default:     "true",
 	}
 
+	SettingDefinitionCurrentLonghornVersion = SettingDefinition{
+		DisplayName: "Current Longhorn Version",
+		Description: "The deployed Longhorn version. Set by manager automatically and used to validate the upgrade path",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeString,
+		Required:    false,
+		ReadOnly:    true,
+	}
+
 	SettingDefinitionLatestLonghornVersion = SettingDefinition{
 		DisplayName: "Latest Longhorn Version",
 		Description: "The latest version of Longhorn available. Updated by Upgrade Checker automatically",
@@ -381,6 +513,8 @@ var (
 		Required:    true,
 		ReadOnly:    false,
 		Default:     "3",
+		MinValue:    settingBound(1),
+		MaxValue:    settingBound(20),
 	}
 
 	SettingDefinitionDefaultDataLocality = SettingDefinition{
@@ -493,6 +627,18 @@ var (
 		Default:  "true",
 	}
 
+	SettingDefinitionAutoCleanupStaleVolumeAttachment = SettingDefinition{
+		DisplayName: "Automatically Clean up Stale Kubernetes Volume Attachments",
+		Description: "If enabled, Longhorn will automatically delete the Kubernetes VolumeAttachment objects of Longhorn volumes that still reference a node removed from the cluster (e.g. after a forced node removal). " +
+			"Without the cleanup, the stale VolumeAttachment blocks Kubernetes from attaching the volume to a new node and the workload pods are stuck in Multi-Attach errors. \n\n" +
+			"If disabled, you will have to delete the stale VolumeAttachment objects manually.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeBool,
+		Required: true,
+		ReadOnly: false,
+		Default:  "true",
+	}
+
 	SettingDefinitionRegistrySecret = SettingDefinition{
 		DisplayName: "Registry secret",
 		Description: "The Kubernetes Secret name",
@@ -520,6 +666,34 @@ var (
 		ReadOnly:    false,
 		Default:     "true",
 	}
+	SettingDefinitionReplicaSpreadTopologyKeys = SettingDefinition{
+		DisplayName: "Replica Spread Topology Keys",
+		Description: "Comma separated list of node label keys, in priority order, that define the failure domains used by the replica zone spread logic instead of the Kubernetes zone label. For example \"topology.longhorn.io/rack,topology.longhorn.io/chassis\" spreads replicas across racks first and chassis within a rack second. Leave it empty to keep spreading across Kubernetes zones.",
+		Category:    SettingCategoryScheduling,
+		Type:        SettingTypeString,
+		Required:    false,
+		ReadOnly:    false,
+		Default:     "",
+	}
+	SettingDefinitionAPIAuthenticationSecret = SettingDefinition{
+		DisplayName: "API Authentication Secret",
+		Description: "Name of a secret in the Longhorn namespace holding the bearer tokens accepted by the manager REST API. The keys \"admin-tokens\" and \"read-only-tokens\" each hold a newline separated token list, for example ServiceAccount tokens. Read-only tokens can only perform GET requests. Leave it empty to keep the API unauthenticated.",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeString,
+		Required:    false,
+		ReadOnly:    false,
+		Default:     "",
+	}
+	SettingDefinitionTLSCertificateSecret = SettingDefinition{
+		DisplayName: "TLS Certificate Secret",
+		Description: "Name of a kubernetes.io/tls secret in the Longhorn namespace used by the HTTPS endpoints Longhorn serves itself, currently the admission and conversion webhook servers. The certificate is validated against the service name and reloaded automatically when the secret is rotated. Leave it empty to keep using the certificate files shipped with the deployment.",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeString,
+		Required:    false,
+		ReadOnly:    false,
+		Default:     "",
+	}
+
 	SettingDefinitionNodeDownPodDeletionPolicy = SettingDefinition{
 		DisplayName: "Pod Deletion Policy When Node is Down",
 		Description: "Defines the Longhorn action when a Volume is stuck with a StatefulSet/Deployment Pod on a node that is down.\n" +
@@ -543,7 +717,8 @@ var (
 	SettingDefinitionAllowNodeDrainWithLastHealthyReplica = SettingDefinition{
 		DisplayName: "Allow Node Drain with the Last Healthy Replica",
 		Description: "By default, Longhorn will block `kubectl drain` action on a node if the node contains the last healthy replica of a volume.\n\n" +
-			"If this setting is enabled, Longhorn will **not** block `kubectl drain` action on a node even if the node contains the last healthy replica of a volume.",
+			"If this setting is enabled, Longhorn will **not** block `kubectl drain` action on a node even if the node contains the last healthy replica of a volume.\n\n" +
+			"DEPRECATED: use the setting `node-drain-policy` instead. Enabling this setting has the same effect as the policy `always-allow`.",
 		Category: SettingCategoryGeneral,
 		Type:     SettingTypeBool,
 		Required: true,
@@ -551,6 +726,24 @@ var (
 		Default:  "false",
 	}
 
+	SettingDefinitionNodeDrainPolicy = SettingDefinition{
+		DisplayName: "Node Drain Policy",
+		Description: "Defines the Longhorn policy when a node is being drained. \n" +
+			"- **block-if-contains-last-replica** Longhorn will block the drain when the node contains the last healthy replica of a volume.\n" +
+			"- **allow-if-replica-is-stopped** Longhorn will allow the drain when all the replicas on the node are stopped, even if one of them is the last healthy replica of a volume. Use this option if you want to drain the node for the short maintenance like a kernel upgrade, and the data on the node won't be lost during the maintenance.\n" +
+			"- **always-allow** Longhorn will always allow the drain. Use this option with caution since the data on the node might be lost.\n",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeString,
+		Required: true,
+		ReadOnly: false,
+		Default:  string(NodeDrainPolicyBlockIfContainsLastReplica),
+		Choices: []string{
+			string(NodeDrainPolicyBlockIfContainsLastReplica),
+			string(NodeDrainPolicyAllowIfReplicaIsStopped),
+			string(NodeDrainPolicyAlwaysAllow),
+		},
+	}
+
 	SettingDefinitionMkfsExt4Parameters = SettingDefinition{
 		DisplayName: "Custom mkfs.ext4 parameters",
 		Description: "Allows setting additional filesystem creation parameters for ext4. For older host kernels it might be necessary to disable the optional ext4 metadata_csum feature by specifying `-O ^64bit,^metadata_csum`",
@@ -570,6 +763,60 @@ var (
 		Required: false,
 		ReadOnly: false,
 	}
+	SettingDefinitionLeaderElectionLeaseDuration = SettingDefinition{
+		DisplayName: "Leader Election Lease Duration",
+		Description: "The duration in seconds that the leaders of the system managed components (e.g. the CSI attacher and provisioner) hold their leases before a standby replica can take over. " +
+			"A lower value reduces the time window where the component is without a leader after a node failure, at the cost of more API server load. " +
+			"This setting only takes effect the next time the Longhorn driver deployer starts.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeInt,
+		Required: true,
+		ReadOnly: false,
+		Default:  "15",
+	}
+	SettingDefinitionLeaderElectionRenewDeadline = SettingDefinition{
+		DisplayName: "Leader Election Renew Deadline",
+		Description: "The duration in seconds that the leaders of the system managed components retry refreshing their leases before giving up the leadership. " +
+			"The value must be smaller than the lease duration. " +
+			"This setting only takes effect the next time the Longhorn driver deployer starts.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeInt,
+		Required: true,
+		ReadOnly: false,
+		Default:  "10",
+	}
+	SettingDefinitionLeaderElectionRetryPeriod = SettingDefinition{
+		DisplayName: "Leader Election Retry Period",
+		Description: "The duration in seconds between the attempts of the system managed components to acquire or renew the leadership. " +
+			"This setting only takes effect the next time the Longhorn driver deployer starts.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeInt,
+		Required: true,
+		ReadOnly: false,
+		Default:  "2",
+	}
+	SettingDefinitionAPIPort = SettingDefinition{
+		DisplayName: "Manager API Port",
+		Description: "The port the longhorn-manager pods listen on for the Longhorn API. Override this when the default port conflicts with other services on the hosts. " +
+			"The longhorn-backend service and the UI need to be updated to match the new port. " +
+			"This setting only takes effect after the longhorn-manager pods are restarted.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeInt,
+		Required: true,
+		ReadOnly: false,
+		Default:  "9500",
+	}
+	SettingDefinitionCSIPluginHostNetwork = SettingDefinition{
+		DisplayName: "CSI Plugin Host Network",
+		Description: "Run the Longhorn CSI plugin pods in the host network namespace. " +
+			"This can be necessary for clusters with restrictive CNI configurations where the kubelet cannot reach the plugin over the pod network. " +
+			"This setting only takes effect the next time the Longhorn driver deployer starts.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeBool,
+		Required: true,
+		ReadOnly: false,
+		Default:  "false",
+	}
 	SettingDefinitionDisableRevisionCounter = SettingDefinition{
 		DisplayName: "Disable Revision Counter",
 		Description: "This setting is only for volumes created by UI. By default, this is false meaning there will be a revision counter file to track every write to the volume. During salvage recovering Longhorn will pick the repica with largest revision counter as candidate to recover the whole volume. If revision counter is disabled, Longhorn will not track every write to the volume. During the salvage recovering, Longhorn will use the 'volume-head-xxx.img' file last modification time and file size to pick the replica candidate to recover the whole volume.",
@@ -600,6 +847,16 @@ var (
 		Default:  "600",
 	}
 
+	SettingDefinitionReplicaCleanupGracePeriod = SettingDefinition{
+		DisplayName: "Replica Cleanup Grace Period",
+		Description: "In seconds. When a replica becomes redundant due to a replica count reduction or the replica auto-balance, Longhorn keeps the replica marked as cleanup-pending for this period before erasing its data, so that a mistaken scale-down can be reverted quickly. Set to 0 to delete redundant replicas immediately.",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeInt,
+		Required:    true,
+		ReadOnly:    false,
+		Default:     "0",
+	}
+
 	SettingDefinitionConcurrentReplicaRebuildPerNodeLimit = SettingDefinition{
 		DisplayName: "Concurrent Replica Rebuild Per Node Limit",
 		Description: "This setting controls how many replicas on a node can be rebuilt simultaneously. \n\n" +
@@ -615,6 +872,16 @@ var (
 		Default:  "5",
 	}
 
+	SettingDefinitionReplicaRebuildWindow = SettingDefinition{
+		DisplayName: "Replica Rebuild Window",
+		Description: "A daily time window in UTC with the format HH:MM-HH:MM, e.g. 22:00-06:00. When set, the rebuild of the replicas for a volume that still has at least 2 healthy replicas is deferred until the current time falls into the window. A volume down to its last healthy replica is always rebuilt immediately regardless of the window. Leave it empty to allow rebuilding at any time.",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeString,
+		Required:    false,
+		ReadOnly:    false,
+		Default:     "",
+	}
+
 	SettingDefinitionSystemManagedPodsImagePullPolicy = SettingDefinition{
 		DisplayName: "System Managed Pod Image Pull Policy",
 		Description: "This setting defines the Image Pull Policy of Longhorn system managed pods, e.g. instance manager, engine image, CSI driver, etc. " +
@@ -651,6 +918,34 @@ var (
 		Default:     "true",
 	}
 
+	SettingDefinitionAutoCleanupSystemGeneratedSnapshotDelay = SettingDefinition{
+		DisplayName: "Automatic System Generated Snapshot Cleanup Delay",
+		Description: "In minutes. The wait interval before Longhorn purges the system generated snapshots once a replica rebuild is done. " +
+			"Set the value to 0 to purge the snapshots immediately. " +
+			"This setting only takes effect while the automatic system generated snapshot cleanup is enabled; " +
+			"disable that setting instead to keep the snapshots until the user deletes them manually. " +
+			"The deferred purge is a best effort and will be skipped if the manager restarts before the delay elapses.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeInt,
+		Required: true,
+		ReadOnly: false,
+		Default:  "0",
+		MinValue: settingBound(0),
+	}
+
+	SettingDefinitionRemoveSnapshotsDuringFilesystemTrim = SettingDefinition{
+		DisplayName: "Remove Snapshots During Filesystem Trim",
+		Description: "This setting allows Longhorn filesystem trim feature to automatically mark the latest snapshot and its ancestors as removed and stops at the snapshot containing multiple children. " +
+			"Since Longhorn filesystem trim feature can be applied to the volume head and the followed continuous removed or system snapshots only, " +
+			"trying to trim a removed file from a valid snapshot will do nothing but the filesystem will discard this kind of in-memory trimmable file info. " +
+			"Later this info may be erased by a new file creation or multiple file deletions.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeBool,
+		Required: true,
+		ReadOnly: false,
+		Default:  "false",
+	}
+
 	SettingDefinitionConcurrentAutomaticEngineUpgradePerNodeLimit = SettingDefinition{
 		DisplayName: "Concurrent Automatic Engine Upgrade Per Node Limit",
 		Description: "This setting controls how Longhorn automatically upgrades volumes' engines after upgrading Longhorn manager. " +
@@ -673,6 +968,16 @@ var (
 		Default:     "60",
 	}
 
+	SettingDefinitionEngineImageCleanupWaitInterval = SettingDefinition{
+		DisplayName: "Engine Image Cleanup Wait Interval",
+		Description: "In minutes. The interval determines how long Longhorn will wait before removing a non-default engine image that is no longer referenced by any volume. Set to 0 to keep unreferenced engine images around forever.",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeInt,
+		Required:    true,
+		ReadOnly:    false,
+		Default:     "60",
+	}
+
 	SettingDefinitionBackingImageRecoveryWaitInterval = SettingDefinition{
 		DisplayName: "Backing Image Recovery Wait Interval",
 		Description: "In seconds. The interval determines how long Longhorn will wait before re-downloading the backing image file when all disk files of this backing image become failed or unknown. \n\n" +
@@ -725,6 +1030,179 @@ var (
 		ReadOnly: false,
 		Default:  "12",
 	}
+
+	SettingDefinitionDisabledControllers = SettingDefinition{
+		DisplayName: "Disabled Controllers",
+		Description: "This setting is a comma separated list of controller names Longhorn stops reconciling at runtime, " +
+			"so operators can shed non-essential load or isolate a misbehaving subsystem during incidents. " +
+			"Only the non-essential controllers can be disabled: " + strings.Join(DisableableControllerNames, ", ") + ". \n\n" +
+			"WARNING: while a controller is disabled its resources are not reconciled at all, " +
+			"e.g. disabling the backup controllers stops the backup creation/deletion progress syncing. " +
+			"Remember to clear this setting after the incident is resolved.",
+		Category: SettingCategoryDangerZone,
+		Type:     SettingTypeString,
+		Required: false,
+		ReadOnly: false,
+		Default:  "",
+	}
+
+	SettingDefinitionInstanceDriftAutoCorrection = SettingDefinition{
+		DisplayName: "Instance Drift Auto Correction",
+		Description: "If enabled, the periodic instance drift checker not only reports the discrepancies between " +
+			"the engine/replica CR statuses and the processes inside the instance managers, " +
+			"but also corrects the drifted CR statuses so the controllers can recover the instances. \n\n" +
+			"WARNING: a correction marks the drifted instance as state error, " +
+			"which may trigger an engine restart or a replica rebuilding.",
+		Category: SettingCategoryDangerZone,
+		Type:     SettingTypeBool,
+		Required: true,
+		ReadOnly: false,
+		Default:  "false",
+	}
+
+	SettingDefinitionAutoSnapshotBeforeRiskyOperation = SettingDefinition{
+		DisplayName: "Automatically Snapshot Before Risky Operation",
+		Description: "This setting enables Longhorn to automatically take a safety snapshot of a volume before an operation that may corrupt the volume data, " +
+			"e.g. an engine live upgrade or a snapshot revert, so that there is a point to roll back to if the operation goes wrong.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeBool,
+		Required: true,
+		ReadOnly: false,
+		Default:  "false",
+	}
+
+	SettingDefinitionOfflineReplicaRebuilding = SettingDefinition{
+		DisplayName: "Offline Replica Rebuilding",
+		Description: "This setting enables Longhorn to rebuild the missing replicas of a degraded detached volume. " +
+			"Longhorn automatically attaches the volume in maintenance mode, rebuilds the missing replicas, and then detaches the volume again. " +
+			"Without this setting the degraded detached volume stays degraded until the next attach.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeBool,
+		Required: true,
+		ReadOnly: false,
+		Default:  "false",
+	}
+
+	SettingDefinitionBackupCompressionMethod = SettingDefinition{
+		DisplayName: "Backup Compression Method",
+		Description: "This setting determines the compression method used by the backup blocks, trading CPU for the bandwidth towards the backup target.\n" +
+			"- **none** disables the compression, preferable for the incompressible data, e.g. the encrypted or already compressed volumes.\n" +
+			"- **lz4** is fast with a moderate compression ratio.\n" +
+			"- **zstd** compresses better at the cost of more CPU, preferable for the backup targets behind slow WAN links.\n" +
+			"A volume can override this setting with its own backup compression method.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeString,
+		Required: true,
+		ReadOnly: false,
+		Default:  "lz4",
+		Choices: []string{
+			"none",
+			"lz4",
+			"zstd",
+		},
+	}
+
+	SettingDefinitionBackupCompressionLevel = SettingDefinition{
+		DisplayName: "Backup Compression Level",
+		Description: "The compression level used by the backup blocks for the compression methods supporting levels, e.g. zstd. " +
+			"A higher level compresses better but uses more CPU. 0 means the default level of the chosen method.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeInt,
+		Required: true,
+		ReadOnly: false,
+		Default:  "0",
+	}
+
+	SettingDefinitionBackupUploadConcurrency = SettingDefinition{
+		DisplayName: "Backup Upload Concurrency",
+		Description: "The number of parts the backup driver uploads to the S3 backup target concurrently. " +
+			"Increase the value to fully utilize a high-bandwidth link, or lower it to throttle the upload on a constrained one. " +
+			"0 means the driver default.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeInt,
+		Required: true,
+		ReadOnly: false,
+		Default:  "0",
+	}
+
+	SettingDefinitionBackupUploadPartSize = SettingDefinition{
+		DisplayName: "Backup Upload Part Size",
+		Description: "In megabytes. The part size used by the multipart uploads to the S3 backup target. 0 means the driver default.",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeInt,
+		Required:    true,
+		ReadOnly:    false,
+		Default:     "0",
+	}
+
+	SettingDefinitionBackupUploadMaxRetries = SettingDefinition{
+		DisplayName: "Backup Upload Max Retries",
+		Description: "The maximum number of times the backup driver retries a failed upload to the S3 backup target before giving up. 0 means the driver default.",
+		Category:    SettingCategoryGeneral,
+		Type:        SettingTypeInt,
+		Required:    true,
+		ReadOnly:    false,
+		Default:     "0",
+	}
+
+	SettingDefinitionStorageNetwork = SettingDefinition{
+		DisplayName: "Storage Network",
+		Description: "The Multus NetworkAttachmentDefinition used for the in-cluster data traffic, in the <namespace>/<name> format. " +
+			"When set, the instance manager and backing image pods get the secondary network annotation so the replication traffic is segregated from the cluster network. " +
+			"Leave it empty to use the cluster network. " +
+			"WARNING: This setting should change after detaching all Longhorn volumes, as the system-managed pods will restart to apply it.",
+		Category: SettingCategoryDangerZone,
+		Type:     SettingTypeString,
+		Required: false,
+		ReadOnly: false,
+		Default:  "",
+	}
+
+	SettingDefinitionAutoSnapshotCleanupWaitInterval = SettingDefinition{
+		DisplayName: "Automatic Snapshot Cleanup Wait Interval",
+		Description: "In minutes. The interval determines how long Longhorn will keep the safety snapshots automatically taken before the risky operations. " +
+			"Set the value to 0 to keep these snapshots until the user deletes them manually.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeInt,
+		Required: true,
+		ReadOnly: false,
+		Default:  "1440",
+	}
+
+	SettingDefinitionFastReplicaRebuildEnabled = SettingDefinition{
+		DisplayName: "Fast Replica Rebuild Enabled",
+		Description: "This setting enables the fast replica rebuilding feature. " +
+			"During a rebuild, the engine skips transferring the snapshots whose checksums already match on the rebuilding replica, " +
+			"which dramatically reduces the rebuild time for mostly-unchanged volumes. " +
+			"It relies on the checksums calculated and stored in the snapshot metadata, " +
+			"so the snapshots without a checksum are still transferred in full.",
+		Category: SettingCategoryGeneral,
+		Type:     SettingTypeBool,
+		Required: true,
+		ReadOnly: false,
+		Default:  "false",
+	}
+)
+
+// DisableableControllerNames lists the controllers considered non-essential
+// to the volume data path, which operators are allowed to stop via the
+// disabled-controllers setting
+var DisableableControllerNames = []string{
+	"longhorn-backup-target",
+	"longhorn-backup-volume",
+	"longhorn-backup",
+	"longhorn-snapshot",
+	"longhorn-recurring-job",
+	"longhorn-system-backup",
+	"longhorn-system-restore",
+}
+
+type NodeDrainPolicy string
+
+const (
+	NodeDrainPolicyBlockIfContainsLastReplica = NodeDrainPolicy("block-if-contains-last-replica")
+	NodeDrainPolicyAllowIfReplicaIsStopped    = NodeDrainPolicy("allow-if-replica-is-stopped")
+	NodeDrainPolicyAlwaysAllow                = NodeDrainPolicy("always-allow")
 )
 
 type NodeDownPodDeletionPolicy string
@@ -744,12 +1222,13 @@ const (
 	SystemManagedPodsImagePullPolicyAlways       = SystemManagedPodsImagePullPolicy("always")
 )
 
-func ValidateInitSetting(name, value string) (err error) {
-	defer func() {
-		err = errors.Wrapf(err, "value %v of settings %v is invalid", value, name)
-	}()
-	sName := SettingName(name)
-
+// ValidateSettingValue checks the value against the typed schema declared in
+// the setting definition: booleans and integers must parse, integers must
+// fall within the declared range, and settings with declared choices must use
+// one of them. ValidateInitSetting adds the setting-specific rules on top of
+// this generic part, so the API server, the admission webhook, and the
+// dry-run endpoint all agree on what is a valid value.
+func ValidateSettingValue(sName SettingName, value string) error {
 	definition, ok := SettingDefinitions[sName]
 	if !ok {
 		return fmt.Errorf("setting %v is not supported", sName)
@@ -757,6 +1236,43 @@ func ValidateInitSetting(name, value string) (err error) {
 	if definition.Required && value == "" {
 		return fmt.Errorf("required setting %v shouldn't be empty", sName)
 	}
+	if value == "" {
+		return nil
+	}
+
+	switch definition.Type {
+	case SettingTypeBool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("value %v of setting %v should be true or false", value, sName)
+		}
+	case SettingTypeInt:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("value %v is not a number", value)
+		}
+		if definition.MinValue != nil && v < *definition.MinValue {
+			return fmt.Errorf("value %v shouldn't be less than %v", v, *definition.MinValue)
+		}
+		if definition.MaxValue != nil && v > *definition.MaxValue {
+			return fmt.Errorf("value %v shouldn't be greater than %v", v, *definition.MaxValue)
+		}
+	}
+
+	if len(definition.Choices) > 0 && !isValidChoice(definition.Choices, value) {
+		return fmt.Errorf("value %v is not a valid choice, available choices %v", value, definition.Choices)
+	}
+	return nil
+}
+
+func ValidateInitSetting(name, value string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "value %v of settings %v is invalid", value, name)
+	}()
+	sName := SettingName(name)
+
+	if err := ValidateSettingValue(sName, value); err != nil {
+		return err
+	}
 
 	switch sName {
 	case SettingNameBackupTarget:
@@ -787,6 +1303,20 @@ func ValidateInitSetting(name, value string) (err error) {
 		fallthrough
 	case SettingNameAutoDeletePodWhenVolumeDetachedUnexpectedly:
 		fallthrough
+	case SettingNameAutoCleanupStaleVolumeAttachment:
+		fallthrough
+	case SettingNameCSIPluginHostNetwork:
+		fallthrough
+	case SettingNameRemoveSnapshotsDuringFilesystemTrim:
+		fallthrough
+	case SettingNameInstanceDriftAutoCorrection:
+		fallthrough
+	case SettingNameAutoSnapshotBeforeRiskyOperation:
+		fallthrough
+	case SettingNameFastReplicaRebuildEnabled:
+		fallthrough
+	case SettingNameOfflineReplicaRebuilding:
+		fallthrough
 	case SettingNameUpgradeChecker:
 		if value != "true" && value != "false" {
 			return fmt.Errorf("value %v of setting %v should be true or false", value, sName)
@@ -810,6 +1340,14 @@ func ValidateInitSetting(name, value string) (err error) {
 		if err != nil || value < 0 || value > 100 {
 			return fmt.Errorf("value %v should between 0 to 100", value)
 		}
+	case SettingNameDefaultEngineImageReadinessPercentage:
+		percentage, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("value %v is not a number", value)
+		}
+		if percentage < 0 || percentage > 100 {
+			return fmt.Errorf("value %v should between 0 to 100", percentage)
+		}
 	case SettingNameDefaultReplicaCount:
 		c, err := strconv.Atoi(value)
 		if err != nil {
@@ -828,14 +1366,32 @@ func ValidateInitSetting(name, value string) (err error) {
 		}
 	case SettingNameBackingImageCleanupWaitInterval:
 		fallthrough
+	case SettingNameEngineImageCleanupWaitInterval:
+		fallthrough
 	case SettingNameBackingImageRecoveryWaitInterval:
 		fallthrough
 	case SettingNameReplicaReplenishmentWaitInterval:
 		fallthrough
+	case SettingNameReplicaCleanupGracePeriod:
+		fallthrough
 	case SettingNameConcurrentReplicaRebuildPerNodeLimit:
 		fallthrough
 	case SettingNameConcurrentAutomaticEngineUpgradePerNodeLimit:
 		fallthrough
+	case SettingNameAutoSnapshotCleanupWaitInterval:
+		fallthrough
+	case SettingNameAutoCleanupSystemGeneratedSnapshotDelay:
+		fallthrough
+	case SettingNameBackupCompressionLevel:
+		fallthrough
+	case SettingNameBackupUploadConcurrency:
+		fallthrough
+	case SettingNameBackupUploadPartSize:
+		fallthrough
+	case SettingNameBackupUploadMaxRetries:
+		fallthrough
+	case SettingNameSnapshotDiskUsageCriticalPercentage:
+		fallthrough
 	case SettingNameBackupstorePollInterval:
 		interval, err := strconv.Atoi(value)
 		if err != nil {
@@ -844,6 +1400,42 @@ func ValidateInitSetting(name, value string) (err error) {
 		if interval < 0 {
 			return fmt.Errorf("the value %v shouldn't be less than 0", value)
 		}
+	case SettingNameLeaderElectionLeaseDuration:
+		fallthrough
+	case SettingNameLeaderElectionRenewDeadline:
+		fallthrough
+	case SettingNameLeaderElectionRetryPeriod:
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("value is not int: %v", err)
+		}
+		if seconds <= 0 {
+			return fmt.Errorf("the value %v should be greater than 0", value)
+		}
+	case SettingNameAPIPort:
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("value is not int: %v", err)
+		}
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("the value %v is not a valid port number", value)
+		}
+	case SettingNameReplicaRebuildWindow:
+		if _, err := util.IsTimeInDailyWindow(time.Now(), value); err != nil {
+			return fmt.Errorf("the value of %v is invalid: %v", sName, err)
+		}
+	case SettingNameDisabledControllers:
+		disableableControllers := map[string]struct{}{}
+		for _, controllerName := range DisableableControllerNames {
+			disableableControllers[controllerName] = struct{}{}
+		}
+		if value != "" {
+			for _, controllerName := range strings.Split(value, ",") {
+				if _, ok := disableableControllers[strings.TrimSpace(controllerName)]; !ok {
+					return fmt.Errorf("controller %v cannot be disabled", strings.TrimSpace(controllerName))
+				}
+			}
+		}
 	case SettingNameTaintToleration:
 		if _, err = UnmarshalTolerations(value); err != nil {
 			return fmt.Errorf("the value of %v is invalid: %v", sName, err)
@@ -852,10 +1444,26 @@ func ValidateInitSetting(name, value string) (err error) {
 		if _, err = UnmarshalNodeSelector(value); err != nil {
 			return fmt.Errorf("the value of %v is invalid: %v", sName, err)
 		}
+	case SettingNameReplicaSpreadTopologyKeys:
+		if value != "" {
+			for _, key := range strings.Split(value, ",") {
+				key = strings.TrimSpace(key)
+				if key == "" {
+					return fmt.Errorf("the value of %v contains an empty topology key", sName)
+				}
+				if strings.ContainsAny(key, " \t") {
+					return fmt.Errorf("topology key %v must not contain whitespace", key)
+				}
+			}
+		}
 
 	// multi-choices
 	case SettingNameNodeDownPodDeletionPolicy:
 		fallthrough
+	case SettingNameNodeDrainPolicy:
+		fallthrough
+	case SettingNameBackupCompressionMethod:
+		fallthrough
 	case SettingNameDefaultDataLocality:
 		fallthrough
 	case SettingNameSystemManagedPodsImagePullPolicy:
@@ -863,6 +1471,10 @@ func ValidateInitSetting(name, value string) (err error) {
 		if !isValidChoice(choices, value) {
 			return fmt.Errorf("value %v is not a valid choice, available choices %v", value, choices)
 		}
+	case SettingNameStorageNetwork:
+		if err := ValidateStorageNetwork(value); err != nil {
+			return err
+		}
 	case SettingNameGuaranteedEngineManagerCPU:
 		fallthrough
 	case SettingNameGuaranteedReplicaManagerCPU:
@@ -28,6 +28,9 @@ const (
 
 	LonghornKindBackingImageDataSource = "BackingImageDataSource"
 
+	LonghornKindSystemBackup  = "SystemBackup"
+	LonghornKindSystemRestore = "SystemRestore"
+
 	CRDAPIVersionV1alpha1 = "longhorn.rancher.io/v1alpha1"
 	CRDAPIVersionV1beta1  = "longhorn.io/v1beta1"
 	CRDAPIVersionV1beta2  = "longhorn.io/v1beta2"
@@ -58,33 +61,36 @@ const (
 
 	LastAppliedTolerationAnnotationKeySuffix = "last-applied-tolerations"
 
-	KubernetesStatusLabel = "KubernetesStatus"
-	KubernetesReplicaSet  = "ReplicaSet"
-	KubernetesStatefulSet = "StatefulSet"
-	RecurringJobLabel     = "RecurringJob"
+	KubernetesStatusLabel  = "KubernetesStatus"
+	KubernetesReplicaSet   = "ReplicaSet"
+	KubernetesStatefulSet  = "StatefulSet"
+	RecurringJobLabel      = "RecurringJob"
+	RecurringJobGroupLabel = "RecurringJobGroup"
 
 	LonghornLabelKeyPrefix = "longhorn.io"
 
 	LonghornLabelRecurringJobKeyPrefixFmt = "recurring-%s.longhorn.io"
 
-	LonghornLabelEngineImage              = "engine-image"
-	LonghornLabelInstanceManager          = "instance-manager"
-	LonghornLabelNode                     = "node"
-	LonghornLabelDiskUUID                 = "disk-uuid"
-	LonghornLabelInstanceManagerType      = "instance-manager-type"
-	LonghornLabelInstanceManagerImage     = "instance-manager-image"
-	LonghornLabelVolume                   = "longhornvolume"
-	LonghornLabelShareManager             = "share-manager"
-	LonghornLabelShareManagerImage        = "share-manager-image"
-	LonghornLabelBackingImage             = "backing-image"
-	LonghornLabelBackingImageManager      = "backing-image-manager"
-	LonghornLabelManagedBy                = "managed-by"
-	LonghornLabelSnapshotForCloningVolume = "for-cloning-volume"
-	LonghornLabelBackingImageDataSource   = "backing-image-data-source"
-	LonghornLabelBackupVolume             = "backup-volume"
-	LonghornLabelRecurringJob             = "job"
-	LonghornLabelRecurringJobGroup        = "job-group"
-	LonghornLabelCRDAPIVersion            = "crd-api-version"
+	LonghornLabelEngineImage                  = "engine-image"
+	LonghornLabelInstanceManager              = "instance-manager"
+	LonghornLabelNode                         = "node"
+	LonghornLabelDiskUUID                     = "disk-uuid"
+	LonghornLabelInstanceManagerType          = "instance-manager-type"
+	LonghornLabelInstanceManagerImage         = "instance-manager-image"
+	LonghornLabelVolume                       = "longhornvolume"
+	LonghornLabelShareManager                 = "share-manager"
+	LonghornLabelShareManagerImage            = "share-manager-image"
+	LonghornLabelBackingImage                 = "backing-image"
+	LonghornLabelBackingImageManager          = "backing-image-manager"
+	LonghornLabelManagedBy                    = "managed-by"
+	LonghornLabelSnapshotForCloningVolume     = "for-cloning-volume"
+	LonghornLabelSnapshotBeforeRiskyOperation = "before-risky-operation"
+	LonghornLabelBackingImageDataSource       = "backing-image-data-source"
+	LonghornLabelBackupVolume                 = "backup-volume"
+	LonghornLabelSystemBackup                 = "system-backup"
+	LonghornLabelRecurringJob                 = "job"
+	LonghornLabelRecurringJobGroup            = "job-group"
+	LonghornLabelCRDAPIVersion                = "crd-api-version"
 
 	LonghornLabelValueEnabled = "enabled"
 
@@ -98,6 +104,10 @@ const (
 
 	LonghornDriverName = "driver.longhorn.io"
 
+	// CNIAnnotationNetworks is the Multus annotation carrying the secondary
+	// network attachments of a pod.
+	CNIAnnotationNetworks = "k8s.v1.cni.cncf.io/networks"
+
 	DefaultDiskPrefix = "default-disk-"
 
 	DeprecatedProvisionerName        = "rancher.io/longhorn"
@@ -107,6 +117,11 @@ const (
 	ControlPlaneName                 = "longhorn-manager"
 
 	DefaultRecurringJobConcurrency = 10
+
+	// DefaultIOFreezeTimeout and MaxIOFreezeTimeout bound in seconds how long
+	// an engine IO freeze request can quiesce a volume.
+	DefaultIOFreezeTimeout = int64(60)
+	MaxIOFreezeTimeout     = int64(300)
 )
 
 const (
@@ -134,6 +149,10 @@ const (
 
 	VirtualHostedStyle = "VIRTUAL_HOSTED_STYLE"
 
+	BackupUploadConcurrency = "BACKUP_UPLOAD_CONCURRENCY"
+	BackupUploadPartSize    = "BACKUP_UPLOAD_PART_SIZE"
+	BackupUploadMaxRetries  = "BACKUP_UPLOAD_MAX_RETRIES"
+
 	OptionFromBackup          = "fromBackup"
 	OptionNumberOfReplicas    = "numberOfReplicas"
 	OptionStaleReplicaTimeout = "staleReplicaTimeout"
@@ -173,24 +192,60 @@ const (
 	replicaManagerPrefix  = instanceManagerPrefix + "r-"
 )
 
+// MaximumNameLength is the longest name Kubernetes accepts for most objects,
+// i.e. the length of a DNS-1123 label.
+const MaximumNameLength = 63
+
+// buildDerivedName joins the base name with the suffix. When the result would
+// exceed the Kubernetes name length limit, the base is deterministically
+// truncated and completed with a short checksum of the full base, so that
+// names derived from long volume names stay unique without hard failures.
+func buildDerivedName(base, suffix string) string {
+	name := base + suffix
+	if len(name) <= MaximumNameLength {
+		return name
+	}
+	hash := util.GetStringChecksum(base)[:ImageChecksumNameLength]
+	maxBaseLength := MaximumNameLength - len(suffix) - len(hash) - 1
+	return base[:maxBaseLength] + "-" + hash + suffix
+}
+
+// buildDerivedNameWithPrefix is the prefix counterpart of buildDerivedName:
+// the prefix is kept intact and the base is truncated and completed with a
+// short checksum of the full base when the result would exceed the Kubernetes
+// name length limit.
+func buildDerivedNameWithPrefix(prefix, base string) string {
+	name := prefix + base
+	if len(name) <= MaximumNameLength {
+		return name
+	}
+	hash := util.GetStringChecksum(base)[:ImageChecksumNameLength]
+	maxBaseLength := MaximumNameLength - len(prefix) - len(hash) - 1
+	return prefix + base[:maxBaseLength] + "-" + hash
+}
+
 func GenerateEngineNameForVolume(vName string) string {
-	return vName + engineSuffix + "-" + util.RandomID()
+	return buildDerivedName(vName, engineSuffix+"-"+util.RandomID())
 }
 
 func GenerateReplicaNameForVolume(vName string) string {
-	return vName + replicaSuffix + "-" + util.RandomID()
+	return buildDerivedName(vName, replicaSuffix+"-"+util.RandomID())
 }
 
 func GetCronJobNameForRecurringJob(name string) string {
-	return name + recurringSuffix
+	return buildDerivedName(name, recurringSuffix)
 }
 
 func GetCronJobNameForVolumeAndJob(vName, job string) string {
-	return vName + "-" + job + recurringSuffix
+	return buildDerivedName(vName, "-"+job+recurringSuffix)
 }
 
 func GetAPIServerAddressFromIP(ip string) string {
-	return net.JoinHostPort(ip, strconv.Itoa(DefaultAPIPort))
+	return GetAPIServerAddress(ip, DefaultAPIPort)
+}
+
+func GetAPIServerAddress(ip string, port int) string {
+	return net.JoinHostPort(ip, strconv.Itoa(port))
 }
 
 func GetDefaultManagerURL() string {
@@ -329,6 +384,17 @@ func GetShareManagerLabels(name, image string) map[string]string {
 	return labels
 }
 
+func GetSystemBackupLabels(systemBackupName string) map[string]string {
+	labels := GetBaseLabelsForSystemManagedComponent()
+	labels[GetLonghornLabelComponentKey()] = LonghornLabelSystemBackup
+	labels[GetLonghornLabelKey(LonghornLabelSystemBackup)] = systemBackupName
+	return labels
+}
+
+func GetSystemBackupConfigMapName(systemBackupName string) string {
+	return fmt.Sprintf("longhorn-system-backup-%s", systemBackupName)
+}
+
 func GetCronJobLabels(job *longhorn.RecurringJobSpec) map[string]string {
 	labels := GetBaseLabelsForSystemManagedComponent()
 	labels[fmt.Sprintf(LonghornLabelRecurringJobKeyPrefixFmt, LonghornLabelRecurringJob)] = job.Name
@@ -380,6 +446,16 @@ func GetVolumeLabels(volumeName string) map[string]string {
 	}
 }
 
+// GetVolumeSystemManagedComponentLabels returns the uniform labels Longhorn applies
+// to every Kubernetes object it creates on behalf of a volume, so that the objects
+// can be selected by label instead of by matching the object name prefix, which
+// breaks on long volume names.
+func GetVolumeSystemManagedComponentLabels(volumeName string) map[string]string {
+	labels := GetBaseLabelsForSystemManagedComponent()
+	labels[GetLonghornLabelKey(LonghornLabelVolume)] = volumeName
+	return labels
+}
+
 func GetRecurringJobLabelKey(labelType, recurringJobName string) string {
 	prefix := fmt.Sprintf(LonghornLabelRecurringJobKeyPrefixFmt, labelType)
 	return fmt.Sprintf("%s/%s", prefix, recurringJobName)
@@ -390,14 +466,21 @@ func GetRecurringJobLabelValueMap(labelType, recurringJobName string) map[string
 		GetRecurringJobLabelKey(labelType, recurringJobName): LonghornLabelValueEnabled,
 	}
 }
+// GetRegionAndZone reads the region and zone from the Kubernetes node labels.
+// The deprecated failure-domain labels are used as a fallback for clusters
+// that predate the GA topology labels.
 func GetRegionAndZone(labels map[string]string) (string, string) {
 	region := ""
 	zone := ""
 	if v, ok := labels[KubernetesTopologyRegionLabelKey]; ok {
 		region = v
+	} else if v, ok := labels[KubernetesFailureDomainRegionLabelKey]; ok {
+		region = v
 	}
 	if v, ok := labels[KubernetesTopologyZoneLabelKey]; ok {
 		zone = v
+	} else if v, ok := labels[KubernetesFailureDomainZoneLabelKey]; ok {
+		zone = v
 	}
 	return region, zone
 }
@@ -415,7 +498,7 @@ func GetShareManagerImageChecksumName(image string) string {
 }
 
 func GetShareManagerPodNameFromShareManagerName(smName string) string {
-	return shareManagerPrefix + smName
+	return buildDerivedNameWithPrefix(shareManagerPrefix, smName)
 }
 
 func GetShareManagerNameFromShareManagerPodName(podName string) string {
@@ -430,9 +513,9 @@ func ValidateEngineImageChecksumName(name string) bool {
 func GetInstanceManagerName(imType longhorn.InstanceManagerType) (string, error) {
 	switch imType {
 	case longhorn.InstanceManagerTypeEngine:
-		return engineManagerPrefix + util.RandomID(), nil
+		return buildDerivedNameWithPrefix(engineManagerPrefix, util.RandomID()), nil
 	case longhorn.InstanceManagerTypeReplica:
-		return replicaManagerPrefix + util.RandomID(), nil
+		return buildDerivedNameWithPrefix(replicaManagerPrefix, util.RandomID()), nil
 	}
 	return "", fmt.Errorf("cannot generate name for unknown instance manager type %v", imType)
 }
@@ -489,6 +572,28 @@ func ValidateReplicaAutoBalance(option longhorn.ReplicaAutoBalance) error {
 	}
 }
 
+func ValidateReplicaSoftAntiAffinity(option longhorn.ReplicaSoftAntiAffinity) error {
+	switch option {
+	case longhorn.ReplicaSoftAntiAffinityIgnored,
+		longhorn.ReplicaSoftAntiAffinityEnabled,
+		longhorn.ReplicaSoftAntiAffinityDisabled:
+		return nil
+	default:
+		return fmt.Errorf("invalid replica soft anti-affinity option: %v", option)
+	}
+}
+
+func ValidateAutoSalvage(option longhorn.AutoSalvage) error {
+	switch option {
+	case longhorn.AutoSalvageIgnored,
+		longhorn.AutoSalvageEnabled,
+		longhorn.AutoSalvageDisabled:
+		return nil
+	default:
+		return fmt.Errorf("invalid auto-salvage option: %v", option)
+	}
+}
+
 func ValidateDataLocality(mode longhorn.DataLocality) error {
 	if mode != longhorn.DataLocalityDisabled && mode != longhorn.DataLocalityBestEffort {
 		return fmt.Errorf("invalid data locality mode: %v", mode)
@@ -496,6 +601,30 @@ func ValidateDataLocality(mode longhorn.DataLocality) error {
 	return nil
 }
 
+func ValidateBackupCompressionMethod(method longhorn.BackupCompressionMethod) error {
+	switch method {
+	case longhorn.BackupCompressionMethodNone,
+		longhorn.BackupCompressionMethodLz4,
+		longhorn.BackupCompressionMethodZstd:
+		return nil
+	default:
+		return fmt.Errorf("invalid backup compression method: %v", method)
+	}
+}
+
+// ValidateStorageNetwork checks the storage network setting is either empty or
+// a NetworkAttachmentDefinition reference in the <namespace>/<name> format.
+func ValidateStorageNetwork(value string) error {
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("storage network %v is not in the <namespace>/<name> format", value)
+	}
+	return nil
+}
+
 func ValidateAccessMode(mode longhorn.AccessMode) error {
 	if mode != longhorn.AccessModeReadWriteMany && mode != longhorn.AccessModeReadWriteOnce {
 		return fmt.Errorf("invalid access mode: %v", mode)
@@ -503,6 +632,17 @@ func ValidateAccessMode(mode longhorn.AccessMode) error {
 	return nil
 }
 
+// IsUnsupportedDiskFilesystem checks the filesystem type reported by stat
+// against the ones known to break replica sparse files, i.e. the network and
+// FAT-family filesystems.
+func IsUnsupportedDiskFilesystem(fsType string) bool {
+	switch strings.ToLower(fsType) {
+	case "nfs", "cifs", "smb", "smb2", "msdos", "vfat", "exfat":
+		return true
+	}
+	return false
+}
+
 func GetDaemonSetNameFromEngineImageName(engineImageName string) string {
 	return "engine-image-" + engineImageName
 }
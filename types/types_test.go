@@ -0,0 +1,70 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDerivedName(t *testing.T) {
+	assert := require.New(t)
+
+	// Short names pass through untouched.
+	assert.Equal("vol-e-abcd1234", buildDerivedName("vol", "-e-abcd1234"))
+
+	// A long base is truncated, completed with a checksum and keeps the
+	// suffix, staying within the Kubernetes name length limit.
+	longBase := strings.Repeat("v", 100)
+	name := buildDerivedName(longBase, "-e-abcd1234")
+	assert.Equal(MaximumNameLength, len(name))
+	assert.True(strings.HasSuffix(name, "-e-abcd1234"))
+	assert.Equal(name, buildDerivedName(longBase, "-e-abcd1234"))
+
+	// Two long bases sharing the truncated portion still get unique names.
+	other := buildDerivedName(longBase+"x", "-e-abcd1234")
+	assert.NotEqual(name, other)
+}
+
+func TestBuildDerivedNameWithPrefix(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal("share-manager-vol", buildDerivedNameWithPrefix("share-manager-", "vol"))
+
+	longBase := strings.Repeat("v", 100)
+	name := buildDerivedNameWithPrefix("share-manager-", longBase)
+	assert.Equal(MaximumNameLength, len(name))
+	assert.True(strings.HasPrefix(name, "share-manager-"))
+	assert.Equal(name, buildDerivedNameWithPrefix("share-manager-", longBase))
+	assert.NotEqual(name, buildDerivedNameWithPrefix("share-manager-", longBase+"x"))
+
+	podName := GetShareManagerPodNameFromShareManagerName(longBase)
+	assert.True(len(podName) <= MaximumNameLength)
+	assert.True(strings.HasPrefix(podName, "share-manager-"))
+}
+
+func TestGetRegionAndZone(t *testing.T) {
+	assert := require.New(t)
+
+	region, zone := GetRegionAndZone(map[string]string{
+		KubernetesTopologyRegionLabelKey: "region-1",
+		KubernetesTopologyZoneLabelKey:   "zone-1",
+	})
+	assert.Equal("region-1", region)
+	assert.Equal("zone-1", zone)
+
+	// The deprecated failure-domain labels are only a fallback.
+	region, zone = GetRegionAndZone(map[string]string{
+		KubernetesFailureDomainRegionLabelKey: "old-region",
+		KubernetesFailureDomainZoneLabelKey:   "old-zone",
+	})
+	assert.Equal("old-region", region)
+	assert.Equal("old-zone", zone)
+
+	region, zone = GetRegionAndZone(map[string]string{
+		KubernetesTopologyRegionLabelKey:      "region-1",
+		KubernetesFailureDomainRegionLabelKey: "old-region",
+	})
+	assert.Equal("region-1", region)
+	assert.Equal("", zone)
+}
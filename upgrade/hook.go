@@ -0,0 +1,76 @@
+package upgrade
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	clientset "k8s.io/client-go/kubernetes"
+
+	lhclientset "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned"
+)
+
+// CRUpgradeHook is a single ordered CR migration step. Downstream forks can
+// register additional hooks via RegisterCRUpgradeHook without editing
+// doCRUpgrade.
+type CRUpgradeHook interface {
+	// Name identifies the hook in logs and error messages.
+	Name() string
+	// FromVersion is the Longhorn version whose resources the hook migrates.
+	FromVersion() string
+	// Run performs the migration. It must be idempotent since the upgrade can
+	// be retried.
+	Run(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error
+}
+
+var crUpgradeHooks []CRUpgradeHook
+
+// RegisterCRUpgradeHook appends a CR migration step after the already
+// registered ones. Hooks run in registration order, typically from an init()
+// of the registering package.
+func RegisterCRUpgradeHook(hook CRUpgradeHook) {
+	crUpgradeHooks = append(crUpgradeHooks, hook)
+}
+
+// funcCRUpgradeHook adapts a plain function to the CRUpgradeHook interface for
+// the built-in migration steps.
+type funcCRUpgradeHook struct {
+	name        string
+	fromVersion string
+	run         func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error
+}
+
+func (h *funcCRUpgradeHook) Name() string {
+	return h.name
+}
+
+func (h *funcCRUpgradeHook) FromVersion() string {
+	return h.fromVersion
+}
+
+func (h *funcCRUpgradeHook) Run(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+	return h.run(namespace, lhClient, kubeClient)
+}
+
+func newFuncCRUpgradeHook(name, fromVersion string,
+	run func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error) CRUpgradeHook {
+	return &funcCRUpgradeHook{
+		name:        name,
+		fromVersion: fromVersion,
+		run:         run,
+	}
+}
+
+func runCRUpgradeHooks(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+	for _, hook := range crUpgradeHooks {
+		logrus.Debugf("Running CR upgrade hook %v (from version %v)", hook.Name(), hook.FromVersion())
+		startTime := time.Now()
+		err := hook.Run(namespace, lhClient, kubeClient)
+		recordStep("crUpgrade", hook.Name(), hook.FromVersion(), startTime, err)
+		if err != nil {
+			return errors.Wrapf(err, "CR upgrade hook %v failed", hook.Name())
+		}
+	}
+	return nil
+}
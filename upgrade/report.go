@@ -0,0 +1,108 @@
+package upgrade
+
+import (
+	"sync"
+	"time"
+
+	"github.com/longhorn/longhorn-manager/util"
+)
+
+// StepReport records the outcome of a single migration step, so the slow
+// steps on big clusters can be identified and reported upstream.
+type StepReport struct {
+	// Phase is the upgrade phase the step belongs to, e.g. "crUpgrade" or
+	// "podsUpgrade".
+	Phase string `json:"phase"`
+	// Name identifies the migration step, matching the hook name in the logs.
+	Name string `json:"name"`
+	// FromVersion is the Longhorn version whose resources the step migrates,
+	// empty for the steps that are not version-bound.
+	FromVersion string `json:"fromVersion,omitempty"`
+	StartedAt   string `json:"startedAt"`
+	Duration    string `json:"duration"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Report describes the last upgrade run in this process.
+type Report struct {
+	StartedAt   string `json:"startedAt"`
+	CompletedAt string `json:"completedAt,omitempty"`
+	Error       string `json:"error,omitempty"`
+	// ObjectCounts snapshots how many resources of each kind the migrations
+	// had to walk, taken right before the CR upgrade starts.
+	ObjectCounts map[string]int64 `json:"objectCounts,omitempty"`
+	Steps        []StepReport     `json:"steps"`
+}
+
+var (
+	reportMutex sync.Mutex
+	report      *Report
+)
+
+func beginReport() {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	report = &Report{
+		StartedAt: util.Now(),
+		Steps:     []StepReport{},
+	}
+}
+
+func recordStep(phase, name, fromVersion string, startTime time.Time, err error) {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	if report == nil {
+		return
+	}
+	step := StepReport{
+		Phase:       phase,
+		Name:        name,
+		FromVersion: fromVersion,
+		StartedAt:   startTime.UTC().Format(time.RFC3339),
+		Duration:    time.Since(startTime).String(),
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	report.Steps = append(report.Steps, step)
+}
+
+func recordObjectCounts(counts map[string]int64) {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	if report == nil {
+		return
+	}
+	report.ObjectCounts = counts
+}
+
+func completeReport(err error) {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	if report == nil {
+		return
+	}
+	report.CompletedAt = util.Now()
+	if err != nil {
+		report.Error = err.Error()
+	}
+}
+
+// GetReport returns a copy of the report of the last upgrade run in this
+// process, or nil if no upgrade has run yet.
+func GetReport() *Report {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	if report == nil {
+		return nil
+	}
+	copied := *report
+	copied.Steps = append([]StepReport{}, report.Steps...)
+	if report.ObjectCounts != nil {
+		copied.ObjectCounts = map[string]int64{}
+		for kind, count := range report.ObjectCounts {
+			copied.ObjectCounts[kind] = count
+		}
+	}
+	return &copied
+}
@@ -37,9 +37,50 @@ import (
 
 const (
 	LeaseLockName = "longhorn-manager-upgrade-lock"
+
+	DefaultLeaseDuration = 20 * time.Second
+	DefaultRenewDeadline = 10 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
 )
 
-func Upgrade(kubeconfigPath, currentNodeID string) error {
+// Options tunes the upgrade leader election and bounds the overall upgrade
+// duration. The hard-coded lease parameters flap on overloaded control planes,
+// so they are exposed as daemon flags.
+type Options struct {
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+
+	// Timeout bounds the whole upgrade including the leader election.
+	// Zero means the upgrade can take arbitrarily long.
+	Timeout time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		LeaseDuration: DefaultLeaseDuration,
+		RenewDeadline: DefaultRenewDeadline,
+		RetryPeriod:   DefaultRetryPeriod,
+	}
+}
+
+func (o Options) validate() error {
+	if o.LeaseDuration <= o.RenewDeadline {
+		return fmt.Errorf("upgrade lease duration %v must be greater than the renew deadline %v", o.LeaseDuration, o.RenewDeadline)
+	}
+	if o.RetryPeriod <= 0 {
+		return fmt.Errorf("upgrade retry period %v must be greater than zero", o.RetryPeriod)
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("upgrade timeout %v cannot be negative", o.Timeout)
+	}
+	return nil
+}
+
+func Upgrade(kubeconfigPath, currentNodeID, managerVersion string, opts Options) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
 	namespace := os.Getenv(types.EnvPodNamespace)
 	if namespace == "" {
 		logrus.Warnf("Cannot detect pod namespace, environment variable %v is missing, "+
@@ -70,15 +111,18 @@ func Upgrade(kubeconfigPath, currentNodeID string) error {
 		return err
 	}
 
-	if err := upgrade(currentNodeID, namespace, config, lhClient, kubeClient); err != nil {
+	if err := upgrade(currentNodeID, namespace, managerVersion, config, lhClient, kubeClient, opts); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func upgrade(currentNodeID, namespace string, config *restclient.Config, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+func upgrade(currentNodeID, namespace, managerVersion string, config *restclient.Config, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset, opts Options) error {
 	ctx, cancel := context.WithCancel(context.Background())
+	if opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
+	}
 	var err error
 	defer cancel()
 
@@ -96,13 +140,14 @@ func upgrade(currentNodeID, namespace string, config *restclient.Config, lhClien
 	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
 		Lock:            lock,
 		ReleaseOnCancel: true,
-		LeaseDuration:   20 * time.Second,
-		RenewDeadline:   10 * time.Second,
-		RetryPeriod:     2 * time.Second,
+		LeaseDuration:   opts.LeaseDuration,
+		RenewDeadline:   opts.RenewDeadline,
+		RetryPeriod:     opts.RetryPeriod,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
 				defer cancel()
 				defer func() {
+					completeReport(err)
 					if err != nil {
 						logrus.Errorf("Upgrade failed: %v", err)
 					} else {
@@ -110,6 +155,14 @@ func upgrade(currentNodeID, namespace string, config *restclient.Config, lhClien
 					}
 				}()
 				logrus.Infof("Start upgrading")
+				beginReport()
+				var sourceVersion string
+				if sourceVersion, err = detectSourceVersion(namespace, lhClient); err != nil {
+					return
+				}
+				if err = checkUpgradePath(sourceVersion, managerVersion); err != nil {
+					return
+				}
 				if err = doAPIVersionUpgrade(namespace, config, lhClient); err != nil {
 					return
 				}
@@ -125,6 +178,9 @@ func upgrade(currentNodeID, namespace string, config *restclient.Config, lhClien
 				if err = doDeploymentAndDaemonSetUpgrade(namespace, kubeClient); err != nil {
 					return
 				}
+				if err = updateCurrentLonghornVersion(namespace, lhClient, managerVersion); err != nil {
+					return
+				}
 			},
 			OnStoppedLeading: func() {
 				logrus.Infof("Upgrade leader lost: %s", currentNodeID)
@@ -138,6 +194,10 @@ func upgrade(currentNodeID, namespace string, config *restclient.Config, lhClien
 		},
 	})
 
+	if err == nil && ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("upgrade did not finish within the timeout %v", opts.Timeout)
+	}
+
 	return err
 }
 
@@ -217,48 +277,114 @@ func upgradeLocalNode() (err error) {
 	return nil
 }
 
-func doCRUpgrade(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) (err error) {
-	defer func() {
-		err = errors.Wrap(err, "upgrade CRD failed")
-	}()
-	if err := v070to080.UpgradeCRs(namespace, lhClient); err != nil {
-		return err
-	}
-	if err := v100to101.UpgradeCRs(namespace, lhClient); err != nil {
-		return err
-	}
-	if err := v102to110.UpgradeCRs(namespace, lhClient); err != nil {
-		return err
+func init() {
+	// The built-in CR migration chain. Additional hooks registered by external
+	// packages run after these, in registration order.
+	RegisterCRUpgradeHook(newFuncCRUpgradeHook("v070to080", "v0.7.0",
+		func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+			return v070to080.UpgradeCRs(namespace, lhClient)
+		}))
+	RegisterCRUpgradeHook(newFuncCRUpgradeHook("v100to101", "v1.0.0",
+		func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+			return v100to101.UpgradeCRs(namespace, lhClient)
+		}))
+	RegisterCRUpgradeHook(newFuncCRUpgradeHook("v102to110", "v1.0.2",
+		func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+			return v102to110.UpgradeCRs(namespace, lhClient)
+		}))
+	RegisterCRUpgradeHook(newFuncCRUpgradeHook("v110to111", "v1.1.0",
+		func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+			return v110to111.UpgradeCRs(namespace, lhClient)
+		}))
+	RegisterCRUpgradeHook(newFuncCRUpgradeHook("v110to120", "v1.1.0",
+		func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+			return v110to120.UpgradeCRs(namespace, lhClient, kubeClient)
+		}))
+	RegisterCRUpgradeHook(newFuncCRUpgradeHook("v111to120", "v1.1.1",
+		func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+			return v111to120.UpgradeCRs(namespace, lhClient)
+		}))
+	RegisterCRUpgradeHook(newFuncCRUpgradeHook("v120to121", "v1.2.0",
+		func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+			return v120to121.UpgradeCRs(namespace, lhClient)
+		}))
+	RegisterCRUpgradeHook(newFuncCRUpgradeHook("v122to123", "v1.2.2",
+		func(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) error {
+			return v122to123.UpgradeCRs(namespace, lhClient)
+		}))
+}
+
+// collectObjectCounts snapshots how many resources of each kind the
+// migrations have to walk, so a slow step in the upgrade report can be
+// correlated with the cluster scale. Counting failures are not fatal since
+// the counts are informational only.
+func collectObjectCounts(namespace string, lhClient *lhclientset.Clientset) map[string]int64 {
+	listOpts := metav1.ListOptions{}
+	counts := map[string]int64{}
+	if list, err := lhClient.LonghornV1beta2().Volumes(namespace).List(context.TODO(), listOpts); err == nil {
+		counts["volumes"] = int64(len(list.Items))
+	} else {
+		logrus.WithError(err).Warn("Failed to count volumes for the upgrade report")
 	}
-	if err := v110to111.UpgradeCRs(namespace, lhClient); err != nil {
-		return err
+	if list, err := lhClient.LonghornV1beta2().Engines(namespace).List(context.TODO(), listOpts); err == nil {
+		counts["engines"] = int64(len(list.Items))
+	} else {
+		logrus.WithError(err).Warn("Failed to count engines for the upgrade report")
 	}
-	if err := v110to120.UpgradeCRs(namespace, lhClient, kubeClient); err != nil {
-		return err
+	if list, err := lhClient.LonghornV1beta2().Replicas(namespace).List(context.TODO(), listOpts); err == nil {
+		counts["replicas"] = int64(len(list.Items))
+	} else {
+		logrus.WithError(err).Warn("Failed to count replicas for the upgrade report")
 	}
-	if err := v111to120.UpgradeCRs(namespace, lhClient); err != nil {
-		return err
+	if list, err := lhClient.LonghornV1beta2().Nodes(namespace).List(context.TODO(), listOpts); err == nil {
+		counts["nodes"] = int64(len(list.Items))
+	} else {
+		logrus.WithError(err).Warn("Failed to count nodes for the upgrade report")
 	}
-	if err := v120to121.UpgradeCRs(namespace, lhClient); err != nil {
-		return err
+	if list, err := lhClient.LonghornV1beta2().InstanceManagers(namespace).List(context.TODO(), listOpts); err == nil {
+		counts["instanceManagers"] = int64(len(list.Items))
+	} else {
+		logrus.WithError(err).Warn("Failed to count instance managers for the upgrade report")
 	}
-	if err := v122to123.UpgradeCRs(namespace, lhClient); err != nil {
-		return err
+	if list, err := lhClient.LonghornV1beta2().Settings(namespace).List(context.TODO(), listOpts); err == nil {
+		counts["settings"] = int64(len(list.Items))
+	} else {
+		logrus.WithError(err).Warn("Failed to count settings for the upgrade report")
 	}
-	return nil
+	return counts
+}
+
+func doCRUpgrade(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) (err error) {
+	defer func() {
+		err = errors.Wrap(err, "upgrade CRD failed")
+	}()
+	recordObjectCounts(collectObjectCounts(namespace, lhClient))
+	return runCRUpgradeHooks(namespace, lhClient, kubeClient)
 }
 
 func doPodsUpgrade(namespace string, lhClient *lhclientset.Clientset, kubeClient *clientset.Clientset) (err error) {
 	defer func() {
 		err = errors.Wrap(err, "upgrade Pods failed")
 	}()
-	if err = v100to101.UpgradeInstanceManagerPods(namespace, lhClient, kubeClient); err != nil {
+	runStep := func(name string, fn func() error) error {
+		startTime := time.Now()
+		err := fn()
+		recordStep("podsUpgrade", name, "", startTime, err)
+		return err
+	}
+	if err = runStep("v100to101", func() error {
+		return v100to101.UpgradeInstanceManagerPods(namespace, lhClient, kubeClient)
+	}); err != nil {
 		return err
 	}
-	if err = v102to110.UpgradePods(namespace, kubeClient); err != nil {
+	if err = runStep("v102to110", func() error {
+		return v102to110.UpgradePods(namespace, kubeClient)
+	}); err != nil {
 		return err
 	}
-	if err = v110to111.UpgradePods(namespace, lhClient, kubeClient); err != nil {
+	if err = runStep("v110to111", func() error {
+		return v110to111.UpgradePods(namespace, lhClient, kubeClient)
+	}); err != nil {
 		return err
 	}
 	return nil
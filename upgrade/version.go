@@ -0,0 +1,114 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhclientset "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned"
+)
+
+var versionRegexp = regexp.MustCompile(`^v(\d+)\.(\d+)\.\d+`)
+
+// parseVersion extracts the major and minor numbers from a Longhorn version
+// string like "v1.2.3". Development builds don't match and return an error.
+func parseVersion(v string) (major, minor int, err error) {
+	matches := versionRegexp.FindStringSubmatch(v)
+	if matches == nil {
+		return 0, 0, fmt.Errorf("invalid version %v", v)
+	}
+	major, err = strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	minor, err = strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, 0, err
+	}
+	return major, minor, nil
+}
+
+// checkUpgradePath validates that the jump from the deployed version to this
+// manager version is supported. The migration chain only covers one minor
+// release at a time, so larger jumps are rejected with the intermediate
+// version the user must go through first.
+func checkUpgradePath(source, target string) error {
+	if source == "" {
+		// Fresh installation or a version that predates the version tracking.
+		return nil
+	}
+	sourceMajor, sourceMinor, err := parseVersion(source)
+	if err != nil {
+		logrus.Warnf("Cannot parse the deployed Longhorn version %v, skipping the upgrade path validation", source)
+		return nil
+	}
+	targetMajor, targetMinor, err := parseVersion(target)
+	if err != nil {
+		logrus.Warnf("Cannot parse the manager version %v, skipping the upgrade path validation", target)
+		return nil
+	}
+
+	if targetMajor < sourceMajor || (targetMajor == sourceMajor && targetMinor < sourceMinor) {
+		return fmt.Errorf("downgrading from %v to %v is not supported", source, target)
+	}
+	if targetMajor != sourceMajor || targetMinor > sourceMinor+1 {
+		return fmt.Errorf("upgrading from %v to %v directly is not supported, please upgrade to v%d.%d.x first",
+			source, target, sourceMajor, sourceMinor+1)
+	}
+	return nil
+}
+
+// detectSourceVersion returns the Longhorn version currently recorded in the
+// cluster, or an empty string when no version has been recorded yet.
+func detectSourceVersion(namespace string, lhClient *lhclientset.Clientset) (string, error) {
+	setting, err := lhClient.LonghornV1beta2().Settings(namespace).Get(context.TODO(),
+		string(types.SettingNameCurrentLonghornVersion), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "unable to get setting %v", types.SettingNameCurrentLonghornVersion)
+	}
+	return setting.Value, nil
+}
+
+// updateCurrentLonghornVersion records the running manager version after a
+// successful upgrade so the next upgrade can validate its path.
+func updateCurrentLonghornVersion(namespace string, lhClient *lhclientset.Clientset, version string) error {
+	setting, err := lhClient.LonghornV1beta2().Settings(namespace).Get(context.TODO(),
+		string(types.SettingNameCurrentLonghornVersion), metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "unable to get setting %v", types.SettingNameCurrentLonghornVersion)
+		}
+		setting = &longhorn.Setting{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: string(types.SettingNameCurrentLonghornVersion),
+			},
+			Value: version,
+		}
+		if _, err := lhClient.LonghornV1beta2().Settings(namespace).Create(context.TODO(), setting, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "cannot create setting %v", types.SettingNameCurrentLonghornVersion)
+		}
+		return nil
+	}
+
+	if setting.Value == version {
+		return nil
+	}
+	setting.Value = version
+	if _, err := lhClient.LonghornV1beta2().Settings(namespace).Update(context.TODO(), setting, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "cannot update setting %v", types.SettingNameCurrentLonghornVersion)
+	}
+	return nil
+}
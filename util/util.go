@@ -267,6 +267,38 @@ func TimestampAfterTimeout(ts string, timeout time.Duration) bool {
 	return now.After(deadline)
 }
 
+// IsTimeInDailyWindow checks whether the given time falls into the daily time
+// window specified as "HH:MM-HH:MM" in UTC. An empty window matches any time,
+// and a window crossing midnight (e.g. "22:00-06:00") is supported.
+func IsTimeInDailyWindow(t time.Time, window string) (bool, error) {
+	if window == "" {
+		return true, nil
+	}
+	parts := strings.Split(window, "-")
+	if len(parts) != 2 {
+		return false, fmt.Errorf("invalid time window %v, the format should be HH:MM-HH:MM", window)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(parts[0]))
+	if err != nil {
+		return false, fmt.Errorf("invalid time window start %v: %v", parts[0], err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(parts[1]))
+	if err != nil {
+		return false, fmt.Errorf("invalid time window end %v: %v", parts[1], err)
+	}
+
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+	currentMinute := t.UTC().Hour()*60 + t.UTC().Minute()
+	if startMinute == endMinute {
+		return true, nil
+	}
+	if startMinute < endMinute {
+		return currentMinute >= startMinute && currentMinute < endMinute, nil
+	}
+	return currentMinute >= startMinute || currentMinute < endMinute, nil
+}
+
 func TimestampWithinLimit(latest time.Time, ts string, limit time.Duration) bool {
 	t, err := time.Parse(time.RFC3339, ts)
 	if err != nil {
@@ -494,6 +526,37 @@ func RemoveHostDirectoryContent(directory string) (err error) {
 	return nil
 }
 
+// TrimFilesystem runs fstrim against the host mount points of the volume
+// block device so the engine can reclaim the space of the freed blocks
+func TrimFilesystem(volumeName string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "failed to trim the filesystem of volume %v", volumeName)
+	}()
+
+	devicePath := filepath.Join(DeviceDirectory, volumeName)
+	initiatorNSPath := iscsi_util.GetHostNamespacePath(HostProcPath)
+	nsExec, err := iscsi_util.NewNamespaceExecutor(initiatorNSPath)
+	if err != nil {
+		return err
+	}
+
+	output, err := nsExec.Execute("bash", []string{"-c", fmt.Sprintf("awk '$1==\"%s\" {print $2}' /proc/mounts", devicePath)})
+	if err != nil {
+		return err
+	}
+	mountPoints := strings.Fields(output)
+	if len(mountPoints) == 0 {
+		return fmt.Errorf("cannot find a mount point for device %v", devicePath)
+	}
+
+	for _, mountPoint := range mountPoints {
+		if _, err := nsExec.Execute("fstrim", []string{mountPoint}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func CopyHostDirectoryContent(src, dest string) (err error) {
 	defer func() {
 		err = errors.Wrapf(err, "failed to copy the content from %v to %v for the host", src, dest)
@@ -787,3 +850,16 @@ func Contains(list []string, item string) bool {
 	}
 	return false
 }
+
+// MergeStringMaps returns a new map with all the entries from baseMap,
+// overwritten by the entries of overwriteMap when the keys collide.
+func MergeStringMaps(baseMap, overwriteMap map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range baseMap {
+		result[k] = v
+	}
+	for k, v := range overwriteMap {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhclientset "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+)
+
+const (
+	// ServicePath is the HTTP path the validating admission webhook is
+	// served on.
+	ServicePath = "/v1/webhook/admission/resources"
+)
+
+// Validator rejects invalid changes to the Longhorn resources before they
+// reach the controllers: node updates that would strand replica data, volume
+// specs the engine cannot run, malformed setting values, and deletion of
+// engine images that are still in use.
+type Validator struct {
+	namespace string
+	lhClient  lhclientset.Interface
+}
+
+func NewValidator(namespace string, lhClient lhclientset.Interface) *Validator {
+	return &Validator{
+		namespace: namespace,
+		lhClient:  lhClient,
+	}
+}
+
+// Handler returns the HTTP handler implementing the validating admission
+// webhook protocol for the Longhorn resources.
+func (v *Validator) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("failed to read admission review request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		review := &admissionv1.AdmissionReview{}
+		if err := json.Unmarshal(body, review); err != nil {
+			http.Error(rw, fmt.Sprintf("failed to unmarshal admission review request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			http.Error(rw, "admission review request is empty", http.StatusBadRequest)
+			return
+		}
+
+		review.Response = &admissionv1.AdmissionResponse{
+			UID:     review.Request.UID,
+			Allowed: true,
+		}
+		if err := v.validate(review.Request); err != nil {
+			review.Response.Allowed = false
+			review.Response.Result = &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+			}
+		}
+		review.Request = nil
+
+		resp, err := json.Marshal(review)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("failed to marshal admission review response: %v", err), http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		if _, err := rw.Write(resp); err != nil {
+			logrus.Warnf("admission webhook: failed to write response: %v", err)
+		}
+	})
+}
+
+func (v *Validator) validate(req *admissionv1.AdmissionRequest) error {
+	if req.Resource.Group != longhorn.SchemeGroupVersion.Group {
+		return nil
+	}
+
+	switch req.Resource.Resource {
+	case "nodes":
+		if req.Operation != admissionv1.Update {
+			return nil
+		}
+		oldNode := &longhorn.Node{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldNode); err != nil {
+			return fmt.Errorf("failed to unmarshal the old node: %v", err)
+		}
+		newNode := &longhorn.Node{}
+		if err := json.Unmarshal(req.Object.Raw, newNode); err != nil {
+			return fmt.Errorf("failed to unmarshal the new node: %v", err)
+		}
+		return v.validateNodeUpdate(oldNode, newNode)
+	case "volumes":
+		if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+			return nil
+		}
+		volume := &longhorn.Volume{}
+		if err := json.Unmarshal(req.Object.Raw, volume); err != nil {
+			return fmt.Errorf("failed to unmarshal the volume: %v", err)
+		}
+		return v.validateVolume(volume)
+	case "settings":
+		if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+			return nil
+		}
+		setting := &longhorn.Setting{}
+		if err := json.Unmarshal(req.Object.Raw, setting); err != nil {
+			return fmt.Errorf("failed to unmarshal the setting: %v", err)
+		}
+		if err := types.ValidateInitSetting(setting.Name, setting.Value); err != nil {
+			return fmt.Errorf("invalid value %v for setting %v: %v", setting.Value, setting.Name, err)
+		}
+		return nil
+	case "engineimages":
+		if req.Operation != admissionv1.Delete {
+			return nil
+		}
+		ei := &longhorn.EngineImage{}
+		if err := json.Unmarshal(req.OldObject.Raw, ei); err != nil {
+			return fmt.Errorf("failed to unmarshal the engine image: %v", err)
+		}
+		if ei.Status.RefCount != 0 {
+			return fmt.Errorf("engine image %v (%v) cannot be deleted while %v volume(s) are still using it",
+				ei.Name, ei.Spec.Image, ei.Status.RefCount)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (v *Validator) validateVolume(volume *longhorn.Volume) error {
+	if volume.Spec.Size <= 0 || volume.Spec.Size%util.SizeAlignment != 0 {
+		return fmt.Errorf("volume size %v must be a positive multiple of %v bytes", volume.Spec.Size, util.SizeAlignment)
+	}
+	if volume.Spec.NumberOfReplicas < 1 {
+		return fmt.Errorf("number of replicas %v must be at least 1", volume.Spec.NumberOfReplicas)
+	}
+	if _, err := util.ValidateTags(volume.Spec.NodeSelector); err != nil {
+		return fmt.Errorf("invalid node selector: %v", err)
+	}
+	if _, err := util.ValidateTags(volume.Spec.DiskSelector); err != nil {
+		return fmt.Errorf("invalid disk selector: %v", err)
+	}
+	return nil
+}
+
+func (v *Validator) validateNodeUpdate(oldNode, newNode *longhorn.Node) error {
+	if oldNode.Spec.Name != "" && newNode.Spec.Name != oldNode.Spec.Name {
+		return fmt.Errorf("node name %v cannot be changed to %v; remove the node and re-add it instead", oldNode.Spec.Name, newNode.Spec.Name)
+	}
+
+	for diskName, newDisk := range newNode.Spec.Disks {
+		oldDisk, exists := oldNode.Spec.Disks[diskName]
+		if !exists || newDisk.Path == oldDisk.Path {
+			continue
+		}
+		replicaCount, err := v.countReplicasOnDisk(oldNode.Name, oldDisk.Path)
+		if err != nil {
+			return fmt.Errorf("failed to check the replicas on disk %v of node %v: %v", diskName, oldNode.Name, err)
+		}
+		if replicaCount > 0 {
+			return fmt.Errorf("path of disk %v on node %v cannot be changed from %v to %v since %v replica(s) are still stored under it; remove the disk and re-add it instead",
+				diskName, oldNode.Name, oldDisk.Path, newDisk.Path, replicaCount)
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) countReplicasOnDisk(nodeName, diskPath string) (int, error) {
+	replicas, err := v.lhClient.LonghornV1beta2().Replicas(v.namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, r := range replicas.Items {
+		if r.Spec.NodeID == nodeName && r.Spec.DiskPath == diskPath {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,187 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhclientset "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+const (
+	// MutationServicePath is the HTTP path the mutating admission webhook
+	// is served on.
+	MutationServicePath = "/v1/webhook/admission/mutations"
+)
+
+// patchOperation is a single JSON patch operation of the mutating admission
+// response.
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Mutator fills the defaults on volume creation that were previously spread
+// over the API server and the controllers: the replica count, the data
+// locality, and the engine image come from the cluster settings, and every
+// volume gets the managed-by label stamped.
+type Mutator struct {
+	namespace string
+	lhClient  lhclientset.Interface
+}
+
+func NewMutator(namespace string, lhClient lhclientset.Interface) *Mutator {
+	return &Mutator{
+		namespace: namespace,
+		lhClient:  lhClient,
+	}
+}
+
+// Handler returns the HTTP handler implementing the mutating admission
+// webhook protocol for the Longhorn resources.
+func (m *Mutator) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("failed to read admission review request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		review := &admissionv1.AdmissionReview{}
+		if err := json.Unmarshal(body, review); err != nil {
+			http.Error(rw, fmt.Sprintf("failed to unmarshal admission review request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			http.Error(rw, "admission review request is empty", http.StatusBadRequest)
+			return
+		}
+
+		review.Response = &admissionv1.AdmissionResponse{
+			UID:     review.Request.UID,
+			Allowed: true,
+		}
+		patchOps, err := m.mutate(review.Request)
+		if err != nil {
+			review.Response.Allowed = false
+			review.Response.Result = &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+			}
+		} else if len(patchOps) > 0 {
+			patch, err := json.Marshal(patchOps)
+			if err != nil {
+				http.Error(rw, fmt.Sprintf("failed to marshal the patch: %v", err), http.StatusInternalServerError)
+				return
+			}
+			patchType := admissionv1.PatchTypeJSONPatch
+			review.Response.Patch = patch
+			review.Response.PatchType = &patchType
+		}
+		review.Request = nil
+
+		resp, err := json.Marshal(review)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("failed to marshal admission review response: %v", err), http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		if _, err := rw.Write(resp); err != nil {
+			logrus.Warnf("mutation webhook: failed to write response: %v", err)
+		}
+	})
+}
+
+func (m *Mutator) mutate(req *admissionv1.AdmissionRequest) ([]patchOperation, error) {
+	if req.Resource.Group != longhorn.SchemeGroupVersion.Group || req.Resource.Resource != "volumes" {
+		return nil, nil
+	}
+	if req.Operation != admissionv1.Create {
+		return nil, nil
+	}
+
+	volume := &longhorn.Volume{}
+	if err := json.Unmarshal(req.Object.Raw, volume); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the volume: %v", err)
+	}
+
+	return m.mutateVolume(volume)
+}
+
+func (m *Mutator) mutateVolume(volume *longhorn.Volume) ([]patchOperation, error) {
+	var patchOps []patchOperation
+
+	if volume.Spec.NumberOfReplicas == 0 {
+		value, err := m.getSettingValue(types.SettingNameDefaultReplicaCount)
+		if err != nil {
+			return nil, err
+		}
+		replicaCount, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the default replica count %v: %v", value, err)
+		}
+		patchOps = append(patchOps, patchOperation{Op: "replace", Path: "/spec/numberOfReplicas", Value: replicaCount})
+	}
+
+	if volume.Spec.DataLocality == "" {
+		value, err := m.getSettingValue(types.SettingNameDefaultDataLocality)
+		if err != nil {
+			return nil, err
+		}
+		patchOps = append(patchOps, patchOperation{Op: "replace", Path: "/spec/dataLocality", Value: value})
+	}
+
+	if volume.Spec.EngineImage == "" {
+		value, err := m.getSettingValue(types.SettingNameDefaultEngineImage)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			patchOps = append(patchOps, patchOperation{Op: "replace", Path: "/spec/engineImage", Value: value})
+		}
+	}
+
+	managedByKey := types.GetLonghornLabelKey(types.LonghornLabelManagedBy)
+	if volume.Labels == nil {
+		patchOps = append(patchOps, patchOperation{Op: "add", Path: "/metadata/labels", Value: map[string]string{managedByKey: types.ControlPlaneName}})
+	} else if volume.Labels[managedByKey] != types.ControlPlaneName {
+		// "/" and "~" have to be escaped in a JSON patch path.
+		escapedKey := strings.ReplaceAll(strings.ReplaceAll(managedByKey, "~", "~0"), "/", "~1")
+		patchOps = append(patchOps, patchOperation{Op: "add", Path: "/metadata/labels/" + escapedKey, Value: types.ControlPlaneName})
+	}
+
+	return patchOps, nil
+}
+
+// getSettingValue reads the setting from the cluster, falling back to the
+// built-in default when the setting has not been created or has no value yet.
+func (m *Mutator) getSettingValue(name types.SettingName) (string, error) {
+	definition, ok := types.SettingDefinitions[name]
+	if !ok {
+		return "", fmt.Errorf("unknown setting %v", name)
+	}
+	setting, err := m.lhClient.LonghornV1beta2().Settings(m.namespace).Get(context.TODO(), string(name), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return definition.Default, nil
+		}
+		return "", fmt.Errorf("failed to get setting %v: %v", name, err)
+	}
+	if setting.Value == "" {
+		return definition.Default, nil
+	}
+	return setting.Value, nil
+}
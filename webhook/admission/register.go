@@ -0,0 +1,161 @@
+package admission
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+const (
+	validatingWebhookConfigurationName = "longhorn-webhook-validator"
+	resourceWebhookName                = "resource-validator.longhorn.io"
+
+	mutatingWebhookConfigurationName = "longhorn-webhook-mutator"
+	volumeMutationWebhookName        = "volume-mutator.longhorn.io"
+)
+
+// RegisterValidatingWebhook creates or updates the validating webhook
+// configuration pointing the API server at the webhook served by the manager
+// for the Longhorn resources.
+func RegisterValidatingWebhook(kubeClient clientset.Interface, namespace, serviceName string, port int32, caBundle []byte) error {
+	path := ServicePath
+	failurePolicy := admissionregv1.Fail
+	sideEffects := admissionregv1.SideEffectClassNone
+
+	webhookConfiguration := &admissionregv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: validatingWebhookConfigurationName,
+		},
+		Webhooks: []admissionregv1.ValidatingWebhook{
+			{
+				Name: resourceWebhookName,
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{
+						Namespace: namespace,
+						Name:      serviceName,
+						Path:      &path,
+						Port:      &port,
+					},
+					CABundle: caBundle,
+				},
+				Rules: []admissionregv1.RuleWithOperations{
+					{
+						Operations: []admissionregv1.OperationType{admissionregv1.Update},
+						Rule: admissionregv1.Rule{
+							APIGroups:   []string{longhorn.SchemeGroupVersion.Group},
+							APIVersions: []string{"v1beta1", "v1beta2"},
+							Resources:   []string{"nodes"},
+						},
+					},
+					{
+						Operations: []admissionregv1.OperationType{admissionregv1.Create, admissionregv1.Update},
+						Rule: admissionregv1.Rule{
+							APIGroups:   []string{longhorn.SchemeGroupVersion.Group},
+							APIVersions: []string{"v1beta1", "v1beta2"},
+							Resources:   []string{"volumes", "settings"},
+						},
+					},
+					{
+						Operations: []admissionregv1.OperationType{admissionregv1.Delete},
+						Rule: admissionregv1.Rule{
+							APIGroups:   []string{longhorn.SchemeGroupVersion.Group},
+							APIVersions: []string{"v1beta1", "v1beta2"},
+							Resources:   []string{"engineimages"},
+						},
+					},
+				},
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+		},
+	}
+
+	existing, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), validatingWebhookConfigurationName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get validating webhook configuration %v", validatingWebhookConfigurationName)
+		}
+		if _, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), webhookConfiguration, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create validating webhook configuration %v", validatingWebhookConfigurationName)
+		}
+		logrus.Infof("Created the validating webhook configuration %v", validatingWebhookConfigurationName)
+		return nil
+	}
+
+	existing.Webhooks = webhookConfiguration.Webhooks
+	if _, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update validating webhook configuration %v", validatingWebhookConfigurationName)
+	}
+	logrus.Infof("Updated the validating webhook configuration %v", validatingWebhookConfigurationName)
+	return nil
+}
+
+// RegisterMutatingWebhook creates or updates the mutating webhook
+// configuration pointing the API server at the webhook defaulting the
+// Longhorn volumes on creation.
+func RegisterMutatingWebhook(kubeClient clientset.Interface, namespace, serviceName string, port int32, caBundle []byte) error {
+	path := MutationServicePath
+	failurePolicy := admissionregv1.Fail
+	sideEffects := admissionregv1.SideEffectClassNone
+
+	webhookConfiguration := &admissionregv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: mutatingWebhookConfigurationName,
+		},
+		Webhooks: []admissionregv1.MutatingWebhook{
+			{
+				Name: volumeMutationWebhookName,
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{
+						Namespace: namespace,
+						Name:      serviceName,
+						Path:      &path,
+						Port:      &port,
+					},
+					CABundle: caBundle,
+				},
+				Rules: []admissionregv1.RuleWithOperations{
+					{
+						Operations: []admissionregv1.OperationType{admissionregv1.Create},
+						Rule: admissionregv1.Rule{
+							APIGroups:   []string{longhorn.SchemeGroupVersion.Group},
+							APIVersions: []string{"v1beta1", "v1beta2"},
+							Resources:   []string{"volumes"},
+						},
+					},
+				},
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+		},
+	}
+
+	existing, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), mutatingWebhookConfigurationName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get mutating webhook configuration %v", mutatingWebhookConfigurationName)
+		}
+		if _, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(context.TODO(), webhookConfiguration, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create mutating webhook configuration %v", mutatingWebhookConfigurationName)
+		}
+		logrus.Infof("Created the mutating webhook configuration %v", mutatingWebhookConfigurationName)
+		return nil
+	}
+
+	existing.Webhooks = webhookConfiguration.Webhooks
+	if _, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update mutating webhook configuration %v", mutatingWebhookConfigurationName)
+	}
+	logrus.Infof("Updated the mutating webhook configuration %v", mutatingWebhookConfigurationName)
+	return nil
+}
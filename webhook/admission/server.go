@@ -0,0 +1,60 @@
+package admission
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-manager/webhook"
+)
+
+// Server serves the validating and mutating admission webhooks over TLS. The
+// API server only talks to admission webhooks via HTTPS, so either a
+// certificate and key file pair or a secret-backed certificate source is
+// mandatory.
+type Server struct {
+	address     string
+	certFile    string
+	keyFile     string
+	certificate *webhook.SecretCertificate
+	validator   *Validator
+	mutator     *Mutator
+}
+
+func NewServer(address, certFile, keyFile string, validator *Validator, mutator *Mutator) *Server {
+	return &Server{
+		address:   address,
+		certFile:  certFile,
+		keyFile:   keyFile,
+		validator: validator,
+		mutator:   mutator,
+	}
+}
+
+// UseCertificateSource makes the server serve the certificate from the given
+// source instead of the certificate files.
+func (s *Server) UseCertificateSource(certificate *webhook.SecretCertificate) {
+	s.certificate = certificate
+}
+
+func (s *Server) Serve() {
+	mux := http.NewServeMux()
+	mux.Handle(ServicePath, s.validator.Handler())
+	mux.Handle(MutationServicePath, s.mutator.Handler())
+
+	logrus.Infof("Admission webhook server listening at %v%v", s.address, ServicePath)
+	var err error
+	if s.certificate != nil {
+		server := &http.Server{
+			Addr:      s.address,
+			Handler:   mux,
+			TLSConfig: &tls.Config{GetCertificate: s.certificate.GetCertificate},
+		}
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = http.ListenAndServeTLS(s.address, s.certFile, s.keyFile, mux)
+	}
+	logrus.Fatalf("http.ListenAndServeTLS returned error: %+v", errors.Wrap(err, "admission webhook server error"))
+}
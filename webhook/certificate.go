@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+const (
+	certReloadInterval = time.Minute
+
+	// tlsSecretCAKey is the conventional key of the CA bundle in a
+	// kubernetes.io/tls secret, next to tls.crt and tls.key.
+	tlsSecretCAKey = "ca.crt"
+)
+
+// SecretCertificate serves the TLS certificate stored in a kubernetes.io/tls
+// secret, so operators can bring their own certificate instead of relying on
+// the files shipped with the deployment. The secret is re-read periodically
+// and a rotated certificate is picked up without restarting the server.
+type SecretCertificate struct {
+	kubeClient clientset.Interface
+	namespace  string
+	secretName string
+	// hostnames the served certificate must be valid for, usually the
+	// in-cluster DNS names of the webhook service.
+	hostnames []string
+
+	lock            sync.RWMutex
+	current         *tls.Certificate
+	caBundle        []byte
+	serialized      []byte
+	rotationHandler func(caBundle []byte)
+}
+
+// NewSecretCertificate loads the certificate from the given secret, verifies
+// it covers the given hostnames, and keeps watching the secret for rotation.
+func NewSecretCertificate(kubeClient clientset.Interface, namespace, secretName string, hostnames []string) (*SecretCertificate, error) {
+	sc := &SecretCertificate{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		secretName: secretName,
+		hostnames:  hostnames,
+	}
+	if err := sc.reload(); err != nil {
+		return nil, err
+	}
+	go sc.watch()
+	return sc, nil
+}
+
+// GetCertificate plugs into tls.Config so every handshake uses the most
+// recently loaded certificate.
+func (sc *SecretCertificate) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	sc.lock.RLock()
+	defer sc.lock.RUnlock()
+	return sc.current, nil
+}
+
+// CABundle returns the CA bundle the served certificate chains up to, i.e.
+// the ca.crt key of the secret, or the certificate itself when the secret
+// carries no separate CA. This is the bundle to register in the webhook
+// configurations so the API server can verify the webhook.
+func (sc *SecretCertificate) CABundle() []byte {
+	sc.lock.RLock()
+	defer sc.lock.RUnlock()
+	return sc.caBundle
+}
+
+// SetRotationHandler registers a function called with the new CA bundle every
+// time a rotated certificate is loaded, so webhook registrations depending on
+// the certificate can be refreshed.
+func (sc *SecretCertificate) SetRotationHandler(handler func(caBundle []byte)) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	sc.rotationHandler = handler
+}
+
+func (sc *SecretCertificate) watch() {
+	for range time.Tick(certReloadInterval) {
+		if err := sc.reload(); err != nil {
+			logrus.WithError(err).Warnf("Failed to reload the TLS certificate from secret %v, keeping the current one", sc.secretName)
+		}
+	}
+}
+
+func (sc *SecretCertificate) reload() error {
+	secret, err := sc.kubeClient.CoreV1().Secrets(sc.namespace).Get(context.TODO(), sc.secretName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "cannot get TLS secret %v", sc.secretName)
+	}
+	certPEM := secret.Data[corev1.TLSCertKey]
+	keyPEM := secret.Data[corev1.TLSPrivateKeyKey]
+	caPEM := secret.Data[tlsSecretCAKey]
+	if len(caPEM) == 0 {
+		// Without a separate CA the certificate is expected to be
+		// self-signed or to carry the full chain itself.
+		caPEM = certPEM
+	}
+	serialized := append(append(append([]byte{}, certPEM...), keyPEM...), caPEM...)
+
+	sc.lock.RLock()
+	unchanged := bytes.Equal(sc.serialized, serialized)
+	sc.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return errors.Wrapf(err, "invalid key pair in TLS secret %v", sc.secretName)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse the certificate in TLS secret %v", sc.secretName)
+	}
+	// Refuse a certificate the clients would reject anyway, so a rotation to
+	// a certificate issued for the wrong service name is caught here instead
+	// of as handshake failures.
+	for _, hostname := range sc.hostnames {
+		if err := leaf.VerifyHostname(hostname); err != nil {
+			return errors.Wrapf(err, "certificate in TLS secret %v does not cover %v", sc.secretName, hostname)
+		}
+	}
+
+	sc.lock.Lock()
+	loaded := sc.current != nil
+	sc.current = &cert
+	sc.caBundle = caPEM
+	sc.serialized = serialized
+	rotationHandler := sc.rotationHandler
+	sc.lock.Unlock()
+
+	if loaded {
+		logrus.Infof("Reloaded the rotated TLS certificate from secret %v", sc.secretName)
+		if rotationHandler != nil {
+			rotationHandler(caPEM)
+		}
+	} else {
+		logrus.Infof("Loaded the TLS certificate from secret %v", sc.secretName)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+const (
+	// ServicePath is the HTTP path the conversion webhook is served on.
+	ServicePath = "/v1/webhook/conversion"
+)
+
+// Handler returns the HTTP handler implementing the CRD conversion webhook
+// protocol for the Longhorn CRs. It converts objects between longhorn.io/v1beta1
+// and longhorn.io/v1beta2 so both API versions stay readable while the CRs are
+// migrated to the storage version.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("failed to read conversion review request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		review := &apiextv1.ConversionReview{}
+		if err := json.Unmarshal(body, review); err != nil {
+			http.Error(rw, fmt.Sprintf("failed to unmarshal conversion review request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			http.Error(rw, "conversion review request is empty", http.StatusBadRequest)
+			return
+		}
+
+		review.Response = convertObjects(review.Request)
+		review.Request = nil
+
+		resp, err := json.Marshal(review)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("failed to marshal conversion review response: %v", err), http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		if _, err := rw.Write(resp); err != nil {
+			logrus.Warnf("conversion webhook: failed to write response: %v", err)
+		}
+	})
+}
+
+func convertObjects(req *apiextv1.ConversionRequest) *apiextv1.ConversionResponse {
+	resp := &apiextv1.ConversionResponse{
+		UID: req.UID,
+		Result: metav1.Status{
+			Status: metav1.StatusSuccess,
+		},
+	}
+
+	for _, raw := range req.Objects {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			return conversionFailure(req.UID, fmt.Sprintf("failed to unmarshal object: %v", err))
+		}
+		converted, err := convertObject(obj, req.DesiredAPIVersion)
+		if err != nil {
+			return conversionFailure(req.UID, fmt.Sprintf("failed to convert %v %v to %v: %v",
+				obj.GetKind(), obj.GetName(), req.DesiredAPIVersion, err))
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Object: converted})
+	}
+
+	return resp
+}
+
+func conversionFailure(uid k8stypes.UID, message string) *apiextv1.ConversionResponse {
+	return &apiextv1.ConversionResponse{
+		UID: uid,
+		Result: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: message,
+		},
+	}
+}
+
+// convertObject converts a single Longhorn CR between v1beta1 and v1beta2.
+// The only structural difference between the two versions is the condition
+// representation: v1beta1 keeps conditions in a map keyed by the condition type
+// while v1beta2 uses a list.
+func convertObject(obj *unstructured.Unstructured, desiredAPIVersion string) (*unstructured.Unstructured, error) {
+	currentAPIVersion := obj.GetAPIVersion()
+	if currentAPIVersion == desiredAPIVersion {
+		return obj, nil
+	}
+
+	supported := map[string]bool{
+		types.CRDAPIVersionV1beta1: true,
+		types.CRDAPIVersionV1beta2: true,
+	}
+	if !supported[currentAPIVersion] || !supported[desiredAPIVersion] {
+		return nil, fmt.Errorf("unsupported conversion from %v to %v", currentAPIVersion, desiredAPIVersion)
+	}
+
+	converted := obj.DeepCopy()
+	converted.SetAPIVersion(desiredAPIVersion)
+
+	toList := desiredAPIVersion == types.CRDAPIVersionV1beta2
+	if err := convertConditionsField(converted.Object, toList, "status", "conditions"); err != nil {
+		return nil, err
+	}
+
+	diskStatus, found, err := unstructured.NestedMap(converted.Object, "status", "diskStatus")
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for diskName, disk := range diskStatus {
+			diskMap, ok := disk.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := convertConditionsField(diskMap, toList, "conditions"); err != nil {
+				return nil, err
+			}
+			diskStatus[diskName] = diskMap
+		}
+		if err := unstructured.SetNestedMap(converted.Object, diskStatus, "status", "diskStatus"); err != nil {
+			return nil, err
+		}
+	}
+
+	return converted, nil
+}
+
+func convertConditionsField(obj map[string]interface{}, toList bool, fields ...string) error {
+	value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil || !found || value == nil {
+		return err
+	}
+
+	switch conditions := value.(type) {
+	case map[string]interface{}:
+		if !toList {
+			return nil
+		}
+		list := []interface{}{}
+		for conditionType, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("invalid condition %v: %+v", conditionType, c)
+			}
+			if _, exist := condition["type"]; !exist {
+				condition["type"] = conditionType
+			}
+			list = append(list, condition)
+		}
+		return unstructured.SetNestedSlice(obj, list, fields...)
+	case []interface{}:
+		if toList {
+			return nil
+		}
+		conditionMap := map[string]interface{}{}
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("invalid condition: %+v", c)
+			}
+			conditionType, ok := condition["type"].(string)
+			if !ok {
+				return fmt.Errorf("condition is missing the type field: %+v", c)
+			}
+			conditionMap[conditionType] = condition
+		}
+		return unstructured.SetNestedMap(obj, conditionMap, fields...)
+	}
+
+	return fmt.Errorf("unrecognized conditions format: %+v", value)
+}
@@ -0,0 +1,73 @@
+package conversion
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextension "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// convertibleCRDNames lists the Longhorn CRDs that serve both v1beta1 and
+// v1beta2 and therefore need the conversion webhook.
+var convertibleCRDNames = []string{
+	"engines.longhorn.io",
+	"replicas.longhorn.io",
+	"volumes.longhorn.io",
+	"engineimages.longhorn.io",
+	"nodes.longhorn.io",
+	"instancemanagers.longhorn.io",
+	"sharemanagers.longhorn.io",
+	"backingimages.longhorn.io",
+	"backingimagemanagers.longhorn.io",
+	"backingimagedatasources.longhorn.io",
+	"backuptargets.longhorn.io",
+	"backupvolumes.longhorn.io",
+	"backups.longhorn.io",
+	"recurringjobs.longhorn.io",
+}
+
+// RegisterConversionWebhook points the conversion strategy of all Longhorn CRDs
+// at the webhook served by the manager, so that clients can keep reading both
+// v1beta1 and v1beta2 objects while the stored CRs are migrated.
+func RegisterConversionWebhook(extensionsClient apiextension.Interface, namespace, serviceName string, port int32, caBundle []byte) error {
+	path := ServicePath
+
+	for _, crdName := range convertibleCRDNames {
+		crd, err := extensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), crdName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logrus.Warnf("Cannot find CRD %v, skipping the conversion webhook registration for it", crdName)
+				continue
+			}
+			return errors.Wrapf(err, "failed to get CRD %v", crdName)
+		}
+
+		crd.Spec.Conversion = &apiextv1.CustomResourceConversion{
+			Strategy: apiextv1.WebhookConverter,
+			Webhook: &apiextv1.WebhookConversion{
+				ClientConfig: &apiextv1.WebhookClientConfig{
+					Service: &apiextv1.ServiceReference{
+						Namespace: namespace,
+						Name:      serviceName,
+						Path:      &path,
+						Port:      &port,
+					},
+					CABundle: caBundle,
+				},
+				ConversionReviewVersions: []string{"v1"},
+			},
+		}
+
+		if _, err := extensionsClient.ApiextensionsV1().CustomResourceDefinitions().Update(context.TODO(), crd, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update the conversion strategy of CRD %v", crdName)
+		}
+		logrus.Infof("Registered the conversion webhook for CRD %v", crdName)
+	}
+
+	return nil
+}
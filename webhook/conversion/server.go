@@ -0,0 +1,54 @@
+package conversion
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-manager/webhook"
+)
+
+// Server serves the CRD conversion webhook over TLS. The API server only talks
+// to conversion webhooks via HTTPS, so either a certificate and key file pair
+// or a secret-backed certificate source is mandatory.
+type Server struct {
+	address     string
+	certFile    string
+	keyFile     string
+	certificate *webhook.SecretCertificate
+}
+
+func NewServer(address, certFile, keyFile string) *Server {
+	return &Server{
+		address:  address,
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+}
+
+// UseCertificateSource makes the server serve the certificate from the given
+// source instead of the certificate files.
+func (s *Server) UseCertificateSource(certificate *webhook.SecretCertificate) {
+	s.certificate = certificate
+}
+
+func (s *Server) Serve() {
+	mux := http.NewServeMux()
+	mux.Handle(ServicePath, Handler())
+
+	logrus.Infof("Conversion webhook server listening at %v%v", s.address, ServicePath)
+	var err error
+	if s.certificate != nil {
+		server := &http.Server{
+			Addr:      s.address,
+			Handler:   mux,
+			TLSConfig: &tls.Config{GetCertificate: s.certificate.GetCertificate},
+		}
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = http.ListenAndServeTLS(s.address, s.certFile, s.keyFile, mux)
+	}
+	logrus.Fatalf("http.ListenAndServeTLS returned error: %+v", errors.Wrap(err, "conversion webhook server error"))
+}